@@ -7,6 +7,21 @@ var Config *MapConfig
 type MapConfig struct {
 	AppHost            string        `mapstructure:"APP_HOST"`
 	DbConnectionString string        `mapstructure:"DB_CONNECTION_STRING"`
-	JwtSecretKey       string        `mapstructure:"JWT_SECRET_KEY"`
 	JwtExpiresIn       time.Duration `mapstructure:"JWT_EXPIRE_DURATION"`
+	JwtSigningMethod   string        `mapstructure:"JWT_SIGNING_METHOD"`
+	JwtKeyRotation     time.Duration `mapstructure:"JWT_KEY_ROTATION_INTERVAL"`
+	JwtKeyEncryption   string        `mapstructure:"JWT_KEY_ENCRYPTION_KEY"`
+	TrashRetention     time.Duration `mapstructure:"TRASH_RETENTION"`
+	MetadataDumpDir    string        `mapstructure:"METADATA_DUMP_DIR"`
+	DefaultLocale      string        `mapstructure:"DEFAULT_LOCALE"`
+	MediaUploadDir     string        `mapstructure:"MEDIA_UPLOAD_DIR"`
+	MediaMaxUploadSize int64         `mapstructure:"MEDIA_MAX_UPLOAD_SIZE"`
+	ImportMaxUpload    int64         `mapstructure:"IMPORT_MAX_UPLOAD_SIZE"`
+	MediaWorkers       int           `mapstructure:"MEDIA_WORKERS"`
+	FfmpegPath         string        `mapstructure:"FFMPEG_PATH"`
+	FfprobePath        string        `mapstructure:"FFPROBE_PATH"`
+	WatchedThreshold   float64       `mapstructure:"WATCHED_THRESHOLD"`
+	ParentalUnlock     time.Duration `mapstructure:"PARENTAL_UNLOCK_DURATION"`
+	ReviewPremoderate  bool          `mapstructure:"REVIEWS_PREMODERATION"`
+	RecommendRefresh   time.Duration `mapstructure:"RECOMMENDATIONS_REFRESH"`
 }
@@ -14,22 +14,32 @@ import (
 )
 
 type AgeHandler struct {
-	ageRepo *repositories.AgeRepository
+	ageRepo   *repositories.AgeRepository
+	auditRepo *repositories.AuditRepository
+
+	translationsRepo *repositories.TranslationsRepository
+	validator        *RequestValidator
 }
 
+// minAge не передан — берётся из подписи рейтинга ("16+" -> 16)
 type createAgeRequest struct {
-	Age    string                `form:"age"`
-	Poster *multipart.FileHeader `form:"poster"`
+	Age    string                `form:"age" validate:"required,max=255"`
+	MinAge *int                  `form:"minAge" validate:"omitempty,min=0,max=21"`
+	Poster *multipart.FileHeader `form:"poster" validate:"required"`
 }
 
 type updateAgeRequest struct {
-	Age    string                `form:"age"`
+	Age    string                `form:"age" validate:"required,max=255"`
+	MinAge *int                  `form:"minAge" validate:"omitempty,min=0,max=21"`
 	Poster *multipart.FileHeader `form:"poster"`
 }
 
-func NewAgeHandler(ageRepo *repositories.AgeRepository) *AgeHandler {
+func NewAgeHandler(ageRepo *repositories.AgeRepository, auditRepo *repositories.AuditRepository, translationsRepo *repositories.TranslationsRepository, validator *RequestValidator) *AgeHandler {
 	return &AgeHandler{
-		ageRepo: ageRepo,
+		ageRepo:          ageRepo,
+		auditRepo:        auditRepo,
+		translationsRepo: translationsRepo,
+		validator:        validator,
 	}
 }
 
@@ -42,42 +52,54 @@ func NewAgeHandler(ageRepo *repositories.AgeRepository) *AgeHandler {
 // @Success      200  {object} object{id=int}  "OK"
 // @Failure   	 400  {object} models.ApiError "Invalid request age"
 // @Failure   	 500  {object} models.ApiError
+// @Failure      404  {object}  models.ApiError "Not found"
+// @Failure   	 422  {object} models.ApiError "Validation failed"
 // @Router       /ages [post]
 func (a *AgeHandler) HandleAddAge(c *gin.Context) {
 	var request createAgeRequest
 	err := c.Bind(&request)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, models.NewApiError("invalid age"))
+		respondError(c, http.StatusBadRequest, models.ErrCodeInvalidPayload)
 		return
 	}
 
-	if request.Poster == nil {
-		c.JSON(http.StatusBadRequest, "Poster file is required")
+	if !a.validator.Validate(c, &request) {
 		return
 	}
 
 	filename, err := a.saveAgePoster(c, request.Poster)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, models.NewApiError(err.Error()))
+		respondInternalError(c, err)
 		return
 	}
 
 	age := models.Age{
 		Age:       request.Age,
+		MinAge:    minAgeOrParsed(request.MinAge, request.Age),
 		PosterUrl: filename,
 	}
 
 	id, err := a.ageRepo.Create(c, age)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, models.NewApiError(err.Error()))
+		respondFindError(c, err)
 		return
 	}
 
+	age.Id = id
+	recordAudit(c, a.auditRepo, models.AuditActionCreate, models.AuditEntityAge, id, nil, age)
+
 	c.JSON(http.StatusOK, gin.H{
 		"id": id,
 	})
 }
 
+func minAgeOrParsed(minAge *int, label string) int {
+	if minAge != nil {
+		return *minAge
+	}
+	return models.ParseMinAge(label)
+}
+
 func (a *AgeHandler) saveAgePoster(c *gin.Context, poster *multipart.FileHeader) (string, error) {
 	filename := fmt.Sprintf("%s%s", uuid.NewString(), filepath.Ext(poster.Filename))
 	filepath := fmt.Sprintf("images/%s", filename)
@@ -93,12 +115,25 @@ func (a *AgeHandler) saveAgePoster(c *gin.Context, poster *multipart.FileHeader)
 // @Produce      json
 // @Success      200  {object}  []models.Age "List of age"
 // @Failure      500  {object}  models.ApiError "Internal Server Error"
+// @Param        lang query string false "kk, ru or en"
+// @Param        Accept-Language header string false "Preferred locales"
 // @Router       /ages [get]
 func (a *AgeHandler) FindAll(c *gin.Context) {
 	ages, err := a.ageRepo.FindAll(c)
 	if err != nil {
-		c.Status(http.StatusInternalServerError)
+		respondInternalError(c, err)
+		return
 	}
+
+	loc := newLocalizer(c, a.translationsRepo)
+	for i := range ages {
+		loc.addAge(&ages[i])
+	}
+	if err := loc.apply(c); err != nil {
+		respondInternalError(c, err)
+		return
+	}
+
 	c.JSON(http.StatusOK, ages)
 }
 
@@ -110,20 +145,33 @@ func (a *AgeHandler) FindAll(c *gin.Context) {
 // @Param        id path int true "Ages id"
 // @Success      200  {object}  models.Age "Ok"
 // @Failure      400  {object}  models.ApiError "Invalid age Id"
+// @Param        lang query string false "kk, ru or en"
+// @Param        Accept-Language header string false "Preferred locales"
+// @Failure      404  {object}  models.ApiError "Not found"
+// @Failure      500  {object}  models.ApiError
 // @Router       /ages/{id} [get]
 func (a *AgeHandler) FindById(c *gin.Context) {
 	idStr := c.Param("id")
 	id, err := strconv.Atoi(idStr)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, models.NewApiError("inavalid age id"))
+		respondError(c, http.StatusBadRequest, models.ErrCodeInvalidId)
+		return
 	}
 
 	age, err := a.ageRepo.FindById(c, id)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, models.NewApiError(err.Error()))
+		respondFindError(c, err)
+		return
+	}
+
+	loc := newLocalizer(c, a.translationsRepo)
+	loc.addAge(&age)
+	if err := loc.apply(c); err != nil {
+		respondInternalError(c, err)
 		return
 	}
 
+	setETag(c, age.Version)
 	c.JSON(http.StatusOK, age)
 
 }
@@ -137,40 +185,77 @@ func (a *AgeHandler) FindById(c *gin.Context) {
 // @Success      200  {object} object{id=int}  "OK"
 // @Failure   	 400  {object} models.ApiError "Invalid Age Id"
 // @Failure   	 500  {object} models.ApiError
+// @Param        If-Match header string true "ETag of the current version"
+// @Failure      412  {object}  models.ApiError "Resource was modified"
+// @Failure      428  {object}  models.ApiError "If-Match header required"
+// @Failure      404  {object}  models.ApiError "Not found"
+// @Failure   	 422  {object} models.ApiError "Validation failed"
 // @Router       /ages/{id} [put]
 func (a *AgeHandler) Update(c *gin.Context) {
 	idStr := c.Param("id")
 	id, err := strconv.Atoi(idStr)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, models.NewApiError("Invalid Age Id"))
+		respondError(c, http.StatusBadRequest, models.ErrCodeInvalidId)
 		return
 	}
 
-	_, err = a.ageRepo.FindById(c, id)
+	before, err := a.ageRepo.FindById(c, id)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, models.NewApiError(err.Error()))
+		respondFindError(c, err)
+		return
+	}
+
+	version, ok := ifMatchVersion(c)
+	if !ok {
+		return
+	}
+
+	if !checkIfMatch(c, version, before.Version) {
 		return
 	}
 
 	var request updateAgeRequest
 	err = c.BindJSON(&request)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, "Invalid request payload")
+		respondError(c, http.StatusBadRequest, models.ErrCodeInvalidPayload)
 		return
 	}
 
-	filename, err := a.saveAgePoster(c, request.Poster)
-	if err != nil {
-		c.JSON(http.StatusInternalServerError, models.NewApiError(err.Error()))
+	if !a.validator.Validate(c, &request) {
 		return
 	}
 
+	// без нового файла постер остаётся прежним
+	filename := before.PosterUrl
+	if request.Poster != nil {
+		filename, err = a.saveAgePoster(c, request.Poster)
+		if err != nil {
+			respondInternalError(c, err)
+			return
+		}
+	}
+
 	age := models.Age{
+		Id:        id,
 		Age:       request.Age,
+		MinAge:    minAgeOrParsed(request.MinAge, request.Age),
 		PosterUrl: filename,
+		Version:   version,
+	}
+
+	err = a.ageRepo.Update(c, id, age)
+	if isVersionConflict(err) {
+		respondVersionConflict(c)
+		return
+	}
+	if err != nil {
+		respondInternalError(c, err)
+		return
 	}
 
-	a.ageRepo.Update(c, id, age)
+	recordAudit(c, a.auditRepo, models.AuditActionUpdate, models.AuditEntityAge, id, before, age)
+
+	setETag(c, version+1)
 
 	c.Status(http.StatusOK)
 }
@@ -183,26 +268,189 @@ func (a *AgeHandler) Update(c *gin.Context) {
 // @Param        id path int true "Ages id"
 // @Success      200  {object}  models.Age "Ok"
 // @Failure      400  {object}  models.ApiError "Invalid age Id"
+// @Param        If-Match header string true "ETag of the current version"
+// @Failure      412  {object}  models.ApiError "Resource was modified"
+// @Failure      428  {object}  models.ApiError "If-Match header required"
+// @Failure      404  {object}  models.ApiError "Not found"
+// @Failure      500  {object}  models.ApiError
 // @Router       /ages/{id} [delete]
 func (a *AgeHandler) Delete(c *gin.Context) {
 	idStr := c.Param("id")
 	id, err := strconv.Atoi(idStr)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, models.NewApiError("Invalid age Id"))
+		respondError(c, http.StatusBadRequest, models.ErrCodeInvalidId)
 		return
 	}
 
-	_, err = a.ageRepo.FindById(c, id)
+	before, err := a.ageRepo.FindById(c, id)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, models.NewApiError(err.Error()))
+		respondFindError(c, err)
+		return
+	}
+
+	version, ok := ifMatchVersion(c)
+	if !ok {
+		return
+	}
+
+	if !checkIfMatch(c, version, before.Version) {
 		return
 	}
 
-	err = a.ageRepo.Delete(c, id)
+	err = a.ageRepo.Delete(c, id, version)
+	if isVersionConflict(err) {
+		respondVersionConflict(c)
+		return
+	}
 	if err != nil {
-		c.JSON(http.StatusBadRequest, models.NewApiError(err.Error()))
+		respondFindError(c, err)
 		return
 	}
 
+	recordAudit(c, a.auditRepo, models.AuditActionDelete, models.AuditEntityAge, id, before, nil)
+
 	c.Status(http.StatusOK)
 }
+
+type agePatchDocument struct {
+	Age    string `json:"age" validate:"required,max=255"`
+	MinAge int    `json:"minAge" validate:"min=0,max=21"`
+}
+
+// Patch godoc
+// @Summary      Partially update age
+// @Description  JSON Merge Patch (RFC 7396). Постер обновляется через PUT /ages/{id}/poster
+// @Tags         ages
+// @Accept       application/merge-patch+json
+// @Produce      json
+// @Param        id path int true "Age id"
+// @Param        request body handlers.agePatchDocument true "Merge patch"
+// @Param        If-Match header string true "ETag of the current version"
+// @Success      200  {object}  models.Age "OK"
+// @Failure      400  {object}  models.ApiError "Invalid data"
+// @Failure      412  {object}  models.ApiError "Resource was modified"
+// @Failure      415  {object}  models.ApiError "Unsupported Content-Type"
+// @Failure      428  {object}  models.ApiError "If-Match header required"
+// @Failure      500  {object}  models.ApiError
+// @Failure      404  {object}  models.ApiError "Not found"
+// @Failure      422  {object}  models.ApiError "Validation failed"
+// @Router       /ages/{id} [patch]
+// @Security Bearer
+func (a *AgeHandler) Patch(c *gin.Context) {
+	idStr := c.Param("id")
+	id, err := strconv.Atoi(idStr)
+	if err != nil {
+		respondError(c, http.StatusBadRequest, models.ErrCodeInvalidId)
+		return
+	}
+
+	before, err := a.ageRepo.FindById(c, id)
+	if err != nil {
+		respondFindError(c, err)
+		return
+	}
+
+	version, ok := ifMatchVersion(c)
+	if !ok {
+		return
+	}
+
+	if !checkIfMatch(c, version, before.Version) {
+		return
+	}
+
+	document := agePatchDocument{Age: before.Age, MinAge: before.MinAge}
+	err = applyMergePatch(c, &document)
+	if err != nil {
+		respondPatchError(c, err)
+		return
+	}
+
+	if !a.validator.Validate(c, &document) {
+		return
+	}
+
+	age := before
+	age.Age = document.Age
+	age.MinAge = document.MinAge
+	age.Version = version
+
+	a.saveAge(c, id, before, age)
+}
+
+// UpdatePoster godoc
+// @Summary      Upload age poster
+// @Tags         ages
+// @Accept       multipart/form-data
+// @Produce      json
+// @Param        id path int true "Age id"
+// @Param        poster formData file true "Poster image"
+// @Param        If-Match header string true "ETag of the current version"
+// @Success      200  {object}  models.Age "OK"
+// @Failure      400  {object}  models.ApiError "Invalid data"
+// @Failure      412  {object}  models.ApiError "Resource was modified"
+// @Failure      428  {object}  models.ApiError "If-Match header required"
+// @Failure      500  {object}  models.ApiError
+// @Failure      404  {object}  models.ApiError "Not found"
+// @Router       /ages/{id}/poster [put]
+// @Security Bearer
+func (a *AgeHandler) UpdatePoster(c *gin.Context) {
+	idStr := c.Param("id")
+	id, err := strconv.Atoi(idStr)
+	if err != nil {
+		respondError(c, http.StatusBadRequest, models.ErrCodeInvalidId)
+		return
+	}
+
+	before, err := a.ageRepo.FindById(c, id)
+	if err != nil {
+		respondFindError(c, err)
+		return
+	}
+
+	version, ok := ifMatchVersion(c)
+	if !ok {
+		return
+	}
+
+	if !checkIfMatch(c, version, before.Version) {
+		return
+	}
+
+	poster, err := c.FormFile("poster")
+	if err != nil {
+		respondError(c, http.StatusBadRequest, models.ErrCodeFileRequired, models.NewApiErrorDetail("poster", models.DetailCodeRequired))
+		return
+	}
+
+	filename, err := a.saveAgePoster(c, poster)
+	if err != nil {
+		respondInternalError(c, err)
+		return
+	}
+
+	age := before
+	age.PosterUrl = filename
+	age.Version = version
+
+	a.saveAge(c, id, before, age)
+}
+
+// saveAge обновляет запись с проверкой версии и отвечает её новым состоянием
+func (a *AgeHandler) saveAge(c *gin.Context, id int, before models.Age, age models.Age) {
+	err := a.ageRepo.Update(c, id, age)
+	if isVersionConflict(err) {
+		respondVersionConflict(c)
+		return
+	}
+	if err != nil {
+		respondInternalError(c, err)
+		return
+	}
+
+	recordAudit(c, a.auditRepo, models.AuditActionUpdate, models.AuditEntityAge, id, before, age)
+
+	age.Version++
+	setETag(c, age.Version)
+	c.JSON(http.StatusOK, age)
+}
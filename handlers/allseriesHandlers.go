@@ -12,31 +12,39 @@ import (
 
 type AllSeriesHandlers struct {
 	allseriesRepo *repositories.AllSeriesRepository
+	auditRepo     *repositories.AuditRepository
+	validator     *RequestValidator
+	subtitlesRepo *repositories.SubtitleTracksRepository
+	parentalRepo  *repositories.ParentalControlsRepository
 }
 
 type createAllSeriesRequest struct {
-	Series      *int    `form:"series"`
-	Title       *string `form:"title"`
+	Series      *int    `form:"series" validate:"required,gt=0"`
+	Title       *string `form:"title" validate:"required,max=255"`
 	Description *string `form:"description"`
-	ReleaseYear *int    `form:"release_year"`
-	Director    *string `form:"director"`
-	Rating      *int    `form:"rating"`
-	TrailerUrl  *string `form:"trailer_url"`
+	ReleaseYear *int    `form:"release_year" validate:"omitempty,year"`
+	Director    *string `form:"director" validate:"omitempty,max=255"`
+	Rating      *int    `form:"rating" validate:"omitempty,min=0,max=10"`
+	TrailerUrl  *string `form:"trailer_url" validate:"omitempty,trailer"`
 }
 
 type updateAllSeriesRequest struct {
-	Series      *int    `form:"series"`
-	Title       *string `form:"title"`
+	Series      *int    `form:"series" validate:"omitempty,gt=0"`
+	Title       *string `form:"title" validate:"omitempty,max=255"`
 	Description *string `form:"description"`
-	ReleaseYear *int    `form:"release_year"`
-	Director    *string `form:"director"`
-	Rating      *int    `form:"rating"`
-	TrailerUrl  *string `form:"trailer_url"`
+	ReleaseYear *int    `form:"release_year" validate:"omitempty,year"`
+	Director    *string `form:"director" validate:"omitempty,max=255"`
+	Rating      *int    `form:"rating" validate:"omitempty,min=0,max=10"`
+	TrailerUrl  *string `form:"trailer_url" validate:"omitempty,trailer"`
 }
 
-func NewAllSeriesHandlers(allseriesRepo *repositories.AllSeriesRepository) *AllSeriesHandlers {
+func NewAllSeriesHandlers(allseriesRepo *repositories.AllSeriesRepository, auditRepo *repositories.AuditRepository, validator *RequestValidator, subtitlesRepo *repositories.SubtitleTracksRepository, parentalRepo *repositories.ParentalControlsRepository) *AllSeriesHandlers {
 	return &AllSeriesHandlers{
 		allseriesRepo: allseriesRepo,
+		auditRepo:     auditRepo,
+		validator:     validator,
+		subtitlesRepo: subtitlesRepo,
+		parentalRepo:  parentalRepo,
 	}
 }
 
@@ -49,12 +57,18 @@ func NewAllSeriesHandlers(allseriesRepo *repositories.AllSeriesRepository) *AllS
 // @Success      200  {object} object{id=int}  "OK"
 // @Failure   	 400  {object} models.ApiError "Invalid request AllSeries"
 // @Failure   	 500  {object} models.ApiError
+// @Failure      404  {object}  models.ApiError "Not found"
+// @Failure   	 422  {object} models.ApiError "Validation failed"
 // @Router       /allseries [post]
 func (h *AllSeriesHandlers) Create(c *gin.Context) {
 	var request createAllSeriesRequest
 	err := c.BindJSON(&request)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, "Invalid request AllSeries")
+		respondError(c, http.StatusBadRequest, models.ErrCodeInvalidPayload)
+		return
+	}
+
+	if !h.validator.Validate(c, &request) {
 		return
 	}
 
@@ -65,15 +79,18 @@ func (h *AllSeriesHandlers) Create(c *gin.Context) {
 		ReleaseYear: request.ReleaseYear,
 		Director:    request.Director,
 		Rating:      request.Rating,
-		TrailerUrl:  request.TrailerUrl,
+		TrailerUrl:  normalizeTrailerUrl(request.TrailerUrl),
 	}
 
 	id, err := h.allseriesRepo.Create(c, allserie)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, models.NewApiError(err.Error()))
+		respondFindError(c, err)
 		return
 	}
 
+	allserie.Id = &id
+	recordAudit(c, h.auditRepo, models.AuditActionCreate, models.AuditEntityAllSeries, id, nil, allserie)
+
 	c.JSON(http.StatusOK, gin.H{
 		"id": id,
 	})
@@ -87,21 +104,36 @@ func (h *AllSeriesHandlers) Create(c *gin.Context) {
 // @Param        id path int true "AllSeries id"
 // @Success      200  {object}  models.AllSeries "Ok"
 // @Failure      400  {object}  models.ApiError "Invalid allseries id"
+// @Failure      403  {object}  models.ApiError "Restricted by parental controls"
+// @Failure      404  {object}  models.ApiError "Not found"
+// @Failure      500  {object}  models.ApiError
 // @Router       /allseries/{id} [get]
 func (h *AllSeriesHandlers) FindById(c *gin.Context) {
 	idStr := c.Param("id")
 	id, err := strconv.Atoi(idStr)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, models.NewApiError("Invalid allseries id"))
+		respondError(c, http.StatusBadRequest, models.ErrCodeInvalidId)
+		return
+	}
+
+	if !checkEpisodeAllowed(c, h.parentalRepo, id) {
+		return
+	}
+
+	allserie, err := h.allseriesRepo.FindById(c, id)
+	if err != nil {
+		respondFindError(c, err)
 		return
 	}
 
-	_, err = h.allseriesRepo.FindById(c, id)
+	allserie.Subtitles, err = h.subtitlesRepo.FindByAllSeries(c, id)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, models.NewApiError(err.Error()))
+		respondInternalError(c, err)
 		return
 	}
 
+	setETag(c, allserie.Version)
+	c.JSON(http.StatusOK, allserie)
 }
 
 // FindAll godoc
@@ -109,13 +141,14 @@ func (h *AllSeriesHandlers) FindById(c *gin.Context) {
 // @Tags          allseries - это эндпоинты для каждой серии
 // @Accept       json
 // @Produce      json
+// @Description  При действующем возрастном лимите — только серии фильмов, которые разрешено смотреть
 // @Success      200  {object}  []models.AllSeries "List of allseries"
 // @Failure      500  {object}  models.ApiError "Internal Server Error"
 // @Router       /allseries [get]
 func (h *AllSeriesHandlers) FindAll(c *gin.Context) {
-	allseries, err := h.allseriesRepo.FindAll(c)
+	allseries, err := h.allseriesRepo.FindAll(c, ageLimit(c))
 	if err != nil {
-		c.Status(http.StatusInternalServerError)
+		respondInternalError(c, err)
 		return
 	}
 	c.JSON(http.StatusOK, allseries)
@@ -130,44 +163,72 @@ func (h *AllSeriesHandlers) FindAll(c *gin.Context) {
 // @Success      200  {object} object{id=int}  "OK"
 // @Failure   	 400  {object} models.ApiError "Invalid AllSeries Id"
 // @Failure   	 500  {object} models.ApiError
+// @Param        If-Match header string true "ETag of the current version"
+// @Failure      412  {object}  models.ApiError "Resource was modified"
+// @Failure      428  {object}  models.ApiError "If-Match header required"
+// @Failure      404  {object}  models.ApiError "Not found"
+// @Failure   	 422  {object} models.ApiError "Validation failed"
 // @Router       /allseries/{id} [put]
 func (h *AllSeriesHandlers) Update(c *gin.Context) {
 	idStr := c.Param("id")
 	id, err := strconv.Atoi(idStr)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, models.NewApiError("Invalid AllSeries Id"))
+		respondError(c, http.StatusBadRequest, models.ErrCodeInvalidId)
 		return
 	}
 
-	_, err = h.allseriesRepo.FindById(c, id)
+	before, err := h.allseriesRepo.FindById(c, id)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, models.NewApiError(err.Error()))
+		respondFindError(c, err)
+		return
+	}
+
+	version, ok := ifMatchVersion(c)
+	if !ok {
+		return
+	}
+
+	if !checkIfMatch(c, version, before.Version) {
 		return
 	}
 
 	var request updateAllSeriesRequest
 	err = c.BindJSON(&request)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, "Invalid request payload")
+		respondError(c, http.StatusBadRequest, models.ErrCodeInvalidPayload)
+		return
+	}
+
+	if !h.validator.Validate(c, &request) {
 		return
 	}
 
 	allserie := models.AllSeries{
+		Id:          &id,
 		Series:      request.Series,
 		Title:       request.Title,
 		Description: request.Description,
 		ReleaseYear: request.ReleaseYear,
 		Director:    request.Director,
 		Rating:      request.Rating,
-		TrailerUrl:  request.TrailerUrl,
+		TrailerUrl:  normalizeTrailerUrl(request.TrailerUrl),
+		Version:     version,
 	}
 
 	err = h.allseriesRepo.Update(c, id, allserie)
+	if isVersionConflict(err) {
+		respondVersionConflict(c)
+		return
+	}
 	if err != nil {
-		c.JSON(http.StatusBadRequest, models.NewApiError(err.Error()))
+		respondFindError(c, err)
 		return
 	}
 
+	recordAudit(c, h.auditRepo, models.AuditActionUpdate, models.AuditEntityAllSeries, id, before, allserie)
+
+	setETag(c, version+1)
+
 	c.Status(http.StatusOK)
 }
 
@@ -179,26 +240,156 @@ func (h *AllSeriesHandlers) Update(c *gin.Context) {
 // @Param        id path int true "Allseries id"
 // @Success      200  {object}  models.AllSeries "Ok"
 // @Failure      400  {object}  models.ApiError "Invalid AllSeries Id"
+// @Param        If-Match header string true "ETag of the current version"
+// @Failure      412  {object}  models.ApiError "Resource was modified"
+// @Failure      428  {object}  models.ApiError "If-Match header required"
+// @Failure      404  {object}  models.ApiError "Not found"
+// @Failure      500  {object}  models.ApiError
 // @Router       /allseries/{id} [delete]
 func (h *AllSeriesHandlers) Delete(c *gin.Context) {
 	idStr := c.Param("id")
 	id, err := strconv.Atoi(idStr)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, models.NewApiError("Invalid AllSeries Id"))
+		respondError(c, http.StatusBadRequest, models.ErrCodeInvalidId)
 		return
 	}
 
-	_, err = h.allseriesRepo.FindById(c, id)
+	before, err := h.allseriesRepo.FindById(c, id)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, models.NewApiError(err.Error()))
+		respondFindError(c, err)
+		return
+	}
+
+	version, ok := ifMatchVersion(c)
+	if !ok {
+		return
+	}
+
+	if !checkIfMatch(c, version, before.Version) {
 		return
 	}
 
-	err = h.allseriesRepo.Delete(c, id)
+	err = h.allseriesRepo.Delete(c, id, version)
+	if isVersionConflict(err) {
+		respondVersionConflict(c)
+		return
+	}
 	if err != nil {
-		c.JSON(http.StatusBadRequest, models.NewApiError(err.Error()))
+		respondFindError(c, err)
 		return
 	}
 
+	recordAudit(c, h.auditRepo, models.AuditActionDelete, models.AuditEntityAllSeries, id, before, nil)
+
 	c.Status(http.StatusOK)
 }
+
+type allSeriesPatchDocument struct {
+	Series      *int    `json:"series" validate:"required,gt=0"`
+	Title       *string `json:"title" validate:"required,max=255"`
+	Description *string `json:"description"`
+	ReleaseYear *int    `json:"releaseYear" validate:"omitempty,year"`
+	Director    *string `json:"director" validate:"omitempty,max=255"`
+	Rating      *int    `json:"rating" validate:"omitempty,min=0,max=10"`
+	TrailerUrl  *string `json:"trailerUrl" validate:"omitempty,trailer"`
+}
+
+// Patch godoc
+// @Summary      Partially update allseries
+// @Description  JSON Merge Patch (RFC 7396): null очищает поле, отсутствующие поля не меняются
+// @Tags         allseries - это эндпоинты для каждой серии
+// @Accept       application/merge-patch+json
+// @Produce      json
+// @Param        id path int true "Allseries id"
+// @Param        request body handlers.allSeriesPatchDocument true "Merge patch"
+// @Param        If-Match header string true "ETag of the current version"
+// @Success      200  {object}  models.AllSeries "OK"
+// @Failure      400  {object}  models.ApiError "Invalid data"
+// @Failure      412  {object}  models.ApiError "Resource was modified"
+// @Failure      415  {object}  models.ApiError "Unsupported Content-Type"
+// @Failure      428  {object}  models.ApiError "If-Match header required"
+// @Failure      500  {object}  models.ApiError
+// @Failure      404  {object}  models.ApiError "Not found"
+// @Failure      422  {object}  models.ApiError "Validation failed"
+// @Router       /allseries/{id} [patch]
+// @Security Bearer
+func (h *AllSeriesHandlers) Patch(c *gin.Context) {
+	idStr := c.Param("id")
+	id, err := strconv.Atoi(idStr)
+	if err != nil {
+		respondError(c, http.StatusBadRequest, models.ErrCodeInvalidId)
+		return
+	}
+
+	before, err := h.allseriesRepo.FindById(c, id)
+	if err != nil {
+		respondFindError(c, err)
+		return
+	}
+
+	version, ok := ifMatchVersion(c)
+	if !ok {
+		return
+	}
+
+	if !checkIfMatch(c, version, before.Version) {
+		return
+	}
+
+	document := allSeriesPatchDocument{
+		Series:      before.Series,
+		Title:       before.Title,
+		Description: before.Description,
+		ReleaseYear: before.ReleaseYear,
+		Director:    before.Director,
+		Rating:      before.Rating,
+		TrailerUrl:  before.TrailerUrl,
+	}
+	err = applyMergePatch(c, &document)
+	if err != nil {
+		respondPatchError(c, err)
+		return
+	}
+
+	if !h.validator.Validate(c, &document) {
+		return
+	}
+
+	allserie := models.AllSeries{
+		Id:          &id,
+		Series:      document.Series,
+		Title:       document.Title,
+		Description: document.Description,
+		ReleaseYear: document.ReleaseYear,
+		Director:    document.Director,
+		Rating:      document.Rating,
+		TrailerUrl:  normalizeTrailerUrl(document.TrailerUrl),
+		Version:     version,
+	}
+
+	err = h.allseriesRepo.Update(c, id, allserie)
+	if isVersionConflict(err) {
+		respondVersionConflict(c)
+		return
+	}
+	if err != nil {
+		respondInternalError(c, err)
+		return
+	}
+
+	recordAudit(c, h.auditRepo, models.AuditActionUpdate, models.AuditEntityAllSeries, id, before, allserie)
+
+	allserie.Version++
+	setETag(c, allserie.Version)
+	c.JSON(http.StatusOK, allserie)
+}
+
+// normalizeTrailerUrl сохраняет ссылку на трейлер серии в каноническом виде
+func normalizeTrailerUrl(trailerUrl *string) *string {
+	if trailerUrl == nil {
+		return nil
+	}
+
+	normalized := models.NormalizeTrailerUrl(*trailerUrl)
+	return &normalized
+}
@@ -0,0 +1,227 @@
+package handlers
+
+import (
+	"fmt"
+	"goozinshe/middlewares"
+	"goozinshe/models"
+	"goozinshe/repositories"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+type ApiKeysHandlers struct {
+	apiKeysRepo *repositories.ApiKeysRepository
+	validator   *RequestValidator
+}
+
+func NewApiKeysHandlers(apiKeysRepo *repositories.ApiKeysRepository, validator *RequestValidator) *ApiKeysHandlers {
+	return &ApiKeysHandlers{apiKeysRepo: apiKeysRepo, validator: validator}
+}
+
+type createApiKeyRequest struct {
+	Name        string     `validate:"required,max=255"`
+	Permissions []string   `validate:"min=1,unique,dive,required,permission"`
+	ExpiresAt   *time.Time `validate:"omitempty,future"`
+}
+
+type apiKeyResponse struct {
+	Id          int        `json:"id"`
+	Name        string     `json:"name"`
+	Prefix      string     `json:"prefix"`
+	Permissions []string   `json:"permissions"`
+	CreatedAt   time.Time  `json:"createdAt"`
+	ExpiresAt   *time.Time `json:"expiresAt"`
+	LastUsedAt  *time.Time `json:"lastUsedAt"`
+	RevokedAt   *time.Time `json:"revokedAt"`
+}
+
+type createApiKeyResponse struct {
+	Id  int    `json:"id"`
+	Key string `json:"key"`
+}
+
+func newApiKeyResponse(key models.ApiKey) apiKeyResponse {
+	return apiKeyResponse{
+		Id:          key.Id,
+		Name:        key.Name,
+		Prefix:      key.Prefix,
+		Permissions: key.Permissions,
+		CreatedAt:   key.CreatedAt,
+		ExpiresAt:   key.ExpiresAt,
+		LastUsedAt:  key.LastUsedAt,
+		RevokedAt:   key.RevokedAt,
+	}
+}
+
+// Create godoc
+// @Tags         apiKeys
+// @Summary      Create API key for machine client
+// @Description  Ключ возвращается в открытом виде только один раз, в базе хранится его хэш.
+// @Description  Права записываются как "*" или "<ресурс>:read|write|*"; API ключ может выдать только те права, которые есть у него самого
+// @Accept       json
+// @Produce      json
+// @Param request body handlers.createApiKeyRequest true "API key data"
+// @Success      200  {object} handlers.createApiKeyResponse "OK"
+// @Failure   	 400  {object} models.ApiError "Invalid data"
+// @Failure   	 403  {object} models.ApiError "Permissions exceed the caller's"
+// @Failure   	 422  {object} models.ApiError "Validation failed"
+// @Failure   	 500  {object} models.ApiError
+// @Router       /admin/apiKeys [post]
+// @Security Bearer
+func (h *ApiKeysHandlers) Create(c *gin.Context) {
+	var request createApiKeyRequest
+	err := c.BindJSON(&request)
+	if err != nil {
+		respondError(c, http.StatusBadRequest, models.ErrCodeInvalidPayload)
+		return
+	}
+
+	if !h.validator.Validate(c, &request) {
+		return
+	}
+
+	if !checkGrantedPermissions(c, request.Permissions) {
+		return
+	}
+
+	plainKey, prefix, err := middlewares.GenerateApiKey()
+	if err != nil {
+		respondInternalError(c, err)
+		return
+	}
+
+	key := models.ApiKey{
+		Name:        request.Name,
+		Prefix:      prefix,
+		KeyHash:     middlewares.HashApiKey(plainKey),
+		Permissions: request.Permissions,
+		ExpiresAt:   request.ExpiresAt,
+	}
+
+	if userId, exists := c.Get("userId"); exists {
+		createdBy := userId.(int)
+		key.CreatedBy = &createdBy
+	}
+
+	id, err := h.apiKeysRepo.Create(c, key)
+	if err != nil {
+		respondInternalError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, createApiKeyResponse{Id: id, Key: plainKey})
+}
+
+// checkGrantedPermissions не даёт API ключу выпустить ключ шире себя, например "*" при праве admin/apiKeys:write.
+// Пользователи ролей не имеют и могут выдавать любые права
+func checkGrantedPermissions(c *gin.Context, permissions []string) bool {
+	value, exists := c.Get(models.ServicePrincipalKey)
+	if !exists {
+		return true
+	}
+	principal := value.(models.ServicePrincipal)
+
+	details := make([]models.ApiErrorDetail, 0)
+	for i, permission := range permissions {
+		if !principal.HasPermission(permission) {
+			details = append(details, models.NewApiErrorDetail(fmt.Sprintf("permissions[%d]", i), models.DetailCodeNotAllowed))
+		}
+	}
+
+	if len(details) > 0 {
+		respondError(c, http.StatusForbidden, models.ErrCodeForbidden, details...)
+		return false
+	}
+
+	return true
+}
+
+// FindAll godoc
+// @Tags         apiKeys
+// @Summary      Get API keys list
+// @Accept       json
+// @Produce      json
+// @Success      200  {array} handlers.apiKeyResponse "OK"
+// @Failure   	 500  {object} models.ApiError
+// @Router       /admin/apiKeys [get]
+// @Security Bearer
+func (h *ApiKeysHandlers) FindAll(c *gin.Context) {
+	keys, err := h.apiKeysRepo.FindAll(c)
+	if err != nil {
+		respondInternalError(c, err)
+		return
+	}
+
+	dtos := make([]apiKeyResponse, 0, len(keys))
+	for _, k := range keys {
+		dtos = append(dtos, newApiKeyResponse(k))
+	}
+
+	c.JSON(http.StatusOK, dtos)
+}
+
+// FindById godoc
+// @Tags         apiKeys
+// @Summary      Find API key by id
+// @Accept       json
+// @Produce      json
+// @Param id path int true "API key id"
+// @Success      200  {object} handlers.apiKeyResponse "OK"
+// @Failure   	 400  {object} models.ApiError "Invalid api key id"
+// @Failure   	 404  {object} models.ApiError "Api key not found"
+// @Router       /admin/apiKeys/{id} [get]
+// @Security Bearer
+func (h *ApiKeysHandlers) FindById(c *gin.Context) {
+	idStr := c.Param("id")
+	id, err := strconv.Atoi(idStr)
+	if err != nil {
+		respondError(c, http.StatusBadRequest, models.ErrCodeInvalidId)
+		return
+	}
+
+	key, err := h.apiKeysRepo.FindById(c, id)
+	if err != nil {
+		respondError(c, http.StatusNotFound, models.ErrCodeNotFound)
+		return
+	}
+
+	c.JSON(http.StatusOK, newApiKeyResponse(key))
+}
+
+// Revoke godoc
+// @Tags         apiKeys
+// @Summary      Revoke API key
+// @Accept       json
+// @Produce      json
+// @Param id path int true "API key id"
+// @Success      200  "OK"
+// @Failure   	 400  {object} models.ApiError "Invalid api key id"
+// @Failure   	 404  {object} models.ApiError "Api key not found"
+// @Failure   	 500  {object} models.ApiError
+// @Router       /admin/apiKeys/{id} [delete]
+// @Security Bearer
+func (h *ApiKeysHandlers) Revoke(c *gin.Context) {
+	idStr := c.Param("id")
+	id, err := strconv.Atoi(idStr)
+	if err != nil {
+		respondError(c, http.StatusBadRequest, models.ErrCodeInvalidId)
+		return
+	}
+
+	_, err = h.apiKeysRepo.FindById(c, id)
+	if err != nil {
+		respondError(c, http.StatusNotFound, models.ErrCodeNotFound)
+		return
+	}
+
+	err = h.apiKeysRepo.Revoke(c, id)
+	if err != nil {
+		respondInternalError(c, err)
+		return
+	}
+
+	c.Status(http.StatusOK)
+}
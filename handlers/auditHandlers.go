@@ -0,0 +1,146 @@
+package handlers
+
+import (
+	"goozinshe/logger"
+	"goozinshe/models"
+	"goozinshe/repositories"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+const (
+	auditDefaultLimit = 50
+	auditMaxLimit     = 500
+)
+
+type AuditHandlers struct {
+	auditRepo *repositories.AuditRepository
+}
+
+func NewAuditHandlers(auditRepo *repositories.AuditRepository) *AuditHandlers {
+	return &AuditHandlers{auditRepo: auditRepo}
+}
+
+// FindAll godoc
+// @Tags         audit
+// @Summary      Audit log of administrative changes
+// @Accept       json
+// @Produce      json
+// @Param actorUserId query int false "Actor user id"
+// @Param action query string false "create, update or delete"
+// @Param entityType query string false "movie, genre, category, age, allseries, user, role, import, person"
+// @Param entityId query int false "Entity id"
+// @Param from query string false "RFC3339 lower bound"
+// @Param to query string false "RFC3339 upper bound"
+// @Param limit query int false "Page size, 50 by default"
+// @Param offset query int false "Offset"
+// @Success      200  {array} models.AuditEvent "OK"
+// @Failure   	 400  {object} models.ApiError "Invalid filters"
+// @Failure   	 500  {object} models.ApiError
+// @Router       /admin/audit [get]
+// @Security Bearer
+func (h *AuditHandlers) FindAll(c *gin.Context) {
+	actorUserId, ok := intQuery(c, "actorUserId")
+	if !ok {
+		return
+	}
+
+	entityId, ok := intQuery(c, "entityId")
+	if !ok {
+		return
+	}
+
+	filters := models.AuditFilters{
+		ActorUserId: actorUserId,
+		Action:      c.Query("action"),
+		EntityType:  c.Query("entityType"),
+		EntityId:    entityId,
+		Limit:       auditDefaultLimit,
+	}
+
+	if from := c.Query("from"); from != "" {
+		t, err := time.Parse(time.RFC3339, from)
+		if err != nil {
+			respondValidationError(c, models.NewApiErrorDetail("from", models.DetailCodeInvalid))
+			return
+		}
+		filters.From = &t
+	}
+
+	if to := c.Query("to"); to != "" {
+		t, err := time.Parse(time.RFC3339, to)
+		if err != nil {
+			respondValidationError(c, models.NewApiErrorDetail("to", models.DetailCodeInvalid))
+			return
+		}
+		filters.To = &t
+	}
+
+	if limitStr := c.Query("limit"); limitStr != "" {
+		limit, err := strconv.Atoi(limitStr)
+		if err != nil || limit <= 0 || limit > auditMaxLimit {
+			respondValidationError(c, models.NewApiErrorDetail("limit", models.DetailCodeInvalid))
+			return
+		}
+		filters.Limit = limit
+	}
+
+	if offsetStr := c.Query("offset"); offsetStr != "" {
+		offset, err := strconv.Atoi(offsetStr)
+		if err != nil || offset < 0 {
+			respondValidationError(c, models.NewApiErrorDetail("offset", models.DetailCodeInvalid))
+			return
+		}
+		filters.Offset = offset
+	}
+
+	events, err := h.auditRepo.FindAll(c, filters)
+	if err != nil {
+		respondInternalError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, events)
+}
+
+// recordAudit пишет событие в журнал аудита. Для update сохраняются только изменившиеся поля.
+// Ошибка записи не ломает запрос — изменение уже применено, поэтому она только логируется.
+func recordAudit(c *gin.Context, auditRepo *repositories.AuditRepository, action string, entityType string, entityId int, before any, after any) {
+	l := logger.GetLogger()
+
+	beforeJson, afterJson, err := models.AuditDiff(before, after)
+	if err != nil {
+		l.Error("Could not build audit diff", zap.String("err", err.Error()))
+	}
+
+	event := models.AuditEvent{
+		Action:     action,
+		EntityType: entityType,
+		EntityId:   entityId,
+		Before:     beforeJson,
+		After:      afterJson,
+		Ip:         c.ClientIP(),
+	}
+
+	if userId, exists := c.Get("userId"); exists {
+		actorUserId := userId.(int)
+		event.ActorUserId = &actorUserId
+	}
+
+	if principal, exists := c.Get(models.ServicePrincipalKey); exists {
+		actorApiKeyId := principal.(models.ServicePrincipal).ApiKeyId
+		event.ActorApiKeyId = &actorApiKeyId
+	}
+
+	err = auditRepo.Record(c, event)
+	if err != nil {
+		l.Error("Could not record audit event",
+			zap.String("action", action),
+			zap.String("entity_type", entityType),
+			zap.Int("entity_id", entityId))
+	}
+}
@@ -1,24 +1,30 @@
 package handlers
 
 import (
+	"errors"
 	"goozinshe/config"
 	"goozinshe/models"
 	"goozinshe/repositories"
+	"goozinshe/tokens"
 	"net/http"
 	"strconv"
 	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
 	"golang.org/x/crypto/bcrypt"
 )
 
 type AuthHandlers struct {
-	usersRepo *repositories.UsersRepository
+	usersRepo    *repositories.UsersRepository
+	sessionsRepo *repositories.SessionsRepository
+	keyManager   *tokens.KeyManager
 }
 
-func NewAuthHandlers(usersRepo *repositories.UsersRepository) *AuthHandlers {
-	return &AuthHandlers{usersRepo: usersRepo}
+func NewAuthHandlers(usersRepo *repositories.UsersRepository, sessionsRepo *repositories.SessionsRepository, keyManager *tokens.KeyManager) *AuthHandlers {
+	return &AuthHandlers{usersRepo: usersRepo, sessionsRepo: sessionsRepo, keyManager: keyManager}
 }
 
 type signInRequest struct {
@@ -29,37 +35,66 @@ type signInRequest struct {
 func (h *AuthHandlers) SignIn(c *gin.Context) {
 	var request signInRequest
 	if err := c.BindJSON(&request); err != nil {
-		c.JSON(http.StatusBadRequest, models.NewApiError("Invalid request payload"))
+		respondError(c, http.StatusBadRequest, models.ErrCodeInvalidPayload)
 		return
 	}
 
 	user, err := h.usersRepo.FindByEmail(c, request.Email)
+	if errors.Is(err, pgx.ErrNoRows) {
+		// не сообщаем, что такого email нет
+		respondError(c, http.StatusUnauthorized, models.ErrCodeInvalidCredentials)
+		return
+	}
 	if err != nil {
-		c.JSON(http.StatusBadRequest, models.NewApiError(err.Error()))
+		respondInternalError(c, err)
 		return
 	}
 
 	err = bcrypt.CompareHashAndPassword([]byte(user.PasswordHash), []byte(request.Password))
 	if err != nil {
-		c.JSON(http.StatusUnauthorized, models.NewApiError("Invalid credials"))
+		respondError(c, http.StatusUnauthorized, models.ErrCodeInvalidCredentials)
+		return
+	}
+
+	session := models.Session{
+		Id:        uuid.NewString(),
+		UserId:    user.Id,
+		UserAgent: c.Request.UserAgent(),
+		Ip:        c.ClientIP(),
+		ExpiresAt: time.Now().Add(config.Config.JwtExpiresIn),
+	}
+	err = h.sessionsRepo.Create(c, session)
+	if err != nil {
+		respondInternalError(c, err)
 		return
 	}
 
 	claims := jwt.RegisteredClaims{
+		ID:        session.Id,
 		Subject:   strconv.Itoa(user.Id),
-		ExpiresAt: jwt.NewNumericDate(time.Now().Add(config.Config.JwtExpiresIn)),
+		ExpiresAt: jwt.NewNumericDate(session.ExpiresAt),
 	}
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-	tokenString, err := token.SignedString([]byte(config.Config.JwtSecretKey))
+	tokenString, err := h.keyManager.Sign(claims)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, models.NewApiError("could not generate jwt token"))
+		respondInternalError(c, err)
 		return
 	}
 
 	c.JSON(http.StatusOK, gin.H{"token": tokenString})
 }
 
+// SignOut завершает сессию токена; у API ключа сессии нет — 403
 func (h *AuthHandlers) SignOut(c *gin.Context) {
+	if _, ok := currentUserId(c); !ok {
+		return
+	}
+
+	err := h.sessionsRepo.Terminate(c, c.GetString("sessionId"))
+	if err != nil {
+		respondInternalError(c, err)
+		return
+	}
+
 	c.Status(http.StatusOK)
 }
 
@@ -67,7 +102,7 @@ func (h *AuthHandlers) GetUserInfo(c *gin.Context) {
 	userId := c.GetInt("userId")
 	user, err := h.usersRepo.FindById(c, userId)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, models.NewApiError(err.Error()))
+		respondInternalError(c, err)
 		return
 	}
 
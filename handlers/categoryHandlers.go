@@ -15,21 +15,28 @@ import (
 
 type CategoryHandlers struct {
 	categoryRepo *repositories.CategoryRepository
+	auditRepo    *repositories.AuditRepository
+
+	translationsRepo *repositories.TranslationsRepository
+	validator        *RequestValidator
 }
 
 type createCategoryRequest struct {
-	Title  string                `form:"title"`
-	Poster *multipart.FileHeader `form:"poster"`
+	Title  string                `form:"title" validate:"required,max=255"`
+	Poster *multipart.FileHeader `form:"poster" validate:"required"`
 }
 
 type updateCategoryRequest struct {
-	Title  string                `form:"title"`
+	Title  string                `form:"title" validate:"required,max=255"`
 	Poster *multipart.FileHeader `form:"poster"`
 }
 
-func NewCategoryHandlers(categoryRepo *repositories.CategoryRepository) *CategoryHandlers {
+func NewCategoryHandlers(categoryRepo *repositories.CategoryRepository, auditRepo *repositories.AuditRepository, translationsRepo *repositories.TranslationsRepository, validator *RequestValidator) *CategoryHandlers {
 	return &CategoryHandlers{
-		categoryRepo: categoryRepo,
+		categoryRepo:     categoryRepo,
+		auditRepo:        auditRepo,
+		translationsRepo: translationsRepo,
+		validator:        validator,
 	}
 }
 
@@ -42,23 +49,24 @@ func NewCategoryHandlers(categoryRepo *repositories.CategoryRepository) *Categor
 // @Success      200  {object} object{id=int}  "OK"
 // @Failure   	 400  {object} models.ApiError "Invalid request category"
 // @Failure   	 500  {object} models.ApiError
+// @Failure      404  {object}  models.ApiError "Not found"
+// @Failure   	 422  {object} models.ApiError "Validation failed"
 // @Router       /categories [post]
 func (h *CategoryHandlers) Create(c *gin.Context) {
 	var request updateCategoryRequest
 	err := c.Bind(&request)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, "Invalid request category")
+		respondError(c, http.StatusBadRequest, models.ErrCodeInvalidPayload)
 		return
 	}
 
-	if request.Poster == nil {
-		c.JSON(http.StatusBadRequest, "Poster file is required")
+	if !h.validator.Validate(c, &request) {
 		return
 	}
 
 	filename, err := h.saveCategoryPoster(c, request.Poster)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, models.NewApiError(err.Error()))
+		respondInternalError(c, err)
 		return
 	}
 
@@ -69,10 +77,13 @@ func (h *CategoryHandlers) Create(c *gin.Context) {
 
 	id, err := h.categoryRepo.Create(c, category)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, models.NewApiError(err.Error()))
+		respondFindError(c, err)
 		return
 	}
 
+	category.Id = id
+	recordAudit(c, h.auditRepo, models.AuditActionCreate, models.AuditEntityCategory, id, nil, category)
+
 	c.JSON(http.StatusOK, gin.H{
 		"id": id,
 	})
@@ -86,20 +97,33 @@ func (h *CategoryHandlers) Create(c *gin.Context) {
 // @Param        id path int true "Category id"
 // @Success      200  {object}  models.Category "Ok"
 // @Failure      400  {object}  models.ApiError "Invalid Movie Id"
+// @Param        lang query string false "kk, ru or en"
+// @Param        Accept-Language header string false "Preferred locales"
+// @Failure      404  {object}  models.ApiError "Not found"
+// @Failure      500  {object}  models.ApiError
 // @Router       /categories/{id} [get]
 func (h *CategoryHandlers) FindById(c *gin.Context) {
 	idStr := c.Param("id")
 	id, err := strconv.Atoi(idStr)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, models.NewApiError("Invalid  categoryId"))
+		respondError(c, http.StatusBadRequest, models.ErrCodeInvalidId)
 		return
 	}
 
 	category, err := h.categoryRepo.FindById(c, id)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, models.NewApiError(err.Error()))
+		respondFindError(c, err)
 		return
 	}
+
+	loc := newLocalizer(c, h.translationsRepo)
+	loc.addCategory(&category)
+	if err := loc.apply(c); err != nil {
+		respondInternalError(c, err)
+		return
+	}
+
+	setETag(c, category.Version)
 	c.JSON(http.StatusOK, category)
 }
 
@@ -116,13 +140,24 @@ func (h *CategoryHandlers) saveCategoryPoster(c *gin.Context, poster *multipart.
 // @Tags         categories
 // @Accept       json
 // @Produce      json
-// @Success      200  {object}  []models.Movie "List of movies"
+// @Param        lang query string false "kk, ru or en"
+// @Param        Accept-Language header string false "Preferred locales"
+// @Success      200  {object}  []models.Category "List of categories"
 // @Failure      500  {object}  models.ApiError "Internal Server Error"
 // @Router       /categories [get]
 func (h *CategoryHandlers) FindAll(c *gin.Context) {
 	categories, err := h.categoryRepo.FindAll(c)
 	if err != nil {
-		c.Status(http.StatusInternalServerError)
+		respondInternalError(c, err)
+		return
+	}
+
+	loc := newLocalizer(c, h.translationsRepo)
+	for i := range categories {
+		loc.addCategory(&categories[i])
+	}
+	if err := loc.apply(c); err != nil {
+		respondInternalError(c, err)
 		return
 	}
 
@@ -138,40 +173,76 @@ func (h *CategoryHandlers) FindAll(c *gin.Context) {
 // @Success      200  {object} object{id=int}  "OK"
 // @Failure   	 400  {object} models.ApiError "Invalid Category Id"
 // @Failure   	 500  {object} models.ApiError
+// @Param        If-Match header string true "ETag of the current version"
+// @Failure      412  {object}  models.ApiError "Resource was modified"
+// @Failure      428  {object}  models.ApiError "If-Match header required"
+// @Failure      404  {object}  models.ApiError "Not found"
+// @Failure   	 422  {object} models.ApiError "Validation failed"
 // @Router       /categories/{id} [put]
 func (h *CategoryHandlers) Update(c *gin.Context) {
 	idStr := c.Param("id")
 	id, err := strconv.Atoi(idStr)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, models.NewApiError("Invalid Genre Id"))
+		respondError(c, http.StatusBadRequest, models.ErrCodeInvalidId)
 		return
 	}
 
-	_, err = h.categoryRepo.FindById(c, id)
+	before, err := h.categoryRepo.FindById(c, id)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, models.NewApiError(err.Error()))
+		respondFindError(c, err)
+		return
+	}
+
+	version, ok := ifMatchVersion(c)
+	if !ok {
+		return
+	}
+
+	if !checkIfMatch(c, version, before.Version) {
 		return
 	}
 
 	var request updateCategoryRequest
 	err = c.Bind(&request)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, "Invalid request payload")
+		respondError(c, http.StatusBadRequest, models.ErrCodeInvalidPayload)
 		return
 	}
 
-	filename, err := h.saveCategoryPoster(c, request.Poster)
-	if err != nil {
-		c.JSON(http.StatusInternalServerError, models.NewApiError(err.Error()))
+	if !h.validator.Validate(c, &request) {
 		return
 	}
 
+	// без нового файла постер остаётся прежним
+	filename := before.PosterUrl
+	if request.Poster != nil {
+		filename, err = h.saveCategoryPoster(c, request.Poster)
+		if err != nil {
+			respondInternalError(c, err)
+			return
+		}
+	}
+
 	category := models.Category{
+		Id:        id,
 		Title:     request.Title,
 		PosterUrl: filename,
+		Version:   version,
+	}
+
+	err = h.categoryRepo.Update(c, id, category)
+	if isVersionConflict(err) {
+		respondVersionConflict(c)
+		return
+	}
+	if err != nil {
+		respondInternalError(c, err)
+		return
 	}
 
-	h.categoryRepo.Update(c, id, category)
+	recordAudit(c, h.auditRepo, models.AuditActionUpdate, models.AuditEntityCategory, id, before, category)
+
+	setETag(c, version+1)
 
 	c.Status(http.StatusOK)
 }
@@ -184,26 +255,187 @@ func (h *CategoryHandlers) Update(c *gin.Context) {
 // @Param        id path int true "Category id"
 // @Success      200  {object}  models.Category "Ok"
 // @Failure      400  {object}  models.ApiError "Invalid category Id"
+// @Param        If-Match header string true "ETag of the current version"
+// @Failure      412  {object}  models.ApiError "Resource was modified"
+// @Failure      428  {object}  models.ApiError "If-Match header required"
+// @Failure      404  {object}  models.ApiError "Not found"
+// @Failure      500  {object}  models.ApiError
 // @Router       /categories/{id} [delete]
 func (h *CategoryHandlers) Delete(c *gin.Context) {
 	idStr := c.Param("id")
 	id, err := strconv.Atoi(idStr)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, models.NewApiError("Invalid category Id"))
+		respondError(c, http.StatusBadRequest, models.ErrCodeInvalidId)
 		return
 	}
 
-	_, err = h.categoryRepo.FindById(c, id)
+	before, err := h.categoryRepo.FindById(c, id)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, models.NewApiError(err.Error()))
+		respondFindError(c, err)
+		return
+	}
+
+	version, ok := ifMatchVersion(c)
+	if !ok {
+		return
+	}
+
+	if !checkIfMatch(c, version, before.Version) {
 		return
 	}
 
-	err = h.categoryRepo.Delete(c, id)
+	err = h.categoryRepo.Delete(c, id, version)
+	if isVersionConflict(err) {
+		respondVersionConflict(c)
+		return
+	}
 	if err != nil {
-		c.JSON(http.StatusBadRequest, models.NewApiError(err.Error()))
+		respondFindError(c, err)
 		return
 	}
 
+	recordAudit(c, h.auditRepo, models.AuditActionDelete, models.AuditEntityCategory, id, before, nil)
+
 	c.Status(http.StatusOK)
 }
+
+type categoryPatchDocument struct {
+	Title string `json:"title" validate:"required,max=255"`
+}
+
+// Patch godoc
+// @Summary      Partially update category
+// @Description  JSON Merge Patch (RFC 7396). Постер обновляется через PUT /categories/{id}/poster
+// @Tags         categories
+// @Accept       application/merge-patch+json
+// @Produce      json
+// @Param        id path int true "Category id"
+// @Param        request body handlers.categoryPatchDocument true "Merge patch"
+// @Param        If-Match header string true "ETag of the current version"
+// @Success      200  {object}  models.Category "OK"
+// @Failure      400  {object}  models.ApiError "Invalid data"
+// @Failure      412  {object}  models.ApiError "Resource was modified"
+// @Failure      415  {object}  models.ApiError "Unsupported Content-Type"
+// @Failure      428  {object}  models.ApiError "If-Match header required"
+// @Failure      500  {object}  models.ApiError
+// @Failure      404  {object}  models.ApiError "Not found"
+// @Failure      422  {object}  models.ApiError "Validation failed"
+// @Router       /categories/{id} [patch]
+// @Security Bearer
+func (h *CategoryHandlers) Patch(c *gin.Context) {
+	idStr := c.Param("id")
+	id, err := strconv.Atoi(idStr)
+	if err != nil {
+		respondError(c, http.StatusBadRequest, models.ErrCodeInvalidId)
+		return
+	}
+
+	before, err := h.categoryRepo.FindById(c, id)
+	if err != nil {
+		respondFindError(c, err)
+		return
+	}
+
+	version, ok := ifMatchVersion(c)
+	if !ok {
+		return
+	}
+
+	if !checkIfMatch(c, version, before.Version) {
+		return
+	}
+
+	document := categoryPatchDocument{Title: before.Title}
+	err = applyMergePatch(c, &document)
+	if err != nil {
+		respondPatchError(c, err)
+		return
+	}
+
+	if !h.validator.Validate(c, &document) {
+		return
+	}
+
+	category := before
+	category.Title = document.Title
+	category.Version = version
+
+	h.saveCategory(c, id, before, category)
+}
+
+// UpdatePoster godoc
+// @Summary      Upload category poster
+// @Tags         categories
+// @Accept       multipart/form-data
+// @Produce      json
+// @Param        id path int true "Category id"
+// @Param        poster formData file true "Poster image"
+// @Param        If-Match header string true "ETag of the current version"
+// @Success      200  {object}  models.Category "OK"
+// @Failure      400  {object}  models.ApiError "Invalid data"
+// @Failure      412  {object}  models.ApiError "Resource was modified"
+// @Failure      428  {object}  models.ApiError "If-Match header required"
+// @Failure      500  {object}  models.ApiError
+// @Failure      404  {object}  models.ApiError "Not found"
+// @Router       /categories/{id}/poster [put]
+// @Security Bearer
+func (h *CategoryHandlers) UpdatePoster(c *gin.Context) {
+	idStr := c.Param("id")
+	id, err := strconv.Atoi(idStr)
+	if err != nil {
+		respondError(c, http.StatusBadRequest, models.ErrCodeInvalidId)
+		return
+	}
+
+	before, err := h.categoryRepo.FindById(c, id)
+	if err != nil {
+		respondFindError(c, err)
+		return
+	}
+
+	version, ok := ifMatchVersion(c)
+	if !ok {
+		return
+	}
+
+	if !checkIfMatch(c, version, before.Version) {
+		return
+	}
+
+	poster, err := c.FormFile("poster")
+	if err != nil {
+		respondError(c, http.StatusBadRequest, models.ErrCodeFileRequired, models.NewApiErrorDetail("poster", models.DetailCodeRequired))
+		return
+	}
+
+	filename, err := h.saveCategoryPoster(c, poster)
+	if err != nil {
+		respondInternalError(c, err)
+		return
+	}
+
+	category := before
+	category.PosterUrl = filename
+	category.Version = version
+
+	h.saveCategory(c, id, before, category)
+}
+
+// saveCategory обновляет запись с проверкой версии и отвечает её новым состоянием
+func (h *CategoryHandlers) saveCategory(c *gin.Context, id int, before models.Category, category models.Category) {
+	err := h.categoryRepo.Update(c, id, category)
+	if isVersionConflict(err) {
+		respondVersionConflict(c)
+		return
+	}
+	if err != nil {
+		respondInternalError(c, err)
+		return
+	}
+
+	recordAudit(c, h.auditRepo, models.AuditActionUpdate, models.AuditEntityCategory, id, before, category)
+
+	category.Version++
+	setETag(c, category.Version)
+	c.JSON(http.StatusOK, category)
+}
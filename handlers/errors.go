@@ -0,0 +1,44 @@
+package handlers
+
+import (
+	"errors"
+	"goozinshe/logger"
+	"goozinshe/middlewares"
+	"goozinshe/models"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5"
+	"go.uber.org/zap"
+)
+
+// respondError отвечает ошибкой с кодом, сообщением на языке запроса и id запроса
+func respondError(c *gin.Context, status int, code string, details ...models.ApiErrorDetail) {
+	c.JSON(status, middlewares.NewApiError(c, code, details...))
+}
+
+// respondValidationError — 400 с ошибками отдельных полей
+func respondValidationError(c *gin.Context, details ...models.ApiErrorDetail) {
+	respondError(c, http.StatusBadRequest, models.ErrCodeValidation, details...)
+}
+
+// respondInternalError пишет причину в лог, а клиенту отдаёт только код и id запроса
+func respondInternalError(c *gin.Context, err error) {
+	l := logger.GetLogger()
+	l.Error("Request failed",
+		zap.String("requestId", middlewares.RequestId(c)),
+		zap.String("path", c.FullPath()),
+		zap.String("err", err.Error()))
+
+	respondError(c, http.StatusInternalServerError, models.ErrCodeInternal)
+}
+
+// respondFindError — 404, если записи нет, иначе 500
+func respondFindError(c *gin.Context, err error) {
+	if errors.Is(err, pgx.ErrNoRows) {
+		respondError(c, http.StatusNotFound, models.ErrCodeNotFound)
+		return
+	}
+
+	respondInternalError(c, err)
+}
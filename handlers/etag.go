@@ -0,0 +1,53 @@
+package handlers
+
+import (
+	"errors"
+	"goozinshe/models"
+	"goozinshe/repositories"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// setETag отдаёт версию записи клиенту, её нужно вернуть в If-Match при изменении
+func setETag(c *gin.Context, version int) {
+	c.Header("ETag", strconv.Quote(strconv.Itoa(version)))
+}
+
+// ifMatchVersion читает ожидаемую версию из If-Match.
+// Если заголовка нет или он некорректный, сразу отвечает клиенту и возвращает false
+func ifMatchVersion(c *gin.Context) (int, bool) {
+	header := c.GetHeader("If-Match")
+	if header == "" {
+		respondError(c, http.StatusPreconditionRequired, models.ErrCodeIfMatchRequired)
+		return 0, false
+	}
+
+	version, err := strconv.Atoi(strings.Trim(strings.TrimPrefix(header, "W/"), `"`))
+	if err != nil {
+		respondError(c, http.StatusBadRequest, models.ErrCodeInvalidIfMatch)
+		return 0, false
+	}
+
+	return version, true
+}
+
+// checkIfMatch сравнивает версию из If-Match с текущей версией записи
+func checkIfMatch(c *gin.Context, expected int, current int) bool {
+	if expected != current {
+		respondVersionConflict(c)
+		return false
+	}
+
+	return true
+}
+
+func respondVersionConflict(c *gin.Context) {
+	respondError(c, http.StatusPreconditionFailed, models.ErrCodeVersionConflict)
+}
+
+func isVersionConflict(err error) bool {
+	return errors.Is(err, repositories.ErrVersionConflict)
+}
@@ -0,0 +1,371 @@
+package handlers
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"goozinshe/logger"
+	"goozinshe/models"
+	"goozinshe/repositories"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+const (
+	exportFormatJson = "json"
+	exportFormatCsv  = "csv"
+	exportFormatXlsx = "xlsx"
+
+	exportTableMovies     = "movies"
+	exportTableEpisodes   = "episodes"
+	exportTableGenres     = "genres"
+	exportTableCategories = "categories"
+	exportTableAges       = "ages"
+)
+
+var exportContentTypes = map[string]string{
+	exportFormatJson: "application/json; charset=utf-8",
+	exportFormatCsv:  "text/csv; charset=utf-8",
+	exportFormatXlsx: "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet",
+}
+
+// exportTables — порядок таблиц в выгрузке, для CSV выбирается одна через table
+var exportTables = []string{exportTableMovies, exportTableEpisodes, exportTableGenres, exportTableCategories, exportTableAges}
+
+var exportHeaders = map[string][]any{
+	exportTableMovies:     {"id", "title", "description", "releaseYear", "director", "rating", "status", "publishAt", "trailerUrl", "posterUrl", "genres", "categories", "ages"},
+	exportTableEpisodes:   {"movieId", "id", "series", "title", "description", "releaseYear", "director", "rating", "trailerUrl"},
+	exportTableGenres:     {"id", "title", "posterUrl"},
+	exportTableCategories: {"id", "title", "posterUrl"},
+	exportTableAges:       {"id", "age", "posterUrl"},
+}
+
+type ExportHandlers struct {
+	moviesAdminRepo *repositories.MoviesAdminRepository
+	genresRepo      *repositories.GenresRepository
+	categoryRepo    *repositories.CategoryRepository
+	ageRepo         *repositories.AgeRepository
+}
+
+func NewExportHandlers(
+	moviesAdminRepo *repositories.MoviesAdminRepository,
+	genresRepo *repositories.GenresRepository,
+	categoryRepo *repositories.CategoryRepository,
+	ageRepo *repositories.AgeRepository) *ExportHandlers {
+	return &ExportHandlers{
+		moviesAdminRepo: moviesAdminRepo,
+		genresRepo:      genresRepo,
+		categoryRepo:    categoryRepo,
+		ageRepo:         ageRepo,
+	}
+}
+
+// Export godoc
+// @Tags         export
+// @Summary      Export catalog
+// @Description  Потоковая выгрузка фильмов с жанрами, категориями, возрастами и сериями, а также справочников.
+// @Description  JSON и XLSX содержат все таблицы, CSV — одну таблицу из параметра table.
+// @Produce      json
+// @Produce      text/csv
+// @Produce      application/vnd.openxmlformats-officedocument.spreadsheetml.sheet
+// @Param        format query string false "json (default), csv or xlsx"
+// @Param        table query string false "CSV only: movies (default), episodes, genres, categories or ages"
+// @Param        search query string false "Search by title"
+// @Param        genreids query string false "Genre id"
+// @Param        personId query int false "Person id"
+// @Param        iswatched query bool false "Watched flag"
+// @Param        status query string false "draft, in_review, published or archived"
+// @Param        sort query string false "Movie column to sort by"
+// @Success      200  {file} file "OK"
+// @Failure   	 400  {object} models.ApiError "Invalid format, table or personId"
+// @Failure   	 500  {object} models.ApiError
+// @Router       /admin/export [get]
+// @Security Bearer
+func (h *ExportHandlers) Export(c *gin.Context) {
+	format := c.DefaultQuery("format", exportFormatJson)
+	contentType, ok := exportContentTypes[format]
+	if !ok {
+		respondValidationError(c, models.NewApiErrorDetail("format", models.DetailCodeUnsupported))
+		return
+	}
+
+	table := c.DefaultQuery("table", exportTableMovies)
+	if _, ok := exportHeaders[table]; !ok {
+		respondValidationError(c, models.NewApiErrorDetail("table", models.DetailCodeUnsupported))
+		return
+	}
+
+	personId, ok := intQuery(c, "personId")
+	if !ok {
+		return
+	}
+
+	filters := models.MovieFilters{
+		SearchTerm: c.Query("search"),
+		IsWatched:  c.Query("iswatched"),
+		GenreId:    c.Query("genreids"),
+		PersonId:   personId,
+		Status:     c.Query("status"),
+		Sort:       c.Query("sort"),
+	}
+
+	// справочники небольшие, их читаем заранее, чтобы ошибка БД успела вернуться обычным ответом
+	dictionaries, err := h.loadDictionaries(c)
+	if err != nil {
+		respondInternalError(c, err)
+		return
+	}
+
+	filename := fmt.Sprintf("catalog-%s", time.Now().Format("20060102"))
+	if format == exportFormatCsv {
+		filename = fmt.Sprintf("%s-%s", filename, table)
+	}
+	c.Header("Content-Type", contentType)
+	c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="%s.%s"`, filename, format))
+
+	switch format {
+	case exportFormatJson:
+		err = h.exportJson(c, filters, dictionaries)
+	case exportFormatCsv:
+		err = h.exportCsv(c, filters, dictionaries, table)
+	case exportFormatXlsx:
+		err = h.exportXlsx(c, filters, dictionaries)
+	}
+
+	if err != nil {
+		// после начала записи статус уже не поменять — остаётся только оборвать ответ
+		if !c.Writer.Written() {
+			c.Writer.Header().Del("Content-Type")
+			c.Writer.Header().Del("Content-Disposition")
+			respondInternalError(c, err)
+			return
+		}
+		l := logger.GetLogger()
+		l.Error("Catalog export interrupted", zap.String("format", format), zap.String("err", err.Error()))
+	}
+}
+
+type exportDictionaries struct {
+	Genres     []models.Genre    `json:"genres"`
+	Categories []models.Category `json:"categories"`
+	Ages       []models.Age      `json:"ages"`
+}
+
+func (h *ExportHandlers) loadDictionaries(c *gin.Context) (exportDictionaries, error) {
+	var dictionaries exportDictionaries
+	var err error
+
+	dictionaries.Genres, err = h.genresRepo.FindAll(c)
+	if err != nil {
+		return dictionaries, err
+	}
+
+	dictionaries.Categories, err = h.categoryRepo.FindAll(c)
+	if err != nil {
+		return dictionaries, err
+	}
+
+	dictionaries.Ages, err = h.ageRepo.FindAll(c)
+	return dictionaries, err
+}
+
+// exportJson пишет {"genres": [...], "categories": [...], "ages": [...], "movies": [...]}, фильмы — по одному
+func (h *ExportHandlers) exportJson(c *gin.Context, filters models.MovieFilters, dictionaries exportDictionaries) error {
+	head, err := json.Marshal(dictionaries)
+	if err != nil {
+		return err
+	}
+
+	// ответ открываем только при первом фильме, чтобы ошибка запроса успела стать 500
+	opening := append(head[:len(head)-1], `,"movies":[`...)
+	opened := false
+	next := func() error {
+		if opened {
+			_, err := c.Writer.WriteString(",")
+			return err
+		}
+		opened = true
+		c.Status(http.StatusOK)
+		_, err := c.Writer.Write(opening)
+		return err
+	}
+
+	err = h.moviesAdminRepo.Stream(c, filters, func(movie models.Movie) error {
+		data, err := json.Marshal(movie)
+		if err != nil {
+			return err
+		}
+
+		err = next()
+		if err != nil {
+			return err
+		}
+
+		_, err = c.Writer.Write(data)
+		return err
+	})
+	if err != nil {
+		return err
+	}
+
+	if !opened {
+		c.Status(http.StatusOK)
+		_, err = c.Writer.Write(opening)
+		if err != nil {
+			return err
+		}
+	}
+
+	_, err = c.Writer.WriteString("]}")
+	return err
+}
+
+func (h *ExportHandlers) exportCsv(c *gin.Context, filters models.MovieFilters, dictionaries exportDictionaries, table string) error {
+	c.Status(http.StatusOK)
+	writer := csv.NewWriter(c.Writer)
+
+	write := func(values []any) error {
+		record := make([]string, 0, len(values))
+		for _, value := range values {
+			record = append(record, exportCell(value))
+		}
+		return writer.Write(record)
+	}
+
+	err := h.writeTable(c, filters, dictionaries, table, write)
+	if err != nil {
+		return err
+	}
+
+	writer.Flush()
+	return writer.Error()
+}
+
+func (h *ExportHandlers) exportXlsx(c *gin.Context, filters models.MovieFilters, dictionaries exportDictionaries) error {
+	c.Status(http.StatusOK)
+	workbook := newXlsxWriter(c.Writer)
+
+	for _, table := range exportTables {
+		err := workbook.StartSheet(table)
+		if err != nil {
+			return err
+		}
+
+		err = h.writeTable(c, filters, dictionaries, table, workbook.WriteRow)
+		if err != nil {
+			return err
+		}
+	}
+
+	return workbook.Close()
+}
+
+// writeTable пишет заголовок и строки одной таблицы; фильмы и серии читаются потоком из репозитория
+func (h *ExportHandlers) writeTable(c *gin.Context, filters models.MovieFilters, dictionaries exportDictionaries, table string, write func([]any) error) error {
+	err := write(exportHeaders[table])
+	if err != nil {
+		return err
+	}
+
+	switch table {
+	case exportTableMovies:
+		return h.moviesAdminRepo.Stream(c, filters, func(movie models.Movie) error {
+			return write(exportMovieRow(movie))
+		})
+	case exportTableEpisodes:
+		return h.moviesAdminRepo.Stream(c, filters, func(movie models.Movie) error {
+			for _, episode := range movie.AllSeries {
+				err := write([]any{movie.Id, episode.Id, episode.Series, episode.Title, episode.Description, episode.ReleaseYear, episode.Director, episode.Rating, episode.TrailerUrl})
+				if err != nil {
+					return err
+				}
+			}
+			return nil
+		})
+	case exportTableGenres:
+		for _, genre := range dictionaries.Genres {
+			err = write([]any{genre.Id, genre.Title, genre.PosterUrl})
+			if err != nil {
+				return err
+			}
+		}
+	case exportTableCategories:
+		for _, category := range dictionaries.Categories {
+			err = write([]any{category.Id, category.Title, category.PosterUrl})
+			if err != nil {
+				return err
+			}
+		}
+	case exportTableAges:
+		for _, age := range dictionaries.Ages {
+			err = write([]any{age.Id, age.Age, age.PosterUrl})
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// exportMovieRow — связи выгружаются названиями через ";", как их принимает импорт
+func exportMovieRow(movie models.Movie) []any {
+	genres := make([]string, 0, len(movie.Genres))
+	for _, genre := range movie.Genres {
+		genres = append(genres, genre.Title)
+	}
+
+	categories := make([]string, 0, len(movie.Category))
+	for _, category := range movie.Category {
+		categories = append(categories, category.Title)
+	}
+
+	ages := make([]string, 0, len(movie.Ages))
+	for _, age := range movie.Ages {
+		ages = append(ages, age.Age)
+	}
+
+	return []any{
+		movie.Id,
+		movie.Title,
+		movie.Description,
+		movie.ReleaseYear,
+		movie.Director,
+		movie.Rating,
+		movie.Status,
+		movie.PublishAt,
+		movie.TrailerUrl,
+		movie.PosterUrl,
+		strings.Join(genres, ";"),
+		strings.Join(categories, ";"),
+		strings.Join(ages, ";"),
+	}
+}
+
+func exportCell(value any) string {
+	switch v := value.(type) {
+	case string:
+		return v
+	case int:
+		return strconv.Itoa(v)
+	case *string:
+		if v != nil {
+			return *v
+		}
+	case *int:
+		if v != nil {
+			return strconv.Itoa(*v)
+		}
+	case *time.Time:
+		if v != nil {
+			return v.Format(time.RFC3339)
+		}
+	}
+
+	return ""
+}
@@ -0,0 +1,157 @@
+package handlers
+
+import (
+	"goozinshe/models"
+	"goozinshe/repositories"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	favoritesLimit = 20
+	favoritesMax   = 100
+)
+
+type FavoritesHandlers struct {
+	favoritesRepo    *repositories.FavoritesRepository
+	moviesRepo       *repositories.MoviesRepository
+	parentalRepo     *repositories.ParentalControlsRepository
+	translationsRepo *repositories.TranslationsRepository
+}
+
+func NewFavoritesHandlers(
+	favoritesRepo *repositories.FavoritesRepository,
+	moviesRepo *repositories.MoviesRepository,
+	parentalRepo *repositories.ParentalControlsRepository,
+	translationsRepo *repositories.TranslationsRepository) *FavoritesHandlers {
+	return &FavoritesHandlers{
+		favoritesRepo:    favoritesRepo,
+		moviesRepo:       moviesRepo,
+		parentalRepo:     parentalRepo,
+		translationsRepo: translationsRepo,
+	}
+}
+
+// FindAll godoc
+// @Tags         favorites
+// @Summary      List favorites
+// @Description  Избранное текущего пользователя или выбранного профиля, недавно добавленное первым
+// @Produce      json
+// @Param        limit query int false "Page size, 20 by default, up to 100"
+// @Param        offset query int false "Offset"
+// @Param        lang query string false "kk, ru or en"
+// @Param        Accept-Language header string false "Preferred locales"
+// @Param        X-Profile-Id header int false "Viewer profile"
+// @Success      200  {array} models.FavoriteItem "OK"
+// @Failure   	 400  {object} models.ApiError "Invalid limit or offset"
+// @Failure   	 403  {object} models.ApiError "Not available for API keys"
+// @Failure   	 500  {object} models.ApiError
+// @Router       /me/favorites [get]
+// @Security Bearer
+func (h *FavoritesHandlers) FindAll(c *gin.Context) {
+	userId, ok := currentUserId(c)
+	if !ok {
+		return
+	}
+
+	limit, offset, ok := pageParams(c, favoritesLimit, favoritesMax)
+	if !ok {
+		return
+	}
+
+	items, err := h.favoritesRepo.FindByUser(c, userId, currentProfileId(c), limit, offset, ageLimit(c))
+	if err != nil {
+		respondInternalError(c, err)
+		return
+	}
+
+	loc := newLocalizer(c, h.translationsRepo)
+	for i := range items {
+		loc.add(models.AuditEntityMovie, items[i].MovieId, map[string]*string{"title": &items[i].Title})
+	}
+	if err := loc.apply(c); err != nil {
+		respondInternalError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, items)
+}
+
+// Add godoc
+// @Tags         favorites
+// @Summary      Add movie to favorites
+// @Description  Повторное добавление ничего не меняет
+// @Param movieId path int true "Movie id"
+// @Param        X-Profile-Id header int false "Viewer profile"
+// @Success      200  "OK"
+// @Failure   	 400  {object} models.ApiError "Invalid id"
+// @Failure   	 403  {object} models.ApiError "Not available for API keys or restricted by parental controls"
+// @Failure   	 404  {object} models.ApiError "Movie not found"
+// @Failure   	 500  {object} models.ApiError
+// @Router       /me/favorites/{movieId} [put]
+// @Security Bearer
+func (h *FavoritesHandlers) Add(c *gin.Context) {
+	userId, ok := currentUserId(c)
+	if !ok {
+		return
+	}
+
+	movieId, err := strconv.Atoi(c.Param("movieId"))
+	if err != nil {
+		respondError(c, http.StatusBadRequest, models.ErrCodeInvalidId)
+		return
+	}
+
+	if !checkMovieAllowed(c, h.parentalRepo, movieId) {
+		return
+	}
+
+	_, err = h.moviesRepo.FindById(c, movieId)
+	if err != nil {
+		respondFindError(c, err)
+		return
+	}
+
+	err = h.favoritesRepo.Add(c, userId, currentProfileId(c), movieId)
+	if err != nil {
+		respondInternalError(c, err)
+		return
+	}
+
+	c.Status(http.StatusOK)
+}
+
+// Remove godoc
+// @Tags         favorites
+// @Summary      Remove movie from favorites
+// @Description  Удаление фильма, которого нет в избранном, тоже отвечает 200
+// @Param movieId path int true "Movie id"
+// @Param        X-Profile-Id header int false "Viewer profile"
+// @Success      200  "OK"
+// @Failure   	 400  {object} models.ApiError "Invalid id"
+// @Failure   	 403  {object} models.ApiError "Not available for API keys"
+// @Failure   	 500  {object} models.ApiError
+// @Router       /me/favorites/{movieId} [delete]
+// @Security Bearer
+func (h *FavoritesHandlers) Remove(c *gin.Context) {
+	userId, ok := currentUserId(c)
+	if !ok {
+		return
+	}
+
+	movieId, err := strconv.Atoi(c.Param("movieId"))
+	if err != nil {
+		respondError(c, http.StatusBadRequest, models.ErrCodeInvalidId)
+		return
+	}
+
+	err = h.favoritesRepo.Remove(c, userId, currentProfileId(c), movieId)
+	if err != nil {
+		respondInternalError(c, err)
+		return
+	}
+
+	c.Status(http.StatusOK)
+}
@@ -14,22 +14,29 @@ import (
 )
 
 type GenreHandlers struct {
-	repo *repositories.GenresRepository
+	repo      *repositories.GenresRepository
+	auditRepo *repositories.AuditRepository
+
+	translationsRepo *repositories.TranslationsRepository
+	validator        *RequestValidator
 }
 
 type createGenreRequest struct {
-	Title  string                `form:"title"`
-	Poster *multipart.FileHeader `form:"poster"`
+	Title  string                `form:"title" validate:"required,max=255"`
+	Poster *multipart.FileHeader `form:"poster" validate:"required"`
 }
 
 type updateGenreRequest struct {
-	Title  string                `form:"title"`
+	Title  string                `form:"title" validate:"required,max=255"`
 	Poster *multipart.FileHeader `form:"poster"`
 }
 
-func NewGenreHanlers(repo *repositories.GenresRepository) *GenreHandlers {
+func NewGenreHanlers(repo *repositories.GenresRepository, auditRepo *repositories.AuditRepository, translationsRepo *repositories.TranslationsRepository, validator *RequestValidator) *GenreHandlers {
 	return &GenreHandlers{
-		repo: repo,
+		repo:             repo,
+		auditRepo:        auditRepo,
+		translationsRepo: translationsRepo,
+		validator:        validator,
 	}
 }
 
@@ -41,21 +48,33 @@ func NewGenreHanlers(repo *repositories.GenresRepository) *GenreHandlers {
 // @Param        id path int true "Genre id"
 // @Success      200  {object}  models.Genre "Ok"
 // @Failure      400  {object}  models.ApiError "Invalid Movie Id"
+// @Param        lang query string false "kk, ru or en"
+// @Param        Accept-Language header string false "Preferred locales"
+// @Failure      404  {object}  models.ApiError "Not found"
+// @Failure      500  {object}  models.ApiError
 // @Router       /genres/{id} [get]
 func (h *GenreHandlers) FindById(c *gin.Context) {
 	idStr := c.Param("id")
 	id, err := strconv.Atoi(idStr)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, models.NewApiError("Invalid Genre Id"))
+		respondError(c, http.StatusBadRequest, models.ErrCodeInvalidId)
 		return
 	}
 
 	genre, err := h.repo.FindById(c, id)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, models.NewApiError(err.Error()))
+		respondFindError(c, err)
 		return
 	}
 
+	loc := newLocalizer(c, h.translationsRepo)
+	loc.addGenre(&genre)
+	if err := loc.apply(c); err != nil {
+		respondInternalError(c, err)
+		return
+	}
+
+	setETag(c, genre.Version)
 	c.JSON(http.StatusOK, genre)
 }
 
@@ -66,11 +85,22 @@ func (h *GenreHandlers) FindById(c *gin.Context) {
 // @Produce      json
 // @Success      200  {object}  []models.Genre "List of genres"
 // @Failure      500  {object}  models.ApiError "Internal Server Error"
+// @Param        lang query string false "kk, ru or en"
+// @Param        Accept-Language header string false "Preferred locales"
 // @Router       /genres [get]
 func (h *GenreHandlers) FindAll(c *gin.Context) {
 	genres, err := h.repo.FindAll(c)
 	if err != nil {
-		c.Status(http.StatusInternalServerError)
+		respondInternalError(c, err)
+		return
+	}
+
+	loc := newLocalizer(c, h.translationsRepo)
+	for i := range genres {
+		loc.addGenre(&genres[i])
+	}
+	if err := loc.apply(c); err != nil {
+		respondInternalError(c, err)
 		return
 	}
 
@@ -85,24 +115,24 @@ func (h *GenreHandlers) FindAll(c *gin.Context) {
 // @Param request body models.Genre true "Genre model"
 // @Success      200  {object} object{id=int}  "OK"
 // @Failure   	 400  {object} models.ApiError "Invalid request category"
+// @Failure   	 422  {object} models.ApiError "Validation failed"
 // @Failure   	 500  {object} models.ApiError
 // @Router       /genres [post]
 func (h *GenreHandlers) Create(c *gin.Context) {
 	var request createGenreRequest
 	err := c.Bind(&request)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, "Invalid request payload")
+		respondError(c, http.StatusBadRequest, models.ErrCodeInvalidPayload)
 		return
 	}
 
-	if request.Poster == nil {
-		c.JSON(http.StatusBadRequest, "Poster file is required")
+	if !h.validator.Validate(c, &request) {
 		return
 	}
 
 	filename, err := h.saveGenrePoster(c, request.Poster)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, models.NewApiError(err.Error()))
+		respondInternalError(c, err)
 		return
 	}
 
@@ -113,10 +143,13 @@ func (h *GenreHandlers) Create(c *gin.Context) {
 
 	id, err := h.repo.Create(c, genre)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, err)
+		respondInternalError(c, err)
 		return
 	}
 
+	genre.Id = id
+	recordAudit(c, h.auditRepo, models.AuditActionCreate, models.AuditEntityGenre, id, nil, genre)
+
 	c.JSON(http.StatusOK, gin.H{
 		"id": id,
 	})
@@ -139,41 +172,76 @@ func (h *GenreHandlers) saveGenrePoster(c *gin.Context, poster *multipart.FileHe
 // @Success      200  {object} object{id=int}  "OK"
 // @Failure   	 400  {object} models.ApiError "Invalid Genre Id"
 // @Failure   	 500  {object} models.ApiError
+// @Param        If-Match header string true "ETag of the current version"
+// @Failure      412  {object}  models.ApiError "Resource was modified"
+// @Failure      428  {object}  models.ApiError "If-Match header required"
+// @Failure      404  {object}  models.ApiError "Not found"
+// @Failure   	 422  {object} models.ApiError "Validation failed"
 // @Router       /genres/{id} [put]
 func (h *GenreHandlers) Update(c *gin.Context) {
 	idStr := c.Param("id")
 	id, err := strconv.Atoi(idStr)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, models.NewApiError("Invalid Genre Id"))
+		respondError(c, http.StatusBadRequest, models.ErrCodeInvalidId)
 		return
 	}
 
-	_, err = h.repo.FindById(c, id)
+	before, err := h.repo.FindById(c, id)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, models.NewApiError(err.Error()))
+		respondFindError(c, err)
+		return
+	}
+
+	version, ok := ifMatchVersion(c)
+	if !ok {
+		return
+	}
+
+	if !checkIfMatch(c, version, before.Version) {
 		return
 	}
 
 	var request updateGenreRequest
 	err = c.Bind(&request)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, "Invalid request payload")
+		respondError(c, http.StatusBadRequest, models.ErrCodeInvalidPayload)
 		return
 	}
 
-	filename, err := h.saveGenrePoster(c, request.Poster)
-	if err != nil {
-		c.JSON(http.StatusInternalServerError, models.NewApiError(err.Error()))
+	if !h.validator.Validate(c, &request) {
 		return
 	}
 
+	// без нового файла постер остаётся прежним
+	filename := before.PosterUrl
+	if request.Poster != nil {
+		filename, err = h.saveGenrePoster(c, request.Poster)
+		if err != nil {
+			respondInternalError(c, err)
+			return
+		}
+	}
+
 	genre := models.Genre{
+		Id:        id,
 		Title:     request.Title,
 		PosterUrl: filename,
+		Version:   version,
 	}
 
-	h.repo.Update(c, id, genre)
-	
+	err = h.repo.Update(c, id, genre)
+	if isVersionConflict(err) {
+		respondVersionConflict(c)
+		return
+	}
+	if err != nil {
+		respondInternalError(c, err)
+		return
+	}
+
+	recordAudit(c, h.auditRepo, models.AuditActionUpdate, models.AuditEntityGenre, id, before, genre)
+
+	setETag(c, version+1)
 
 	c.Status(http.StatusOK)
 }
@@ -186,26 +254,187 @@ func (h *GenreHandlers) Update(c *gin.Context) {
 // @Param        id path int true "Genre id"
 // @Success      200  {object}  models.Genre "Ok"
 // @Failure      400  {object}  models.ApiError "Invalid genre Id"
+// @Param        If-Match header string true "ETag of the current version"
+// @Failure      412  {object}  models.ApiError "Resource was modified"
+// @Failure      428  {object}  models.ApiError "If-Match header required"
+// @Failure      404  {object}  models.ApiError "Not found"
+// @Failure      500  {object}  models.ApiError
 // @Router       /genres/{id} [delete]
 func (h *GenreHandlers) Delete(c *gin.Context) {
 	idStr := c.Param("id")
 	id, err := strconv.Atoi(idStr)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, models.NewApiError("Invalid Genre Id"))
+		respondError(c, http.StatusBadRequest, models.ErrCodeInvalidId)
 		return
 	}
 
-	_, err = h.repo.FindById(c, id)
+	before, err := h.repo.FindById(c, id)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, models.NewApiError(err.Error()))
+		respondFindError(c, err)
 		return
 	}
 
-	err = h.repo.Delete(c, id)
+	version, ok := ifMatchVersion(c)
+	if !ok {
+		return
+	}
+
+	if !checkIfMatch(c, version, before.Version) {
+		return
+	}
+
+	err = h.repo.Delete(c, id, version)
+	if isVersionConflict(err) {
+		respondVersionConflict(c)
+		return
+	}
 	if err != nil {
-		c.JSON(http.StatusBadRequest, models.NewApiError(err.Error()))
+		respondFindError(c, err)
 		return
 	}
 
+	recordAudit(c, h.auditRepo, models.AuditActionDelete, models.AuditEntityGenre, id, before, nil)
+
 	c.Status(http.StatusOK)
 }
+
+type genrePatchDocument struct {
+	Title string `json:"title" validate:"required,max=255"`
+}
+
+// Patch godoc
+// @Summary      Partially update genre
+// @Description  JSON Merge Patch (RFC 7396). Постер обновляется через PUT /genres/{id}/poster
+// @Tags         genres
+// @Accept       application/merge-patch+json
+// @Produce      json
+// @Param        id path int true "Genre id"
+// @Param        request body handlers.genrePatchDocument true "Merge patch"
+// @Param        If-Match header string true "ETag of the current version"
+// @Success      200  {object}  models.Genre "OK"
+// @Failure      400  {object}  models.ApiError "Invalid data"
+// @Failure      412  {object}  models.ApiError "Resource was modified"
+// @Failure      415  {object}  models.ApiError "Unsupported Content-Type"
+// @Failure      428  {object}  models.ApiError "If-Match header required"
+// @Failure      500  {object}  models.ApiError
+// @Failure      404  {object}  models.ApiError "Not found"
+// @Failure      422  {object}  models.ApiError "Validation failed"
+// @Router       /genres/{id} [patch]
+// @Security Bearer
+func (h *GenreHandlers) Patch(c *gin.Context) {
+	idStr := c.Param("id")
+	id, err := strconv.Atoi(idStr)
+	if err != nil {
+		respondError(c, http.StatusBadRequest, models.ErrCodeInvalidId)
+		return
+	}
+
+	before, err := h.repo.FindById(c, id)
+	if err != nil {
+		respondFindError(c, err)
+		return
+	}
+
+	version, ok := ifMatchVersion(c)
+	if !ok {
+		return
+	}
+
+	if !checkIfMatch(c, version, before.Version) {
+		return
+	}
+
+	document := genrePatchDocument{Title: before.Title}
+	err = applyMergePatch(c, &document)
+	if err != nil {
+		respondPatchError(c, err)
+		return
+	}
+
+	if !h.validator.Validate(c, &document) {
+		return
+	}
+
+	genre := before
+	genre.Title = document.Title
+	genre.Version = version
+
+	h.saveGenre(c, id, before, genre)
+}
+
+// UpdatePoster godoc
+// @Summary      Upload genre poster
+// @Tags         genres
+// @Accept       multipart/form-data
+// @Produce      json
+// @Param        id path int true "Genre id"
+// @Param        poster formData file true "Poster image"
+// @Param        If-Match header string true "ETag of the current version"
+// @Success      200  {object}  models.Genre "OK"
+// @Failure      400  {object}  models.ApiError "Invalid data"
+// @Failure      412  {object}  models.ApiError "Resource was modified"
+// @Failure      428  {object}  models.ApiError "If-Match header required"
+// @Failure      500  {object}  models.ApiError
+// @Failure      404  {object}  models.ApiError "Not found"
+// @Router       /genres/{id}/poster [put]
+// @Security Bearer
+func (h *GenreHandlers) UpdatePoster(c *gin.Context) {
+	idStr := c.Param("id")
+	id, err := strconv.Atoi(idStr)
+	if err != nil {
+		respondError(c, http.StatusBadRequest, models.ErrCodeInvalidId)
+		return
+	}
+
+	before, err := h.repo.FindById(c, id)
+	if err != nil {
+		respondFindError(c, err)
+		return
+	}
+
+	version, ok := ifMatchVersion(c)
+	if !ok {
+		return
+	}
+
+	if !checkIfMatch(c, version, before.Version) {
+		return
+	}
+
+	poster, err := c.FormFile("poster")
+	if err != nil {
+		respondError(c, http.StatusBadRequest, models.ErrCodeFileRequired, models.NewApiErrorDetail("poster", models.DetailCodeRequired))
+		return
+	}
+
+	filename, err := h.saveGenrePoster(c, poster)
+	if err != nil {
+		respondInternalError(c, err)
+		return
+	}
+
+	genre := before
+	genre.PosterUrl = filename
+	genre.Version = version
+
+	h.saveGenre(c, id, before, genre)
+}
+
+// saveGenre обновляет запись с проверкой версии и отвечает её новым состоянием
+func (h *GenreHandlers) saveGenre(c *gin.Context, id int, before models.Genre, genre models.Genre) {
+	err := h.repo.Update(c, id, genre)
+	if isVersionConflict(err) {
+		respondVersionConflict(c)
+		return
+	}
+	if err != nil {
+		respondInternalError(c, err)
+		return
+	}
+
+	recordAudit(c, h.auditRepo, models.AuditActionUpdate, models.AuditEntityGenre, id, before, genre)
+
+	genre.Version++
+	setETag(c, genre.Version)
+	c.JSON(http.StatusOK, genre)
+}
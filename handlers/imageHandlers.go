@@ -2,6 +2,8 @@ package handlers
 
 import (
 	"fmt"
+	"goozinshe/models"
+	"goozinshe/storage"
 	"net/http"
 	"os"
 	"path/filepath"
@@ -9,10 +11,12 @@ import (
 	"github.com/gin-gonic/gin"
 )
 
-type imageHandlers struct{}
+type imageHandlers struct {
+	storage *storage.LocalStorage
+}
 
-func NewImageHandlers() *imageHandlers {
-	return &imageHandlers{}
+func NewImageHandlers(storage *storage.LocalStorage) *imageHandlers {
+	return &imageHandlers{storage: storage}
 }
 
 // HandleGetImageById godoc
@@ -23,19 +27,24 @@ func NewImageHandlers() *imageHandlers {
 // @Param imageId path int true "image id"
 // @Success      200  {string} string "Image to download"
 // @Failure 400 {object} models.ApiError "Invalid image id"
+// @Failure 404 {object} models.ApiError "Image not found"
 // @Failure   	 500  {object} models.ApiError
 // @Router       /images/:imageId [get]
 func (h *imageHandlers) HandleGetImageById(c *gin.Context) {
 	imageId := c.Param("imageId")
 	if imageId == "" {
-		c.JSON(http.StatusBadRequest, "Invalid image id")
+		respondError(c, http.StatusBadRequest, models.ErrCodeInvalidId)
 		return
 	}
 
 	fileName := filepath.Base(imageId)
-	byteFile, err := os.ReadFile(fmt.Sprintf("images/%s", imageId))
+	byteFile, err := h.storage.ReadFile(fileName)
+	if os.IsNotExist(err) {
+		respondError(c, http.StatusNotFound, models.ErrCodeNotFound)
+		return
+	}
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, err.Error())
+		respondInternalError(c, err)
 		return
 	}
 
@@ -0,0 +1,238 @@
+package handlers
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"goozinshe/config"
+	"goozinshe/jobs"
+	"goozinshe/models"
+	"goozinshe/repositories"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5"
+)
+
+type ImportHandlers struct {
+	importJobsRepo *repositories.ImportJobsRepository
+	auditRepo      *repositories.AuditRepository
+	importer       *jobs.CatalogImporter
+}
+
+func NewImportHandlers(importJobsRepo *repositories.ImportJobsRepository, auditRepo *repositories.AuditRepository, importer *jobs.CatalogImporter) *ImportHandlers {
+	return &ImportHandlers{importJobsRepo: importJobsRepo, auditRepo: auditRepo, importer: importer}
+}
+
+// Create godoc
+// @Tags         import
+// @Summary      Start bulk catalog import
+// @Description  CSV (с заголовком) или NDJSON. Колонки: externalKey, title, description, releaseYear, director, trailerUrl, poster, genres, categories, ages.
+// @Description  Жанры, категории и возрасты — id или названия через ";", нужен хотя бы один каждого вида. poster — URL или имя файла в архиве archive. Фильмы upsert-ятся по externalKey; как и в API, каждая строка попадает в аудит, а обновление сохраняет ревизию.
+// @Description  Новые фильмы создаются черновиками (draft), у существующих статус не меняется. Постеры по URL качаются только с публичных адресов.
+// @Accept       multipart/form-data
+// @Produce      json
+// @Param file formData file true "CSV or NDJSON file"
+// @Param archive formData file false "Zip archive with posters"
+// @Param format formData string false "csv or ndjson, detected by file extension by default"
+// @Success      202  {object} models.ImportJob "Accepted"
+// @Failure   	 400  {object} models.ApiError "Invalid file"
+// @Failure   	 413  {object} models.ApiError "Files are larger than IMPORT_MAX_UPLOAD_SIZE"
+// @Failure   	 503  {object} models.ApiError "Import queue is full"
+// @Failure   	 500  {object} models.ApiError
+// @Router       /admin/import [post]
+// @Security Bearer
+func (h *ImportHandlers) Create(c *gin.Context) {
+	// файл и архив держатся в памяти, пока задача ждёт в очереди, поэтому размер всего тела ограничен
+	if maxSize := config.Config.ImportMaxUpload; maxSize > 0 {
+		c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, maxSize)
+	}
+
+	file, err := c.FormFile("file")
+	var tooLarge *http.MaxBytesError
+	if errors.As(err, &tooLarge) {
+		respondError(c, http.StatusRequestEntityTooLarge, models.ErrCodeFileTooLarge)
+		return
+	}
+	if err != nil {
+		respondError(c, http.StatusBadRequest, models.ErrCodeFileRequired, models.NewApiErrorDetail("file", models.DetailCodeRequired))
+		return
+	}
+
+	format, ok := importFormat(c.PostForm("format"), file.Filename)
+	if !ok {
+		respondValidationError(c, models.NewApiErrorDetail("format", models.DetailCodeUnsupported))
+		return
+	}
+
+	data, err := readFormFile(file)
+	if err != nil {
+		respondValidationError(c, models.NewApiErrorDetail("file", models.DetailCodeInvalid))
+		return
+	}
+
+	var archive []byte
+	if archiveFile, err := c.FormFile("archive"); err == nil {
+		archive, err = readFormFile(archiveFile)
+		if err != nil {
+			respondValidationError(c, models.NewApiErrorDetail("archive", models.DetailCodeInvalid))
+			return
+		}
+	}
+
+	job := models.ImportJob{
+		Status: models.ImportStatusPending,
+		Format: format,
+	}
+	if userId, exists := c.Get("userId"); exists {
+		createdBy := userId.(int)
+		job.CreatedBy = &createdBy
+	}
+
+	job.Id, err = h.importJobsRepo.Create(c, job)
+	if err != nil {
+		respondInternalError(c, err)
+		return
+	}
+
+	task := jobs.ImportTask{JobId: job.Id, Format: format, Data: data, Archive: archive, ActorUserId: job.CreatedBy}
+	if principal, exists := c.Get(models.ServicePrincipalKey); exists {
+		actorApiKeyId := principal.(models.ServicePrincipal).ApiKeyId
+		task.ActorApiKeyId = &actorApiKeyId
+	}
+
+	err = h.importer.Enqueue(task)
+	if err != nil {
+		message := err.Error()
+		job.Status = models.ImportStatusFailed
+		job.Error = &message
+		h.importJobsRepo.Finish(c, job)
+		respondError(c, http.StatusServiceUnavailable, models.ErrCodeBusy)
+		return
+	}
+
+	recordAudit(c, h.auditRepo, models.AuditActionCreate, models.AuditEntityImport, job.Id, nil, job)
+
+	c.JSON(http.StatusAccepted, job)
+}
+
+func importFormat(format string, filename string) (string, bool) {
+	if format == "" {
+		switch strings.ToLower(filepath.Ext(filename)) {
+		case ".csv":
+			format = models.ImportFormatCsv
+		case ".ndjson", ".jsonl":
+			format = models.ImportFormatNdjson
+		}
+	}
+
+	switch format {
+	case models.ImportFormatCsv, models.ImportFormatNdjson:
+		return format, true
+	default:
+		return "", false
+	}
+}
+
+func readFormFile(file *multipart.FileHeader) ([]byte, error) {
+	reader, err := file.Open()
+	if err != nil {
+		return nil, fmt.Errorf("could not read %s", file.Filename)
+	}
+	defer reader.Close()
+
+	return io.ReadAll(reader)
+}
+
+// FindAll godoc
+// @Tags         import
+// @Summary      List recent import jobs
+// @Accept       json
+// @Produce      json
+// @Success      200  {array} models.ImportJob "OK"
+// @Failure   	 500  {object} models.ApiError
+// @Router       /admin/import [get]
+// @Security Bearer
+func (h *ImportHandlers) FindAll(c *gin.Context) {
+	importJobs, err := h.importJobsRepo.FindAll(c)
+	if err != nil {
+		respondInternalError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, importJobs)
+}
+
+// FindById godoc
+// @Tags         import
+// @Summary      Import job status
+// @Accept       json
+// @Produce      json
+// @Param id path int true "Import job id"
+// @Success      200  {object} models.ImportJob "OK"
+// @Failure   	 400  {object} models.ApiError "Invalid import job id"
+// @Failure   	 404  {object} models.ApiError "Import job not found"
+// @Router       /admin/import/{id} [get]
+// @Security Bearer
+func (h *ImportHandlers) FindById(c *gin.Context) {
+	job, ok := h.findJob(c)
+	if !ok {
+		return
+	}
+
+	c.JSON(http.StatusOK, job)
+}
+
+// Report godoc
+// @Tags         import
+// @Summary      Download per-row error report
+// @Description  CSV с колонками row, externalKey, error
+// @Produce      text/csv
+// @Param id path int true "Import job id"
+// @Success      200  {file} file "OK"
+// @Failure   	 400  {object} models.ApiError "Invalid import job id"
+// @Failure   	 404  {object} models.ApiError "Import job not found"
+// @Router       /admin/import/{id}/report [get]
+// @Security Bearer
+func (h *ImportHandlers) Report(c *gin.Context) {
+	job, ok := h.findJob(c)
+	if !ok {
+		return
+	}
+
+	c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="import-%d-report.csv"`, job.Id))
+	c.Status(http.StatusOK)
+	c.Writer.Header().Set("Content-Type", "text/csv; charset=utf-8")
+
+	writer := csv.NewWriter(c.Writer)
+	writer.Write([]string{"row", "externalKey", "error"})
+	for _, rowError := range job.RowErrors {
+		writer.Write([]string{strconv.Itoa(rowError.Row), rowError.ExternalKey, rowError.Message})
+	}
+	writer.Flush()
+}
+
+func (h *ImportHandlers) findJob(c *gin.Context) (models.ImportJob, bool) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		respondError(c, http.StatusBadRequest, models.ErrCodeInvalidId)
+		return models.ImportJob{}, false
+	}
+
+	job, err := h.importJobsRepo.FindById(c, id)
+	if errors.Is(err, pgx.ErrNoRows) {
+		respondError(c, http.StatusNotFound, models.ErrCodeNotFound)
+		return models.ImportJob{}, false
+	}
+	if err != nil {
+		respondInternalError(c, err)
+		return models.ImportJob{}, false
+	}
+
+	return job, true
+}
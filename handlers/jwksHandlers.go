@@ -0,0 +1,27 @@
+package handlers
+
+import (
+	"goozinshe/tokens"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+type JwksHandlers struct {
+	keyManager *tokens.KeyManager
+}
+
+func NewJwksHandlers(keyManager *tokens.KeyManager) *JwksHandlers {
+	return &JwksHandlers{keyManager: keyManager}
+}
+
+// HandleGetJwks godoc
+// @Summary      Public keys for verifying Ozinshe tokens
+// @Tags         auth
+// @Produce      json
+// @Success      200  {object} models.JsonWebKeySet "OK"
+// @Router       /.well-known/jwks.json [get]
+func (h *JwksHandlers) HandleGetJwks(c *gin.Context) {
+	c.Header("Cache-Control", "public, max-age=300")
+	c.JSON(http.StatusOK, h.keyManager.JWKS(c))
+}
@@ -0,0 +1,118 @@
+package handlers
+
+import (
+	"goozinshe/config"
+	"goozinshe/middlewares"
+	"goozinshe/models"
+	"goozinshe/repositories"
+	"slices"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// translatable — переводимые поля одной записи, значения подменяются на месте
+type translatable struct {
+	key    models.TranslationKey
+	fields map[string]*string
+}
+
+// localizer подставляет переводы в ответ и запоминает, какие языки реально отданы
+type localizer struct {
+	translationsRepo *repositories.TranslationsRepository
+	locale           string
+	served           []string
+	items            []translatable
+}
+
+func newLocalizer(c *gin.Context, translationsRepo *repositories.TranslationsRepository) *localizer {
+	return &localizer{translationsRepo: translationsRepo, locale: middlewares.Locale(c)}
+}
+
+func (l *localizer) add(entityType string, id int, fields map[string]*string) {
+	l.items = append(l.items, translatable{
+		key:    models.TranslationKey{EntityType: entityType, EntityId: id},
+		fields: fields,
+	})
+}
+
+func (l *localizer) addMovie(movie *models.Movie) {
+	l.add(models.AuditEntityMovie, movie.Id, map[string]*string{"title": &movie.Title, "description": &movie.Description})
+	for i := range movie.Genres {
+		l.addGenre(&movie.Genres[i])
+	}
+	for i := range movie.Category {
+		l.addCategory(&movie.Category[i])
+	}
+	for i := range movie.Ages {
+		l.addAge(&movie.Ages[i])
+	}
+}
+
+func (l *localizer) addGenre(genre *models.Genre) {
+	l.add(models.AuditEntityGenre, genre.Id, map[string]*string{"title": &genre.Title})
+}
+
+func (l *localizer) addCategory(category *models.Category) {
+	l.add(models.AuditEntityCategory, category.Id, map[string]*string{"title": &category.Title})
+}
+
+func (l *localizer) addAge(age *models.Age) {
+	l.add(models.AuditEntityAge, age.Id, map[string]*string{"age": &age.Age})
+}
+
+// apply подставляет переводы; поле без перевода остаётся на языке по умолчанию.
+// Content-Language перечисляет все отданные языки, первым — запрошенный
+func (l *localizer) apply(c *gin.Context) error {
+	defaultLocale := config.Config.DefaultLocale
+	c.Header("Vary", "Accept-Language")
+
+	if l.locale == defaultLocale || len(l.items) == 0 {
+		c.Header("Content-Language", l.locale)
+		return nil
+	}
+
+	refs := make([]models.TranslationKey, 0, len(l.items))
+	for _, item := range l.items {
+		refs = append(refs, item.key)
+	}
+
+	translations, err := l.translationsRepo.FindByLocale(c, l.locale, refs)
+	if err != nil {
+		return err
+	}
+
+	for _, item := range l.items {
+		for field, value := range item.fields {
+			translated, ok := translations[item.key][field]
+			if ok {
+				*value = translated
+				l.serve(l.locale)
+			} else if *value != "" {
+				l.serve(defaultLocale)
+			}
+		}
+	}
+
+	if len(l.served) == 0 {
+		l.serve(l.locale)
+	}
+	slices.SortStableFunc(l.served, func(a, b string) int {
+		if a == l.locale {
+			return -1
+		}
+		if b == l.locale {
+			return 1
+		}
+		return 0
+	})
+
+	c.Header("Content-Language", strings.Join(l.served, ", "))
+	return nil
+}
+
+func (l *localizer) serve(locale string) {
+	if !slices.Contains(l.served, locale) {
+		l.served = append(l.served, locale)
+	}
+}
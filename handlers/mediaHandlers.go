@@ -0,0 +1,454 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"goozinshe/config"
+	"goozinshe/jobs"
+	"goozinshe/models"
+	"goozinshe/repositories"
+	"goozinshe/storage"
+	"io"
+	"net/http"
+	"os"
+	"path"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// загрузка по частям в духе tus: POST создаёт загрузку, HEAD отдаёт текущий offset,
+// PATCH дописывает кусок с этого offset
+const (
+	uploadOffsetHeader      = "Upload-Offset"
+	uploadLengthHeader      = "Upload-Length"
+	uploadChunkContentType  = "application/offset+octet-stream"
+	hlsPlaylistContentType  = "application/vnd.apple.mpegurl"
+	hlsSegmentContentType   = "video/mp2t"
+	mediaDefaultContentType = "application/octet-stream"
+)
+
+type MediaHandlers struct {
+	mediaRepo       *repositories.MediaAssetsRepository
+	moviesAdminRepo *repositories.MoviesAdminRepository
+	auditRepo       *repositories.AuditRepository
+	transcoder      *jobs.MediaTranscoder
+	storage         *storage.LocalStorage
+	validator       *RequestValidator
+	parentalRepo    *repositories.ParentalControlsRepository
+}
+
+func NewMediaHandlers(
+	mediaRepo *repositories.MediaAssetsRepository,
+	moviesAdminRepo *repositories.MoviesAdminRepository,
+	auditRepo *repositories.AuditRepository,
+	transcoder *jobs.MediaTranscoder,
+	storage *storage.LocalStorage,
+	validator *RequestValidator,
+	parentalRepo *repositories.ParentalControlsRepository) *MediaHandlers {
+	return &MediaHandlers{
+		mediaRepo:       mediaRepo,
+		moviesAdminRepo: moviesAdminRepo,
+		auditRepo:       auditRepo,
+		transcoder:      transcoder,
+		storage:         storage,
+		validator:       validator,
+		parentalRepo:    parentalRepo,
+	}
+}
+
+type createUploadRequest struct {
+	MovieId     int    `json:"movieId" validate:"required,exists=movies"`
+	AllSeriesId *int   `json:"allSeriesId" validate:"omitempty,exists=allseries"`
+	Filename    string `json:"filename" validate:"required,max=255"`
+	Size        int64  `json:"size" validate:"required,gt=0"`
+}
+
+// CreateUpload godoc
+// @Tags         media
+// @Summary      Start resumable video upload
+// @Description  Создаёт загрузку видео фильма или серии. Дальше файл отправляется кусками через PATCH /admin/media/uploads/{id},
+// @Description  после последнего куска видео уходит в очередь на нарезку HLS.
+// @Accept       json
+// @Produce      json
+// @Param request body handlers.createUploadRequest true "Upload data"
+// @Success      201  {object} models.MediaAsset "Created"
+// @Header       201  {string} Location "Upload URL"
+// @Header       201  {int} Upload-Offset "Uploaded bytes"
+// @Failure   	 400  {object} models.ApiError "Invalid data"
+// @Failure   	 422  {object} models.ApiError "Validation failed"
+// @Failure   	 500  {object} models.ApiError
+// @Router       /admin/media/uploads [post]
+// @Security Bearer
+func (h *MediaHandlers) CreateUpload(c *gin.Context) {
+	var request createUploadRequest
+	err := c.BindJSON(&request)
+	if err != nil {
+		respondError(c, http.StatusBadRequest, models.ErrCodeInvalidPayload)
+		return
+	}
+
+	if !h.validator.Validate(c, &request) {
+		return
+	}
+
+	details := make([]models.ApiErrorDetail, 0)
+	if maxSize := config.Config.MediaMaxUploadSize; maxSize > 0 && request.Size > maxSize {
+		details = append(details, models.NewApiErrorDetail("size", models.DetailCodeOutOfRange))
+	}
+	if request.AllSeriesId != nil {
+		movie, err := h.moviesAdminRepo.FindById(c, request.MovieId)
+		if err != nil {
+			respondFindError(c, err)
+			return
+		}
+
+		belongs := slices.ContainsFunc(movie.AllSeries, func(episode models.AllSeries) bool {
+			return episode.Id != nil && *episode.Id == *request.AllSeriesId
+		})
+		if !belongs {
+			details = append(details, models.NewApiErrorDetail("allSeriesId", models.DetailCodeNotAllowed))
+		}
+	}
+	if len(details) > 0 {
+		respondError(c, http.StatusUnprocessableEntity, models.ErrCodeValidation, details...)
+		return
+	}
+
+	asset := models.MediaAsset{
+		MovieId:     request.MovieId,
+		AllSeriesId: request.AllSeriesId,
+		Filename:    path.Base(request.Filename),
+		Size:        request.Size,
+	}
+	if userId, exists := c.Get("userId"); exists {
+		createdBy := userId.(int)
+		asset.CreatedBy = &createdBy
+	}
+
+	asset, err = h.mediaRepo.Create(c, asset)
+	if err != nil {
+		respondInternalError(c, err)
+		return
+	}
+
+	recordAudit(c, h.auditRepo, models.AuditActionCreate, models.AuditEntityMedia, asset.Id, nil, asset)
+
+	c.Header("Location", fmt.Sprintf("/admin/media/uploads/%d", asset.Id))
+	setUploadHeaders(c, asset)
+	c.JSON(http.StatusCreated, asset)
+}
+
+// UploadOffset godoc
+// @Tags         media
+// @Summary      Get upload offset
+// @Description  Сколько байт уже принято — с этого места нужно продолжить загрузку после обрыва
+// @Param id path int true "Media id"
+// @Success      200  "OK"
+// @Header       200  {int} Upload-Offset "Uploaded bytes"
+// @Header       200  {int} Upload-Length "File size"
+// @Failure   	 400  {object} models.ApiError "Invalid id"
+// @Failure   	 404  {object} models.ApiError "Upload not found"
+// @Failure   	 500  {object} models.ApiError
+// @Router       /admin/media/uploads/{id} [head]
+// @Security Bearer
+func (h *MediaHandlers) UploadOffset(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		respondError(c, http.StatusBadRequest, models.ErrCodeInvalidId)
+		return
+	}
+
+	asset, err := h.mediaRepo.FindById(c, id)
+	if err != nil {
+		respondFindError(c, err)
+		return
+	}
+
+	c.Header("Cache-Control", "no-store")
+	setUploadHeaders(c, asset)
+	c.Status(http.StatusOK)
+}
+
+// UploadChunk godoc
+// @Tags         media
+// @Summary      Upload video chunk
+// @Description  Тело — байты файла начиная с Upload-Offset. Offset должен совпадать с уже принятым, иначе 409:
+// @Description  актуальный offset можно узнать через HEAD. Куски одной загрузки принимаются по одному: пока пишется кусок, параллельный PATCH тоже получает 409.
+// @Accept       application/offset+octet-stream
+// @Produce      json
+// @Param id path int true "Media id"
+// @Param Upload-Offset header int true "Offset of the chunk"
+// @Success      204  "Chunk accepted"
+// @Header       204  {int} Upload-Offset "Uploaded bytes"
+// @Failure   	 400  {object} models.ApiError "Invalid Upload-Offset"
+// @Failure   	 404  {object} models.ApiError "Upload not found"
+// @Failure   	 409  {object} models.ApiError "Offset mismatch or upload already finished"
+// @Failure   	 415  {object} models.ApiError "Unsupported Content-Type"
+// @Failure   	 500  {object} models.ApiError
+// @Router       /admin/media/uploads/{id} [patch]
+// @Security Bearer
+func (h *MediaHandlers) UploadChunk(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		respondError(c, http.StatusBadRequest, models.ErrCodeInvalidId)
+		return
+	}
+
+	if c.ContentType() != uploadChunkContentType {
+		respondError(c, http.StatusUnsupportedMediaType, models.ErrCodeUnsupportedMedia)
+		return
+	}
+
+	offset, err := strconv.ParseInt(c.GetHeader(uploadOffsetHeader), 10, 64)
+	if err != nil || offset < 0 {
+		respondValidationError(c, models.NewApiErrorDetail(uploadOffsetHeader, models.DetailCodeInvalid))
+		return
+	}
+
+	// файл пишется под блокировкой загрузки: параллельный PATCH с тем же offset получит 409, а не перемешает байты
+	asset, err := h.mediaRepo.AppendUpload(c, id, offset, func(asset models.MediaAsset) (int64, error) {
+		return h.writeChunk(id, offset, io.LimitReader(c.Request.Body, asset.Size-offset))
+	})
+	if errors.Is(err, repositories.ErrUploadOffset) {
+		setUploadHeaders(c, asset)
+		respondError(c, http.StatusConflict, models.ErrCodeUploadOffset)
+		return
+	}
+	if errors.Is(err, repositories.ErrUploadBusy) {
+		respondError(c, http.StatusConflict, models.ErrCodeUploadOffset)
+		return
+	}
+	if errors.Is(err, repositories.ErrUploadFinished) {
+		respondError(c, http.StatusConflict, models.ErrCodeInvalidTransition)
+		return
+	}
+	if err != nil {
+		respondFindError(c, err)
+		return
+	}
+
+	if asset.Status == models.MediaStatusQueued {
+		h.transcoder.Notify()
+	}
+
+	setUploadHeaders(c, asset)
+	c.Status(http.StatusNoContent)
+}
+
+// writeChunk пишет кусок с offset; хвост от оборванной раньше попытки отрезается
+func (h *MediaHandlers) writeChunk(id int, offset int64, chunk io.Reader) (int64, error) {
+	file, err := os.OpenFile(h.transcoder.UploadPath(id), os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return 0, err
+	}
+	defer file.Close()
+
+	err = file.Truncate(offset)
+	if err != nil {
+		return 0, err
+	}
+
+	_, err = file.Seek(offset, io.SeekStart)
+	if err != nil {
+		return 0, err
+	}
+
+	written, err := io.Copy(file, chunk)
+	if err != nil {
+		return 0, err
+	}
+
+	return written, file.Sync()
+}
+
+func setUploadHeaders(c *gin.Context, asset models.MediaAsset) {
+	c.Header(uploadOffsetHeader, strconv.FormatInt(asset.UploadedBytes, 10))
+	c.Header(uploadLengthHeader, strconv.FormatInt(asset.Size, 10))
+}
+
+// FindById godoc
+// @Tags         media
+// @Summary      Get video processing status
+// @Produce      json
+// @Param id path int true "Media id"
+// @Success      200  {object} models.MediaAsset "OK"
+// @Failure   	 400  {object} models.ApiError "Invalid id"
+// @Failure   	 404  {object} models.ApiError "Media not found"
+// @Failure   	 500  {object} models.ApiError
+// @Router       /admin/media/{id} [get]
+// @Security Bearer
+func (h *MediaHandlers) FindById(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		respondError(c, http.StatusBadRequest, models.ErrCodeInvalidId)
+		return
+	}
+
+	asset, err := h.mediaRepo.FindById(c, id)
+	if err != nil {
+		respondFindError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, asset)
+}
+
+// FindByMovie godoc
+// @Tags         media
+// @Summary      List movie videos
+// @Description  Видео фильма и его серий с состоянием обработки, новые первыми
+// @Produce      json
+// @Param id path int true "Movie id"
+// @Success      200  {array} models.MediaAsset "OK"
+// @Failure   	 400  {object} models.ApiError "Invalid id"
+// @Failure   	 403  {object} models.ApiError "Restricted by parental controls"
+// @Failure   	 500  {object} models.ApiError
+// @Router       /movies/{id}/media [get]
+// @Security Bearer
+func (h *MediaHandlers) FindByMovie(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		respondError(c, http.StatusBadRequest, models.ErrCodeInvalidId)
+		return
+	}
+
+	if !checkMovieAllowed(c, h.parentalRepo, id) {
+		return
+	}
+
+	assets, err := h.mediaRepo.FindByMovie(c, id)
+	if err != nil {
+		respondInternalError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, assets)
+}
+
+// Delete godoc
+// @Tags         media
+// @Summary      Delete video
+// @Description  Удаляет видео вместе с загруженным файлом и HLS. Видео в обработке удалить нельзя
+// @Param id path int true "Media id"
+// @Success      200  "OK"
+// @Failure   	 400  {object} models.ApiError "Invalid id"
+// @Failure   	 404  {object} models.ApiError "Media not found"
+// @Failure   	 409  {object} models.ApiError "Video is being processed"
+// @Failure   	 500  {object} models.ApiError
+// @Router       /admin/media/{id} [delete]
+// @Security Bearer
+func (h *MediaHandlers) Delete(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		respondError(c, http.StatusBadRequest, models.ErrCodeInvalidId)
+		return
+	}
+
+	asset, err := h.mediaRepo.FindById(c, id)
+	if err != nil {
+		respondFindError(c, err)
+		return
+	}
+	if asset.Status == models.MediaStatusProcessing {
+		respondError(c, http.StatusConflict, models.ErrCodeBusy)
+		return
+	}
+
+	err = h.mediaRepo.Delete(c, id)
+	if err != nil {
+		respondInternalError(c, err)
+		return
+	}
+
+	os.Remove(h.transcoder.UploadPath(id))
+	err = h.storage.Remove(h.transcoder.StorageKey(id))
+	if err != nil {
+		respondInternalError(c, err)
+		return
+	}
+
+	recordAudit(c, h.auditRepo, models.AuditActionDelete, models.AuditEntityMedia, id, asset, nil)
+
+	c.Status(http.StatusOK)
+}
+
+// HandleGetMediaFile godoc
+// @Summary      Stream HLS playlist or segment
+// @Tags         media
+// @Produce      application/vnd.apple.mpegurl
+// @Param mediaId path int true "Media id"
+// @Description  Только видео опубликованных фильмов с рейтингом не выше действующего возрастного лимита
+// @Param mediaId path int true "Media id"
+// @Param file path string true "master.m3u8, <rendition>/index.m3u8 or <rendition>/segment_NNN.ts"
+// @Param        X-Profile-Id header int false "Viewer profile"
+// @Success      200  {string} string "Playlist or segment"
+// @Failure 400 {object} models.ApiError "Invalid media id or file"
+// @Failure 403 {object} models.ApiError "Restricted by parental controls"
+// @Failure 404 {object} models.ApiError "File not found"
+// @Failure   	 500  {object} models.ApiError
+// @Router       /media/{mediaId}/{file} [get]
+// @Security Bearer
+func (h *MediaHandlers) HandleGetMediaFile(c *gin.Context) {
+	mediaId, err := strconv.Atoi(c.Param("mediaId"))
+	if err != nil {
+		respondError(c, http.StatusBadRequest, models.ErrCodeInvalidId)
+		return
+	}
+
+	// файл ищется только внутри каталога видео: "..", выводящие из него, не принимаются
+	name := path.Clean(c.Param("file"))
+	if strings.Contains(c.Param("file"), "..") || !strings.HasPrefix(name, "/") || name == "/" {
+		respondError(c, http.StatusBadRequest, models.ErrCodeInvalidId)
+		return
+	}
+
+	asset, err := h.mediaRepo.FindPublished(c, mediaId)
+	if err != nil {
+		respondFindError(c, err)
+		return
+	}
+
+	if !checkMovieAllowed(c, h.parentalRepo, asset.MovieId) {
+		return
+	}
+
+	file, err := h.storage.Open(h.transcoder.StorageKey(mediaId) + name)
+	if errors.Is(err, storage.ErrInvalidKey) {
+		respondError(c, http.StatusBadRequest, models.ErrCodeInvalidId)
+		return
+	}
+	if os.IsNotExist(err) {
+		respondError(c, http.StatusNotFound, models.ErrCodeNotFound)
+		return
+	}
+	if err != nil {
+		respondInternalError(c, err)
+		return
+	}
+	defer file.Close()
+
+	info, err := file.Stat()
+	if err != nil {
+		respondInternalError(c, err)
+		return
+	}
+	if info.IsDir() {
+		respondError(c, http.StatusNotFound, models.ErrCodeNotFound)
+		return
+	}
+
+	switch path.Ext(info.Name()) {
+	case ".m3u8":
+		c.Header("Content-Type", hlsPlaylistContentType)
+	case ".ts":
+		c.Header("Content-Type", hlsSegmentContentType)
+	default:
+		c.Header("Content-Type", mediaDefaultContentType)
+	}
+
+	// ServeContent отвечает на Range-запросы плееров
+	http.ServeContent(c.Writer, c.Request, info.Name(), info.ModTime(), file)
+}
@@ -0,0 +1,98 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"goozinshe/models"
+	"io"
+	"net/http"
+	"reflect"
+
+	"github.com/gin-gonic/gin"
+)
+
+const mergePatchContentType = "application/merge-patch+json"
+
+var errUnsupportedPatchType = errors.New("Content-Type must be " + mergePatchContentType)
+
+// applyMergePatch накладывает JSON Merge Patch (RFC 7396) из тела запроса на document.
+// document — указатель на структуру с json-тегами, заполненную текущими значениями записи
+func applyMergePatch(c *gin.Context, document any) error {
+	contentType := c.ContentType()
+	if contentType != mergePatchContentType && contentType != gin.MIMEJSON {
+		return errUnsupportedPatchType
+	}
+
+	body, err := io.ReadAll(c.Request.Body)
+	if err != nil {
+		return err
+	}
+
+	var patch any
+	err = json.Unmarshal(body, &patch)
+	if err != nil {
+		return err
+	}
+
+	if _, ok := patch.(map[string]any); !ok {
+		return errors.New("merge patch must be a JSON object")
+	}
+
+	current, err := json.Marshal(document)
+	if err != nil {
+		return err
+	}
+
+	var target any
+	err = json.Unmarshal(current, &target)
+	if err != nil {
+		return err
+	}
+
+	merged, err := json.Marshal(mergePatch(target, patch))
+	if err != nil {
+		return err
+	}
+
+	// поля, удалённые через null, должны получить нулевые значения
+	reflect.ValueOf(document).Elem().SetZero()
+
+	decoder := json.NewDecoder(bytes.NewReader(merged))
+	decoder.DisallowUnknownFields()
+
+	return decoder.Decode(document)
+}
+
+func mergePatch(target any, patch any) any {
+	patchObject, ok := patch.(map[string]any)
+	if !ok {
+		return patch
+	}
+
+	targetObject, ok := target.(map[string]any)
+	if !ok {
+		targetObject = make(map[string]any)
+	}
+
+	for key, value := range patchObject {
+		if value == nil {
+			delete(targetObject, key)
+			continue
+		}
+
+		targetObject[key] = mergePatch(targetObject[key], value)
+	}
+
+	return targetObject
+}
+
+// respondPatchError отвечает 415 на неподдерживаемый Content-Type и 400 на некорректный патч
+func respondPatchError(c *gin.Context, err error) {
+	if errors.Is(err, errUnsupportedPatchType) {
+		respondError(c, http.StatusUnsupportedMediaType, models.ErrCodeUnsupportedMedia)
+		return
+	}
+
+	respondError(c, http.StatusBadRequest, models.ErrCodeInvalidPayload)
+}
@@ -0,0 +1,159 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestMain(m *testing.M) {
+	gin.SetMode(gin.TestMode)
+	os.Exit(m.Run())
+}
+
+// примеры из приложения A RFC 7396
+func TestMergePatch(t *testing.T) {
+	tests := []struct {
+		name   string
+		target string
+		patch  string
+		want   string
+	}{
+		{"replace value", `{"a":"b"}`, `{"a":"c"}`, `{"a":"c"}`},
+		{"add member", `{"a":"b"}`, `{"b":"c"}`, `{"a":"b","b":"c"}`},
+		{"remove member", `{"a":"b"}`, `{"a":null}`, `{}`},
+		{"remove one of two", `{"a":"b","b":"c"}`, `{"a":null}`, `{"b":"c"}`},
+		{"array replaces array", `{"a":["b"]}`, `{"a":"c"}`, `{"a":"c"}`},
+		{"value replaces array", `{"a":"c"}`, `{"a":["b"]}`, `{"a":["b"]}`},
+		{"nested merge", `{"a":{"b":"c"}}`, `{"a":{"b":"d","c":null}}`, `{"a":{"b":"d"}}`},
+		{"arrays are not merged", `{"a":[{"b":"c"}]}`, `{"a":[1]}`, `{"a":[1]}`},
+		{"object replaces scalar", `{"e":null}`, `{"a":1}`, `{"a":1,"e":null}`},
+		{"nested null is removed", `{}`, `{"a":{"bb":{"ccc":null}}}`, `{"a":{"bb":{}}}`},
+		{"empty patch", `{"a":"b"}`, `{}`, `{"a":"b"}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := mergePatch(decodeJson(t, tt.target), decodeJson(t, tt.patch))
+			if want := decodeJson(t, tt.want); !reflect.DeepEqual(got, want) {
+				t.Errorf("mergePatch(%s, %s) = %v, want %v", tt.target, tt.patch, got, want)
+			}
+		})
+	}
+}
+
+type patchDocument struct {
+	Title       string  `json:"title"`
+	Description *string `json:"description"`
+	Rating      int     `json:"rating"`
+	Tags        []int   `json:"tags"`
+}
+
+func TestApplyMergePatch(t *testing.T) {
+	description := "old"
+
+	tests := []struct {
+		name        string
+		contentType string
+		body        string
+		want        patchDocument
+		wantErr     bool
+		unsupported bool
+	}{
+		{
+			name:        "changes only sent fields",
+			contentType: mergePatchContentType,
+			body:        `{"title":"new"}`,
+			want:        patchDocument{Title: "new", Description: &description, Rating: 5, Tags: []int{1, 2}},
+		},
+		{
+			name:        "null resets field",
+			contentType: mergePatchContentType,
+			body:        `{"description":null,"tags":null}`,
+			want:        patchDocument{Title: "old", Rating: 5},
+		},
+		{
+			name:        "plain json is accepted",
+			contentType: gin.MIMEJSON,
+			body:        `{"rating":7}`,
+			want:        patchDocument{Title: "old", Description: &description, Rating: 7, Tags: []int{1, 2}},
+		},
+		{
+			name:        "unsupported content type",
+			contentType: "text/plain",
+			body:        `{"title":"new"}`,
+			wantErr:     true,
+			unsupported: true,
+		},
+		{
+			name:        "patch must be an object",
+			contentType: mergePatchContentType,
+			body:        `["title"]`,
+			wantErr:     true,
+		},
+		{
+			name:        "unknown field",
+			contentType: mergePatchContentType,
+			body:        `{"version":2}`,
+			wantErr:     true,
+		},
+		{
+			name:        "wrong type",
+			contentType: mergePatchContentType,
+			body:        `{"rating":"high"}`,
+			wantErr:     true,
+		},
+		{
+			name:        "invalid json",
+			contentType: mergePatchContentType,
+			body:        `{"title":`,
+			wantErr:     true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, _ := gin.CreateTestContext(httptest.NewRecorder())
+			c.Request = httptest.NewRequest(http.MethodPatch, "/", strings.NewReader(tt.body))
+			c.Request.Header.Set("Content-Type", tt.contentType)
+
+			old := "old"
+			document := patchDocument{Title: "old", Description: &old, Rating: 5, Tags: []int{1, 2}}
+			err := applyMergePatch(c, &document)
+
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("applyMergePatch() error = nil, want error")
+				}
+				if got := errors.Is(err, errUnsupportedPatchType); got != tt.unsupported {
+					t.Errorf("errors.Is(err, errUnsupportedPatchType) = %v, want %v", got, tt.unsupported)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("applyMergePatch() error = %v", err)
+			}
+			if !reflect.DeepEqual(document, tt.want) {
+				t.Errorf("applyMergePatch() = %+v, want %+v", document, tt.want)
+			}
+		})
+	}
+}
+
+func decodeJson(t *testing.T, data string) any {
+	t.Helper()
+
+	var value any
+	if err := json.Unmarshal([]byte(data), &value); err != nil {
+		t.Fatalf("invalid test json %s: %v", data, err)
+	}
+
+	return value
+}
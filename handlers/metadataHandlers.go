@@ -0,0 +1,277 @@
+package handlers
+
+import (
+	"errors"
+	"goozinshe/jobs"
+	"goozinshe/models"
+	"goozinshe/repositories"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5"
+)
+
+const (
+	metadataSearchDefaultLimit = 20
+	metadataSearchMaxLimit     = 100
+)
+
+type MetadataHandlers struct {
+	metadataRepo *repositories.MetadataRepository
+	genresRepo   *repositories.GenresRepository
+	indexer      *jobs.MetadataIndexer
+	validator    *RequestValidator
+}
+
+func NewMetadataHandlers(metadataRepo *repositories.MetadataRepository, genresRepo *repositories.GenresRepository, indexer *jobs.MetadataIndexer, validator *RequestValidator) *MetadataHandlers {
+	return &MetadataHandlers{metadataRepo: metadataRepo, genresRepo: genresRepo, indexer: indexer, validator: validator}
+}
+
+type applyMetadataRequest struct {
+	Source     string `json:"source" validate:"required,oneof=imdb tmdb"`
+	ExternalId string `json:"externalId" validate:"required"`
+}
+
+// metadataPrefill — поля для формы создания фильма. Жанры без сопоставления возвращаются отдельно
+type metadataPrefill struct {
+	Title          string   `json:"title"`
+	Description    string   `json:"description"`
+	ReleaseYear    int      `json:"releaseYear"`
+	Director       string   `json:"director"`
+	GenreIds       []int    `json:"genreIds"`
+	UnmappedGenres []string `json:"unmappedGenres"`
+}
+
+// RebuildIndex godoc
+// @Tags         metadata
+// @Summary      Rebuild local metadata index from dumps
+// @Description  Читает из METADATA_DUMP_DIR title.basics.tsv, title.crew.tsv, name.basics.tsv (imdb) или tmdb_movies.json (tmdb), можно в .gz
+// @Accept       json
+// @Produce      json
+// @Param source query string true "imdb or tmdb"
+// @Success      202  {object} models.MetadataIndexStatus "Accepted"
+// @Failure   	 400  {object} models.ApiError "Unknown source"
+// @Failure   	 409  {object} models.ApiError "Rebuild is already running"
+// @Router       /admin/metadata/index [post]
+// @Security Bearer
+func (h *MetadataHandlers) RebuildIndex(c *gin.Context) {
+	err := h.indexer.Start(c.Query("source"))
+	if errors.Is(err, jobs.ErrMetadataIndexRunning) {
+		respondError(c, http.StatusConflict, models.ErrCodeBusy)
+		return
+	}
+	if err != nil {
+		respondValidationError(c, models.NewApiErrorDetail("source", models.DetailCodeUnsupported))
+		return
+	}
+
+	c.JSON(http.StatusAccepted, h.indexer.Status())
+}
+
+// IndexStatus godoc
+// @Tags         metadata
+// @Summary      Metadata index rebuild status
+// @Accept       json
+// @Produce      json
+// @Success      200  {object} models.MetadataIndexStatus "OK"
+// @Router       /admin/metadata/index [get]
+// @Security Bearer
+func (h *MetadataHandlers) IndexStatus(c *gin.Context) {
+	c.JSON(http.StatusOK, h.indexer.Status())
+}
+
+// Search godoc
+// @Tags         metadata
+// @Summary      Search metadata candidates
+// @Accept       json
+// @Produce      json
+// @Param q query string true "Title prefix"
+// @Param year query int false "Release year"
+// @Param limit query int false "Page size, 20 by default"
+// @Success      200  {array} models.MetadataTitle "OK"
+// @Failure   	 400  {object} models.ApiError "Invalid query"
+// @Failure   	 500  {object} models.ApiError
+// @Router       /admin/metadata/search [get]
+// @Security Bearer
+func (h *MetadataHandlers) Search(c *gin.Context) {
+	q := strings.TrimSpace(c.Query("q"))
+	if len([]rune(q)) < 2 {
+		respondValidationError(c, models.NewApiErrorDetail("q", models.DetailCodeTooShort))
+		return
+	}
+
+	year := 0
+	if yearStr := c.Query("year"); yearStr != "" {
+		var err error
+		year, err = strconv.Atoi(yearStr)
+		if err != nil {
+			respondValidationError(c, models.NewApiErrorDetail("year", models.DetailCodeInvalid))
+			return
+		}
+	}
+
+	limit := metadataSearchDefaultLimit
+	if limitStr := c.Query("limit"); limitStr != "" {
+		var err error
+		limit, err = strconv.Atoi(limitStr)
+		if err != nil || limit <= 0 || limit > metadataSearchMaxLimit {
+			respondValidationError(c, models.NewApiErrorDetail("limit", models.DetailCodeInvalid))
+			return
+		}
+	}
+
+	titles, err := h.metadataRepo.Search(c, q, year, limit)
+	if err != nil {
+		respondInternalError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, titles)
+}
+
+// Apply godoc
+// @Tags         metadata
+// @Summary      Prefill movie from metadata
+// @Description  Возвращает название, описание, год, режиссёра и id наших жанров. Жанры сопоставляются через /admin/metadata/genres, затем по совпадению названия
+// @Accept       json
+// @Produce      json
+// @Param request body handlers.applyMetadataRequest true "Candidate"
+// @Success      200  {object} handlers.metadataPrefill "OK"
+// @Failure   	 400  {object} models.ApiError "Invalid data"
+// @Failure   	 404  {object} models.ApiError "Candidate not found"
+// @Failure   	 422  {object} models.ApiError "Validation failed"
+// @Failure   	 500  {object} models.ApiError
+// @Router       /admin/metadata/apply [post]
+// @Security Bearer
+func (h *MetadataHandlers) Apply(c *gin.Context) {
+	var request applyMetadataRequest
+	err := c.BindJSON(&request)
+	if err != nil {
+		respondError(c, http.StatusBadRequest, models.ErrCodeInvalidPayload)
+		return
+	}
+
+	if !h.validator.Validate(c, &request) {
+		return
+	}
+
+	title, err := h.metadataRepo.FindByExternalId(c, request.Source, request.ExternalId)
+	if errors.Is(err, pgx.ErrNoRows) {
+		respondError(c, http.StatusNotFound, models.ErrCodeNotFound)
+		return
+	}
+	if err != nil {
+		respondInternalError(c, err)
+		return
+	}
+
+	genreIds, err := h.genreLookup(c)
+	if err != nil {
+		respondInternalError(c, err)
+		return
+	}
+
+	prefill := metadataPrefill{
+		Title:          title.Title,
+		Description:    title.Description,
+		Director:       strings.Join(title.Directors, ", "),
+		GenreIds:       make([]int, 0),
+		UnmappedGenres: make([]string, 0),
+	}
+	if title.Year != nil {
+		prefill.ReleaseYear = *title.Year
+	}
+
+	for _, genre := range title.Genres {
+		id, ok := genreIds[strings.ToLower(genre)]
+		if !ok {
+			prefill.UnmappedGenres = append(prefill.UnmappedGenres, genre)
+			continue
+		}
+		if !slices.Contains(prefill.GenreIds, id) {
+			prefill.GenreIds = append(prefill.GenreIds, id)
+		}
+	}
+
+	c.JSON(http.StatusOK, prefill)
+}
+
+// genreLookup — жанр из дампа в нижнем регистре -> id нашего жанра; явные сопоставления важнее совпадения названий
+func (h *MetadataHandlers) genreLookup(c *gin.Context) (map[string]int, error) {
+	genres, err := h.genresRepo.FindAll(c)
+	if err != nil {
+		return nil, err
+	}
+
+	lookup := make(map[string]int)
+	for _, genre := range genres {
+		lookup[strings.ToLower(genre.Title)] = genre.Id
+	}
+
+	mappings, err := h.metadataRepo.FindGenreMappings(c)
+	if err != nil {
+		return nil, err
+	}
+	for _, mapping := range mappings {
+		lookup[mapping.SourceGenre] = mapping.GenreId
+	}
+
+	return lookup, nil
+}
+
+// FindGenreMappings godoc
+// @Tags         metadata
+// @Summary      List genre mappings
+// @Accept       json
+// @Produce      json
+// @Success      200  {array} models.MetadataGenreMapping "OK"
+// @Failure   	 500  {object} models.ApiError
+// @Router       /admin/metadata/genres [get]
+// @Security Bearer
+func (h *MetadataHandlers) FindGenreMappings(c *gin.Context) {
+	mappings, err := h.metadataRepo.FindGenreMappings(c)
+	if err != nil {
+		respondInternalError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, mappings)
+}
+
+// SetGenreMapping godoc
+// @Tags         metadata
+// @Summary      Map dump genre onto our genre
+// @Accept       json
+// @Produce      json
+// @Param request body models.MetadataGenreMapping true "Mapping"
+// @Success      200  {object} models.MetadataGenreMapping "OK"
+// @Failure   	 400  {object} models.ApiError "Invalid data"
+// @Failure   	 500  {object} models.ApiError
+// @Router       /admin/metadata/genres [put]
+// @Security Bearer
+func (h *MetadataHandlers) SetGenreMapping(c *gin.Context) {
+	var mapping models.MetadataGenreMapping
+	err := c.BindJSON(&mapping)
+	if err != nil || strings.TrimSpace(mapping.SourceGenre) == "" {
+		respondError(c, http.StatusBadRequest, models.ErrCodeInvalidPayload)
+		return
+	}
+
+	_, err = h.genresRepo.FindById(c, mapping.GenreId)
+	if err != nil {
+		respondValidationError(c, models.NewApiErrorDetail("genreId", models.DetailCodeNotFound))
+		return
+	}
+
+	mapping.SourceGenre = strings.ToLower(strings.TrimSpace(mapping.SourceGenre))
+	err = h.metadataRepo.SetGenreMapping(c, mapping)
+	if err != nil {
+		respondInternalError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, mapping)
+}
@@ -0,0 +1,225 @@
+package handlers
+
+import (
+	"encoding/json"
+	"goozinshe/models"
+	"goozinshe/repositories"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+type MovieRevisionsHandlers struct {
+	moviesRepo      *repositories.MoviesRepository
+	revisionsRepo   *repositories.MovieRevisionsRepository
+	auditRepo       *repositories.AuditRepository
+	moviesAdminRepo *repositories.MoviesAdminRepository
+}
+
+func NewMovieRevisionsHandlers(
+	moviesRepo *repositories.MoviesRepository,
+	revisionsRepo *repositories.MovieRevisionsRepository,
+	auditRepo *repositories.AuditRepository,
+	moviesAdminRepo *repositories.MoviesAdminRepository,
+) *MovieRevisionsHandlers {
+	return &MovieRevisionsHandlers{
+		moviesRepo:      moviesRepo,
+		revisionsRepo:   revisionsRepo,
+		auditRepo:       auditRepo,
+		moviesAdminRepo: moviesAdminRepo,
+	}
+}
+
+// findMovie — фильм в любом статусе: ревизии нужны и черновикам, и снятым с публикации
+func (h *MovieRevisionsHandlers) findMovie(c *gin.Context, id int) (models.Movie, error) {
+	movie, err := h.moviesAdminRepo.FindById(c, id)
+
+	return models.Movie(movie), err
+}
+
+type movieRevisionDiffResponse struct {
+	From int `json:"from"`
+	// 0 — текущая версия фильма
+	To     int             `json:"to"`
+	Before json.RawMessage `json:"before" swaggertype:"object"`
+	After  json.RawMessage `json:"after" swaggertype:"object"`
+}
+
+// newMovieRevision — ревизия с текущим состоянием фильма; репозиторий сохраняет её вместе с изменением фильма
+func newMovieRevision(c *gin.Context, movie models.Movie) models.MovieRevision {
+	revision := models.MovieRevision{MovieId: movie.Id, Snapshot: withoutViewerStats(movie)}
+	if userId, exists := c.Get("userId"); exists {
+		id := userId.(int)
+		revision.CreatedBy = &id
+	}
+
+	return revision
+}
+
+// withoutViewerStats убирает из фильма данные зрителей: это не часть редакции и не должно попадать в диффы ревизий
+func withoutViewerStats(movie models.Movie) models.Movie {
+	movie.IsFavorite = false
+	movie.FavoriteCount = 0
+
+	return movie
+}
+
+// FindAll godoc
+// @Summary      Movie revision history
+// @Tags         movies
+// @Accept       json
+// @Produce      json
+// @Param        id path int true "Movie id"
+// @Success      200  {array}  models.MovieRevision "OK"
+// @Failure      400  {object}  models.ApiError "Invalid Movie Id"
+// @Failure      500  {object}  models.ApiError
+// @Router       /movies/{id}/revisions [get]
+// @Security Bearer
+func (h *MovieRevisionsHandlers) FindAll(c *gin.Context) {
+	idStr := c.Param("id")
+	id, err := strconv.Atoi(idStr)
+	if err != nil {
+		respondError(c, http.StatusBadRequest, models.ErrCodeInvalidId)
+		return
+	}
+
+	revisions, err := h.revisionsRepo.FindAllByMovie(c, id)
+	if err != nil {
+		respondInternalError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, revisions)
+}
+
+// Diff godoc
+// @Summary      Diff between two movie revisions
+// @Description  Возвращает только изменившиеся поля. Если to не указан, сравнение идёт с текущей версией фильма
+// @Tags         movies
+// @Accept       json
+// @Produce      json
+// @Param        id path int true "Movie id"
+// @Param        from query int true "Revision number"
+// @Param        to query int false "Revision number"
+// @Success      200  {object}  handlers.movieRevisionDiffResponse "OK"
+// @Failure      400  {object}  models.ApiError "Invalid data"
+// @Failure      404  {object}  models.ApiError "Revision not found"
+// @Failure      500  {object}  models.ApiError
+// @Router       /movies/{id}/revisions/diff [get]
+// @Security Bearer
+func (h *MovieRevisionsHandlers) Diff(c *gin.Context) {
+	idStr := c.Param("id")
+	id, err := strconv.Atoi(idStr)
+	if err != nil {
+		respondError(c, http.StatusBadRequest, models.ErrCodeInvalidId)
+		return
+	}
+
+	fromRev, err := strconv.Atoi(c.Query("from"))
+	if err != nil {
+		respondValidationError(c, models.NewApiErrorDetail("from", models.DetailCodeInvalid))
+		return
+	}
+
+	from, err := h.revisionsRepo.FindByRevision(c, id, fromRev)
+	if err != nil {
+		respondError(c, http.StatusNotFound, models.ErrCodeNotFound)
+		return
+	}
+
+	response := movieRevisionDiffResponse{From: fromRev}
+
+	var to models.Movie
+	if toStr := c.Query("to"); toStr != "" {
+		toRev, err := strconv.Atoi(toStr)
+		if err != nil {
+			respondValidationError(c, models.NewApiErrorDetail("to", models.DetailCodeInvalid))
+			return
+		}
+
+		revision, err := h.revisionsRepo.FindByRevision(c, id, toRev)
+		if err != nil {
+			respondError(c, http.StatusNotFound, models.ErrCodeNotFound)
+			return
+		}
+
+		to = revision.Snapshot
+		response.To = toRev
+	} else {
+		to, err = h.findMovie(c, id)
+		if err != nil {
+			respondError(c, http.StatusNotFound, models.ErrCodeNotFound)
+			return
+		}
+	}
+
+	response.Before, response.After, err = models.AuditDiff(from.Snapshot, withoutViewerStats(to))
+	if err != nil {
+		respondInternalError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// Restore godoc
+// @Summary      Roll movie back to revision
+// @Description  Текущее состояние фильма перед откатом сохраняется как новая ревизия
+// @Tags         movies
+// @Accept       json
+// @Produce      json
+// @Param        id path int true "Movie id"
+// @Param        rev path int true "Revision number"
+// @Success      200  "OK"
+// @Failure      400  {object}  models.ApiError "Invalid data"
+// @Failure      404  {object}  models.ApiError "Revision not found"
+// @Failure      500  {object}  models.ApiError
+// @Router       /movies/{id}/revisions/{rev}/restore [post]
+// @Security Bearer
+func (h *MovieRevisionsHandlers) Restore(c *gin.Context) {
+	idStr := c.Param("id")
+	id, err := strconv.Atoi(idStr)
+	if err != nil {
+		respondError(c, http.StatusBadRequest, models.ErrCodeInvalidId)
+		return
+	}
+
+	rev, err := strconv.Atoi(c.Param("rev"))
+	if err != nil {
+		respondValidationError(c, models.NewApiErrorDetail("revision", models.DetailCodeInvalid))
+		return
+	}
+
+	before, err := h.findMovie(c, id)
+	if err != nil {
+		respondError(c, http.StatusNotFound, models.ErrCodeNotFound)
+		return
+	}
+
+	revision, err := h.revisionsRepo.FindByRevision(c, id, rev)
+	if err != nil {
+		respondError(c, http.StatusNotFound, models.ErrCodeNotFound)
+		return
+	}
+
+	// откатываемся поверх текущей версии; если фильм успели изменить, Update вернёт конфликт
+	revision.Snapshot.Version = before.Version
+
+	err = h.moviesRepo.Update(c, id, revision.Snapshot, newMovieRevision(c, before))
+	if isVersionConflict(err) {
+		respondVersionConflict(c)
+		return
+	}
+	if err != nil {
+		respondInternalError(c, err)
+		return
+	}
+
+	after, err := h.findMovie(c, id)
+	if err == nil {
+		recordAudit(c, h.auditRepo, models.AuditActionUpdate, models.AuditEntityMovie, id, before, after)
+	}
+
+	c.Status(http.StatusOK)
+}
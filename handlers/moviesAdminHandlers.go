@@ -8,7 +8,9 @@ import (
 	"mime/multipart"
 	"net/http"
 	"path/filepath"
+	"slices"
 	"strconv"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
@@ -21,34 +23,38 @@ type MovieAdminResponseHandler struct {
 	categoryRepo    *repositories.CategoryRepository
 	ageRepo         *repositories.AgeRepository
 	allserieRepo    *repositories.AllSeriesRepository
+	auditRepo       *repositories.AuditRepository
+	validator       *RequestValidator
+	mediaRepo       *repositories.MediaAssetsRepository
+	subtitlesRepo   *repositories.SubtitleTracksRepository
 }
 
 type createMovieAdminResponseRequest struct {
-	Title       string                `form:"title"`
-	Description string                `form:"description"`
-	ReleaseYear int                   `form:"releaseYear"`
-	Director    string                `form:"director"`
+	Title       string                `form:"title" validate:"required,max=255"`
+	Description string                `form:"description" validate:"required"`
+	ReleaseYear int                   `form:"releaseYear" validate:"required,year"`
+	Director    string                `form:"director" validate:"max=255"`
 	IsWatched   bool                  `form:"is_watched"`
-	TrailerUrl  string                `form:"trailerUrl"`
-	PosterUrl   *multipart.FileHeader `form:"posterUrl"`
-	GenreIds    []int                 `form:"genreIds"`
-	CategoryIds []int                 `form:"categoryIds"`
-	AgeIds      []int                 `form:"ageIds"`
-	AllserieIds []int                 `form:"allserieIds"`
+	TrailerUrl  string                `form:"trailerUrl" validate:"omitempty,trailer"`
+	PosterUrl   *multipart.FileHeader `form:"posterUrl" validate:"required"`
+	GenreIds    []int                 `form:"genreIds" validate:"min=1,unique,dive,gt=0,exists=genres"`
+	CategoryIds []int                 `form:"categoryIds" validate:"min=1,unique,dive,gt=0,exists=categories"`
+	AgeIds      []int                 `form:"ageIds" validate:"min=1,unique,dive,gt=0,exists=ages"`
+	AllserieIds []int                 `form:"allserieIds" validate:"unique,dive,gt=0,exists=allseries"`
 }
 
 type updateMovieAdminResponseRequest struct {
-	Title       string                `form:"title"`
-	Description string                `form:"description"`
-	ReleaseYear int                   `form:"releaseYear"`
-	Director    string                `form:"director"`
+	Title       string                `form:"title" validate:"required,max=255"`
+	Description string                `form:"description" validate:"required"`
+	ReleaseYear int                   `form:"releaseYear" validate:"required,year"`
+	Director    string                `form:"director" validate:"max=255"`
 	IsWatched   bool                  `form:"is_watched"`
-	TrailerUrl  string                `form:"trailerUrl"`
+	TrailerUrl  string                `form:"trailerUrl" validate:"omitempty,trailer"`
 	PosterUrl   *multipart.FileHeader `form:"posterUrl"`
-	GenreIds    []int                 `form:"genreIds"`
-	CategoryIds []int                 `form:"categoryIds"`
-	AgeIds      []int                 `form:"ageIds"`
-	AllserieIds []int                 `form:"allserieIds"`
+	GenreIds    []int                 `form:"genreIds" validate:"min=1,unique,dive,gt=0,exists=genres"`
+	CategoryIds []int                 `form:"categoryIds" validate:"min=1,unique,dive,gt=0,exists=categories"`
+	AgeIds      []int                 `form:"ageIds" validate:"min=1,unique,dive,gt=0,exists=ages"`
+	AllserieIds []int                 `form:"allserieIds" validate:"unique,dive,gt=0,exists=allseries"`
 }
 
 func NewMovieAdminResponseHandler(
@@ -57,6 +63,10 @@ func NewMovieAdminResponseHandler(
 	categoryRepo *repositories.CategoryRepository,
 	ageRepo *repositories.AgeRepository,
 	allserieRepo *repositories.AllSeriesRepository,
+	auditRepo *repositories.AuditRepository,
+	validator *RequestValidator,
+	mediaRepo *repositories.MediaAssetsRepository,
+	subtitlesRepo *repositories.SubtitleTracksRepository,
 ) *MovieAdminResponseHandler {
 	return &MovieAdminResponseHandler{
 		moviesAdminRepo: moviesAdminRepo,
@@ -64,6 +74,10 @@ func NewMovieAdminResponseHandler(
 		categoryRepo:    categoryRepo,
 		ageRepo:         ageRepo,
 		allserieRepo:    allserieRepo,
+		auditRepo:       auditRepo,
+		validator:       validator,
+		mediaRepo:       mediaRepo,
+		subtitlesRepo:   subtitlesRepo,
 	}
 }
 
@@ -75,22 +89,38 @@ func NewMovieAdminResponseHandler(
 // @Param        id path int true "Movie id"
 // @Success      200  {object}  models.MovieAdminResponse
 // @Failure      400  {object}  models.ApiError "Invalid Movie Id"
+// @Failure      404  {object}  models.ApiError "Not found"
+// @Failure      500  {object}  models.ApiError
 // @Router       /moviesAdmin/{id} [get]
 func (h *MovieAdminResponseHandler) FindById(c *gin.Context) {
 	idStr := c.Param("id")
 	id, err := strconv.Atoi(idStr)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, models.NewApiError("Invalid Movie Id"))
+		respondError(c, http.StatusBadRequest, models.ErrCodeInvalidId)
 
 		return
 	}
 
 	movie, err := h.moviesAdminRepo.FindById(c, id)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, models.NewApiError(err.Error()))
+		respondFindError(c, err)
+		return
+	}
+
+	movie.Media, err = h.mediaRepo.FindByMovie(c, id)
+	if err != nil {
+		respondInternalError(c, err)
+		return
+	}
+
+	tracks, err := h.subtitlesRepo.FindByMovie(c, id)
+	if err != nil {
+		respondInternalError(c, err)
 		return
 	}
+	movie.Subtitles = attachSubtitles(tracks, movie.AllSeries)
 
+	setETag(c, movie.Version)
 	c.JSON(http.StatusOK, movie)
 }
 
@@ -99,19 +129,29 @@ func (h *MovieAdminResponseHandler) FindById(c *gin.Context) {
 // @Tags         moviesAdmin
 // @Accept       json
 // @Produce      json
+// @Param        status query string false "draft, in_review, published or archived"
+// @Param        personId query int false "Only movies with this person in cast or crew"
 // @Success      200  {object}  []models.MovieAdminResponse "List of movies"
+// @Failure      400  {object}  models.ApiError "Invalid personId"
 // @Failure      500  {object}  models.ApiError "Internal Server Error"
 // @Router       /moviesAdmin [get]
 func (h *MovieAdminResponseHandler) FindAll(c *gin.Context) {
+	personId, ok := intQuery(c, "personId")
+	if !ok {
+		return
+	}
+
 	filters := models.MovieFilters{
 		SearchTerm: c.Query("search"),
 		IsWatched:  c.Query("iswatched"),
 		GenreId:    c.Query("genreids"),
+		PersonId:   personId,
+		Status:     c.Query("status"),
 		Sort:       c.Query("sort"),
 	}
 	movies, err := h.moviesAdminRepo.FindAll(c, filters)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, err)
+		respondInternalError(c, err)
 		return
 	}
 
@@ -127,12 +167,13 @@ func (h *MovieAdminResponseHandler) FindAll(c *gin.Context) {
 // @Param        description body string true "Description of the movie"
 // @Param        releaseYear body int true "ReleaseYear of the movie"
 // @Param        director body string true "Director"
-// @Param        trailerUrl body string true "TrailerUrl"
+// @Param        trailerUrl body string true "Trailer URL: YouTube, Vimeo or direct MP4/HLS"
 // @Param      	 genreIds body []int true "Genre ids"
 // @Param		 categoryIds body []int true "Category ids"
 // @Param        ageIds body []int true "Age ids"
 // @Success      200  {object}  object{id=int} "OK"
 // @Failure      400  {object}  models.ApiError "Could not bind json"
+// @Failure      422  {object}  models.ApiError "Validation failed"
 // @Failure      500  {object}  models.ApiError
 // @Router       /moviesAdmin [post]
 func (h *MovieAdminResponseHandler) Create(c *gin.Context) {
@@ -140,42 +181,41 @@ func (h *MovieAdminResponseHandler) Create(c *gin.Context) {
 
 	err := c.Bind(&request)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, models.NewApiError("Could not bind json"))
+		respondError(c, http.StatusBadRequest, models.ErrCodeInvalidPayload)
+		return
+	}
+
+	if !h.validator.Validate(c, &request) {
 		return
 	}
 
 	genres, err := h.genresRepo.FindAllByIds(c, request.GenreIds)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, models.NewApiError(err.Error()))
+		respondInternalError(c, err)
 		return
 	}
 
 	categories, err := h.categoryRepo.FindAllByIds(c, request.CategoryIds)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, models.NewApiError(err.Error()))
+		respondInternalError(c, err)
 		return
 	}
 
 	ages, err := h.ageRepo.FindAllByIds(c, request.AgeIds)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, models.NewApiError(err.Error()))
+		respondInternalError(c, err)
 		return
 	}
 
 	allserie, err := h.allserieRepo.FindAllByIds(c, request.AllserieIds)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, models.NewApiError(err.Error()))
-		return
-	}
-
-	if request.PosterUrl == nil {
-		c.JSON(http.StatusBadRequest, "Poster file is required")
+		respondInternalError(c, err)
 		return
 	}
 
 	filename, err := h.saveMoviesPoster(c, request.PosterUrl)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, models.NewApiError(err.Error()))
+		respondInternalError(c, err)
 		return
 	}
 
@@ -185,7 +225,7 @@ func (h *MovieAdminResponseHandler) Create(c *gin.Context) {
 		ReleaseYear: request.ReleaseYear,
 		Director:    request.Director,
 		IsWatched:   request.IsWatched,
-		TrailerUrl:  request.TrailerUrl,
+		TrailerUrl:  models.NormalizeTrailerUrl(request.TrailerUrl),
 		PosterUrl:   filename,
 		Genres:      genres,
 		Category:    categories,
@@ -197,13 +237,16 @@ func (h *MovieAdminResponseHandler) Create(c *gin.Context) {
 
 	id, err := h.moviesAdminRepo.Create(c, movies)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, err)
+		respondInternalError(c, err)
 		return
 	}
 
 	logger := logger.GetLogger()
 	logger.Info("Movie has been created", zap.Int("movie_id", id))
 
+	movies.Id = id
+	recordAudit(c, h.auditRepo, models.AuditActionCreate, models.AuditEntityMovie, id, nil, movies)
+
 	c.JSON(http.StatusOK, gin.H{
 		"id": id,
 	})
@@ -226,62 +269,84 @@ func (h *MovieAdminResponseHandler) saveMoviesPoster(c *gin.Context, poster *mul
 // @Param        description body string true "Description of the movie"
 // @Param        releaseYear body int true "ReleaseYear of the movie"
 // @Param        director body string true "Director"
-// @Param        trailerUrl body string true "TrailerUrl"
+// @Param        trailerUrl body string true "Trailer URL: YouTube, Vimeo or direct MP4/HLS"
 // @Param      	 genreIds body []int true "Genre ids"
 // @Param		 categoryIds body []int true "Category ids"
 // @Param        ageIds body []int true "Age ids"
 // @Success      200  {object}  object{id=int} "OK"
 // @Failure      400  {object}  models.ApiError "Could not bind json"
 // @Failure      500  {object}  models.ApiError
+// @Param        If-Match header string true "ETag of the current version"
+// @Failure      412  {object}  models.ApiError "Resource was modified"
+// @Failure      428  {object}  models.ApiError "If-Match header required"
+// @Failure      404  {object}  models.ApiError "Not found"
+// @Failure      422  {object}  models.ApiError "Validation failed"
 // @Router       /moviesAdmin/{id} [put]
 func (h *MovieAdminResponseHandler) Update(c *gin.Context) {
 	idStr := c.Param("id")
 	id, err := strconv.Atoi(idStr)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, models.NewApiError("Invalid Movie Id"))
+		respondError(c, http.StatusBadRequest, models.ErrCodeInvalidId)
 		return
 	}
 
-	_, err = h.moviesAdminRepo.FindById(c, id)
+	before, err := h.moviesAdminRepo.FindById(c, id)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, models.NewApiError(err.Error()))
+		respondFindError(c, err)
+		return
+	}
+
+	version, ok := ifMatchVersion(c)
+	if !ok {
+		return
+	}
+
+	if !checkIfMatch(c, version, before.Version) {
 		return
 	}
 
 	var request updateMovieAdminResponseRequest
 	err = c.Bind(&request)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, models.NewApiError("Could not bind json"))
+		respondError(c, http.StatusBadRequest, models.ErrCodeInvalidPayload)
+		return
+	}
+
+	if !h.validator.Validate(c, &request) {
 		return
 	}
 
 	genres, err := h.genresRepo.FindAllByIds(c, request.GenreIds)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, models.NewApiError(err.Error()))
+		respondInternalError(c, err)
 		return
 	}
 	categories, err := h.categoryRepo.FindAllByIds(c, request.CategoryIds)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, models.NewApiError(err.Error()))
+		respondInternalError(c, err)
 		return
 	}
 
 	ages, err := h.ageRepo.FindAllByIds(c, request.AgeIds)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, models.NewApiError(err.Error()))
+		respondInternalError(c, err)
 		return
 	}
 
-	allseries, err := h.allserieRepo.FindAllByIds(c, request.AgeIds)
+	allseries, err := h.allserieRepo.FindAllByIds(c, request.AllserieIds)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, models.NewApiError(err.Error()))
+		respondInternalError(c, err)
 		return
 	}
 
-	filename, err := h.saveMoviesPoster(c, request.PosterUrl)
-	if err != nil {
-		c.JSON(http.StatusInternalServerError, models.NewApiError(err.Error()))
-		return
+	// без нового файла постер остаётся прежним
+	filename := before.PosterUrl
+	if request.PosterUrl != nil {
+		filename, err = h.saveMoviesPoster(c, request.PosterUrl)
+		if err != nil {
+			respondInternalError(c, err)
+			return
+		}
 	}
 
 	movie := models.MovieAdminResponse{
@@ -290,20 +355,33 @@ func (h *MovieAdminResponseHandler) Update(c *gin.Context) {
 		ReleaseYear: request.ReleaseYear,
 		Director:    request.Director,
 		IsWatched:   request.IsWatched,
-		TrailerUrl:  request.TrailerUrl,
+		TrailerUrl:  models.NormalizeTrailerUrl(request.TrailerUrl),
 		PosterUrl:   filename,
 		Genres:      genres,
 		Category:    categories,
 		Ages:        ages,
 		AllSeries:   allseries,
+
+		Version: version,
 	}
 
-	err = h.moviesAdminRepo.Update(c, id, movie)
+	err = h.moviesAdminRepo.Update(c, id, movie, newMovieRevision(c, models.Movie(before)))
+	if isVersionConflict(err) {
+		respondVersionConflict(c)
+		return
+	}
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, models.NewApiError("Failed to update movie"))
+		respondInternalError(c, err)
 		return
 	}
 
+	after, err := h.moviesAdminRepo.FindById(c, id)
+	if err == nil {
+		recordAudit(c, h.auditRepo, models.AuditActionUpdate, models.AuditEntityMovie, id, before, after)
+	}
+
+	setETag(c, version+1)
+
 	c.Status(http.StatusOK)
 }
 
@@ -316,22 +394,45 @@ func (h *MovieAdminResponseHandler) Update(c *gin.Context) {
 // @Success      200  "OK"
 // @Failure   	 400  {object} models.ApiError "Invalid data"
 // @Failure   	 500  {object} models.ApiError
+// @Param        If-Match header string true "ETag of the current version"
+// @Failure      412  {object}  models.ApiError "Resource was modified"
+// @Failure      428  {object}  models.ApiError "If-Match header required"
+// @Failure      404  {object}  models.ApiError "Not found"
 // @Router       /moviesAdmin/{id} [delete]
 func (h *MovieAdminResponseHandler) Delete(c *gin.Context) {
 	idStr := c.Param("id")
 	id, err := strconv.Atoi(idStr)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, models.NewApiError("Invalid Movie Id"))
+		respondError(c, http.StatusBadRequest, models.ErrCodeInvalidId)
+		return
+	}
+
+	before, err := h.moviesAdminRepo.FindById(c, id)
+	if err != nil {
+		respondFindError(c, err)
 		return
 	}
 
-	_, err = h.moviesAdminRepo.FindById(c, id)
+	version, ok := ifMatchVersion(c)
+	if !ok {
+		return
+	}
+
+	if !checkIfMatch(c, version, before.Version) {
+		return
+	}
+
+	err = h.moviesAdminRepo.Delete(c, id, version)
+	if isVersionConflict(err) {
+		respondVersionConflict(c)
+		return
+	}
 	if err != nil {
-		c.JSON(http.StatusBadRequest, models.NewApiError(err.Error()))
+		respondInternalError(c, err)
 		return
 	}
 
-	h.moviesAdminRepo.Delete(c, id)
+	recordAudit(c, h.auditRepo, models.AuditActionDelete, models.AuditEntityMovie, id, before, nil)
 
 	c.Status(http.StatusNoContent)
 }
@@ -345,28 +446,143 @@ func (h *MovieAdminResponseHandler) Delete(c *gin.Context) {
 // @Success      200  "OK"
 // @Failure   	 400  {object} models.ApiError "Invalid data"
 // @Failure   	 500  {object} models.ApiError
+// @Param        If-Match header string true "ETag of the current version"
+// @Failure      412  {object}  models.ApiError "Resource was modified"
+// @Failure      428  {object}  models.ApiError "If-Match header required"
 // @Router       /moviesAdmin/{id}/setWatched [patch]
 // @Security Bearer
 func (h *MovieAdminResponseHandler) HandleSetWatched(c *gin.Context) {
 	idStr := c.Param("movieId")
 	id, err := strconv.Atoi(idStr)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, models.NewApiError("Invalid Movie Id"))
+		respondError(c, http.StatusBadRequest, models.ErrCodeInvalidId)
 		return
 	}
 
 	isWatchedStr := c.Query("isWatched")
 	isWatched, err := strconv.ParseBool(isWatchedStr)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, models.NewApiError("Invalid isWatched value"))
+		respondValidationError(c, models.NewApiErrorDetail("iswatched", models.DetailCodeInvalid))
+		return
+	}
+
+	version, ok := ifMatchVersion(c)
+	if !ok {
+		return
+	}
+
+	err = h.moviesAdminRepo.SetWatched(c, id, isWatched, version)
+	if isVersionConflict(err) {
+		respondVersionConflict(c)
+		return
+	}
+	if err != nil {
+		respondInternalError(c, err)
 		return
 	}
 
-	err = h.moviesAdminRepo.SetWatched(c, id, isWatched)
+	recordAudit(c, h.auditRepo, models.AuditActionUpdate, models.AuditEntityMovie, id, nil, gin.H{"IsWatched": isWatched})
+
+	setETag(c, version+1)
+
+	c.Status(http.StatusOK)
+}
+
+type setMovieStatusRequest struct {
+	Status string
+	// только для published: если время в будущем, фильм опубликует планировщик
+	PublishAt *time.Time
+}
+
+// HandleSetStatus godoc
+// @Summary      Change movie publication status
+// @Description  draft, in_review, published, archived. Публикация с publishAt в будущем ставит фильм в расписание
+// @Tags         moviesAdmin
+// @Accept       json
+// @Produce      json
+// @Param movieId path int true "Movie id"
+// @Param request body handlers.setMovieStatusRequest true "Status data"
+// @Success      200  "OK"
+// @Failure   	 400  {object} models.ApiError "Invalid data"
+// @Failure   	 404  {object} models.ApiError "Movie not found"
+// @Failure   	 409  {object} models.ApiError "Transition is not allowed"
+// @Failure   	 500  {object} models.ApiError
+// @Param        If-Match header string true "ETag of the current version"
+// @Failure      412  {object}  models.ApiError "Resource was modified"
+// @Failure      428  {object}  models.ApiError "If-Match header required"
+// @Router       /moviesAdmin/{movieId}/status [patch]
+// @Security Bearer
+func (h *MovieAdminResponseHandler) HandleSetStatus(c *gin.Context) {
+	idStr := c.Param("movieId")
+	id, err := strconv.Atoi(idStr)
+	if err != nil {
+		respondError(c, http.StatusBadRequest, models.ErrCodeInvalidId)
+		return
+	}
+
+	var request setMovieStatusRequest
+	err = c.BindJSON(&request)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, models.NewApiError(err.Error()))
+		respondError(c, http.StatusBadRequest, models.ErrCodeInvalidPayload)
 		return
 	}
 
+	if _, ok := models.MovieStatusTransitions[request.Status]; !ok {
+		respondValidationError(c, models.NewApiErrorDetail("status", models.DetailCodeInvalid))
+		return
+	}
+
+	movie, err := h.moviesAdminRepo.FindById(c, id)
+	if err != nil {
+		respondError(c, http.StatusNotFound, models.ErrCodeNotFound)
+		return
+	}
+
+	version, ok := ifMatchVersion(c)
+	if !ok {
+		return
+	}
+
+	if !checkIfMatch(c, version, movie.Version) {
+		return
+	}
+
+	if request.Status != movie.Status && !slices.Contains(models.MovieStatusTransitions[movie.Status], request.Status) {
+		respondError(c, http.StatusConflict, models.ErrCodeInvalidTransition)
+		return
+	}
+
+	status := request.Status
+	publishAt := request.PublishAt
+	switch status {
+	case models.MovieStatusPublished:
+		now := time.Now()
+		if publishAt != nil && publishAt.After(now) {
+			// до наступления publishAt фильм ждёт на проверке
+			status = models.MovieStatusInReview
+		} else if publishAt == nil {
+			publishAt = &now
+		}
+	default:
+		// расписание задаётся только публикацией, иначе планировщик опубликует фильм в обход проверки
+		publishAt = nil
+	}
+
+	err = h.moviesAdminRepo.SetStatus(c, id, status, publishAt, version)
+	if isVersionConflict(err) {
+		respondVersionConflict(c)
+		return
+	}
+	if err != nil {
+		respondInternalError(c, err)
+		return
+	}
+
+	recordAudit(c, h.auditRepo, models.AuditActionUpdate, models.AuditEntityMovie, id,
+		gin.H{"Status": movie.Status, "PublishAt": movie.PublishAt},
+		gin.H{"Status": status, "PublishAt": publishAt})
+
+	setETag(c, version+1)
+
 	c.Status(http.StatusOK)
 }
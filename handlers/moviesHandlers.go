@@ -16,35 +16,42 @@ import (
 )
 
 type MoviesHandler struct {
-	moviesRepo   *repositories.MoviesRepository
-	genresRepo   *repositories.GenresRepository
-	categoryRepo *repositories.CategoryRepository
-	ageRepo      *repositories.AgeRepository
+	moviesRepo       *repositories.MoviesRepository
+	genresRepo       *repositories.GenresRepository
+	categoryRepo     *repositories.CategoryRepository
+	ageRepo          *repositories.AgeRepository
+	auditRepo        *repositories.AuditRepository
+	translationsRepo *repositories.TranslationsRepository
+	validator        *RequestValidator
+	mediaRepo        *repositories.MediaAssetsRepository
+	subtitlesRepo    *repositories.SubtitleTracksRepository
+	parentalRepo     *repositories.ParentalControlsRepository
+	moviesAdminRepo  *repositories.MoviesAdminRepository
 }
 
 type createMovieRequest struct {
-	Title       string                `form:"title"`
-	Description string                `form:"description"`
-	ReleaseYear int                   `form:"releaseYear"`
-	Director    string                `form:"director"`
-	TrailerUrl  string                `form:"trailerUrl"`
-	PosterUrl   *multipart.FileHeader `form:"posterUrl"`
-	GenreIds    []int                 `form:"genreIds"`
-	CategoryIds []int                 `form:"categoryIds"`
-	AgeIds      []int                 `form:"ageIds"`
-	AllserieIds []int                 `form:"allserieIds"`
+	Title       string                `form:"title" validate:"required,max=255"`
+	Description string                `form:"description" validate:"required"`
+	ReleaseYear int                   `form:"releaseYear" validate:"required,year"`
+	Director    string                `form:"director" validate:"max=255"`
+	TrailerUrl  string                `form:"trailerUrl" validate:"omitempty,trailer"`
+	PosterUrl   *multipart.FileHeader `form:"posterUrl" validate:"required"`
+	GenreIds    []int                 `form:"genreIds" validate:"min=1,unique,dive,gt=0,exists=genres"`
+	CategoryIds []int                 `form:"categoryIds" validate:"min=1,unique,dive,gt=0,exists=categories"`
+	AgeIds      []int                 `form:"ageIds" validate:"min=1,unique,dive,gt=0,exists=ages"`
+	AllserieIds []int                 `form:"allserieIds" validate:"unique,dive,gt=0,exists=allseries"`
 }
 
 type updateMovieRequest struct {
-	Title       string                `form:"title"`
-	Description string                `form:"description"`
-	ReleaseYear int                   `form:"releaseYear"`
-	Director    string                `form:"director"`
-	TrailerUrl  string                `form:"trailerUrl"`
+	Title       string                `form:"title" validate:"required,max=255"`
+	Description string                `form:"description" validate:"required"`
+	ReleaseYear int                   `form:"releaseYear" validate:"required,year"`
+	Director    string                `form:"director" validate:"max=255"`
+	TrailerUrl  string                `form:"trailerUrl" validate:"omitempty,trailer"`
 	PosterUrl   *multipart.FileHeader `form:"posterUrl"`
-	GenreIds    []int                 `form:"genreIds"`
-	CategoryIds []int                 `form:"categoryIds"`
-	AgeIds      []int                 `form:"ageIds"`
+	GenreIds    []int                 `form:"genreIds" validate:"min=1,unique,dive,gt=0,exists=genres"`
+	CategoryIds []int                 `form:"categoryIds" validate:"min=1,unique,dive,gt=0,exists=categories"`
+	AgeIds      []int                 `form:"ageIds" validate:"min=1,unique,dive,gt=0,exists=ages"`
 }
 
 func NewMoviesHandler(
@@ -52,39 +59,90 @@ func NewMoviesHandler(
 	genreRepo *repositories.GenresRepository,
 	categoryRepo *repositories.CategoryRepository,
 	ageRepo *repositories.AgeRepository,
+	auditRepo *repositories.AuditRepository,
+	translationsRepo *repositories.TranslationsRepository,
+	validator *RequestValidator,
+	mediaRepo *repositories.MediaAssetsRepository,
+	subtitlesRepo *repositories.SubtitleTracksRepository,
+	parentalRepo *repositories.ParentalControlsRepository,
+	moviesAdminRepo *repositories.MoviesAdminRepository,
 ) *MoviesHandler {
 	return &MoviesHandler{
-		moviesRepo:   moviesRepo,
-		genresRepo:   genreRepo,
-		categoryRepo: categoryRepo,
-		ageRepo:      ageRepo,
+		moviesRepo:       moviesRepo,
+		genresRepo:       genreRepo,
+		categoryRepo:     categoryRepo,
+		ageRepo:          ageRepo,
+		auditRepo:        auditRepo,
+		translationsRepo: translationsRepo,
+		validator:        validator,
+		mediaRepo:        mediaRepo,
+		subtitlesRepo:    subtitlesRepo,
+		parentalRepo:     parentalRepo,
+		moviesAdminRepo:  moviesAdminRepo,
 	}
 }
 
+// findMovie — фильм в любом статусе: редактировать и удалять можно и черновики, и снятые с публикации
+func (h *MoviesHandler) findMovie(c *gin.Context, id int) (models.Movie, error) {
+	movie, err := h.moviesAdminRepo.FindById(c, id)
+
+	return models.Movie(movie), err
+}
+
 // FindById godoc
 // @Summary      Find by id
 // @Tags         movies
 // @Accept       json
 // @Produce      json
 // @Param        id path int true "Movie id"
+// @Param        lang query string false "kk, ru or en"
+// @Param        Accept-Language header string false "Preferred locales"
 // @Success      200  {object}  models.Movie "Ok"
 // @Failure      400  {object}  models.ApiError "Invalid Movie Id"
+// @Failure      403  {object}  models.ApiError "Restricted by parental controls"
+// @Failure      500  {object}  models.ApiError
+// @Failure      404  {object}  models.ApiError "Not found"
 // @Router       /movies/{id} [get]
 func (h *MoviesHandler) FindById(c *gin.Context) {
 	idStr := c.Param("id")
 	id, err := strconv.Atoi(idStr)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, models.NewApiError("Invalid Movie Id"))
+		respondError(c, http.StatusBadRequest, models.ErrCodeInvalidId)
 
 		return
 	}
 
-	movie, err := h.moviesRepo.FindById(c, id)
+	if !checkMovieAllowed(c, h.parentalRepo, id) {
+		return
+	}
+
+	movie, err := h.moviesRepo.FindByIdForViewer(c, id, viewerUserId(c), currentProfileId(c))
 	if err != nil {
-		c.JSON(http.StatusBadRequest, models.NewApiError(err.Error()))
+		respondFindError(c, err)
 		return
 	}
 
+	movie.Media, err = h.mediaRepo.FindByMovie(c, id)
+	if err != nil {
+		respondInternalError(c, err)
+		return
+	}
+
+	tracks, err := h.subtitlesRepo.FindByMovie(c, id)
+	if err != nil {
+		respondInternalError(c, err)
+		return
+	}
+	movie.Subtitles = attachSubtitles(tracks, movie.AllSeries)
+
+	loc := newLocalizer(c, h.translationsRepo)
+	loc.addMovie(&movie)
+	if err := loc.apply(c); err != nil {
+		respondInternalError(c, err)
+		return
+	}
+
+	setETag(c, movie.Version)
 	c.JSON(http.StatusOK, movie)
 }
 
@@ -93,19 +151,41 @@ func (h *MoviesHandler) FindById(c *gin.Context) {
 // @Tags         movies
 // @Accept       json
 // @Produce      json
+// @Param        personId query int false "Only movies with this person in cast or crew"
+// @Param        lang query string false "kk, ru or en"
+// @Param        Accept-Language header string false "Preferred locales"
 // @Success      200  {object}  []models.Movie "List of movies"
+// @Failure      400  {object}  models.ApiError "Invalid personId"
 // @Failure      500  {object}  models.ApiError "Internal Server Error"
 // @Router       /movies [get]
 func (h *MoviesHandler) FindAll(c *gin.Context) {
+	personId, ok := intQuery(c, "personId")
+	if !ok {
+		return
+	}
+
 	filters := models.MovieFilters{
 		SearchTerm: c.Query("search"),
 		IsWatched:  c.Query("iswatched"),
 		GenreId:    c.Query("genreids"),
+		PersonId:   personId,
 		Sort:       c.Query("sort"),
+		MaxAge:     ageLimit(c),
+		UserId:     viewerUserId(c),
+		ProfileId:  currentProfileId(c),
 	}
 	movies, err := h.moviesRepo.FindAll(c, filters)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, err)
+		respondInternalError(c, err)
+		return
+	}
+
+	loc := newLocalizer(c, h.translationsRepo)
+	for i := range movies {
+		loc.addMovie(&movies[i])
+	}
+	if err := loc.apply(c); err != nil {
+		respondInternalError(c, err)
 		return
 	}
 
@@ -121,12 +201,13 @@ func (h *MoviesHandler) FindAll(c *gin.Context) {
 // @Param        description body string true "Description of the movie"
 // @Param        releaseYear body int true "ReleaseYear of the movie"
 // @Param        director body string true "Director"
-// @Param        trailerUrl body string true "TrailerUrl"
+// @Param        trailerUrl body string true "Trailer URL: YouTube, Vimeo or direct MP4/HLS"
 // @Param      	 genreIds body []int true "Genre ids"
 // @Param		 categoryIds body []int true "Category ids"
 // @Param        ageIds body []int true "Age ids"
 // @Success      200  {object}  object{id=int} "OK"
 // @Failure      400  {object}  models.ApiError "Could not bind json"
+// @Failure      422  {object}  models.ApiError "Validation failed"
 // @Failure      500  {object}  models.ApiError
 // @Router       /movies [post]
 func (h *MoviesHandler) Create(c *gin.Context) {
@@ -134,36 +215,35 @@ func (h *MoviesHandler) Create(c *gin.Context) {
 
 	err := c.Bind(&request)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, models.NewApiError("Could not bind json"))
+		respondError(c, http.StatusBadRequest, models.ErrCodeInvalidPayload)
+		return
+	}
+
+	if !h.validator.Validate(c, &request) {
 		return
 	}
 
 	genres, err := h.genresRepo.FindAllByIds(c, request.GenreIds)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, models.NewApiError(err.Error()))
+		respondInternalError(c, err)
 		return
 	}
 
 	categories, err := h.categoryRepo.FindAllByIds(c, request.CategoryIds)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, models.NewApiError(err.Error()))
+		respondInternalError(c, err)
 		return
 	}
 
 	ages, err := h.ageRepo.FindAllByIds(c, request.AgeIds)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, models.NewApiError(err.Error()))
-		return
-	}
-
-	if request.PosterUrl == nil {
-		c.JSON(http.StatusBadRequest, "Poster file is required")
+		respondInternalError(c, err)
 		return
 	}
 
 	filename, err := h.saveMoviesPoster(c, request.PosterUrl)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, models.NewApiError(err.Error()))
+		respondInternalError(c, err)
 		return
 	}
 
@@ -172,7 +252,7 @@ func (h *MoviesHandler) Create(c *gin.Context) {
 		Description: request.Description,
 		ReleaseYear: request.ReleaseYear,
 		Director:    request.Director,
-		TrailerUrl:  request.TrailerUrl,
+		TrailerUrl:  models.NormalizeTrailerUrl(request.TrailerUrl),
 		PosterUrl:   filename,
 		Genres:      genres,
 		Category:    categories,
@@ -181,13 +261,16 @@ func (h *MoviesHandler) Create(c *gin.Context) {
 
 	id, err := h.moviesRepo.Create(c, movie)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, err)
+		respondInternalError(c, err)
 		return
 	}
 
 	logger := logger.GetLogger()
 	logger.Info("Movie has been created", zap.Int("movie_id", id))
 
+	movie.Id = id
+	recordAudit(c, h.auditRepo, models.AuditActionCreate, models.AuditEntityMovie, id, nil, movie)
+
 	c.JSON(http.StatusOK, gin.H{
 		"id": id,
 	})
@@ -210,56 +293,78 @@ func (h *MoviesHandler) saveMoviesPoster(c *gin.Context, poster *multipart.FileH
 // @Param        description body string true "Description of the movie"
 // @Param        releaseYear body int true "ReleaseYear of the movie"
 // @Param        director body string true "Director"
-// @Param        trailerUrl body string true "TrailerUrl"
+// @Param        trailerUrl body string true "Trailer URL: YouTube, Vimeo or direct MP4/HLS"
 // @Param      	 genreIds body []int true "Genre ids"
 // @Param		 categoryIds body []int true "Category ids"
 // @Param        ageIds body []int true "Age ids"
 // @Success      200  {object}  object{id=int} "OK"
 // @Failure      400  {object}  models.ApiError "Could not bind json"
 // @Failure      500  {object}  models.ApiError
+// @Param        If-Match header string true "ETag of the current version"
+// @Failure      412  {object}  models.ApiError "Resource was modified"
+// @Failure      428  {object}  models.ApiError "If-Match header required"
+// @Failure      404  {object}  models.ApiError "Not found"
+// @Failure      422  {object}  models.ApiError "Validation failed"
 // @Router       /movies/{id} [put]
 func (h *MoviesHandler) Update(c *gin.Context) {
 	idStr := c.Param("id")
 	id, err := strconv.Atoi(idStr)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, models.NewApiError("Invalid Movie Id"))
+		respondError(c, http.StatusBadRequest, models.ErrCodeInvalidId)
 		return
 	}
 
-	_, err = h.moviesRepo.FindById(c, id)
+	before, err := h.findMovie(c, id)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, models.NewApiError(err.Error()))
+		respondFindError(c, err)
+		return
+	}
+
+	version, ok := ifMatchVersion(c)
+	if !ok {
+		return
+	}
+
+	if !checkIfMatch(c, version, before.Version) {
 		return
 	}
 
 	var request updateMovieRequest
 	err = c.Bind(&request)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, models.NewApiError("Could not bind json"))
+		respondError(c, http.StatusBadRequest, models.ErrCodeInvalidPayload)
+		return
+	}
+
+	if !h.validator.Validate(c, &request) {
 		return
 	}
 
 	genres, err := h.genresRepo.FindAllByIds(c, request.GenreIds)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, models.NewApiError(err.Error()))
+		respondInternalError(c, err)
 		return
 	}
 	categories, err := h.categoryRepo.FindAllByIds(c, request.CategoryIds)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, models.NewApiError(err.Error()))
+		respondInternalError(c, err)
 		return
 	}
 
 	ages, err := h.ageRepo.FindAllByIds(c, request.AgeIds)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, models.NewApiError(err.Error()))
+		respondInternalError(c, err)
 		return
 	}
 
-	filename, err := h.saveMoviesPoster(c, request.PosterUrl)
-	if err != nil {
-		c.JSON(http.StatusInternalServerError, models.NewApiError(err.Error()))
-		return
+	// без нового файла постер остаётся прежним
+	filename := before.PosterUrl
+	if request.PosterUrl != nil {
+		filename, err = h.saveMoviesPoster(c, request.PosterUrl)
+		if err != nil {
+			respondInternalError(c, err)
+			return
+		}
 	}
 
 	movie := models.Movie{
@@ -267,19 +372,32 @@ func (h *MoviesHandler) Update(c *gin.Context) {
 		Description: request.Description,
 		ReleaseYear: request.ReleaseYear,
 		Director:    request.Director,
-		TrailerUrl:  request.TrailerUrl,
+		TrailerUrl:  models.NormalizeTrailerUrl(request.TrailerUrl),
 		PosterUrl:   filename,
 		Genres:      genres,
 		Category:    categories,
 		Ages:        ages,
+
+		Version: version,
 	}
 
-	err = h.moviesRepo.Update(c, id, movie)
+	err = h.moviesRepo.Update(c, id, movie, newMovieRevision(c, before))
+	if isVersionConflict(err) {
+		respondVersionConflict(c)
+		return
+	}
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, models.NewApiError("Failed to update movie"))
+		respondInternalError(c, err)
 		return
 	}
 
+	after, err := h.findMovie(c, id)
+	if err == nil {
+		recordAudit(c, h.auditRepo, models.AuditActionUpdate, models.AuditEntityMovie, id, before, after)
+	}
+
+	setETag(c, version+1)
+
 	c.Status(http.StatusOK)
 }
 
@@ -292,22 +410,250 @@ func (h *MoviesHandler) Update(c *gin.Context) {
 // @Success      200  "OK"
 // @Failure   	 400  {object} models.ApiError "Invalid data"
 // @Failure   	 500  {object} models.ApiError
+// @Param        If-Match header string true "ETag of the current version"
+// @Failure      412  {object}  models.ApiError "Resource was modified"
+// @Failure      428  {object}  models.ApiError "If-Match header required"
+// @Failure      404  {object}  models.ApiError "Not found"
 // @Router       /movies/{id} [delete]
 func (h *MoviesHandler) Delete(c *gin.Context) {
 	idStr := c.Param("id")
 	id, err := strconv.Atoi(idStr)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, models.NewApiError("Invalid Movie Id"))
+		respondError(c, http.StatusBadRequest, models.ErrCodeInvalidId)
 		return
 	}
 
-	_, err = h.moviesRepo.FindById(c, id)
+	before, err := h.findMovie(c, id)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, models.NewApiError(err.Error()))
+		respondFindError(c, err)
 		return
 	}
 
-	h.moviesRepo.Delete(c, id)
+	version, ok := ifMatchVersion(c)
+	if !ok {
+		return
+	}
+
+	if !checkIfMatch(c, version, before.Version) {
+		return
+	}
+
+	err = h.moviesRepo.Delete(c, id, version)
+	if isVersionConflict(err) {
+		respondVersionConflict(c)
+		return
+	}
+	if err != nil {
+		respondInternalError(c, err)
+		return
+	}
+
+	recordAudit(c, h.auditRepo, models.AuditActionDelete, models.AuditEntityMovie, id, before, nil)
 
 	c.Status(http.StatusNoContent)
 }
+
+type moviePatchDocument struct {
+	Title       string `json:"title" validate:"required,max=255"`
+	Description string `json:"description" validate:"required"`
+	ReleaseYear int    `json:"releaseYear" validate:"required,year"`
+	Director    string `json:"director" validate:"max=255"`
+	TrailerUrl  string `json:"trailerUrl" validate:"omitempty,trailer"`
+	GenreIds    []int  `json:"genreIds" validate:"min=1,unique,dive,gt=0,exists=genres"`
+	CategoryIds []int  `json:"categoryIds" validate:"min=1,unique,dive,gt=0,exists=categories"`
+	AgeIds      []int  `json:"ageIds" validate:"min=1,unique,dive,gt=0,exists=ages"`
+}
+
+func newMoviePatchDocument(movie models.Movie) moviePatchDocument {
+	document := moviePatchDocument{
+		Title:       movie.Title,
+		Description: movie.Description,
+		ReleaseYear: movie.ReleaseYear,
+		Director:    movie.Director,
+		TrailerUrl:  movie.TrailerUrl,
+		GenreIds:    make([]int, 0, len(movie.Genres)),
+		CategoryIds: make([]int, 0, len(movie.Category)),
+		AgeIds:      make([]int, 0, len(movie.Ages)),
+	}
+
+	for _, genre := range movie.Genres {
+		document.GenreIds = append(document.GenreIds, genre.Id)
+	}
+	for _, category := range movie.Category {
+		document.CategoryIds = append(document.CategoryIds, category.Id)
+	}
+	for _, age := range movie.Ages {
+		document.AgeIds = append(document.AgeIds, age.Id)
+	}
+
+	return document
+}
+
+// Patch godoc
+// @Summary      Partially update movie
+// @Description  JSON Merge Patch (RFC 7396): меняются только переданные поля и связи. Постер обновляется через PUT /movies/{id}/poster
+// @Tags         movies
+// @Accept       application/merge-patch+json
+// @Produce      json
+// @Param        id path int true "Movie id"
+// @Param        request body handlers.moviePatchDocument true "Merge patch"
+// @Param        If-Match header string true "ETag of the current version"
+// @Success      200  {object}  models.Movie "OK"
+// @Failure      400  {object}  models.ApiError "Invalid data"
+// @Failure      404  {object}  models.ApiError "Movie not found"
+// @Failure      412  {object}  models.ApiError "Resource was modified"
+// @Failure      415  {object}  models.ApiError "Unsupported Content-Type"
+// @Failure      428  {object}  models.ApiError "If-Match header required"
+// @Failure      422  {object}  models.ApiError "Validation failed"
+// @Failure      500  {object}  models.ApiError
+// @Router       /movies/{id} [patch]
+// @Security Bearer
+func (h *MoviesHandler) Patch(c *gin.Context) {
+	idStr := c.Param("id")
+	id, err := strconv.Atoi(idStr)
+	if err != nil {
+		respondError(c, http.StatusBadRequest, models.ErrCodeInvalidId)
+		return
+	}
+
+	before, err := h.findMovie(c, id)
+	if err != nil {
+		respondError(c, http.StatusNotFound, models.ErrCodeNotFound)
+		return
+	}
+
+	version, ok := ifMatchVersion(c)
+	if !ok {
+		return
+	}
+
+	if !checkIfMatch(c, version, before.Version) {
+		return
+	}
+
+	document := newMoviePatchDocument(before)
+	err = applyMergePatch(c, &document)
+	if err != nil {
+		respondPatchError(c, err)
+		return
+	}
+
+	if !h.validator.Validate(c, &document) {
+		return
+	}
+
+	genres, err := h.genresRepo.FindAllByIds(c, document.GenreIds)
+	if err != nil {
+		respondInternalError(c, err)
+		return
+	}
+
+	categories, err := h.categoryRepo.FindAllByIds(c, document.CategoryIds)
+	if err != nil {
+		respondInternalError(c, err)
+		return
+	}
+
+	ages, err := h.ageRepo.FindAllByIds(c, document.AgeIds)
+	if err != nil {
+		respondInternalError(c, err)
+		return
+	}
+
+	movie := models.Movie{
+		Title:       document.Title,
+		Description: document.Description,
+		ReleaseYear: document.ReleaseYear,
+		Director:    document.Director,
+		TrailerUrl:  models.NormalizeTrailerUrl(document.TrailerUrl),
+		PosterUrl:   before.PosterUrl,
+		Genres:      genres,
+		Category:    categories,
+		Ages:        ages,
+		Version:     version,
+	}
+
+	h.saveMovie(c, id, before, movie)
+}
+
+// UpdatePoster godoc
+// @Summary      Upload movie poster
+// @Tags         movies
+// @Accept       multipart/form-data
+// @Produce      json
+// @Param        id path int true "Movie id"
+// @Param        poster formData file true "Poster image"
+// @Param        If-Match header string true "ETag of the current version"
+// @Success      200  {object}  models.Movie "OK"
+// @Failure      400  {object}  models.ApiError "Invalid data"
+// @Failure      404  {object}  models.ApiError "Movie not found"
+// @Failure      412  {object}  models.ApiError "Resource was modified"
+// @Failure      428  {object}  models.ApiError "If-Match header required"
+// @Failure      500  {object}  models.ApiError
+// @Router       /movies/{id}/poster [put]
+// @Security Bearer
+func (h *MoviesHandler) UpdatePoster(c *gin.Context) {
+	idStr := c.Param("id")
+	id, err := strconv.Atoi(idStr)
+	if err != nil {
+		respondError(c, http.StatusBadRequest, models.ErrCodeInvalidId)
+		return
+	}
+
+	before, err := h.findMovie(c, id)
+	if err != nil {
+		respondError(c, http.StatusNotFound, models.ErrCodeNotFound)
+		return
+	}
+
+	version, ok := ifMatchVersion(c)
+	if !ok {
+		return
+	}
+
+	if !checkIfMatch(c, version, before.Version) {
+		return
+	}
+
+	poster, err := c.FormFile("poster")
+	if err != nil {
+		respondError(c, http.StatusBadRequest, models.ErrCodeFileRequired, models.NewApiErrorDetail("poster", models.DetailCodeRequired))
+		return
+	}
+
+	filename, err := h.saveMoviesPoster(c, poster)
+	if err != nil {
+		respondInternalError(c, err)
+		return
+	}
+
+	movie := before
+	movie.PosterUrl = filename
+	movie.Version = version
+
+	h.saveMovie(c, id, before, movie)
+}
+
+// saveMovie обновляет фильм вместе с ревизией и отвечает его новой версией
+func (h *MoviesHandler) saveMovie(c *gin.Context, id int, before models.Movie, movie models.Movie) {
+	err := h.moviesRepo.Update(c, id, movie, newMovieRevision(c, before))
+	if isVersionConflict(err) {
+		respondVersionConflict(c)
+		return
+	}
+	if err != nil {
+		respondInternalError(c, err)
+		return
+	}
+
+	after, err := h.findMovie(c, id)
+	if err != nil {
+		respondInternalError(c, err)
+		return
+	}
+
+	recordAudit(c, h.auditRepo, models.AuditActionUpdate, models.AuditEntityMovie, id, before, after)
+
+	setETag(c, after.Version)
+	c.JSON(http.StatusOK, after)
+}
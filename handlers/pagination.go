@@ -0,0 +1,59 @@
+package handlers
+
+import (
+	"goozinshe/models"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+// pageParams читает limit и offset из запроса; при ошибке отвечает 400 и возвращает false
+func pageParams(c *gin.Context, defaultLimit int, maxLimit int) (int, int, bool) {
+	limit, ok := limitParam(c, defaultLimit, maxLimit)
+	if !ok {
+		return 0, 0, false
+	}
+
+	offset := 0
+	if value := c.Query("offset"); value != "" {
+		parsed, err := strconv.Atoi(value)
+		if err != nil || parsed < 0 {
+			respondValidationError(c, models.NewApiErrorDetail("offset", models.DetailCodeOutOfRange))
+			return 0, 0, false
+		}
+		offset = parsed
+	}
+
+	return limit, offset, true
+}
+
+// intQuery читает необязательный целочисленный параметр запроса; нет параметра — nil, не число — 400
+func intQuery(c *gin.Context, name string) (*int, bool) {
+	value := c.Query(name)
+	if value == "" {
+		return nil, true
+	}
+
+	parsed, err := strconv.Atoi(value)
+	if err != nil {
+		respondValidationError(c, models.NewApiErrorDetail(name, models.DetailCodeInvalid))
+		return nil, false
+	}
+
+	return &parsed, true
+}
+
+// limitParam читает только limit — для списков без постраничного вывода
+func limitParam(c *gin.Context, defaultLimit int, maxLimit int) (int, bool) {
+	limit := defaultLimit
+	if value := c.Query("limit"); value != "" {
+		parsed, err := strconv.Atoi(value)
+		if err != nil || parsed < 1 || parsed > maxLimit {
+			respondValidationError(c, models.NewApiErrorDetail("limit", models.DetailCodeOutOfRange))
+			return 0, false
+		}
+		limit = parsed
+	}
+
+	return limit, true
+}
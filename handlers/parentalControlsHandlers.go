@@ -0,0 +1,271 @@
+package handlers
+
+import (
+	"goozinshe/config"
+	"goozinshe/models"
+	"goozinshe/repositories"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// время, на которое PIN снимает ограничение, если PARENTAL_UNLOCK_DURATION не задан
+const defaultParentalUnlock = time.Hour
+
+type ParentalControlsHandlers struct {
+	parentalRepo *repositories.ParentalControlsRepository
+	validator    *RequestValidator
+}
+
+func NewParentalControlsHandlers(parentalRepo *repositories.ParentalControlsRepository, validator *RequestValidator) *ParentalControlsHandlers {
+	return &ParentalControlsHandlers{parentalRepo: parentalRepo, validator: validator}
+}
+
+type parentalControlsRequest struct {
+	MaxAgeId *int   `json:"maxAgeId" validate:"omitempty,exists=ages"`
+	Pin      string `json:"pin" validate:"omitempty,len=4,numeric"`
+}
+
+type parentalPinRequest struct {
+	Pin string `json:"pin" validate:"required"`
+}
+
+// Find godoc
+// @Tags         parental controls
+// @Summary      Get parental controls of current user
+// @Produce      json
+// @Success      200  {object} models.ParentalControls "OK"
+// @Failure   	 403  {object} models.ApiError "Not available for API keys"
+// @Failure   	 500  {object} models.ApiError
+// @Router       /me/parentalControls [get]
+// @Security Bearer
+func (h *ParentalControlsHandlers) Find(c *gin.Context) {
+	userId, ok := currentUserId(c)
+	if !ok {
+		return
+	}
+
+	controls, err := h.parentalRepo.Find(c, userId, c.GetString("sessionId"))
+	if err != nil {
+		respondInternalError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, controls)
+}
+
+// Update godoc
+// @Tags         parental controls
+// @Summary      Update parental controls
+// @Description  maxAgeId — самый высокий возрастной рейтинг для всего аккаунта, null снимает лимит. Лимит профиля действует вместе с ним: берётся более строгий.
+// @Description  pin — 4 цифры; без него PIN не меняется. Если PIN уже задан, сначала снимите ограничение через /me/parentalControls/unlock
+// @Accept       json
+// @Param request body handlers.parentalControlsRequest true "Parental controls"
+// @Success      200  "OK"
+// @Failure   	 400  {object} models.ApiError "Invalid data"
+// @Failure   	 403  {object} models.ApiError "PIN required"
+// @Failure   	 422  {object} models.ApiError "Validation failed"
+// @Failure   	 500  {object} models.ApiError
+// @Router       /me/parentalControls [put]
+// @Security Bearer
+func (h *ParentalControlsHandlers) Update(c *gin.Context) {
+	userId, ok := currentUserId(c)
+	if !ok {
+		return
+	}
+
+	var request parentalControlsRequest
+	err := c.BindJSON(&request)
+	if err != nil {
+		respondError(c, http.StatusBadRequest, models.ErrCodeInvalidPayload)
+		return
+	}
+
+	if !h.validator.Validate(c, &request) {
+		return
+	}
+
+	if !requireParentalUnlock(c, h.parentalRepo) {
+		return
+	}
+
+	var pinHash *string
+	if request.Pin != "" {
+		hash, err := bcrypt.GenerateFromPassword([]byte(request.Pin), bcrypt.DefaultCost)
+		if err != nil {
+			respondInternalError(c, err)
+			return
+		}
+		value := string(hash)
+		pinHash = &value
+	}
+
+	err = h.parentalRepo.Update(c, userId, request.MaxAgeId, pinHash)
+	if err != nil {
+		respondInternalError(c, err)
+		return
+	}
+
+	c.Status(http.StatusOK)
+}
+
+// Unlock godoc
+// @Tags         parental controls
+// @Summary      Lift parental controls for current session
+// @Description  Верный PIN снимает возрастные ограничения в текущей сессии на время PARENTAL_UNLOCK_DURATION.
+// @Description  После 5 неверных PIN подряд ввод блокируется на 15 минут
+// @Accept       json
+// @Produce      json
+// @Param request body handlers.parentalPinRequest true "PIN"
+// @Success      200  {object} object{unlockedUntil=string} "OK"
+// @Failure   	 400  {object} models.ApiError "Invalid data"
+// @Failure   	 403  {object} models.ApiError "Invalid PIN"
+// @Failure   	 422  {object} models.ApiError "Validation failed"
+// @Failure   	 429  {object} models.ApiError "Too many invalid PINs"
+// @Failure   	 500  {object} models.ApiError
+// @Router       /me/parentalControls/unlock [post]
+// @Security Bearer
+func (h *ParentalControlsHandlers) Unlock(c *gin.Context) {
+	userId, ok := currentUserId(c)
+	if !ok {
+		return
+	}
+
+	var request parentalPinRequest
+	err := c.BindJSON(&request)
+	if err != nil {
+		respondError(c, http.StatusBadRequest, models.ErrCodeInvalidPayload)
+		return
+	}
+
+	if !h.validator.Validate(c, &request) {
+		return
+	}
+
+	sessionId := c.GetString("sessionId")
+	controls, err := h.parentalRepo.Find(c, userId, sessionId)
+	if err != nil {
+		respondInternalError(c, err)
+		return
+	}
+
+	if controls.IsBlocked() {
+		respondError(c, http.StatusTooManyRequests, models.ErrCodePinBlocked)
+		return
+	}
+
+	// без заданного PIN снять ограничение нельзя
+	if !controls.HasPin || bcrypt.CompareHashAndPassword([]byte(*controls.PinHash), []byte(request.Pin)) != nil {
+		if controls.HasPin {
+			err = h.parentalRepo.RecordPinFailure(c, userId, models.MaxParentalPinFailures, models.ParentalPinBlock)
+			if err != nil {
+				respondInternalError(c, err)
+				return
+			}
+		}
+		respondError(c, http.StatusForbidden, models.ErrCodeInvalidPin)
+		return
+	}
+
+	duration := config.Config.ParentalUnlock
+	if duration <= 0 {
+		duration = defaultParentalUnlock
+	}
+	until := time.Now().Add(duration)
+
+	err = h.parentalRepo.Unlock(c, userId, sessionId, until)
+	if err != nil {
+		respondInternalError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"unlockedUntil": until})
+}
+
+// Lock godoc
+// @Tags         parental controls
+// @Summary      Restore parental controls for current session
+// @Success      200  "OK"
+// @Failure   	 403  {object} models.ApiError "Not available for API keys"
+// @Failure   	 500  {object} models.ApiError
+// @Router       /me/parentalControls/lock [post]
+// @Security Bearer
+func (h *ParentalControlsHandlers) Lock(c *gin.Context) {
+	if _, ok := currentUserId(c); !ok {
+		return
+	}
+
+	err := h.parentalRepo.Lock(c, c.GetString("sessionId"))
+	if err != nil {
+		respondInternalError(c, err)
+		return
+	}
+
+	c.Status(http.StatusOK)
+}
+
+// requireParentalUnlock пропускает изменение настроек, влияющих на ограничения, только если PIN не задан
+// или ограничение снято в этой сессии. Иначе отвечает 403 parental_locked
+func requireParentalUnlock(c *gin.Context, parentalRepo *repositories.ParentalControlsRepository) bool {
+	userId, ok := currentUserId(c)
+	if !ok {
+		return false
+	}
+
+	controls, err := parentalRepo.Find(c, userId, c.GetString("sessionId"))
+	if err != nil {
+		respondInternalError(c, err)
+		return false
+	}
+
+	if controls.HasPin && !controls.IsUnlocked() {
+		respondError(c, http.StatusForbidden, models.ErrCodeParentalLocked)
+		return false
+	}
+
+	return true
+}
+
+// checkMovieAllowed отвечает 403 parental_locked, если рейтинг фильма выше действующего лимита
+func checkMovieAllowed(c *gin.Context, parentalRepo *repositories.ParentalControlsRepository, movieId int) bool {
+	limit := ageLimit(c)
+	if limit == nil {
+		return true
+	}
+
+	allowed, err := parentalRepo.IsMovieAllowed(c, movieId, *limit)
+	if err != nil {
+		respondInternalError(c, err)
+		return false
+	}
+
+	if !allowed {
+		respondError(c, http.StatusForbidden, models.ErrCodeParentalLocked)
+		return false
+	}
+
+	return true
+}
+
+// checkEpisodeAllowed — то же для серии: рейтинг берётся из фильмов, в которые она входит
+func checkEpisodeAllowed(c *gin.Context, parentalRepo *repositories.ParentalControlsRepository, allserieId int) bool {
+	limit := ageLimit(c)
+	if limit == nil {
+		return true
+	}
+
+	allowed, err := parentalRepo.IsEpisodeAllowed(c, allserieId, *limit)
+	if err != nil {
+		respondInternalError(c, err)
+		return false
+	}
+
+	if !allowed {
+		respondError(c, http.StatusForbidden, models.ErrCodeParentalLocked)
+		return false
+	}
+
+	return true
+}
@@ -0,0 +1,490 @@
+package handlers
+
+import (
+	"fmt"
+	"goozinshe/models"
+	"goozinshe/repositories"
+	"mime/multipart"
+	"net/http"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+type PeopleHandlers struct {
+	peopleRepo      *repositories.PeopleRepository
+	moviesAdminRepo *repositories.MoviesAdminRepository
+	auditRepo       *repositories.AuditRepository
+	validator       *RequestValidator
+	parentalRepo    *repositories.ParentalControlsRepository
+	moviesRepo      *repositories.MoviesRepository
+}
+
+func NewPeopleHandlers(peopleRepo *repositories.PeopleRepository, moviesAdminRepo *repositories.MoviesAdminRepository, auditRepo *repositories.AuditRepository, validator *RequestValidator, parentalRepo *repositories.ParentalControlsRepository, moviesRepo *repositories.MoviesRepository) *PeopleHandlers {
+	return &PeopleHandlers{peopleRepo: peopleRepo, moviesAdminRepo: moviesAdminRepo, auditRepo: auditRepo, validator: validator, parentalRepo: parentalRepo, moviesRepo: moviesRepo}
+}
+
+type personRequest struct {
+	Name         string     `json:"name" validate:"required,max=255"`
+	OriginalName string     `json:"originalName" validate:"max=255"`
+	Bio          string     `json:"bio"`
+	BirthDate    *time.Time `json:"birthDate" validate:"omitempty,lt"`
+}
+
+type movieCreditRequest struct {
+	PersonId    int    `json:"personId" validate:"required,exists=people"`
+	Role        string `json:"role" validate:"required,oneof=director actor writer voice_actor"`
+	Character   string `json:"character" validate:"max=255"`
+	Order       int    `json:"order" validate:"gte=0"`
+	AllSeriesId *int   `json:"allSeriesId" validate:"omitempty,exists=allseries"`
+}
+
+// Create godoc
+// @Tags         people
+// @Summary      Create person
+// @Accept       json
+// @Produce      json
+// @Param request body handlers.personRequest true "Person data"
+// @Success      200  {object} object{id=int} "OK"
+// @Failure   	 400  {object} models.ApiError "Invalid data"
+// @Failure   	 422  {object} models.ApiError "Validation failed"
+// @Failure   	 500  {object} models.ApiError
+// @Router       /people [post]
+// @Security Bearer
+func (h *PeopleHandlers) Create(c *gin.Context) {
+	var request personRequest
+	err := c.BindJSON(&request)
+	if err != nil {
+		respondError(c, http.StatusBadRequest, models.ErrCodeInvalidPayload)
+		return
+	}
+
+	request.Name = strings.TrimSpace(request.Name)
+	if !h.validator.Validate(c, &request) {
+		return
+	}
+
+	person := models.Person{
+		Name:         request.Name,
+		OriginalName: request.OriginalName,
+		Bio:          request.Bio,
+		BirthDate:    request.BirthDate,
+	}
+
+	id, err := h.peopleRepo.Create(c, person)
+	if err != nil {
+		respondInternalError(c, err)
+		return
+	}
+	person.Id = id
+
+	recordAudit(c, h.auditRepo, models.AuditActionCreate, models.AuditEntityPerson, id, nil, person)
+
+	c.JSON(http.StatusOK, gin.H{"id": id})
+}
+
+// FindAll godoc
+// @Tags         people
+// @Summary      List people
+// @Accept       json
+// @Produce      json
+// @Param search query string false "Search by name"
+// @Param role query string false "director, actor, writer or voice_actor"
+// @Success      200  {array} models.Person "OK"
+// @Failure   	 500  {object} models.ApiError
+// @Router       /people [get]
+// @Security Bearer
+func (h *PeopleHandlers) FindAll(c *gin.Context) {
+	filters := models.PersonFilters{
+		SearchTerm: c.Query("search"),
+		Role:       c.Query("role"),
+	}
+
+	people, err := h.peopleRepo.FindAll(c, filters)
+	if err != nil {
+		respondInternalError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, people)
+}
+
+// FindById godoc
+// @Tags         people
+// @Summary      Person with filmography
+// @Accept       json
+// @Produce      json
+// @Param id path int true "Person id"
+// @Success      200  {object} models.PersonDetails "OK"
+// @Failure   	 400  {object} models.ApiError "Invalid person id"
+// @Failure   	 404  {object} models.ApiError "Person not found"
+// @Failure   	 500  {object} models.ApiError
+// @Router       /people/{id} [get]
+// @Security Bearer
+func (h *PeopleHandlers) FindById(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		respondError(c, http.StatusBadRequest, models.ErrCodeInvalidId)
+		return
+	}
+
+	person, err := h.peopleRepo.FindById(c, id)
+	if err != nil {
+		respondError(c, http.StatusNotFound, models.ErrCodeNotFound)
+		return
+	}
+
+	filmography, err := h.peopleRepo.FindFilmography(c, id, ageLimit(c))
+	if err != nil {
+		respondInternalError(c, err)
+		return
+	}
+
+	setETag(c, person.Version)
+	c.JSON(http.StatusOK, models.PersonDetails{Person: person, Filmography: filmography})
+}
+
+// Update godoc
+// @Tags         people
+// @Summary      Update person
+// @Accept       json
+// @Produce      json
+// @Param id path int true "Person id"
+// @Param request body handlers.personRequest true "Person data"
+// @Param        If-Match header string true "ETag of the current version"
+// @Success      200  "OK"
+// @Failure   	 400  {object} models.ApiError "Invalid data"
+// @Failure   	 404  {object} models.ApiError "Person not found"
+// @Failure      412  {object} models.ApiError "Resource was modified"
+// @Failure      428  {object} models.ApiError "If-Match header required"
+// @Failure   	 422  {object} models.ApiError "Validation failed"
+// @Failure   	 500  {object} models.ApiError
+// @Router       /people/{id} [put]
+// @Security Bearer
+func (h *PeopleHandlers) Update(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		respondError(c, http.StatusBadRequest, models.ErrCodeInvalidId)
+		return
+	}
+
+	before, err := h.peopleRepo.FindById(c, id)
+	if err != nil {
+		respondError(c, http.StatusNotFound, models.ErrCodeNotFound)
+		return
+	}
+
+	version, ok := ifMatchVersion(c)
+	if !ok {
+		return
+	}
+
+	if !checkIfMatch(c, version, before.Version) {
+		return
+	}
+
+	var request personRequest
+	err = c.BindJSON(&request)
+	if err != nil {
+		respondError(c, http.StatusBadRequest, models.ErrCodeInvalidPayload)
+		return
+	}
+
+	request.Name = strings.TrimSpace(request.Name)
+	if !h.validator.Validate(c, &request) {
+		return
+	}
+
+	person := before
+	person.Name = request.Name
+	person.OriginalName = request.OriginalName
+	person.Bio = request.Bio
+	person.BirthDate = request.BirthDate
+	person.Version = version
+
+	h.savePerson(c, id, before, person)
+}
+
+// UpdatePhoto godoc
+// @Tags         people
+// @Summary      Upload person photo
+// @Accept       multipart/form-data
+// @Produce      json
+// @Param id path int true "Person id"
+// @Param photo formData file true "Photo"
+// @Param        If-Match header string true "ETag of the current version"
+// @Success      200  "OK"
+// @Failure   	 400  {object} models.ApiError "Invalid data"
+// @Failure   	 404  {object} models.ApiError "Person not found"
+// @Failure      412  {object} models.ApiError "Resource was modified"
+// @Failure      428  {object} models.ApiError "If-Match header required"
+// @Failure   	 500  {object} models.ApiError
+// @Router       /people/{id}/photo [put]
+// @Security Bearer
+func (h *PeopleHandlers) UpdatePhoto(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		respondError(c, http.StatusBadRequest, models.ErrCodeInvalidId)
+		return
+	}
+
+	before, err := h.peopleRepo.FindById(c, id)
+	if err != nil {
+		respondError(c, http.StatusNotFound, models.ErrCodeNotFound)
+		return
+	}
+
+	version, ok := ifMatchVersion(c)
+	if !ok {
+		return
+	}
+
+	if !checkIfMatch(c, version, before.Version) {
+		return
+	}
+
+	photo, err := c.FormFile("photo")
+	if err != nil {
+		respondError(c, http.StatusBadRequest, models.ErrCodeFileRequired, models.NewApiErrorDetail("photo", models.DetailCodeRequired))
+		return
+	}
+
+	filename, err := h.savePersonPhoto(c, photo)
+	if err != nil {
+		respondInternalError(c, err)
+		return
+	}
+
+	person := before
+	person.PhotoUrl = filename
+	person.Version = version
+
+	h.savePerson(c, id, before, person)
+}
+
+func (h *PeopleHandlers) savePersonPhoto(c *gin.Context, photo *multipart.FileHeader) (string, error) {
+	filename := fmt.Sprintf("%s%s", uuid.NewString(), filepath.Ext(photo.Filename))
+	filepath := fmt.Sprintf("images/%s", filename)
+	err := c.SaveUploadedFile(photo, filepath)
+
+	return filename, err
+}
+
+func (h *PeopleHandlers) savePerson(c *gin.Context, id int, before models.Person, person models.Person) {
+	err := h.peopleRepo.Update(c, id, person)
+	if isVersionConflict(err) {
+		respondVersionConflict(c)
+		return
+	}
+	if err != nil {
+		respondInternalError(c, err)
+		return
+	}
+
+	recordAudit(c, h.auditRepo, models.AuditActionUpdate, models.AuditEntityPerson, id, before, person)
+
+	setETag(c, person.Version+1)
+	c.Status(http.StatusOK)
+}
+
+// Delete godoc
+// @Tags         people
+// @Summary      Delete person
+// @Accept       json
+// @Produce      json
+// @Param id path int true "Person id"
+// @Param        If-Match header string true "ETag of the current version"
+// @Success      200  "OK"
+// @Failure   	 400  {object} models.ApiError "Invalid person id"
+// @Failure   	 404  {object} models.ApiError "Person not found"
+// @Failure      412  {object} models.ApiError "Resource was modified"
+// @Failure      428  {object} models.ApiError "If-Match header required"
+// @Failure   	 500  {object} models.ApiError
+// @Router       /people/{id} [delete]
+// @Security Bearer
+func (h *PeopleHandlers) Delete(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		respondError(c, http.StatusBadRequest, models.ErrCodeInvalidId)
+		return
+	}
+
+	before, err := h.peopleRepo.FindById(c, id)
+	if err != nil {
+		respondError(c, http.StatusNotFound, models.ErrCodeNotFound)
+		return
+	}
+
+	version, ok := ifMatchVersion(c)
+	if !ok {
+		return
+	}
+
+	if !checkIfMatch(c, version, before.Version) {
+		return
+	}
+
+	err = h.peopleRepo.Delete(c, id, version)
+	if isVersionConflict(err) {
+		respondVersionConflict(c)
+		return
+	}
+	if err != nil {
+		respondInternalError(c, err)
+		return
+	}
+
+	recordAudit(c, h.auditRepo, models.AuditActionDelete, models.AuditEntityPerson, id, before, nil)
+
+	c.Status(http.StatusOK)
+}
+
+// FindMovieCredits godoc
+// @Tags         people
+// @Summary      Cast and crew of a movie
+// @Accept       json
+// @Produce      json
+// @Param id path int true "Movie id"
+// @Success      200  {array} models.MovieCredit "OK"
+// @Failure   	 400  {object} models.ApiError "Invalid movie id"
+// @Failure   	 403  {object} models.ApiError "Restricted by parental controls"
+// @Failure   	 404  {object} models.ApiError "Movie not found or not published"
+// @Failure   	 500  {object} models.ApiError
+// @Router       /movies/{id}/credits [get]
+// @Security Bearer
+func (h *PeopleHandlers) FindMovieCredits(c *gin.Context) {
+	movieId, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		respondError(c, http.StatusBadRequest, models.ErrCodeInvalidId)
+		return
+	}
+
+	if !checkMovieAllowed(c, h.parentalRepo, movieId) {
+		return
+	}
+
+	_, err = h.moviesRepo.FindById(c, movieId)
+	if err != nil {
+		respondFindError(c, err)
+		return
+	}
+
+	credits, err := h.peopleRepo.FindCreditsByMovie(c, movieId)
+	if err != nil {
+		respondInternalError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, credits)
+}
+
+// ReplaceMovieCredits godoc
+// @Tags         people
+// @Summary      Replace cast and crew of a movie
+// @Description  Полный список состава. allSeriesId — серия этого фильма, если человек участвует только в ней; order — порядок в титрах
+// @Accept       json
+// @Produce      json
+// @Param id path int true "Movie id"
+// @Param request body []handlers.movieCreditRequest true "Credits"
+// @Success      200  {array} models.MovieCredit "OK"
+// @Failure   	 400  {object} models.ApiError "Invalid data"
+// @Failure   	 404  {object} models.ApiError "Movie not found"
+// @Failure   	 422  {object} models.ApiError "Validation failed"
+// @Failure   	 500  {object} models.ApiError
+// @Router       /movies/{id}/credits [put]
+// @Security Bearer
+func (h *PeopleHandlers) ReplaceMovieCredits(c *gin.Context) {
+	movieId, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		respondError(c, http.StatusBadRequest, models.ErrCodeInvalidId)
+		return
+	}
+
+	movie, err := h.moviesAdminRepo.FindById(c, movieId)
+	if err != nil {
+		respondError(c, http.StatusNotFound, models.ErrCodeNotFound)
+		return
+	}
+
+	var request []movieCreditRequest
+	err = c.BindJSON(&request)
+	if err != nil {
+		respondError(c, http.StatusBadRequest, models.ErrCodeInvalidPayload)
+		return
+	}
+
+	if !h.validator.Validate(c, &request) {
+		return
+	}
+
+	personIds := make([]int, 0, len(request))
+	for _, credit := range request {
+		personIds = append(personIds, credit.PersonId)
+	}
+
+	people, err := h.peopleRepo.FindAllByIds(c, personIds)
+	if err != nil {
+		respondInternalError(c, err)
+		return
+	}
+	names := make(map[int]models.Person, len(people))
+	for _, person := range people {
+		names[person.Id] = person
+	}
+
+	episodeIds := make([]int, 0, len(movie.AllSeries))
+	for _, episode := range movie.AllSeries {
+		episodeIds = append(episodeIds, *episode.Id)
+	}
+
+	credits := make([]models.MovieCredit, 0, len(request))
+	seen := make(map[string]bool)
+	for i, credit := range request {
+		person, ok := names[credit.PersonId]
+		if !ok {
+			respondValidationError(c, models.NewApiErrorDetail(fmt.Sprintf("[%d].personId", i), models.DetailCodeNotFound))
+			return
+		}
+		if credit.AllSeriesId != nil && !slices.Contains(episodeIds, *credit.AllSeriesId) {
+			respondValidationError(c, models.NewApiErrorDetail(fmt.Sprintf("[%d].allSeriesId", i), models.DetailCodeNotAllowed))
+			return
+		}
+
+		// 0 — участие во всём фильме, а не в отдельной серии
+		episodeId := 0
+		if credit.AllSeriesId != nil {
+			episodeId = *credit.AllSeriesId
+		}
+		key := fmt.Sprintf("%d/%s/%d", credit.PersonId, credit.Role, episodeId)
+		if seen[key] {
+			respondValidationError(c, models.NewApiErrorDetail(fmt.Sprintf("[%d]", i), models.DetailCodeDuplicate))
+			return
+		}
+		seen[key] = true
+
+		credits = append(credits, models.MovieCredit{
+			PersonId:    credit.PersonId,
+			Name:        person.Name,
+			PhotoUrl:    person.PhotoUrl,
+			Role:        credit.Role,
+			Character:   credit.Character,
+			Order:       credit.Order,
+			AllSeriesId: credit.AllSeriesId,
+		})
+	}
+
+	err = h.peopleRepo.ReplaceCredits(c, movieId, credits)
+	if err != nil {
+		respondInternalError(c, err)
+		return
+	}
+
+	recordAudit(c, h.auditRepo, models.AuditActionUpdate, models.AuditEntityMovie, movieId, gin.H{"credits": movie.Credits}, gin.H{"credits": credits})
+
+	c.JSON(http.StatusOK, credits)
+}
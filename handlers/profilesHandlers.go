@@ -0,0 +1,428 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"goozinshe/models"
+	"goozinshe/repositories"
+	"goozinshe/tokens"
+	"net/http"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+)
+
+type ProfilesHandlers struct {
+	profilesRepo *repositories.ProfilesRepository
+	sessionsRepo *repositories.SessionsRepository
+	keyManager   *tokens.KeyManager
+	validator    *RequestValidator
+	parentalRepo *repositories.ParentalControlsRepository
+}
+
+func NewProfilesHandlers(
+	profilesRepo *repositories.ProfilesRepository,
+	sessionsRepo *repositories.SessionsRepository,
+	keyManager *tokens.KeyManager,
+	validator *RequestValidator,
+	parentalRepo *repositories.ParentalControlsRepository) *ProfilesHandlers {
+	return &ProfilesHandlers{
+		profilesRepo: profilesRepo,
+		sessionsRepo: sessionsRepo,
+		keyManager:   keyManager,
+		validator:    validator,
+		parentalRepo: parentalRepo,
+	}
+}
+
+type profileRequest struct {
+	Name     string `json:"name" validate:"required,max=50"`
+	Language string `json:"language" validate:"required,oneof=kk ru en"`
+	MaxAgeId *int   `json:"maxAgeId" validate:"omitempty,exists=ages"`
+	IsKids   bool   `json:"isKids"`
+}
+
+// FindAll godoc
+// @Tags         profiles
+// @Summary      List profiles of current user
+// @Produce      json
+// @Success      200  {array} models.Profile "OK"
+// @Failure   	 403  {object} models.ApiError "Not available for API keys"
+// @Failure   	 500  {object} models.ApiError
+// @Router       /me/profiles [get]
+// @Security Bearer
+func (h *ProfilesHandlers) FindAll(c *gin.Context) {
+	userId, ok := currentUserId(c)
+	if !ok {
+		return
+	}
+
+	profiles, err := h.profilesRepo.FindByUser(c, userId)
+	if err != nil {
+		respondInternalError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, profiles)
+}
+
+// FindById godoc
+// @Tags         profiles
+// @Summary      Get profile
+// @Produce      json
+// @Param id path int true "Profile id"
+// @Success      200  {object} models.Profile "OK"
+// @Header       200  {string} ETag "Profile version"
+// @Failure   	 400  {object} models.ApiError "Invalid id"
+// @Failure   	 403  {object} models.ApiError "Not available for API keys"
+// @Failure   	 404  {object} models.ApiError "Profile not found"
+// @Failure   	 500  {object} models.ApiError
+// @Router       /me/profiles/{id} [get]
+// @Security Bearer
+func (h *ProfilesHandlers) FindById(c *gin.Context) {
+	profile, ok := h.findOwnProfile(c)
+	if !ok {
+		return
+	}
+
+	setETag(c, profile.Version)
+	c.JSON(http.StatusOK, profile)
+}
+
+// Create godoc
+// @Tags         profiles
+// @Summary      Create profile
+// @Description  У аккаунта может быть до 5 профилей. maxAgeId — самый высокий возрастной рейтинг из справочника ages.
+// @Description  Если задан PIN родительского контроля, сначала снимите ограничение через /me/parentalControls/unlock
+// @Accept       json
+// @Produce      json
+// @Param request body handlers.profileRequest true "Profile data"
+// @Success      201  {object} models.Profile "Created"
+// @Failure   	 400  {object} models.ApiError "Invalid data"
+// @Failure   	 403  {object} models.ApiError "Not available for API keys or PIN required"
+// @Failure   	 409  {object} models.ApiError "Profile limit reached"
+// @Failure   	 422  {object} models.ApiError "Validation failed"
+// @Failure   	 500  {object} models.ApiError
+// @Router       /me/profiles [post]
+// @Security Bearer
+func (h *ProfilesHandlers) Create(c *gin.Context) {
+	userId, ok := currentUserId(c)
+	if !ok {
+		return
+	}
+
+	var request profileRequest
+	err := c.BindJSON(&request)
+	if err != nil {
+		respondError(c, http.StatusBadRequest, models.ErrCodeInvalidPayload)
+		return
+	}
+
+	request.Name = strings.TrimSpace(request.Name)
+	if !h.validator.Validate(c, &request) {
+		return
+	}
+
+	if !requireParentalUnlock(c, h.parentalRepo) {
+		return
+	}
+
+	profile, err := h.profilesRepo.Create(c, models.Profile{
+		UserId:   userId,
+		Name:     request.Name,
+		Language: request.Language,
+		MaxAgeId: request.MaxAgeId,
+		IsKids:   request.IsKids,
+	}, models.MaxProfilesPerUser)
+	if errors.Is(err, pgx.ErrNoRows) {
+		respondError(c, http.StatusConflict, models.ErrCodeProfileLimit)
+		return
+	}
+	if err != nil {
+		respondInternalError(c, err)
+		return
+	}
+
+	setETag(c, profile.Version)
+	c.JSON(http.StatusCreated, profile)
+}
+
+// Update godoc
+// @Tags         profiles
+// @Summary      Update profile
+// @Description  Смена maxAgeId или isKids при заданном PIN родительского контроля требует снятого ограничения
+// @Accept       json
+// @Produce      json
+// @Param id path int true "Profile id"
+// @Param        If-Match header string true "ETag of the current version"
+// @Param request body handlers.profileRequest true "Profile data"
+// @Success      200  "OK"
+// @Failure   	 400  {object} models.ApiError "Invalid data"
+// @Failure   	 403  {object} models.ApiError "Not available for API keys or PIN required"
+// @Failure   	 404  {object} models.ApiError "Profile not found"
+// @Failure      412  {object} models.ApiError "Resource was modified"
+// @Failure   	 422  {object} models.ApiError "Validation failed"
+// @Failure      428  {object} models.ApiError "If-Match header required"
+// @Failure   	 500  {object} models.ApiError
+// @Router       /me/profiles/{id} [put]
+// @Security Bearer
+func (h *ProfilesHandlers) Update(c *gin.Context) {
+	before, ok := h.findOwnProfile(c)
+	if !ok {
+		return
+	}
+
+	version, ok := ifMatchVersion(c)
+	if !ok {
+		return
+	}
+
+	if !checkIfMatch(c, version, before.Version) {
+		return
+	}
+
+	var request profileRequest
+	err := c.BindJSON(&request)
+	if err != nil {
+		respondError(c, http.StatusBadRequest, models.ErrCodeInvalidPayload)
+		return
+	}
+
+	request.Name = strings.TrimSpace(request.Name)
+	if !h.validator.Validate(c, &request) {
+		return
+	}
+
+	restrictionsChanged := request.IsKids != before.IsKids ||
+		(request.MaxAgeId == nil) != (before.MaxAgeId == nil) ||
+		(request.MaxAgeId != nil && *request.MaxAgeId != *before.MaxAgeId)
+	if restrictionsChanged && !requireParentalUnlock(c, h.parentalRepo) {
+		return
+	}
+
+	profile := before
+	profile.Name = request.Name
+	profile.Language = request.Language
+	profile.MaxAgeId = request.MaxAgeId
+	profile.IsKids = request.IsKids
+	profile.Version = version
+
+	h.saveProfile(c, profile)
+}
+
+// UpdateAvatar godoc
+// @Tags         profiles
+// @Summary      Upload profile avatar
+// @Accept       multipart/form-data
+// @Produce      json
+// @Param id path int true "Profile id"
+// @Param avatar formData file true "Avatar"
+// @Param        If-Match header string true "ETag of the current version"
+// @Success      200  "OK"
+// @Failure   	 400  {object} models.ApiError "Invalid data"
+// @Failure   	 403  {object} models.ApiError "Not available for API keys"
+// @Failure   	 404  {object} models.ApiError "Profile not found"
+// @Failure      412  {object} models.ApiError "Resource was modified"
+// @Failure      428  {object} models.ApiError "If-Match header required"
+// @Failure   	 500  {object} models.ApiError
+// @Router       /me/profiles/{id}/avatar [put]
+// @Security Bearer
+func (h *ProfilesHandlers) UpdateAvatar(c *gin.Context) {
+	before, ok := h.findOwnProfile(c)
+	if !ok {
+		return
+	}
+
+	version, ok := ifMatchVersion(c)
+	if !ok {
+		return
+	}
+
+	if !checkIfMatch(c, version, before.Version) {
+		return
+	}
+
+	avatar, err := c.FormFile("avatar")
+	if err != nil {
+		respondError(c, http.StatusBadRequest, models.ErrCodeFileRequired, models.NewApiErrorDetail("avatar", models.DetailCodeRequired))
+		return
+	}
+
+	filename := fmt.Sprintf("%s%s", uuid.NewString(), filepath.Ext(avatar.Filename))
+	err = c.SaveUploadedFile(avatar, fmt.Sprintf("images/%s", filename))
+	if err != nil {
+		respondInternalError(c, err)
+		return
+	}
+
+	profile := before
+	profile.AvatarUrl = &filename
+	profile.Version = version
+
+	h.saveProfile(c, profile)
+}
+
+func (h *ProfilesHandlers) saveProfile(c *gin.Context, profile models.Profile) {
+	err := h.profilesRepo.Update(c, profile.Id, profile)
+	if isVersionConflict(err) {
+		respondVersionConflict(c)
+		return
+	}
+	if err != nil {
+		respondInternalError(c, err)
+		return
+	}
+
+	setETag(c, profile.Version+1)
+	c.Status(http.StatusOK)
+}
+
+// Delete godoc
+// @Tags         profiles
+// @Summary      Delete profile
+// @Description  Удаляет профиль вместе с его прогрессом просмотра. При заданном PIN родительского контроля требует снятого ограничения
+// @Param id path int true "Profile id"
+// @Param        If-Match header string true "ETag of the current version"
+// @Success      200  "OK"
+// @Failure   	 400  {object} models.ApiError "Invalid id"
+// @Failure   	 403  {object} models.ApiError "Not available for API keys or PIN required"
+// @Failure   	 404  {object} models.ApiError "Profile not found"
+// @Failure      412  {object} models.ApiError "Resource was modified"
+// @Failure      428  {object} models.ApiError "If-Match header required"
+// @Failure   	 500  {object} models.ApiError
+// @Router       /me/profiles/{id} [delete]
+// @Security Bearer
+func (h *ProfilesHandlers) Delete(c *gin.Context) {
+	profile, ok := h.findOwnProfile(c)
+	if !ok {
+		return
+	}
+
+	version, ok := ifMatchVersion(c)
+	if !ok {
+		return
+	}
+
+	if !checkIfMatch(c, version, profile.Version) {
+		return
+	}
+
+	if !requireParentalUnlock(c, h.parentalRepo) {
+		return
+	}
+
+	err := h.profilesRepo.Delete(c, profile.Id, version)
+	if isVersionConflict(err) {
+		respondVersionConflict(c)
+		return
+	}
+	if err != nil {
+		respondInternalError(c, err)
+		return
+	}
+
+	c.Status(http.StatusOK)
+}
+
+// Select godoc
+// @Tags         profiles
+// @Summary      Issue token for profile
+// @Description  Закрепляет профиль за текущей сессией и выпускает токен с claim pid: дальше профиль выбирается
+// @Description  без заголовка X-Profile-Id, а токены и заголовки с другим профилем отклоняются. Переход на профиль с более мягким возрастным лимитом
+// @Description  требует снять ограничения PIN (POST /me/parentalControls/unlock).
+// @Produce      json
+// @Param id path int true "Profile id"
+// @Success      200  {object} object{token=string} "OK"
+// @Failure   	 400  {object} models.ApiError "Invalid id"
+// @Failure   	 403  {object} models.ApiError "Not available for API keys or parental controls are locked"
+// @Failure   	 404  {object} models.ApiError "Profile not found"
+// @Failure   	 500  {object} models.ApiError
+// @Router       /me/profiles/{id}/token [post]
+// @Security Bearer
+func (h *ProfilesHandlers) Select(c *gin.Context) {
+	profile, ok := h.findOwnProfile(c)
+	if !ok {
+		return
+	}
+
+	if !h.checkProfileSwitch(c, profile) {
+		return
+	}
+
+	session, err := h.sessionsRepo.FindById(c, c.GetString("sessionId"))
+	if err != nil {
+		respondInternalError(c, err)
+		return
+	}
+
+	err = h.sessionsRepo.SelectProfile(c, session.Id, profile.Id)
+	if err != nil {
+		respondInternalError(c, err)
+		return
+	}
+
+	claims := jwt.MapClaims{
+		"jti":                 session.Id,
+		"sub":                 strconv.Itoa(session.UserId),
+		"exp":                 jwt.NewNumericDate(session.ExpiresAt),
+		models.ProfileIdClaim: profile.Id,
+	}
+	tokenString, err := h.keyManager.Sign(claims)
+	if err != nil {
+		respondInternalError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"token": tokenString})
+}
+
+// checkProfileSwitch требует снятия ограничений PIN, если у профиля лимит возраста мягче действующего
+func (h *ProfilesHandlers) checkProfileSwitch(c *gin.Context, profile models.Profile) bool {
+	current := ageLimit(c)
+	if current == nil {
+		return true
+	}
+
+	limit, err := h.parentalRepo.AgeLimit(c, profile.UserId, &profile.Id, c.GetString("sessionId"))
+	if err != nil {
+		respondInternalError(c, err)
+		return false
+	}
+
+	if limit != nil && *limit <= *current {
+		return true
+	}
+
+	return requireParentalUnlock(c, h.parentalRepo)
+}
+
+// findOwnProfile читает профиль из пути; чужой профиль для пользователя не существует — 404
+func (h *ProfilesHandlers) findOwnProfile(c *gin.Context) (models.Profile, bool) {
+	userId, ok := currentUserId(c)
+	if !ok {
+		return models.Profile{}, false
+	}
+
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		respondError(c, http.StatusBadRequest, models.ErrCodeInvalidId)
+		return models.Profile{}, false
+	}
+
+	profile, err := h.profilesRepo.FindById(c, id)
+	if err != nil {
+		respondFindError(c, err)
+		return models.Profile{}, false
+	}
+	if profile.UserId != userId {
+		respondError(c, http.StatusNotFound, models.ErrCodeNotFound)
+		return models.Profile{}, false
+	}
+
+	return profile, true
+}
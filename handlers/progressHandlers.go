@@ -0,0 +1,199 @@
+package handlers
+
+import (
+	"goozinshe/config"
+	"goozinshe/models"
+	"goozinshe/repositories"
+	"math"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	defaultWatchedThreshold = 0.9
+	continueWatchingLimit   = 20
+	continueWatchingMax     = 50
+)
+
+type ProgressHandlers struct {
+	progressRepo     *repositories.WatchProgressRepository
+	translationsRepo *repositories.TranslationsRepository
+	validator        *RequestValidator
+}
+
+func NewProgressHandlers(
+	progressRepo *repositories.WatchProgressRepository,
+	translationsRepo *repositories.TranslationsRepository,
+	validator *RequestValidator) *ProgressHandlers {
+	return &ProgressHandlers{
+		progressRepo:     progressRepo,
+		translationsRepo: translationsRepo,
+		validator:        validator,
+	}
+}
+
+type saveProgressRequest struct {
+	MovieId         int  `json:"movieId" validate:"required,exists=movies"`
+	AllSeriesId     *int `json:"allSeriesId" validate:"omitempty,exists=allseries"`
+	PositionSeconds int  `json:"positionSeconds" validate:"min=0"`
+	DurationSeconds int  `json:"durationSeconds" validate:"required,gt=0"`
+}
+
+// Save godoc
+// @Tags         progress
+// @Summary      Save playback position
+// @Description  Heartbeat плеера. Когда позиция проходит порог (по умолчанию 90% длительности),
+// @Description  фильм или серия считается просмотренной.
+// @Accept       json
+// @Produce      json
+// @Param request body handlers.saveProgressRequest true "Playback position"
+// @Param        X-Profile-Id header int false "Viewer profile"
+// @Success      200  {object} models.WatchProgress "OK"
+// @Failure   	 400  {object} models.ApiError "Invalid data"
+// @Failure   	 403  {object} models.ApiError "Not available for API keys"
+// @Failure   	 422  {object} models.ApiError "Validation failed"
+// @Failure   	 500  {object} models.ApiError
+// @Router       /me/progress [put]
+// @Security Bearer
+func (h *ProgressHandlers) Save(c *gin.Context) {
+	userId, ok := currentUserId(c)
+	if !ok {
+		return
+	}
+
+	var request saveProgressRequest
+	err := c.BindJSON(&request)
+	if err != nil {
+		respondError(c, http.StatusBadRequest, models.ErrCodeInvalidPayload)
+		return
+	}
+
+	if !h.validator.Validate(c, &request) {
+		return
+	}
+
+	if request.AllSeriesId != nil {
+		belongs, err := h.progressRepo.IsMovieEpisode(c, request.MovieId, *request.AllSeriesId)
+		if err != nil {
+			respondInternalError(c, err)
+			return
+		}
+		if !belongs {
+			respondError(c, http.StatusUnprocessableEntity, models.ErrCodeValidation, models.NewApiErrorDetail("allSeriesId", models.DetailCodeNotAllowed))
+			return
+		}
+	}
+
+	position := min(request.PositionSeconds, request.DurationSeconds)
+	progress, err := h.progressRepo.Save(c, models.WatchProgress{
+		UserId:          userId,
+		ProfileId:       currentProfileId(c),
+		MovieId:         request.MovieId,
+		AllSeriesId:     request.AllSeriesId,
+		PositionSeconds: position,
+		DurationSeconds: request.DurationSeconds,
+		Completed:       isWatched(position, request.DurationSeconds),
+	})
+	if err != nil {
+		respondInternalError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, progress)
+}
+
+// isWatched: досмотрено, если позиция прошла порог WATCHED_THRESHOLD от длительности
+func isWatched(position int, duration int) bool {
+	threshold := config.Config.WatchedThreshold
+	if threshold <= 0 || threshold > 1 {
+		threshold = defaultWatchedThreshold
+	}
+
+	return position >= int(math.Ceil(float64(duration)*threshold))
+}
+
+// FindByMovie godoc
+// @Tags         progress
+// @Summary      Get playback positions for a movie
+// @Description  Позиции текущего пользователя по фильму и всем его сериям
+// @Produce      json
+// @Param movieId path int true "Movie id"
+// @Param        X-Profile-Id header int false "Viewer profile"
+// @Success      200  {array} models.WatchProgress "OK"
+// @Failure   	 400  {object} models.ApiError "Invalid id"
+// @Failure   	 403  {object} models.ApiError "Not available for API keys"
+// @Failure   	 500  {object} models.ApiError
+// @Router       /me/progress/{movieId} [get]
+// @Security Bearer
+func (h *ProgressHandlers) FindByMovie(c *gin.Context) {
+	userId, ok := currentUserId(c)
+	if !ok {
+		return
+	}
+
+	movieId, err := strconv.Atoi(c.Param("movieId"))
+	if err != nil {
+		respondError(c, http.StatusBadRequest, models.ErrCodeInvalidId)
+		return
+	}
+
+	progress, err := h.progressRepo.FindByMovie(c, userId, currentProfileId(c), movieId)
+	if err != nil {
+		respondInternalError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, progress)
+}
+
+// ContinueWatching godoc
+// @Tags         progress
+// @Summary      Continue watching
+// @Description  Недосмотренные фильмы и серии с позицией, с которой продолжить, последние первыми.
+// @Description  Если серия досмотрена, возвращается следующая по номеру с isNextEpisode = true.
+// @Produce      json
+// @Param        limit query int false "Max items, 20 by default, up to 50"
+// @Param        lang query string false "kk, ru or en"
+// @Param        Accept-Language header string false "Preferred locales"
+// @Param        X-Profile-Id header int false "Viewer profile"
+// @Success      200  {array} models.ContinueWatchingItem "OK"
+// @Failure   	 400  {object} models.ApiError "Invalid limit"
+// @Failure   	 403  {object} models.ApiError "Not available for API keys"
+// @Failure   	 500  {object} models.ApiError
+// @Router       /me/continueWatching [get]
+// @Security Bearer
+func (h *ProgressHandlers) ContinueWatching(c *gin.Context) {
+	userId, ok := currentUserId(c)
+	if !ok {
+		return
+	}
+
+	limit := continueWatchingLimit
+	if value := c.Query("limit"); value != "" {
+		parsed, err := strconv.Atoi(value)
+		if err != nil || parsed < 1 || parsed > continueWatchingMax {
+			respondValidationError(c, models.NewApiErrorDetail("limit", models.DetailCodeOutOfRange))
+			return
+		}
+		limit = parsed
+	}
+
+	items, err := h.progressRepo.FindContinueWatching(c, userId, currentProfileId(c), limit, ageLimit(c))
+	if err != nil {
+		respondInternalError(c, err)
+		return
+	}
+
+	loc := newLocalizer(c, h.translationsRepo)
+	for i := range items {
+		loc.add(models.AuditEntityMovie, items[i].MovieId, map[string]*string{"title": &items[i].Title})
+	}
+	if err := loc.apply(c); err != nil {
+		respondInternalError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, items)
+}
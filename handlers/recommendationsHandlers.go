@@ -0,0 +1,145 @@
+package handlers
+
+import (
+	"goozinshe/models"
+	"goozinshe/repositories"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	similarLimit         = 12
+	similarMax           = 50
+	recommendationsLimit = 20
+	recommendationsMax   = 50
+)
+
+type RecommendationsHandlers struct {
+	recommendationsRepo *repositories.RecommendationsRepository
+	moviesRepo          *repositories.MoviesRepository
+	parentalRepo        *repositories.ParentalControlsRepository
+	translationsRepo    *repositories.TranslationsRepository
+}
+
+func NewRecommendationsHandlers(
+	recommendationsRepo *repositories.RecommendationsRepository,
+	moviesRepo *repositories.MoviesRepository,
+	parentalRepo *repositories.ParentalControlsRepository,
+	translationsRepo *repositories.TranslationsRepository) *RecommendationsHandlers {
+	return &RecommendationsHandlers{
+		recommendationsRepo: recommendationsRepo,
+		moviesRepo:          moviesRepo,
+		parentalRepo:        parentalRepo,
+		translationsRepo:    translationsRepo,
+	}
+}
+
+// FindSimilar godoc
+// @Tags         recommendations
+// @Summary      Similar titles
+// @Description  Похожие фильмы: общие жанры, категории, актёры и съёмочная группа, а также совместные просмотры
+// @Description  (что ещё смотрели зрители этого фильма). Самые похожие первыми.
+// @Produce      json
+// @Param id path int true "Movie id"
+// @Param        limit query int false "Max items, 12 by default, up to 50"
+// @Param        lang query string false "kk, ru or en"
+// @Param        Accept-Language header string false "Preferred locales"
+// @Param        X-Profile-Id header int false "Viewer profile"
+// @Success      200  {array} models.RecommendedMovie "OK"
+// @Failure   	 400  {object} models.ApiError "Invalid id or limit"
+// @Failure   	 403  {object} models.ApiError "Restricted by parental controls"
+// @Failure   	 404  {object} models.ApiError "Movie not found"
+// @Failure   	 500  {object} models.ApiError
+// @Router       /movies/{id}/similar [get]
+// @Security Bearer
+func (h *RecommendationsHandlers) FindSimilar(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		respondError(c, http.StatusBadRequest, models.ErrCodeInvalidId)
+		return
+	}
+
+	limit, ok := limitParam(c, similarLimit, similarMax)
+	if !ok {
+		return
+	}
+
+	if !checkMovieAllowed(c, h.parentalRepo, id) {
+		return
+	}
+
+	_, err = h.moviesRepo.FindById(c, id)
+	if err != nil {
+		respondFindError(c, err)
+		return
+	}
+
+	movies, err := h.recommendationsRepo.FindSimilar(c, id, limit, ageLimit(c))
+	if err != nil {
+		respondInternalError(c, err)
+		return
+	}
+
+	h.respond(c, movies)
+}
+
+// FindForViewer godoc
+// @Tags         recommendations
+// @Summary      Personal recommendations
+// @Description  Рекомендации по избранному, истории просмотров и отзывам текущего пользователя или профиля.
+// @Description  Пересчитываются фоновой задачей раз в RECOMMENDATIONS_REFRESH; начатые и просмотренные фильмы исключаются.
+// @Description  Пока рекомендаций нет (новый зритель), возвращаются популярные непросмотренные фильмы.
+// @Produce      json
+// @Param        limit query int false "Max items, 20 by default, up to 50"
+// @Param        lang query string false "kk, ru or en"
+// @Param        Accept-Language header string false "Preferred locales"
+// @Param        X-Profile-Id header int false "Viewer profile"
+// @Success      200  {array} models.RecommendedMovie "OK"
+// @Failure   	 400  {object} models.ApiError "Invalid limit"
+// @Failure   	 403  {object} models.ApiError "Not available for API keys"
+// @Failure   	 500  {object} models.ApiError
+// @Router       /me/recommendations [get]
+// @Security Bearer
+func (h *RecommendationsHandlers) FindForViewer(c *gin.Context) {
+	userId, ok := currentUserId(c)
+	if !ok {
+		return
+	}
+
+	limit, ok := limitParam(c, recommendationsLimit, recommendationsMax)
+	if !ok {
+		return
+	}
+
+	viewer := models.Viewer{UserId: userId, ProfileId: currentProfileId(c)}
+	movies, err := h.recommendationsRepo.FindForViewer(c, viewer, limit, ageLimit(c))
+	if err != nil {
+		respondInternalError(c, err)
+		return
+	}
+
+	if len(movies) == 0 {
+		movies, err = h.recommendationsRepo.FindPopular(c, viewer, limit, ageLimit(c))
+		if err != nil {
+			respondInternalError(c, err)
+			return
+		}
+	}
+
+	h.respond(c, movies)
+}
+
+func (h *RecommendationsHandlers) respond(c *gin.Context, movies []models.RecommendedMovie) {
+	loc := newLocalizer(c, h.translationsRepo)
+	for i := range movies {
+		loc.add(models.AuditEntityMovie, movies[i].MovieId, map[string]*string{"title": &movies[i].Title})
+	}
+	if err := loc.apply(c); err != nil {
+		respondInternalError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, movies)
+}
@@ -0,0 +1,324 @@
+package handlers
+
+import (
+	"errors"
+	"goozinshe/models"
+	"goozinshe/moderation"
+	"goozinshe/repositories"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5"
+)
+
+type ReviewModerationHandlers struct {
+	reviewsRepo  *repositories.ReviewsRepository
+	badWordsRepo *repositories.BadWordsRepository
+	auditRepo    *repositories.AuditRepository
+	validator    *RequestValidator
+}
+
+func NewReviewModerationHandlers(
+	reviewsRepo *repositories.ReviewsRepository,
+	badWordsRepo *repositories.BadWordsRepository,
+	auditRepo *repositories.AuditRepository,
+	validator *RequestValidator) *ReviewModerationHandlers {
+	return &ReviewModerationHandlers{
+		reviewsRepo:  reviewsRepo,
+		badWordsRepo: badWordsRepo,
+		auditRepo:    auditRepo,
+		validator:    validator,
+	}
+}
+
+type moderateReviewRequest struct {
+	Status string  `json:"status" validate:"required,oneof=approved rejected"`
+	Note   *string `json:"note" validate:"omitempty,max=500"`
+}
+
+type badWordRequest struct {
+	Language string `json:"language" validate:"required,oneof=kk ru"`
+	Word     string `json:"word" validate:"required,max=100"`
+}
+
+// FindQueue godoc
+// @Tags         review moderation
+// @Summary      Moderation queue
+// @Description  Отзывы в статусе status, старые первыми. flaggedWords — слова фильтра, найденные в тексте, reportCount — нерассмотренные жалобы
+// @Produce      json
+// @Param        status query string false "pending (default), approved or rejected"
+// @Param        reported query bool false "Only reviews with unresolved reports"
+// @Param        limit query int false "Page size, 20 by default, up to 100"
+// @Param        offset query int false "Offset"
+// @Success      200  {array} models.Review "OK"
+// @Failure   	 400  {object} models.ApiError "Invalid filters"
+// @Failure   	 500  {object} models.ApiError
+// @Router       /admin/reviews [get]
+// @Security Bearer
+func (h *ReviewModerationHandlers) FindQueue(c *gin.Context) {
+	filters := models.ModerationFilters{Status: models.ReviewStatusPending}
+
+	if status := c.Query("status"); status != "" {
+		if status != models.ReviewStatusPending && status != models.ReviewStatusApproved && status != models.ReviewStatusRejected {
+			respondValidationError(c, models.NewApiErrorDetail("status", models.DetailCodeUnsupported))
+			return
+		}
+		filters.Status = status
+	}
+
+	if value := c.Query("reported"); value != "" {
+		reported, err := strconv.ParseBool(value)
+		if err != nil {
+			respondValidationError(c, models.NewApiErrorDetail("reported", models.DetailCodeInvalid))
+			return
+		}
+		filters.Reported = reported
+	}
+
+	var ok bool
+	filters.Limit, filters.Offset, ok = pageParams(c, reviewsLimit, reviewsMax)
+	if !ok {
+		return
+	}
+
+	reviews, err := h.reviewsRepo.FindForModeration(c, filters)
+	if err != nil {
+		respondInternalError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, reviews)
+}
+
+// FindById godoc
+// @Tags         review moderation
+// @Summary      Get review for moderation
+// @Produce      json
+// @Param id path int true "Review id"
+// @Success      200  {object} models.Review "OK"
+// @Header       200  {string} ETag "Review version"
+// @Failure   	 400  {object} models.ApiError "Invalid id"
+// @Failure   	 404  {object} models.ApiError "Review not found"
+// @Failure   	 500  {object} models.ApiError
+// @Router       /admin/reviews/{id} [get]
+// @Security Bearer
+func (h *ReviewModerationHandlers) FindById(c *gin.Context) {
+	review, ok := h.findReview(c)
+	if !ok {
+		return
+	}
+
+	setETag(c, review.Version)
+	c.JSON(http.StatusOK, review)
+}
+
+// FindReports godoc
+// @Tags         review moderation
+// @Summary      Reports on a review
+// @Produce      json
+// @Param id path int true "Review id"
+// @Success      200  {array} models.ReviewReport "OK"
+// @Failure   	 400  {object} models.ApiError "Invalid id"
+// @Failure   	 404  {object} models.ApiError "Review not found"
+// @Failure   	 500  {object} models.ApiError
+// @Router       /admin/reviews/{id}/reports [get]
+// @Security Bearer
+func (h *ReviewModerationHandlers) FindReports(c *gin.Context) {
+	review, ok := h.findReview(c)
+	if !ok {
+		return
+	}
+
+	reports, err := h.reviewsRepo.FindReports(c, review.Id)
+	if err != nil {
+		respondInternalError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, reports)
+}
+
+// Moderate godoc
+// @Tags         review moderation
+// @Summary      Approve or reject review
+// @Description  Решение закрывает все жалобы на отзыв. note видна автору отзыва
+// @Accept       json
+// @Param id path int true "Review id"
+// @Param        If-Match header string true "ETag of the current version"
+// @Param request body handlers.moderateReviewRequest true "Decision"
+// @Success      200  "OK"
+// @Failure   	 400  {object} models.ApiError "Invalid data"
+// @Failure   	 404  {object} models.ApiError "Review not found"
+// @Failure      412  {object} models.ApiError "Resource was modified"
+// @Failure   	 422  {object} models.ApiError "Validation failed"
+// @Failure      428  {object} models.ApiError "If-Match header required"
+// @Failure   	 500  {object} models.ApiError
+// @Router       /admin/reviews/{id}/moderation [put]
+// @Security Bearer
+func (h *ReviewModerationHandlers) Moderate(c *gin.Context) {
+	before, ok := h.findReview(c)
+	if !ok {
+		return
+	}
+
+	version, ok := ifMatchVersion(c)
+	if !ok {
+		return
+	}
+
+	if !checkIfMatch(c, version, before.Version) {
+		return
+	}
+
+	var request moderateReviewRequest
+	err := c.BindJSON(&request)
+	if err != nil {
+		respondError(c, http.StatusBadRequest, models.ErrCodeInvalidPayload)
+		return
+	}
+
+	if !h.validator.Validate(c, &request) {
+		return
+	}
+
+	err = h.reviewsRepo.Moderate(c, before.Id, version, request.Status, request.Note, viewerUserId(c))
+	if isVersionConflict(err) {
+		respondVersionConflict(c)
+		return
+	}
+	if err != nil {
+		respondInternalError(c, err)
+		return
+	}
+
+	after := before
+	after.Status = request.Status
+	after.ModerationNote = request.Note
+	after.ReportCount = 0
+	recordAudit(c, h.auditRepo, models.AuditActionUpdate, models.AuditEntityReview, before.Id, before, after)
+
+	setETag(c, version+1)
+	c.Status(http.StatusOK)
+}
+
+// FindBadWords godoc
+// @Tags         review moderation
+// @Summary      List review filter words
+// @Produce      json
+// @Success      200  {array} models.BadWord "OK"
+// @Failure   	 500  {object} models.ApiError
+// @Router       /admin/badWords [get]
+// @Security Bearer
+func (h *ReviewModerationHandlers) FindBadWords(c *gin.Context) {
+	words, err := h.badWordsRepo.FindAll(c)
+	if err != nil {
+		respondInternalError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, words)
+}
+
+// CreateBadWord godoc
+// @Tags         review moderation
+// @Summary      Add review filter word
+// @Description  Одно слово; со * в конце ловит все слова с этим началом ("бля*"). Регистр, ё/е, повторы букв
+// @Description  и латиница вместо похожих кириллических букв при проверке не учитываются
+// @Accept       json
+// @Produce      json
+// @Param request body handlers.badWordRequest true "Word"
+// @Success      201  {object} models.BadWord "Created"
+// @Failure   	 400  {object} models.ApiError "Invalid data"
+// @Failure   	 422  {object} models.ApiError "Validation failed"
+// @Failure   	 500  {object} models.ApiError
+// @Router       /admin/badWords [post]
+// @Security Bearer
+func (h *ReviewModerationHandlers) CreateBadWord(c *gin.Context) {
+	var request badWordRequest
+	err := c.BindJSON(&request)
+	if err != nil {
+		respondError(c, http.StatusBadRequest, models.ErrCodeInvalidPayload)
+		return
+	}
+
+	request.Word = strings.TrimSpace(request.Word)
+	if !h.validator.Validate(c, &request) {
+		return
+	}
+
+	if !moderation.IsValidWord(request.Word) {
+		respondError(c, http.StatusUnprocessableEntity, models.ErrCodeValidation, models.NewApiErrorDetail("word", models.DetailCodeFormat))
+		return
+	}
+
+	word, err := h.badWordsRepo.Create(c, models.BadWord{Language: request.Language, Word: request.Word})
+	if errors.Is(err, pgx.ErrNoRows) {
+		respondError(c, http.StatusUnprocessableEntity, models.ErrCodeValidation, models.NewApiErrorDetail("word", models.DetailCodeDuplicate))
+		return
+	}
+	if err != nil {
+		respondInternalError(c, err)
+		return
+	}
+
+	recordAudit(c, h.auditRepo, models.AuditActionCreate, models.AuditEntityBadWord, word.Id, nil, word)
+
+	c.JSON(http.StatusCreated, word)
+}
+
+// DeleteBadWord godoc
+// @Tags         review moderation
+// @Summary      Delete review filter word
+// @Param id path int true "Word id"
+// @Success      200  "OK"
+// @Failure   	 400  {object} models.ApiError "Invalid id"
+// @Failure   	 404  {object} models.ApiError "Word not found"
+// @Failure   	 500  {object} models.ApiError
+// @Router       /admin/badWords/{id} [delete]
+// @Security Bearer
+func (h *ReviewModerationHandlers) DeleteBadWord(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		respondError(c, http.StatusBadRequest, models.ErrCodeInvalidId)
+		return
+	}
+
+	word, err := h.badWordsRepo.FindById(c, id)
+	if err != nil {
+		respondFindError(c, err)
+		return
+	}
+
+	err = h.badWordsRepo.Delete(c, id)
+	if err != nil {
+		respondInternalError(c, err)
+		return
+	}
+
+	recordAudit(c, h.auditRepo, models.AuditActionDelete, models.AuditEntityBadWord, id, word, nil)
+
+	c.Status(http.StatusOK)
+}
+
+// findReview читает из пути отзыв в любом статусе; удалённый автором модерировать нечего — 404
+func (h *ReviewModerationHandlers) findReview(c *gin.Context) (models.Review, bool) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		respondError(c, http.StatusBadRequest, models.ErrCodeInvalidId)
+		return models.Review{}, false
+	}
+
+	review, err := h.reviewsRepo.FindById(c, id)
+	if err != nil {
+		respondFindError(c, err)
+		return models.Review{}, false
+	}
+	if review.IsDeleted {
+		respondError(c, http.StatusNotFound, models.ErrCodeNotFound)
+		return models.Review{}, false
+	}
+
+	return review, true
+}
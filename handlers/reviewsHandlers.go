@@ -0,0 +1,663 @@
+package handlers
+
+import (
+	"goozinshe/config"
+	"goozinshe/models"
+	"goozinshe/moderation"
+	"goozinshe/repositories"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	reviewsLimit = 20
+	reviewsMax   = 100
+)
+
+type ReviewsHandlers struct {
+	reviewsRepo  *repositories.ReviewsRepository
+	badWordsRepo *repositories.BadWordsRepository
+	moviesRepo   *repositories.MoviesRepository
+	parentalRepo *repositories.ParentalControlsRepository
+	validator    *RequestValidator
+}
+
+func NewReviewsHandlers(
+	reviewsRepo *repositories.ReviewsRepository,
+	badWordsRepo *repositories.BadWordsRepository,
+	moviesRepo *repositories.MoviesRepository,
+	parentalRepo *repositories.ParentalControlsRepository,
+	validator *RequestValidator) *ReviewsHandlers {
+	return &ReviewsHandlers{
+		reviewsRepo:  reviewsRepo,
+		badWordsRepo: badWordsRepo,
+		moviesRepo:   moviesRepo,
+		parentalRepo: parentalRepo,
+		validator:    validator,
+	}
+}
+
+type createReviewRequest struct {
+	MovieId     int    `json:"movieId" validate:"required_without=ParentId,omitempty,exists=movies"`
+	AllSeriesId *int   `json:"allSeriesId" validate:"omitempty,exists=allseries"`
+	ParentId    *int   `json:"parentId" validate:"omitempty,exists=reviews"`
+	Body        string `json:"body" validate:"required,max=5000"`
+}
+
+type updateReviewRequest struct {
+	Body string `json:"body" validate:"required,max=5000"`
+}
+
+type reportReviewRequest struct {
+	Reason  string  `json:"reason" validate:"required,oneof=spam abuse spoiler other"`
+	Comment *string `json:"comment" validate:"omitempty,max=500"`
+}
+
+// FindByMovie godoc
+// @Tags         reviews
+// @Summary      List movie reviews
+// @Description  Одобренные отзывы верхнего уровня на фильм, а с allSeriesId — на серию. Ответы — в /reviews/{id}/replies
+// @Produce      json
+// @Param id path int true "Movie id"
+// @Param        allSeriesId query int false "Episode id"
+// @Param        sort query string false "newest (default) or helpful"
+// @Param        limit query int false "Page size, 20 by default, up to 100"
+// @Param        offset query int false "Offset"
+// @Success      200  {array} models.Review "OK"
+// @Failure   	 400  {object} models.ApiError "Invalid id or filters"
+// @Failure   	 403  {object} models.ApiError "Restricted by parental controls"
+// @Failure   	 500  {object} models.ApiError
+// @Router       /movies/{id}/reviews [get]
+// @Security Bearer
+func (h *ReviewsHandlers) FindByMovie(c *gin.Context) {
+	movieId, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		respondError(c, http.StatusBadRequest, models.ErrCodeInvalidId)
+		return
+	}
+
+	filters := models.ReviewFilters{
+		MovieId: movieId,
+		Sort:    models.ReviewSortNewest,
+	}
+
+	if value := c.Query("allSeriesId"); value != "" {
+		allSeriesId, err := strconv.Atoi(value)
+		if err != nil {
+			respondValidationError(c, models.NewApiErrorDetail("allSeriesId", models.DetailCodeInvalid))
+			return
+		}
+		filters.AllSeriesId = &allSeriesId
+	}
+
+	if sort := c.Query("sort"); sort != "" {
+		if sort != models.ReviewSortNewest && sort != models.ReviewSortHelpful {
+			respondValidationError(c, models.NewApiErrorDetail("sort", models.DetailCodeUnsupported))
+			return
+		}
+		filters.Sort = sort
+	}
+
+	var ok bool
+	filters.Limit, filters.Offset, ok = pageParams(c, reviewsLimit, reviewsMax)
+	if !ok {
+		return
+	}
+
+	if !checkMovieAllowed(c, h.parentalRepo, movieId) {
+		return
+	}
+
+	reviews, err := h.reviewsRepo.FindByMovie(c, filters)
+	if err != nil {
+		respondInternalError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, publicReviews(c, reviews))
+}
+
+// FindReplies godoc
+// @Tags         reviews
+// @Summary      List replies to a review
+// @Description  Одобренные ответы в порядке написания. Удалённый ответ, на который тоже ответили, приходит с isDeleted = true
+// @Produce      json
+// @Param id path int true "Review id"
+// @Param        limit query int false "Page size, 20 by default, up to 100"
+// @Param        offset query int false "Offset"
+// @Success      200  {array} models.Review "OK"
+// @Failure   	 400  {object} models.ApiError "Invalid id"
+// @Failure   	 403  {object} models.ApiError "Restricted by parental controls"
+// @Failure   	 404  {object} models.ApiError "Review not found"
+// @Failure   	 500  {object} models.ApiError
+// @Router       /reviews/{id}/replies [get]
+// @Security Bearer
+func (h *ReviewsHandlers) FindReplies(c *gin.Context) {
+	parent, ok := h.findPublishedReview(c, true)
+	if !ok {
+		return
+	}
+
+	limit, offset, ok := pageParams(c, reviewsLimit, reviewsMax)
+	if !ok {
+		return
+	}
+
+	replies, err := h.reviewsRepo.FindReplies(c, parent.Id, limit, offset)
+	if err != nil {
+		respondInternalError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, publicReviews(c, replies))
+}
+
+// FindById godoc
+// @Tags         reviews
+// @Summary      Get review
+// @Description  Чужой отзыв виден, только если он одобрен; свой — в любом статусе, с причиной решения модератора
+// @Produce      json
+// @Param id path int true "Review id"
+// @Success      200  {object} models.Review "OK"
+// @Header       200  {string} ETag "Review version"
+// @Failure   	 400  {object} models.ApiError "Invalid id"
+// @Failure   	 403  {object} models.ApiError "Restricted by parental controls"
+// @Failure   	 404  {object} models.ApiError "Review not found"
+// @Failure   	 500  {object} models.ApiError
+// @Router       /reviews/{id} [get]
+// @Security Bearer
+func (h *ReviewsHandlers) FindById(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		respondError(c, http.StatusBadRequest, models.ErrCodeInvalidId)
+		return
+	}
+
+	review, err := h.reviewsRepo.FindById(c, id)
+	if err != nil {
+		respondFindError(c, err)
+		return
+	}
+
+	if isOwnReview(c, review) {
+		if review.IsDeleted {
+			respondError(c, http.StatusNotFound, models.ErrCodeNotFound)
+			return
+		}
+		review.IsOwn = true
+		review.ReportCount = 0
+	} else {
+		if review.IsDeleted || review.Status != models.ReviewStatusApproved {
+			respondError(c, http.StatusNotFound, models.ErrCodeNotFound)
+			return
+		}
+		review = publicReviews(c, []models.Review{review})[0]
+	}
+
+	if !checkMovieAllowed(c, h.parentalRepo, review.MovieId) {
+		return
+	}
+
+	setETag(c, review.Version)
+	c.JSON(http.StatusOK, review)
+}
+
+// FindMine godoc
+// @Tags         reviews
+// @Summary      List own reviews
+// @Description  Отзывы и ответы текущего пользователя во всех статусах, новые первыми
+// @Produce      json
+// @Param        limit query int false "Page size, 20 by default, up to 100"
+// @Param        offset query int false "Offset"
+// @Success      200  {array} models.Review "OK"
+// @Failure   	 400  {object} models.ApiError "Invalid limit or offset"
+// @Failure   	 403  {object} models.ApiError "Not available for API keys"
+// @Failure   	 500  {object} models.ApiError
+// @Router       /me/reviews [get]
+// @Security Bearer
+func (h *ReviewsHandlers) FindMine(c *gin.Context) {
+	userId, ok := currentUserId(c)
+	if !ok {
+		return
+	}
+
+	limit, offset, ok := pageParams(c, reviewsLimit, reviewsMax)
+	if !ok {
+		return
+	}
+
+	reviews, err := h.reviewsRepo.FindByUser(c, userId, limit, offset)
+	if err != nil {
+		respondInternalError(c, err)
+		return
+	}
+
+	for i := range reviews {
+		reviews[i].IsOwn = true
+		reviews[i].ReportCount = 0
+	}
+
+	c.JSON(http.StatusOK, reviews)
+}
+
+// Create godoc
+// @Tags         reviews
+// @Summary      Post review or reply
+// @Description  С parentId — ответ в ветке: movieId и allSeriesId берутся из родительского отзыва.
+// @Description  Отзыв со словами из фильтра (или любой, если включена премодерация) попадает в очередь модерации со статусом pending
+// @Accept       json
+// @Produce      json
+// @Param        X-Profile-Id header int false "Viewer profile"
+// @Param request body handlers.createReviewRequest true "Review"
+// @Success      201  {object} models.Review "Created"
+// @Header       201  {string} ETag "Review version"
+// @Failure   	 400  {object} models.ApiError "Invalid data"
+// @Failure   	 403  {object} models.ApiError "Not available for API keys or restricted by parental controls"
+// @Failure   	 404  {object} models.ApiError "Movie not found"
+// @Failure   	 422  {object} models.ApiError "Validation failed"
+// @Failure   	 500  {object} models.ApiError
+// @Router       /reviews [post]
+// @Security Bearer
+func (h *ReviewsHandlers) Create(c *gin.Context) {
+	userId, ok := currentUserId(c)
+	if !ok {
+		return
+	}
+
+	var request createReviewRequest
+	err := c.BindJSON(&request)
+	if err != nil {
+		respondError(c, http.StatusBadRequest, models.ErrCodeInvalidPayload)
+		return
+	}
+
+	request.Body = strings.TrimSpace(request.Body)
+	if !h.validator.Validate(c, &request) {
+		return
+	}
+
+	review := models.Review{
+		MovieId:     request.MovieId,
+		AllSeriesId: request.AllSeriesId,
+		ParentId:    request.ParentId,
+		UserId:      userId,
+		ProfileId:   currentProfileId(c),
+		Body:        request.Body,
+	}
+
+	if request.ParentId != nil {
+		parent, err := h.reviewsRepo.FindById(c, *request.ParentId)
+		if err != nil {
+			respondFindError(c, err)
+			return
+		}
+		// отвечать можно только на видимые отзывы
+		if parent.IsDeleted || parent.Status != models.ReviewStatusApproved {
+			respondError(c, http.StatusUnprocessableEntity, models.ErrCodeValidation, models.NewApiErrorDetail("parentId", models.DetailCodeNotAllowed))
+			return
+		}
+		review.MovieId = parent.MovieId
+		review.AllSeriesId = parent.AllSeriesId
+	} else if request.AllSeriesId != nil {
+		belongs, err := h.reviewsRepo.IsMovieEpisode(c, request.MovieId, *request.AllSeriesId)
+		if err != nil {
+			respondInternalError(c, err)
+			return
+		}
+		if !belongs {
+			respondError(c, http.StatusUnprocessableEntity, models.ErrCodeValidation, models.NewApiErrorDetail("allSeriesId", models.DetailCodeNotAllowed))
+			return
+		}
+	}
+
+	if !checkMovieAllowed(c, h.parentalRepo, review.MovieId) {
+		return
+	}
+
+	_, err = h.moviesRepo.FindById(c, review.MovieId)
+	if err != nil {
+		respondFindError(c, err)
+		return
+	}
+
+	review.Status, review.FlaggedWords, err = h.screen(c, review.Body)
+	if err != nil {
+		respondInternalError(c, err)
+		return
+	}
+
+	review, err = h.reviewsRepo.Create(c, review)
+	if err != nil {
+		respondInternalError(c, err)
+		return
+	}
+	review.IsOwn = true
+
+	setETag(c, review.Version)
+	c.JSON(http.StatusCreated, review)
+}
+
+// Update godoc
+// @Tags         reviews
+// @Summary      Edit own review
+// @Description  Текст заново проверяется фильтром, решение модератора сбрасывается
+// @Accept       json
+// @Param id path int true "Review id"
+// @Param        If-Match header string true "ETag of the current version"
+// @Param request body handlers.updateReviewRequest true "Review"
+// @Success      200  "OK"
+// @Failure   	 400  {object} models.ApiError "Invalid data"
+// @Failure   	 403  {object} models.ApiError "Not available for API keys"
+// @Failure   	 404  {object} models.ApiError "Review not found"
+// @Failure      412  {object} models.ApiError "Resource was modified"
+// @Failure   	 422  {object} models.ApiError "Validation failed"
+// @Failure      428  {object} models.ApiError "If-Match header required"
+// @Failure   	 500  {object} models.ApiError
+// @Router       /reviews/{id} [put]
+// @Security Bearer
+func (h *ReviewsHandlers) Update(c *gin.Context) {
+	review, ok := h.findOwnReview(c)
+	if !ok {
+		return
+	}
+
+	version, ok := ifMatchVersion(c)
+	if !ok {
+		return
+	}
+
+	if !checkIfMatch(c, version, review.Version) {
+		return
+	}
+
+	var request updateReviewRequest
+	err := c.BindJSON(&request)
+	if err != nil {
+		respondError(c, http.StatusBadRequest, models.ErrCodeInvalidPayload)
+		return
+	}
+
+	request.Body = strings.TrimSpace(request.Body)
+	if !h.validator.Validate(c, &request) {
+		return
+	}
+
+	review.Body = request.Body
+	review.Version = version
+	review.Status, review.FlaggedWords, err = h.screen(c, review.Body)
+	if err != nil {
+		respondInternalError(c, err)
+		return
+	}
+
+	err = h.reviewsRepo.UpdateBody(c, review.Id, review)
+	if isVersionConflict(err) {
+		respondVersionConflict(c)
+		return
+	}
+	if err != nil {
+		respondInternalError(c, err)
+		return
+	}
+
+	setETag(c, version+1)
+	c.Status(http.StatusOK)
+}
+
+// Delete godoc
+// @Tags         reviews
+// @Summary      Delete own review
+// @Description  Ответы на удалённый отзыв остаются видны, сам отзыв показывается в ветке без текста
+// @Param id path int true "Review id"
+// @Param        If-Match header string true "ETag of the current version"
+// @Success      200  "OK"
+// @Failure   	 400  {object} models.ApiError "Invalid id"
+// @Failure   	 403  {object} models.ApiError "Not available for API keys"
+// @Failure   	 404  {object} models.ApiError "Review not found"
+// @Failure      412  {object} models.ApiError "Resource was modified"
+// @Failure      428  {object} models.ApiError "If-Match header required"
+// @Failure   	 500  {object} models.ApiError
+// @Router       /reviews/{id} [delete]
+// @Security Bearer
+func (h *ReviewsHandlers) Delete(c *gin.Context) {
+	review, ok := h.findOwnReview(c)
+	if !ok {
+		return
+	}
+
+	version, ok := ifMatchVersion(c)
+	if !ok {
+		return
+	}
+
+	if !checkIfMatch(c, version, review.Version) {
+		return
+	}
+
+	err := h.reviewsRepo.Delete(c, review.Id, version)
+	if isVersionConflict(err) {
+		respondVersionConflict(c)
+		return
+	}
+	if err != nil {
+		respondInternalError(c, err)
+		return
+	}
+
+	c.Status(http.StatusOK)
+}
+
+// Vote godoc
+// @Tags         reviews
+// @Summary      Mark review as helpful
+// @Description  Один голос от пользователя; повторный ничего не меняет
+// @Param id path int true "Review id"
+// @Success      200  "OK"
+// @Failure   	 400  {object} models.ApiError "Invalid id"
+// @Failure   	 403  {object} models.ApiError "Own review or not available for API keys"
+// @Failure   	 404  {object} models.ApiError "Review not found"
+// @Failure   	 500  {object} models.ApiError
+// @Router       /reviews/{id}/helpful [put]
+// @Security Bearer
+func (h *ReviewsHandlers) Vote(c *gin.Context) {
+	userId, ok := currentUserId(c)
+	if !ok {
+		return
+	}
+
+	review, ok := h.findPublishedReview(c, false)
+	if !ok {
+		return
+	}
+
+	if review.UserId == userId {
+		respondError(c, http.StatusForbidden, models.ErrCodeOwnReview)
+		return
+	}
+
+	err := h.reviewsRepo.Vote(c, review.Id, userId)
+	if err != nil {
+		respondInternalError(c, err)
+		return
+	}
+
+	c.Status(http.StatusOK)
+}
+
+// Unvote godoc
+// @Tags         reviews
+// @Summary      Remove helpful mark
+// @Param id path int true "Review id"
+// @Success      200  "OK"
+// @Failure   	 400  {object} models.ApiError "Invalid id"
+// @Failure   	 403  {object} models.ApiError "Not available for API keys"
+// @Failure   	 500  {object} models.ApiError
+// @Router       /reviews/{id}/helpful [delete]
+// @Security Bearer
+func (h *ReviewsHandlers) Unvote(c *gin.Context) {
+	userId, ok := currentUserId(c)
+	if !ok {
+		return
+	}
+
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		respondError(c, http.StatusBadRequest, models.ErrCodeInvalidId)
+		return
+	}
+
+	err = h.reviewsRepo.Unvote(c, id, userId)
+	if err != nil {
+		respondInternalError(c, err)
+		return
+	}
+
+	c.Status(http.StatusOK)
+}
+
+// Report godoc
+// @Tags         reviews
+// @Summary      Report review
+// @Description  Одна жалоба от пользователя на отзыв. Одобренный отзыв с 3 нерассмотренными жалобами возвращается на модерацию
+// @Accept       json
+// @Param id path int true "Review id"
+// @Param request body handlers.reportReviewRequest true "Report"
+// @Success      200  "OK"
+// @Failure   	 400  {object} models.ApiError "Invalid data"
+// @Failure   	 403  {object} models.ApiError "Own review or not available for API keys"
+// @Failure   	 404  {object} models.ApiError "Review not found"
+// @Failure   	 422  {object} models.ApiError "Validation failed"
+// @Failure   	 500  {object} models.ApiError
+// @Router       /reviews/{id}/reports [post]
+// @Security Bearer
+func (h *ReviewsHandlers) Report(c *gin.Context) {
+	userId, ok := currentUserId(c)
+	if !ok {
+		return
+	}
+
+	review, ok := h.findPublishedReview(c, false)
+	if !ok {
+		return
+	}
+
+	if review.UserId == userId {
+		respondError(c, http.StatusForbidden, models.ErrCodeOwnReview)
+		return
+	}
+
+	var request reportReviewRequest
+	err := c.BindJSON(&request)
+	if err != nil {
+		respondError(c, http.StatusBadRequest, models.ErrCodeInvalidPayload)
+		return
+	}
+
+	if !h.validator.Validate(c, &request) {
+		return
+	}
+
+	err = h.reviewsRepo.Report(c, models.ReviewReport{
+		ReviewId: review.Id,
+		UserId:   userId,
+		Reason:   request.Reason,
+		Comment:  request.Comment,
+	}, models.ReviewReportsToRequeue)
+	if err != nil {
+		respondInternalError(c, err)
+		return
+	}
+
+	c.Status(http.StatusOK)
+}
+
+// screen проверяет текст фильтром слов и выбирает статус нового или изменённого отзыва
+func (h *ReviewsHandlers) screen(c *gin.Context, body string) (string, []string, error) {
+	words, err := h.badWordsRepo.FindAll(c)
+	if err != nil {
+		return "", nil, err
+	}
+
+	list := make([]string, len(words))
+	for i, word := range words {
+		list[i] = word.Word
+	}
+
+	flagged := moderation.NewFilter(list).Match(body)
+	if len(flagged) > 0 || config.Config.ReviewPremoderate {
+		return models.ReviewStatusPending, flagged, nil
+	}
+
+	return models.ReviewStatusApproved, flagged, nil
+}
+
+// findOwnReview читает отзыв из пути; чужой или удалённый отзыв для пользователя не существует — 404
+func (h *ReviewsHandlers) findOwnReview(c *gin.Context) (models.Review, bool) {
+	userId, ok := currentUserId(c)
+	if !ok {
+		return models.Review{}, false
+	}
+
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		respondError(c, http.StatusBadRequest, models.ErrCodeInvalidId)
+		return models.Review{}, false
+	}
+
+	review, err := h.reviewsRepo.FindById(c, id)
+	if err != nil {
+		respondFindError(c, err)
+		return models.Review{}, false
+	}
+	if review.UserId != userId || review.IsDeleted {
+		respondError(c, http.StatusNotFound, models.ErrCodeNotFound)
+		return models.Review{}, false
+	}
+
+	return review, true
+}
+
+// findPublishedReview читает из пути одобренный отзыв; allowDeleted — подходит и удалённый, если ветка на нём держится
+func (h *ReviewsHandlers) findPublishedReview(c *gin.Context, allowDeleted bool) (models.Review, bool) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		respondError(c, http.StatusBadRequest, models.ErrCodeInvalidId)
+		return models.Review{}, false
+	}
+
+	review, err := h.reviewsRepo.FindById(c, id)
+	if err != nil {
+		respondFindError(c, err)
+		return models.Review{}, false
+	}
+	if review.Status != models.ReviewStatusApproved || (review.IsDeleted && !allowDeleted) {
+		respondError(c, http.StatusNotFound, models.ErrCodeNotFound)
+		return models.Review{}, false
+	}
+
+	if !checkMovieAllowed(c, h.parentalRepo, review.MovieId) {
+		return models.Review{}, false
+	}
+
+	return review, true
+}
+
+func isOwnReview(c *gin.Context, review models.Review) bool {
+	userId := viewerUserId(c)
+	return userId != nil && *userId == review.UserId
+}
+
+// publicReviews убирает из отзывов служебные поля модерации и отмечает свои
+func publicReviews(c *gin.Context, reviews []models.Review) []models.Review {
+	for i := range reviews {
+		reviews[i].IsOwn = isOwnReview(c, reviews[i])
+		reviews[i].FlaggedWords = nil
+		reviews[i].ModerationNote = nil
+		reviews[i].ReportCount = 0
+	}
+
+	return reviews
+}
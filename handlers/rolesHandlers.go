@@ -23,6 +23,8 @@ type RolesHandlers struct {
 	categoryRepo    *repositories.CategoryRepository
 	ageRepo         *repositories.AgeRepository
 	allserieRepo    *repositories.AllSeriesRepository
+	auditRepo       *repositories.AuditRepository
+	validator       *RequestValidator
 }
 
 func NewRolesHandlers(
@@ -32,7 +34,9 @@ func NewRolesHandlers(
 	genreRepo *repositories.GenresRepository,
 	categoryRepo *repositories.CategoryRepository,
 	ageRepo *repositories.AgeRepository,
-	allserieRepo *repositories.AllSeriesRepository) *RolesHandlers {
+	allserieRepo *repositories.AllSeriesRepository,
+	auditRepo *repositories.AuditRepository,
+	validator *RequestValidator) *RolesHandlers {
 	return &RolesHandlers{
 		rolesRepo:       rolesRepo,
 		userRepo:        userRepo,
@@ -40,26 +44,28 @@ func NewRolesHandlers(
 		genresRepo:      genreRepo,
 		categoryRepo:    categoryRepo,
 		ageRepo:         ageRepo,
-		allserieRepo:    allserieRepo}
+		allserieRepo:    allserieRepo,
+		auditRepo:       auditRepo,
+		validator:       validator}
 }
 
 type createRolesRequest struct {
-	Name        string
-	Email       string
-	Password    string
-	PhoneNumber *int
-	Birthday    *time.Time
+	Name        string     `validate:"required,max=255"`
+	Email       string     `validate:"required,email"`
+	Password    string     `validate:"required,min=6,max=72"`
+	PhoneNumber *int       `validate:"omitempty,gt=0"`
+	Birthday    *time.Time `validate:"omitempty,lt"`
 }
 
 type updateRolesRequest struct {
-	Name        string
-	Email       string
-	PhoneNumber *int
-	Birthday    *time.Time
+	Name        string     `validate:"required,max=255"`
+	Email       string     `validate:"required,email"`
+	PhoneNumber *int       `validate:"omitempty,gt=0"`
+	Birthday    *time.Time `validate:"omitempty,lt"`
 }
 
 type changeRolesPasswordRequest struct {
-	Password string
+	Password string `validate:"required,min=6,max=72"`
 }
 
 type rolesResponse struct {
@@ -70,6 +76,17 @@ type rolesResponse struct {
 	Birthday    *time.Time `json:"birthday"`
 }
 
+// auditRole — данные роли для журнала аудита, без хэша пароля
+func auditRole(role models.Roles) rolesResponse {
+	return rolesResponse{
+		Id:          role.Id,
+		Name:        role.Name,
+		Email:       role.Email,
+		PhoneNumber: role.PhoneNumber,
+		Birthday:    role.Birthday,
+	}
+}
+
 // FindById godoc
 // @Tags         roles - это выполняет роль Админа
 // @Summary      Find role by id
@@ -85,13 +102,13 @@ func (h *RolesHandlers) FindById(c *gin.Context) {
 	idStr := c.Param("id")
 	id, err := strconv.Atoi(idStr)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, models.NewApiError("Invalid role Id"))
+		respondError(c, http.StatusBadRequest, models.ErrCodeInvalidId)
 		return
 	}
 
 	role, err := h.rolesRepo.FindById(c, id)
 	if err != nil {
-		c.JSON(http.StatusNotFound, models.NewApiError("Role not found"))
+		respondError(c, http.StatusNotFound, models.ErrCodeNotFound)
 		return
 	}
 
@@ -117,7 +134,7 @@ func (h *RolesHandlers) FindById(c *gin.Context) {
 func (h *RolesHandlers) FindAll(c *gin.Context) {
 	roles, err := h.rolesRepo.FindAll(c)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, models.NewApiError("could not load roles"))
+		respondInternalError(c, err)
 		return
 	}
 
@@ -144,19 +161,24 @@ func (h *RolesHandlers) FindAll(c *gin.Context) {
 // @Param request body handlers.createRolesRequest true "Roles data"
 // @Success      200  {object} object{id=int} "OK"
 // @Failure   	 400  {object} models.ApiError "Invalid data"
+// @Failure   	 422  {object} models.ApiError "Validation failed"
 // @Failure   	 500  {object} models.ApiError
 // @Router       /roles [post]
 func (h *RolesHandlers) Create(c *gin.Context) {
 	var request createRolesRequest
 	err := c.BindJSON(&request)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, models.NewApiError("Invalid payload"))
+		respondError(c, http.StatusBadRequest, models.ErrCodeInvalidPayload)
+		return
+	}
+
+	if !h.validator.Validate(c, &request) {
 		return
 	}
 
 	passwordHash, err := bcrypt.GenerateFromPassword([]byte(request.Password), bcrypt.DefaultCost)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, models.NewApiError("Failed to hash password"))
+		respondInternalError(c, err)
 		return
 	}
 
@@ -170,10 +192,13 @@ func (h *RolesHandlers) Create(c *gin.Context) {
 
 	id, err := h.rolesRepo.Create(c, role)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, models.NewApiError("could not create role"))
+		respondInternalError(c, err)
 		return
 	}
 
+	role.Id = id
+	recordAudit(c, h.auditRepo, models.AuditActionCreate, models.AuditEntityRole, id, nil, auditRole(role))
+
 	c.JSON(http.StatusOK, gin.H{"id": id})
 }
 
@@ -187,28 +212,35 @@ func (h *RolesHandlers) Create(c *gin.Context) {
 // @Success      200  {object} object{id=int} "OK"
 // @Failure   	 400  {object} models.ApiError "Invalid data"
 // @Failure   	 404  {object} models.ApiError "Role not found"
+// @Failure   	 422  {object} models.ApiError "Validation failed"
 // @Failure   	 500  {object} models.ApiError
 // @Router       /roles/{id} [put]
 func (h *RolesHandlers) Update(c *gin.Context) {
 	idStr := c.Param("id")
 	id, err := strconv.Atoi(idStr)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, models.NewApiError("Invalid role Id"))
+		respondError(c, http.StatusBadRequest, models.ErrCodeInvalidId)
 		return
 	}
 
 	var request updateRolesRequest
 	if err := c.BindJSON(&request); err != nil {
-		c.JSON(http.StatusBadRequest, models.NewApiError("Invalid request payload"))
+		respondError(c, http.StatusBadRequest, models.ErrCodeInvalidPayload)
+		return
+	}
+
+	if !h.validator.Validate(c, &request) {
 		return
 	}
 
 	roles, err := h.rolesRepo.FindById(c, id)
 	if err != nil {
-		c.JSON(http.StatusNotFound, models.NewApiError("Role not found"))
+		respondError(c, http.StatusNotFound, models.ErrCodeNotFound)
 		return
 	}
 
+	before := auditRole(roles)
+
 	roles.Name = request.Name
 	roles.Email = request.Email
 	roles.PhoneNumber = request.PhoneNumber
@@ -216,10 +248,12 @@ func (h *RolesHandlers) Update(c *gin.Context) {
 
 	err = h.rolesRepo.Update(c, id, roles)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, models.NewApiError(err.Error()))
+		respondInternalError(c, err)
 		return
 	}
 
+	recordAudit(c, h.auditRepo, models.AuditActionUpdate, models.AuditEntityRole, id, before, auditRole(roles))
+
 	c.Status(http.StatusOK)
 }
 
@@ -238,22 +272,24 @@ func (h *RolesHandlers) Delete(c *gin.Context) {
 	idStr := c.Param("id")
 	id, err := strconv.Atoi(idStr)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, models.NewApiError("Invalid role Id"))
+		respondError(c, http.StatusBadRequest, models.ErrCodeInvalidId)
 		return
 	}
 
-	_, err = h.rolesRepo.FindById(c, id)
+	role, err := h.rolesRepo.FindById(c, id)
 	if err != nil {
-		c.JSON(http.StatusNotFound, models.NewApiError("Role not found"))
+		respondError(c, http.StatusNotFound, models.ErrCodeNotFound)
 		return
 	}
 
 	err = h.rolesRepo.Delete(c, id)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, models.NewApiError(err.Error()))
+		respondInternalError(c, err)
 		return
 	}
 
+	recordAudit(c, h.auditRepo, models.AuditActionDelete, models.AuditEntityRole, id, auditRole(role), nil)
+
 	c.Status(http.StatusOK)
 }
 
@@ -267,31 +303,36 @@ func (h *RolesHandlers) Delete(c *gin.Context) {
 // @Success      200  "OK"
 // @Failure   	 400  {object} models.ApiError "Invalid data"
 // @Failure   	 404  {object} models.ApiError "Role not found"
+// @Failure   	 422  {object} models.ApiError "Validation failed"
 // @Failure   	 500  {object} models.ApiError
 // @Router       /roles/{id}/changePassword [patch]
 func (h *RolesHandlers) ChangePassword(c *gin.Context) {
 	idStr := c.Param("id")
 	id, err := strconv.Atoi(idStr)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, models.NewApiError("Invalid role Id"))
+		respondError(c, http.StatusBadRequest, models.ErrCodeInvalidId)
 		return
 	}
 
 	var request changePasswordRequest
 	if err := c.BindJSON(&request); err != nil {
-		c.JSON(http.StatusBadRequest, models.NewApiError("Invalid request payload"))
+		respondError(c, http.StatusBadRequest, models.ErrCodeInvalidPayload)
+		return
+	}
+
+	if !h.validator.Validate(c, &request) {
 		return
 	}
 
 	passwordHash, err := bcrypt.GenerateFromPassword([]byte(request.Password), bcrypt.DefaultCost)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, models.NewApiError("Failed to hash password"))
+		respondInternalError(c, err)
 		return
 	}
 
 	role, err := h.rolesRepo.FindById(c, id)
 	if err != nil {
-		c.JSON(http.StatusNotFound, models.NewApiError("Role not found"))
+		respondError(c, http.StatusNotFound, models.ErrCodeNotFound)
 		return
 	}
 
@@ -299,10 +340,12 @@ func (h *RolesHandlers) ChangePassword(c *gin.Context) {
 
 	err = h.rolesRepo.Update(c, id, role)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, models.NewApiError(err.Error()))
+		respondInternalError(c, err)
 		return
 	}
 
+	recordAudit(c, h.auditRepo, models.AuditActionUpdate, models.AuditEntityRole, id, nil, auditPasswordChanged)
+
 	c.Status(http.StatusOK)
 }
 
@@ -321,13 +364,13 @@ func (h *RolesHandlers) FindByIdUser(c *gin.Context) {
 	idStr := c.Param("id")
 	id, err := strconv.Atoi(idStr)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, models.NewApiError("Invalid user Id"))
+		respondError(c, http.StatusBadRequest, models.ErrCodeInvalidId)
 		return
 	}
 
 	user, err := h.userRepo.FindById(c, id)
 	if err != nil {
-		c.JSON(http.StatusNotFound, models.NewApiError("User not found"))
+		respondError(c, http.StatusNotFound, models.ErrCodeNotFound)
 		return
 	}
 
@@ -352,7 +395,7 @@ func (h *RolesHandlers) FindByIdUser(c *gin.Context) {
 func (h *RolesHandlers) FindAllUsers(c *gin.Context) {
 	users, err := h.userRepo.FindAll(c)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, models.NewApiError("could not load users"))
+		respondInternalError(c, err)
 		return
 	}
 
@@ -381,28 +424,35 @@ func (h *RolesHandlers) FindAllUsers(c *gin.Context) {
 // @Success      200  {object} object{id=int} "OK"
 // @Failure   	 400  {object} models.ApiError "Invalid data"
 // @Failure   	 404  {object} models.ApiError "User not found"
+// @Failure   	 422  {object} models.ApiError "Validation failed"
 // @Failure   	 500  {object} models.ApiError
 // @Router       /rolesuser/{id} [put]
 func (h *RolesHandlers) UpdateUser(c *gin.Context) {
 	idStr := c.Param("id")
 	id, err := strconv.Atoi(idStr)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, models.NewApiError("Invalid user Id"))
+		respondError(c, http.StatusBadRequest, models.ErrCodeInvalidId)
 		return
 	}
 
 	var request updateUserRequest
 	if err := c.BindJSON(&request); err != nil {
-		c.JSON(http.StatusBadRequest, models.NewApiError("Invalid request payload"))
+		respondError(c, http.StatusBadRequest, models.ErrCodeInvalidPayload)
+		return
+	}
+
+	if !h.validator.Validate(c, &request) {
 		return
 	}
 
 	user, err := h.userRepo.FindById(c, id)
 	if err != nil {
-		c.JSON(http.StatusNotFound, models.NewApiError("User not found"))
+		respondError(c, http.StatusNotFound, models.ErrCodeNotFound)
 		return
 	}
 
+	before := auditUser(user)
+
 	user.Name = request.Name
 	user.Email = request.Email
 	user.PhoneNumber = request.PhoneNumber
@@ -410,10 +460,12 @@ func (h *RolesHandlers) UpdateUser(c *gin.Context) {
 
 	err = h.userRepo.Update(c, id, user)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, models.NewApiError(err.Error()))
+		respondInternalError(c, err)
 		return
 	}
 
+	recordAudit(c, h.auditRepo, models.AuditActionUpdate, models.AuditEntityUser, id, before, auditUser(user))
+
 	c.Status(http.StatusOK)
 }
 
@@ -432,22 +484,24 @@ func (h *RolesHandlers) DeleteUser(c *gin.Context) {
 	idStr := c.Param("id")
 	id, err := strconv.Atoi(idStr)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, models.NewApiError("Invalid user Id"))
+		respondError(c, http.StatusBadRequest, models.ErrCodeInvalidId)
 		return
 	}
 
-	_, err = h.userRepo.FindById(c, id)
+	user, err := h.userRepo.FindById(c, id)
 	if err != nil {
-		c.JSON(http.StatusNotFound, models.NewApiError("User not found"))
+		respondError(c, http.StatusNotFound, models.ErrCodeNotFound)
 		return
 	}
 
 	err = h.userRepo.Delete(c, id)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, models.NewApiError(err.Error()))
+		respondInternalError(c, err)
 		return
 	}
 
+	recordAudit(c, h.auditRepo, models.AuditActionDelete, models.AuditEntityUser, id, auditUser(user), nil)
+
 	c.Status(http.StatusOK)
 }
 
@@ -461,31 +515,36 @@ func (h *RolesHandlers) DeleteUser(c *gin.Context) {
 // @Success      200  "OK"
 // @Failure   	 400  {object} models.ApiError "Invalid data"
 // @Failure   	 404  {object} models.ApiError "User not found"
+// @Failure   	 422  {object} models.ApiError "Validation failed"
 // @Failure   	 500  {object} models.ApiError
 // @Router       /rolesuser/{id}/changePassword [patch]
 func (h *RolesHandlers) ChangePasswordUser(c *gin.Context) {
 	idStr := c.Param("id")
 	id, err := strconv.Atoi(idStr)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, models.NewApiError("Invalid user Id"))
+		respondError(c, http.StatusBadRequest, models.ErrCodeInvalidId)
 		return
 	}
 
 	var request changePasswordRequest
 	if err := c.BindJSON(&request); err != nil {
-		c.JSON(http.StatusBadRequest, models.NewApiError("Invalid request payload"))
+		respondError(c, http.StatusBadRequest, models.ErrCodeInvalidPayload)
+		return
+	}
+
+	if !h.validator.Validate(c, &request) {
 		return
 	}
 
 	passwordHash, err := bcrypt.GenerateFromPassword([]byte(request.Password), bcrypt.DefaultCost)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, models.NewApiError("Failed to hash password"))
+		respondInternalError(c, err)
 		return
 	}
 
 	user, err := h.userRepo.FindById(c, id)
 	if err != nil {
-		c.JSON(http.StatusNotFound, models.NewApiError("User not found"))
+		respondError(c, http.StatusNotFound, models.ErrCodeNotFound)
 		return
 	}
 
@@ -493,10 +552,12 @@ func (h *RolesHandlers) ChangePasswordUser(c *gin.Context) {
 
 	err = h.userRepo.Update(c, id, user)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, models.NewApiError(err.Error()))
+		respondInternalError(c, err)
 		return
 	}
 
+	recordAudit(c, h.auditRepo, models.AuditActionUpdate, models.AuditEntityUser, id, nil, auditPasswordChanged)
+
 	c.Status(http.StatusOK)
 }
 
@@ -508,22 +569,25 @@ func (h *RolesHandlers) ChangePasswordUser(c *gin.Context) {
 // @Param        id path int true "Movie id"
 // @Success      200  {object}  models.MovieAdminResponse
 // @Failure      400  {object}  models.ApiError "Invalid Movie Id"
+// @Failure      404  {object}  models.ApiError "Not found"
+// @Failure      500  {object}  models.ApiError
 // @Router       /rolesmovie/{id} [get]
 func (h *RolesHandlers) FindByIdMoviesAdmin(c *gin.Context) {
 	idStr := c.Param("id")
 	id, err := strconv.Atoi(idStr)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, models.NewApiError("Invalid Movie Id"))
+		respondError(c, http.StatusBadRequest, models.ErrCodeInvalidId)
 
 		return
 	}
 
 	movie, err := h.moviesAdminRepo.FindById(c, id)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, models.NewApiError(err.Error()))
+		respondFindError(c, err)
 		return
 	}
 
+	setETag(c, movie.Version)
 	c.JSON(http.StatusOK, movie)
 }
 
@@ -532,19 +596,27 @@ func (h *RolesHandlers) FindByIdMoviesAdmin(c *gin.Context) {
 // @Tags         Админ получает список фильмов
 // @Accept       json
 // @Produce      json
+// @Param        personId query int false "Only movies with this person in cast or crew"
 // @Success      200  {object}  []models.MovieAdminResponse "List of movies"
+// @Failure      400  {object}  models.ApiError "Invalid personId"
 // @Failure      500  {object}  models.ApiError "Internal Server Error"
 // @Router       /rolesmovie [get]
 func (h *RolesHandlers) FindAllMoviesforAdmin(c *gin.Context) {
+	personId, ok := intQuery(c, "personId")
+	if !ok {
+		return
+	}
+
 	filters := models.MovieFilters{
 		SearchTerm: c.Query("search"),
 		IsWatched:  c.Query("iswatched"),
 		GenreId:    c.Query("genreids"),
+		PersonId:   personId,
 		Sort:       c.Query("sort"),
 	}
 	movies, err := h.moviesAdminRepo.FindAll(c, filters)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, err)
+		respondInternalError(c, err)
 		return
 	}
 
@@ -568,62 +640,84 @@ func (h *RolesHandlers) saveMoviesPoster(c *gin.Context, poster *multipart.FileH
 // @Param        description body string true "Description of the movie"
 // @Param        releaseYear body int true "ReleaseYear of the movie"
 // @Param        director body string true "Director"
-// @Param        trailerUrl body string true "TrailerUrl"
+// @Param        trailerUrl body string true "Trailer URL: YouTube, Vimeo or direct MP4/HLS"
 // @Param      	 genreIds body []int true "Genre ids"
 // @Param		 categoryIds body []int true "Category ids"
 // @Param        ageIds body []int true "Age ids"
 // @Success      200  {object}  object{id=int} "OK"
 // @Failure      400  {object}  models.ApiError "Could not bind json"
 // @Failure      500  {object}  models.ApiError
+// @Param        If-Match header string true "ETag of the current version"
+// @Failure      412  {object}  models.ApiError "Resource was modified"
+// @Failure      428  {object}  models.ApiError "If-Match header required"
+// @Failure      404  {object}  models.ApiError "Not found"
+// @Failure      422  {object}  models.ApiError "Validation failed"
 // @Router       /rolesmovie/{id} [put]
 func (h *RolesHandlers) UpdateMovies(c *gin.Context) {
 	idStr := c.Param("id")
 	id, err := strconv.Atoi(idStr)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, models.NewApiError("Invalid Movie Id"))
+		respondError(c, http.StatusBadRequest, models.ErrCodeInvalidId)
 		return
 	}
 
-	_, err = h.moviesAdminRepo.FindById(c, id)
+	before, err := h.moviesAdminRepo.FindById(c, id)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, models.NewApiError(err.Error()))
+		respondFindError(c, err)
+		return
+	}
+
+	version, ok := ifMatchVersion(c)
+	if !ok {
+		return
+	}
+
+	if !checkIfMatch(c, version, before.Version) {
 		return
 	}
 
 	var request updateMovieAdminResponseRequest
 	err = c.Bind(&request)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, models.NewApiError("Could not bind json"))
+		respondError(c, http.StatusBadRequest, models.ErrCodeInvalidPayload)
+		return
+	}
+
+	if !h.validator.Validate(c, &request) {
 		return
 	}
 
 	genres, err := h.genresRepo.FindAllByIds(c, request.GenreIds)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, models.NewApiError(err.Error()))
+		respondInternalError(c, err)
 		return
 	}
 	categories, err := h.categoryRepo.FindAllByIds(c, request.CategoryIds)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, models.NewApiError(err.Error()))
+		respondInternalError(c, err)
 		return
 	}
 
 	ages, err := h.ageRepo.FindAllByIds(c, request.AgeIds)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, models.NewApiError(err.Error()))
+		respondInternalError(c, err)
 		return
 	}
 
-	allseries, err := h.allserieRepo.FindAllByIds(c, request.AgeIds)
+	allseries, err := h.allserieRepo.FindAllByIds(c, request.AllserieIds)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, models.NewApiError(err.Error()))
+		respondInternalError(c, err)
 		return
 	}
 
-	filename, err := h.saveMoviesPoster(c, request.PosterUrl)
-	if err != nil {
-		c.JSON(http.StatusInternalServerError, models.NewApiError(err.Error()))
-		return
+	// без нового файла постер остаётся прежним
+	filename := before.PosterUrl
+	if request.PosterUrl != nil {
+		filename, err = h.saveMoviesPoster(c, request.PosterUrl)
+		if err != nil {
+			respondInternalError(c, err)
+			return
+		}
 	}
 
 	movie := models.MovieAdminResponse{
@@ -631,20 +725,33 @@ func (h *RolesHandlers) UpdateMovies(c *gin.Context) {
 		Description: request.Description,
 		ReleaseYear: request.ReleaseYear,
 		Director:    request.Director,
-		TrailerUrl:  request.TrailerUrl,
+		TrailerUrl:  models.NormalizeTrailerUrl(request.TrailerUrl),
 		PosterUrl:   filename,
 		Genres:      genres,
 		Category:    categories,
 		Ages:        ages,
 		AllSeries:   allseries,
+
+		Version: version,
 	}
 
-	err = h.moviesAdminRepo.Update(c, id, movie)
+	err = h.moviesAdminRepo.Update(c, id, movie, newMovieRevision(c, models.Movie(before)))
+	if isVersionConflict(err) {
+		respondVersionConflict(c)
+		return
+	}
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, models.NewApiError("Failed to update movie"))
+		respondInternalError(c, err)
 		return
 	}
 
+	after, err := h.moviesAdminRepo.FindById(c, id)
+	if err == nil {
+		recordAudit(c, h.auditRepo, models.AuditActionUpdate, models.AuditEntityMovie, id, before, after)
+	}
+
+	setETag(c, version+1)
+
 	c.Status(http.StatusOK)
 }
 
@@ -657,22 +764,45 @@ func (h *RolesHandlers) UpdateMovies(c *gin.Context) {
 // @Success      200  "OK"
 // @Failure   	 400  {object} models.ApiError "Invalid data"
 // @Failure   	 500  {object} models.ApiError
+// @Param        If-Match header string true "ETag of the current version"
+// @Failure      412  {object}  models.ApiError "Resource was modified"
+// @Failure      428  {object}  models.ApiError "If-Match header required"
+// @Failure      404  {object}  models.ApiError "Not found"
 // @Router       /rolesmovie/{id} [delete]
 func (h *RolesHandlers) DeleteMovie(c *gin.Context) {
 	idStr := c.Param("id")
 	id, err := strconv.Atoi(idStr)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, models.NewApiError("Invalid Movie Id"))
+		respondError(c, http.StatusBadRequest, models.ErrCodeInvalidId)
 		return
 	}
 
-	_, err = h.moviesAdminRepo.FindById(c, id)
+	before, err := h.moviesAdminRepo.FindById(c, id)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, models.NewApiError(err.Error()))
+		respondFindError(c, err)
 		return
 	}
 
-	h.moviesAdminRepo.Delete(c, id)
+	version, ok := ifMatchVersion(c)
+	if !ok {
+		return
+	}
+
+	if !checkIfMatch(c, version, before.Version) {
+		return
+	}
+
+	err = h.moviesAdminRepo.Delete(c, id, version)
+	if isVersionConflict(err) {
+		respondVersionConflict(c)
+		return
+	}
+	if err != nil {
+		respondInternalError(c, err)
+		return
+	}
+
+	recordAudit(c, h.auditRepo, models.AuditActionDelete, models.AuditEntityMovie, id, before, nil)
 
 	c.Status(http.StatusNoContent)
 }
@@ -687,27 +817,43 @@ func (h *RolesHandlers) DeleteMovie(c *gin.Context) {
 // @Success      200  "OK"
 // @Failure   	 400  {object} models.ApiError "Invalid data"
 // @Failure   	 500  {object} models.ApiError
+// @Param        If-Match header string true "ETag of the current version"
+// @Failure      412  {object}  models.ApiError "Resource was modified"
+// @Failure      428  {object}  models.ApiError "If-Match header required"
 // @Router       /rolesmovie/{id}/setWatched [patch]
 func (h *RolesHandlers) HandleSetWatched(c *gin.Context) {
 	idStr := c.Param("movieId")
 	id, err := strconv.Atoi(idStr)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, models.NewApiError("Invalid Movie Id"))
+		respondError(c, http.StatusBadRequest, models.ErrCodeInvalidId)
 		return
 	}
 
 	isWatchedStr := c.Query("isWatched")
 	isWatched, err := strconv.ParseBool(isWatchedStr)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, models.NewApiError("Invalid isWatched value"))
+		respondValidationError(c, models.NewApiErrorDetail("iswatched", models.DetailCodeInvalid))
+		return
+	}
+
+	version, ok := ifMatchVersion(c)
+	if !ok {
 		return
 	}
 
-	err = h.moviesAdminRepo.SetWatched(c, id, isWatched)
+	err = h.moviesAdminRepo.SetWatched(c, id, isWatched, version)
+	if isVersionConflict(err) {
+		respondVersionConflict(c)
+		return
+	}
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, models.NewApiError(err.Error()))
+		respondInternalError(c, err)
 		return
 	}
 
+	recordAudit(c, h.auditRepo, models.AuditActionUpdate, models.AuditEntityMovie, id, nil, gin.H{"IsWatched": isWatched})
+
+	setETag(c, version+1)
+
 	c.Status(http.StatusOK)
 }
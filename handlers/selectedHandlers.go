@@ -12,10 +12,11 @@ import (
 type SelectedlistHandler struct {
 	moviesRepo       *repositories.MoviesRepository
 	SelectedlistRepo *repositories.SelectedlistRepository
+	translationsRepo *repositories.TranslationsRepository
 }
 
-func NewSelectedlistHandler(moviesRepo *repositories.MoviesRepository, SelectedlistRepo *repositories.SelectedlistRepository) *SelectedlistHandler {
-	return &SelectedlistHandler{moviesRepo: moviesRepo, SelectedlistRepo: SelectedlistRepo}
+func NewSelectedlistHandler(moviesRepo *repositories.MoviesRepository, SelectedlistRepo *repositories.SelectedlistRepository, translationsRepo *repositories.TranslationsRepository) *SelectedlistHandler {
+	return &SelectedlistHandler{moviesRepo: moviesRepo, SelectedlistRepo: SelectedlistRepo, translationsRepo: translationsRepo}
 }
 
 // HandleGetMovies godoc
@@ -25,11 +26,22 @@ func NewSelectedlistHandler(moviesRepo *repositories.MoviesRepository, Selectedl
 // @Produce      json
 // @Success      200 {array} models.Movie "OK"
 // @Failure   	 500  {object} models.ApiError
+// @Param        lang query string false "kk, ru or en"
+// @Param        Accept-Language header string false "Preferred locales"
 // @Router       /selected [get]
 func (h *SelectedlistHandler) HandleGetMoviesAndSeries(c *gin.Context) {
-	movies, err := h.SelectedlistRepo.GetMoviesFromSelectedlist(c)
+	movies, err := h.SelectedlistRepo.GetMoviesFromSelectedlist(c, ageLimit(c), viewerUserId(c), currentProfileId(c))
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, models.NewApiError(err.Error()))
+		respondInternalError(c, err)
+		return
+	}
+
+	loc := newLocalizer(c, h.translationsRepo)
+	for i := range movies {
+		loc.addMovie(&movies[i])
+	}
+	if err := loc.apply(c); err != nil {
+		respondInternalError(c, err)
 		return
 	}
 
@@ -54,18 +66,18 @@ func (h *SelectedlistHandler) HandleAddMovie(c *gin.Context) {
 	movieIdStr := c.Param("movieId")
 	movieId, err := strconv.Atoi(movieIdStr)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, models.NewApiError("Invalid movie id"))
+		respondError(c, http.StatusBadRequest, models.ErrCodeInvalidId)
 		return
 	}
 
 	_, err = h.moviesRepo.FindById(c, movieId)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, models.NewApiError(err.Error()))
+		respondInternalError(c, err)
 		return
 	}
 	err = h.SelectedlistRepo.AddToSelectedMovie(c, movieId)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, models.NewApiError(err.Error()))
+		respondInternalError(c, err)
 		return
 	}
 }
@@ -84,19 +96,19 @@ func (h *SelectedlistHandler) HandleRemoveMovie(c *gin.Context) {
 	movieIdStr := c.Param("movieId")
 	movieId, err := strconv.Atoi(movieIdStr)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, models.NewApiError("Invalid movie id"))
+		respondError(c, http.StatusBadRequest, models.ErrCodeInvalidId)
 		return
 	}
 
 	_, err = h.moviesRepo.FindById(c, movieId)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, models.NewApiError(err.Error()))
+		respondInternalError(c, err)
 		return
 	}
 
 	err = h.SelectedlistRepo.RemoveFromSelectedlist(c, movieId)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, models.NewApiError(err.Error()))
+		respondInternalError(c, err)
 		return
 	}
 
@@ -0,0 +1,202 @@
+package handlers
+
+import (
+	"goozinshe/models"
+	"goozinshe/repositories"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+type SessionsHandlers struct {
+	sessionsRepo *repositories.SessionsRepository
+	usersRepo    *repositories.UsersRepository
+}
+
+func NewSessionsHandlers(sessionsRepo *repositories.SessionsRepository, usersRepo *repositories.UsersRepository) *SessionsHandlers {
+	return &SessionsHandlers{sessionsRepo: sessionsRepo, usersRepo: usersRepo}
+}
+
+type sessionResponse struct {
+	Id         string    `json:"id"`
+	UserAgent  string    `json:"userAgent"`
+	Ip         string    `json:"ip"`
+	CreatedAt  time.Time `json:"createdAt"`
+	LastSeenAt time.Time `json:"lastSeenAt"`
+	ExpiresAt  time.Time `json:"expiresAt"`
+	Current    bool      `json:"current"`
+}
+
+func newSessionResponses(sessions []models.Session, currentSessionId string) []sessionResponse {
+	dtos := make([]sessionResponse, 0, len(sessions))
+	for _, s := range sessions {
+		dtos = append(dtos, sessionResponse{
+			Id:         s.Id,
+			UserAgent:  s.UserAgent,
+			Ip:         s.Ip,
+			CreatedAt:  s.CreatedAt,
+			LastSeenAt: s.LastSeenAt,
+			ExpiresAt:  s.ExpiresAt,
+			Current:    s.Id == currentSessionId,
+		})
+	}
+
+	return dtos
+}
+
+// FindMine godoc
+// @Tags         sessions
+// @Summary      List active sessions of current user
+// @Accept       json
+// @Produce      json
+// @Success      200  {array} handlers.sessionResponse "OK"
+// @Failure   	 500  {object} models.ApiError
+// @Router       /me/sessions [get]
+// @Security Bearer
+func (h *SessionsHandlers) FindMine(c *gin.Context) {
+	sessions, err := h.sessionsRepo.FindActiveByUser(c, c.GetInt("userId"))
+	if err != nil {
+		respondInternalError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, newSessionResponses(sessions, c.GetString("sessionId")))
+}
+
+// TerminateMine godoc
+// @Tags         sessions
+// @Summary      Sign out one of current user's sessions
+// @Accept       json
+// @Produce      json
+// @Param id path string true "Session id"
+// @Success      200  "OK"
+// @Failure   	 404  {object} models.ApiError "Session not found"
+// @Failure   	 500  {object} models.ApiError
+// @Router       /me/sessions/{id} [delete]
+// @Security Bearer
+func (h *SessionsHandlers) TerminateMine(c *gin.Context) {
+	sessionId := c.Param("id")
+
+	session, err := h.sessionsRepo.FindById(c, sessionId)
+	if err != nil || session.UserId != c.GetInt("userId") {
+		respondError(c, http.StatusNotFound, models.ErrCodeNotFound)
+		return
+	}
+
+	err = h.sessionsRepo.Terminate(c, sessionId)
+	if err != nil {
+		respondInternalError(c, err)
+		return
+	}
+
+	c.Status(http.StatusOK)
+}
+
+// FindByUser godoc
+// @Tags         sessions
+// @Summary      List active sessions of user (admin)
+// @Accept       json
+// @Produce      json
+// @Param id path int true "User id"
+// @Success      200  {array} handlers.sessionResponse "OK"
+// @Failure   	 400  {object} models.ApiError "Invalid user id"
+// @Failure   	 404  {object} models.ApiError "User not found"
+// @Failure   	 500  {object} models.ApiError
+// @Router       /admin/users/{id}/sessions [get]
+// @Security Bearer
+func (h *SessionsHandlers) FindByUser(c *gin.Context) {
+	idStr := c.Param("id")
+	userId, err := strconv.Atoi(idStr)
+	if err != nil {
+		respondError(c, http.StatusBadRequest, models.ErrCodeInvalidId)
+		return
+	}
+
+	_, err = h.usersRepo.FindById(c, userId)
+	if err != nil {
+		respondError(c, http.StatusNotFound, models.ErrCodeNotFound)
+		return
+	}
+
+	sessions, err := h.sessionsRepo.FindActiveByUser(c, userId)
+	if err != nil {
+		respondInternalError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, newSessionResponses(sessions, c.GetString("sessionId")))
+}
+
+// TerminateByUser godoc
+// @Tags         sessions
+// @Summary      Sign out user's session (admin)
+// @Accept       json
+// @Produce      json
+// @Param id path int true "User id"
+// @Param sessionId path string true "Session id"
+// @Success      200  "OK"
+// @Failure   	 400  {object} models.ApiError "Invalid user id"
+// @Failure   	 404  {object} models.ApiError "Session not found"
+// @Failure   	 500  {object} models.ApiError
+// @Router       /admin/users/{id}/sessions/{sessionId} [delete]
+// @Security Bearer
+func (h *SessionsHandlers) TerminateByUser(c *gin.Context) {
+	idStr := c.Param("id")
+	userId, err := strconv.Atoi(idStr)
+	if err != nil {
+		respondError(c, http.StatusBadRequest, models.ErrCodeInvalidId)
+		return
+	}
+
+	sessionId := c.Param("sessionId")
+	session, err := h.sessionsRepo.FindById(c, sessionId)
+	if err != nil || session.UserId != userId {
+		respondError(c, http.StatusNotFound, models.ErrCodeNotFound)
+		return
+	}
+
+	err = h.sessionsRepo.Terminate(c, sessionId)
+	if err != nil {
+		respondInternalError(c, err)
+		return
+	}
+
+	c.Status(http.StatusOK)
+}
+
+// TerminateAllByUser godoc
+// @Tags         sessions
+// @Summary      Sign out user from all devices (admin)
+// @Accept       json
+// @Produce      json
+// @Param id path int true "User id"
+// @Success      200  "OK"
+// @Failure   	 400  {object} models.ApiError "Invalid user id"
+// @Failure   	 404  {object} models.ApiError "User not found"
+// @Failure   	 500  {object} models.ApiError
+// @Router       /admin/users/{id}/sessions [delete]
+// @Security Bearer
+func (h *SessionsHandlers) TerminateAllByUser(c *gin.Context) {
+	idStr := c.Param("id")
+	userId, err := strconv.Atoi(idStr)
+	if err != nil {
+		respondError(c, http.StatusBadRequest, models.ErrCodeInvalidId)
+		return
+	}
+
+	_, err = h.usersRepo.FindById(c, userId)
+	if err != nil {
+		respondError(c, http.StatusNotFound, models.ErrCodeNotFound)
+		return
+	}
+
+	err = h.sessionsRepo.TerminateAllByUser(c, userId)
+	if err != nil {
+		respondInternalError(c, err)
+		return
+	}
+
+	c.Status(http.StatusOK)
+}
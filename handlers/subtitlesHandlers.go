@@ -0,0 +1,480 @@
+package handlers
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"goozinshe/models"
+	"goozinshe/repositories"
+	"goozinshe/storage"
+	"goozinshe/subtitles"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	subtitleMaxSize     = 2 << 20
+	subtitleContentType = "text/vtt; charset=utf-8"
+)
+
+type SubtitlesHandlers struct {
+	subtitlesRepo   *repositories.SubtitleTracksRepository
+	moviesAdminRepo *repositories.MoviesAdminRepository
+	auditRepo       *repositories.AuditRepository
+	storage         *storage.LocalStorage
+	validator       *RequestValidator
+	parentalRepo    *repositories.ParentalControlsRepository
+}
+
+func NewSubtitlesHandlers(
+	subtitlesRepo *repositories.SubtitleTracksRepository,
+	moviesAdminRepo *repositories.MoviesAdminRepository,
+	auditRepo *repositories.AuditRepository,
+	storage *storage.LocalStorage,
+	validator *RequestValidator,
+	parentalRepo *repositories.ParentalControlsRepository) *SubtitlesHandlers {
+	return &SubtitlesHandlers{
+		subtitlesRepo:   subtitlesRepo,
+		moviesAdminRepo: moviesAdminRepo,
+		auditRepo:       auditRepo,
+		storage:         storage,
+		validator:       validator,
+		parentalRepo:    parentalRepo,
+	}
+}
+
+type createSubtitleRequest struct {
+	MovieId     int                   `form:"movieId" validate:"required,exists=movies"`
+	AllSeriesId *int                  `form:"allSeriesId" validate:"omitempty,exists=allseries"`
+	Language    string                `form:"language" validate:"required,bcp47_language_tag"`
+	Label       string                `form:"label" validate:"required,max=100"`
+	IsDefault   bool                  `form:"isDefault"`
+	OffsetMs    int                   `form:"offsetMs" validate:"min=-3600000,max=3600000"`
+	File        *multipart.FileHeader `form:"file" validate:"required"`
+}
+
+type updateSubtitleRequest struct {
+	Language  string `json:"language" validate:"required,bcp47_language_tag"`
+	Label     string `json:"label" validate:"required,max=100"`
+	IsDefault bool   `json:"isDefault"`
+	OffsetMs  int    `json:"offsetMs" validate:"min=-3600000,max=3600000"`
+}
+
+// Create godoc
+// @Tags         subtitles
+// @Summary      Upload subtitle track
+// @Description  Принимает SRT или WebVTT в UTF-8 (до 2 МБ), проверяет тайминги и сохраняет в WebVTT.
+// @Description  Ошибка разбора возвращается в поле file с номером строки.
+// @Accept       multipart/form-data
+// @Produce      json
+// @Param movieId formData int true "Movie id"
+// @Param allSeriesId formData int false "Episode id, if the track belongs to an episode"
+// @Param language formData string true "BCP 47 language tag, e.g. ru or kk"
+// @Param label formData string true "Track name shown in the player"
+// @Param isDefault formData bool false "Show this track by default"
+// @Param offsetMs formData int false "Timing shift in milliseconds"
+// @Param file formData file true "SRT or WebVTT file"
+// @Success      201  {object} models.SubtitleTrack "Created"
+// @Header       201  {string} ETag "Track version"
+// @Failure   	 400  {object} models.ApiError "Invalid data"
+// @Failure   	 422  {object} models.ApiError "Validation failed"
+// @Failure   	 500  {object} models.ApiError
+// @Router       /admin/subtitles [post]
+// @Security Bearer
+func (h *SubtitlesHandlers) Create(c *gin.Context) {
+	var request createSubtitleRequest
+	err := c.Bind(&request)
+	if err != nil {
+		respondError(c, http.StatusBadRequest, models.ErrCodeInvalidPayload)
+		return
+	}
+
+	if !h.validator.Validate(c, &request) {
+		return
+	}
+
+	details := make([]models.ApiErrorDetail, 0)
+	if request.AllSeriesId != nil {
+		movie, err := h.moviesAdminRepo.FindById(c, request.MovieId)
+		if err != nil {
+			respondFindError(c, err)
+			return
+		}
+
+		belongs := slices.ContainsFunc(movie.AllSeries, func(episode models.AllSeries) bool {
+			return episode.Id != nil && *episode.Id == *request.AllSeriesId
+		})
+		if !belongs {
+			details = append(details, models.NewApiErrorDetail("allSeriesId", models.DetailCodeNotAllowed))
+		}
+	}
+
+	content, detail, err := readSubtitleFile(request.File)
+	if err != nil {
+		respondInternalError(c, err)
+		return
+	}
+	if detail != nil {
+		details = append(details, *detail)
+	}
+	if len(details) > 0 {
+		respondError(c, http.StatusUnprocessableEntity, models.ErrCodeValidation, details...)
+		return
+	}
+
+	track, err := h.subtitlesRepo.Create(c, models.SubtitleTrack{
+		MovieId:     request.MovieId,
+		AllSeriesId: request.AllSeriesId,
+		Language:    request.Language,
+		Label:       request.Label,
+		IsDefault:   request.IsDefault,
+		OffsetMs:    request.OffsetMs,
+	})
+	if err != nil {
+		respondInternalError(c, err)
+		return
+	}
+
+	err = h.storage.WriteFile(subtitleStorageKey(track.Id), content)
+	if err != nil {
+		h.subtitlesRepo.Delete(c, track.Id, track.Version)
+		respondInternalError(c, err)
+		return
+	}
+
+	recordAudit(c, h.auditRepo, models.AuditActionCreate, models.AuditEntitySubtitle, track.Id, nil, track)
+
+	setETag(c, track.Version)
+	c.JSON(http.StatusCreated, track)
+}
+
+// readSubtitleFile разбирает загруженный файл и возвращает его в WebVTT без сдвига.
+// Ошибки содержимого возвращаются как detail для 422, err — только ошибки чтения
+func readSubtitleFile(header *multipart.FileHeader) ([]byte, *models.ApiErrorDetail, error) {
+	if header.Size > subtitleMaxSize {
+		detail := models.NewApiErrorDetail("file", models.DetailCodeOutOfRange)
+		return nil, &detail, nil
+	}
+
+	file, err := header.Open()
+	if err != nil {
+		return nil, nil, err
+	}
+	defer file.Close()
+
+	data, err := io.ReadAll(io.LimitReader(file, subtitleMaxSize))
+	if err != nil {
+		return nil, nil, err
+	}
+
+	cues, err := subtitles.Parse(data)
+	if err != nil {
+		detail := models.NewApiErrorDetail("file", models.DetailCodeFormat)
+		var parseErr *subtitles.ParseError
+		if errors.As(err, &parseErr) {
+			detail.Message = parseErr.Error()
+		}
+		if errors.Is(err, subtitles.ErrNotUtf8) {
+			detail.Code = models.DetailCodeUnsupported
+		}
+		return nil, &detail, nil
+	}
+
+	var content bytes.Buffer
+	err = subtitles.WriteWebVtt(&content, cues, 0)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return content.Bytes(), nil, nil
+}
+
+// FindByMovie godoc
+// @Tags         subtitles
+// @Summary      List movie subtitle tracks
+// @Description  Дорожки фильма и его серий; у дорожек серий заполнен allSeriesId
+// @Produce      json
+// @Param id path int true "Movie id"
+// @Success      200  {array} models.SubtitleTrack "OK"
+// @Failure   	 400  {object} models.ApiError "Invalid id"
+// @Failure   	 403  {object} models.ApiError "Restricted by parental controls"
+// @Failure   	 500  {object} models.ApiError
+// @Router       /movies/{id}/subtitles [get]
+// @Security Bearer
+func (h *SubtitlesHandlers) FindByMovie(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		respondError(c, http.StatusBadRequest, models.ErrCodeInvalidId)
+		return
+	}
+
+	if !checkMovieAllowed(c, h.parentalRepo, id) {
+		return
+	}
+
+	tracks, err := h.subtitlesRepo.FindByMovie(c, id)
+	if err != nil {
+		respondInternalError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, tracks)
+}
+
+// FindById godoc
+// @Tags         subtitles
+// @Summary      Get subtitle track
+// @Produce      json
+// @Param id path int true "Subtitle track id"
+// @Success      200  {object} models.SubtitleTrack "OK"
+// @Header       200  {string} ETag "Track version"
+// @Failure   	 400  {object} models.ApiError "Invalid id"
+// @Failure   	 404  {object} models.ApiError "Subtitle track not found"
+// @Failure   	 500  {object} models.ApiError
+// @Router       /admin/subtitles/{id} [get]
+// @Security Bearer
+func (h *SubtitlesHandlers) FindById(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		respondError(c, http.StatusBadRequest, models.ErrCodeInvalidId)
+		return
+	}
+
+	track, err := h.subtitlesRepo.FindById(c, id)
+	if err != nil {
+		respondFindError(c, err)
+		return
+	}
+
+	setETag(c, track.Version)
+	c.JSON(http.StatusOK, track)
+}
+
+// Update godoc
+// @Tags         subtitles
+// @Summary      Update subtitle track
+// @Description  Меняет язык, название, флаг по умолчанию и сдвиг времени. Сдвиг применяется при отдаче файла,
+// @Description  поэтому его можно менять сколько угодно раз без повторной загрузки.
+// @Accept       json
+// @Produce      json
+// @Param id path int true "Subtitle track id"
+// @Param        If-Match header string true "ETag of the current version"
+// @Param request body handlers.updateSubtitleRequest true "Track data"
+// @Success      200  "OK"
+// @Failure   	 400  {object} models.ApiError "Invalid data"
+// @Failure   	 404  {object} models.ApiError "Subtitle track not found"
+// @Failure      412  {object} models.ApiError "Resource was modified"
+// @Failure   	 422  {object} models.ApiError "Validation failed"
+// @Failure      428  {object} models.ApiError "If-Match header required"
+// @Failure   	 500  {object} models.ApiError
+// @Router       /admin/subtitles/{id} [put]
+// @Security Bearer
+func (h *SubtitlesHandlers) Update(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		respondError(c, http.StatusBadRequest, models.ErrCodeInvalidId)
+		return
+	}
+
+	before, err := h.subtitlesRepo.FindById(c, id)
+	if err != nil {
+		respondFindError(c, err)
+		return
+	}
+
+	version, ok := ifMatchVersion(c)
+	if !ok {
+		return
+	}
+
+	if !checkIfMatch(c, version, before.Version) {
+		return
+	}
+
+	var request updateSubtitleRequest
+	err = c.BindJSON(&request)
+	if err != nil {
+		respondError(c, http.StatusBadRequest, models.ErrCodeInvalidPayload)
+		return
+	}
+
+	if !h.validator.Validate(c, &request) {
+		return
+	}
+
+	track := before
+	track.Language = request.Language
+	track.Label = request.Label
+	track.IsDefault = request.IsDefault
+	track.OffsetMs = request.OffsetMs
+	track.Version = version
+
+	err = h.subtitlesRepo.Update(c, id, track)
+	if isVersionConflict(err) {
+		respondVersionConflict(c)
+		return
+	}
+	if err != nil {
+		respondInternalError(c, err)
+		return
+	}
+
+	recordAudit(c, h.auditRepo, models.AuditActionUpdate, models.AuditEntitySubtitle, id, before, track)
+
+	setETag(c, track.Version+1)
+	c.Status(http.StatusOK)
+}
+
+// Delete godoc
+// @Tags         subtitles
+// @Summary      Delete subtitle track
+// @Param id path int true "Subtitle track id"
+// @Param        If-Match header string true "ETag of the current version"
+// @Success      200  "OK"
+// @Failure   	 400  {object} models.ApiError "Invalid id"
+// @Failure   	 404  {object} models.ApiError "Subtitle track not found"
+// @Failure      412  {object} models.ApiError "Resource was modified"
+// @Failure      428  {object} models.ApiError "If-Match header required"
+// @Failure   	 500  {object} models.ApiError
+// @Router       /admin/subtitles/{id} [delete]
+// @Security Bearer
+func (h *SubtitlesHandlers) Delete(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		respondError(c, http.StatusBadRequest, models.ErrCodeInvalidId)
+		return
+	}
+
+	before, err := h.subtitlesRepo.FindById(c, id)
+	if err != nil {
+		respondFindError(c, err)
+		return
+	}
+
+	version, ok := ifMatchVersion(c)
+	if !ok {
+		return
+	}
+
+	if !checkIfMatch(c, version, before.Version) {
+		return
+	}
+
+	err = h.subtitlesRepo.Delete(c, id, version)
+	if isVersionConflict(err) {
+		respondVersionConflict(c)
+		return
+	}
+	if err != nil {
+		respondInternalError(c, err)
+		return
+	}
+
+	err = h.storage.Remove(subtitleStorageKey(id))
+	if err != nil {
+		respondInternalError(c, err)
+		return
+	}
+
+	recordAudit(c, h.auditRepo, models.AuditActionDelete, models.AuditEntitySubtitle, id, before, nil)
+
+	c.Status(http.StatusOK)
+}
+
+// HandleGetSubtitleFile godoc
+// @Summary      Get subtitle track as WebVTT
+// @Description  Постоянный адрес дорожки для плеера; текущий сдвиг времени уже применён.
+// @Description  Только дорожки опубликованных фильмов с рейтингом не выше действующего возрастного лимита
+// @Tags         subtitles
+// @Produce      text/vtt
+// @Param file path string true "<id>.vtt"
+// @Param        X-Profile-Id header int false "Viewer profile"
+// @Success      200  {string} string "WebVTT"
+// @Failure 400 {object} models.ApiError "Invalid id"
+// @Failure 403 {object} models.ApiError "Restricted by parental controls"
+// @Failure 404 {object} models.ApiError "Subtitle track not found"
+// @Failure   	 500  {object} models.ApiError
+// @Router       /subtitles/{file} [get]
+// @Security Bearer
+func (h *SubtitlesHandlers) HandleGetSubtitleFile(c *gin.Context) {
+	idStr, ok := strings.CutSuffix(c.Param("file"), ".vtt")
+	if !ok {
+		respondError(c, http.StatusNotFound, models.ErrCodeNotFound)
+		return
+	}
+
+	id, err := strconv.Atoi(idStr)
+	if err != nil {
+		respondError(c, http.StatusBadRequest, models.ErrCodeInvalidId)
+		return
+	}
+
+	track, err := h.subtitlesRepo.FindPublished(c, id)
+	if err != nil {
+		respondFindError(c, err)
+		return
+	}
+
+	if !checkMovieAllowed(c, h.parentalRepo, track.MovieId) {
+		return
+	}
+
+	data, err := h.storage.ReadFile(subtitleStorageKey(id))
+	if os.IsNotExist(err) {
+		respondError(c, http.StatusNotFound, models.ErrCodeNotFound)
+		return
+	}
+	if err != nil {
+		respondInternalError(c, err)
+		return
+	}
+
+	cues, err := subtitles.Parse(data)
+	if err != nil {
+		respondInternalError(c, err)
+		return
+	}
+
+	var content bytes.Buffer
+	err = subtitles.WriteWebVtt(&content, cues, time.Duration(track.OffsetMs)*time.Millisecond)
+	if err != nil {
+		respondInternalError(c, err)
+		return
+	}
+
+	// Last-Modified меняется вместе со сдвигом, плеер перезапросит файл
+	c.Header("Content-Type", subtitleContentType)
+	c.Header("Cache-Control", "no-cache")
+	http.ServeContent(c.Writer, c.Request, c.Param("file"), track.UpdatedAt, bytes.NewReader(content.Bytes()))
+}
+
+// attachSubtitles раскладывает дорожки фильма по сериям и возвращает дорожки самого фильма
+func attachSubtitles(tracks []models.SubtitleTrack, episodes []models.AllSeries) []models.SubtitleTrack {
+	movieTracks := make([]models.SubtitleTrack, 0)
+	for _, track := range tracks {
+		if track.AllSeriesId == nil {
+			movieTracks = append(movieTracks, track)
+			continue
+		}
+
+		for i := range episodes {
+			if episodes[i].Id != nil && *episodes[i].Id == *track.AllSeriesId {
+				episodes[i].Subtitles = append(episodes[i].Subtitles, track)
+			}
+		}
+	}
+
+	return movieTracks
+}
+
+func subtitleStorageKey(id int) string {
+	return fmt.Sprintf("subtitles/%d.vtt", id)
+}
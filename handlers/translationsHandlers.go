@@ -0,0 +1,202 @@
+package handlers
+
+import (
+	"errors"
+	"goozinshe/config"
+	"goozinshe/models"
+	"goozinshe/repositories"
+	"net/http"
+	"slices"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+type TranslationsHandlers struct {
+	translationsRepo *repositories.TranslationsRepository
+	auditRepo        *repositories.AuditRepository
+}
+
+func NewTranslationsHandlers(translationsRepo *repositories.TranslationsRepository, auditRepo *repositories.AuditRepository) *TranslationsHandlers {
+	return &TranslationsHandlers{translationsRepo: translationsRepo, auditRepo: auditRepo}
+}
+
+// FindByEntity godoc
+// @Tags         translations
+// @Summary      List translations of a record
+// @Accept       json
+// @Produce      json
+// @Param type path string true "movie, genre, category or age"
+// @Param id path int true "Record id"
+// @Success      200  {array} models.Translation "OK"
+// @Failure   	 400  {object} models.ApiError "Invalid type or id"
+// @Failure   	 404  {object} models.ApiError "Record not found"
+// @Failure   	 500  {object} models.ApiError
+// @Router       /admin/translations/{type}/{id} [get]
+// @Security Bearer
+func (h *TranslationsHandlers) FindByEntity(c *gin.Context) {
+	entityType, id, ok := h.findEntity(c)
+	if !ok {
+		return
+	}
+
+	translations, err := h.translationsRepo.FindByEntity(c, entityType, id)
+	if err != nil {
+		respondInternalError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, translations)
+}
+
+// Set godoc
+// @Tags         translations
+// @Summary      Set translations of a record for one locale
+// @Description  Тело — поле -> перевод, например {"title": "...", "description": "..."}. Пустая строка удаляет перевод поля.
+// @Description  Язык по умолчанию хранится в самой записи и меняется её обычным обновлением
+// @Accept       json
+// @Produce      json
+// @Param type path string true "movie, genre, category or age"
+// @Param id path int true "Record id"
+// @Param locale path string true "kk, ru or en"
+// @Param request body object true "Field translations"
+// @Success      200  {array} models.Translation "OK"
+// @Failure   	 400  {object} models.ApiError "Invalid data"
+// @Failure   	 404  {object} models.ApiError "Record not found"
+// @Failure   	 500  {object} models.ApiError
+// @Router       /admin/translations/{type}/{id}/{locale} [put]
+// @Security Bearer
+func (h *TranslationsHandlers) Set(c *gin.Context) {
+	entityType, id, ok := h.findEntity(c)
+	if !ok {
+		return
+	}
+
+	locale, ok := translationLocale(c)
+	if !ok {
+		return
+	}
+
+	var values map[string]string
+	err := c.BindJSON(&values)
+	if err != nil || len(values) == 0 {
+		respondError(c, http.StatusBadRequest, models.ErrCodeInvalidPayload)
+		return
+	}
+
+	for field := range values {
+		if !slices.Contains(models.TranslatableFields[entityType], field) {
+			respondValidationError(c, models.NewApiErrorDetail(field, models.DetailCodeNotAllowed))
+			return
+		}
+	}
+
+	before, err := h.translationsRepo.FindByEntity(c, entityType, id)
+	if err != nil {
+		respondInternalError(c, err)
+		return
+	}
+
+	err = h.translationsRepo.Set(c, entityType, id, locale, values)
+	if err != nil {
+		respondInternalError(c, err)
+		return
+	}
+
+	after, err := h.translationsRepo.FindByEntity(c, entityType, id)
+	if err != nil {
+		respondInternalError(c, err)
+		return
+	}
+
+	recordAudit(c, h.auditRepo, models.AuditActionUpdate, entityType, id, gin.H{"translations": before}, gin.H{"translations": after})
+
+	c.JSON(http.StatusOK, after)
+}
+
+// Delete godoc
+// @Tags         translations
+// @Summary      Delete all translations of a record for one locale
+// @Accept       json
+// @Produce      json
+// @Param type path string true "movie, genre, category or age"
+// @Param id path int true "Record id"
+// @Param locale path string true "kk, ru or en"
+// @Success      200  "OK"
+// @Failure   	 400  {object} models.ApiError "Invalid data"
+// @Failure   	 404  {object} models.ApiError "Record not found"
+// @Failure   	 500  {object} models.ApiError
+// @Router       /admin/translations/{type}/{id}/{locale} [delete]
+// @Security Bearer
+func (h *TranslationsHandlers) Delete(c *gin.Context) {
+	entityType, id, ok := h.findEntity(c)
+	if !ok {
+		return
+	}
+
+	locale, ok := translationLocale(c)
+	if !ok {
+		return
+	}
+
+	before, err := h.translationsRepo.FindByEntity(c, entityType, id)
+	if err != nil {
+		respondInternalError(c, err)
+		return
+	}
+
+	err = h.translationsRepo.DeleteLocale(c, entityType, id, locale)
+	if err != nil {
+		respondInternalError(c, err)
+		return
+	}
+
+	after, err := h.translationsRepo.FindByEntity(c, entityType, id)
+	if err != nil {
+		respondInternalError(c, err)
+		return
+	}
+
+	recordAudit(c, h.auditRepo, models.AuditActionUpdate, entityType, id, gin.H{"translations": before}, gin.H{"translations": after})
+
+	c.Status(http.StatusOK)
+}
+
+func (h *TranslationsHandlers) findEntity(c *gin.Context) (string, int, bool) {
+	entityType := c.Param("type")
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		respondError(c, http.StatusBadRequest, models.ErrCodeInvalidId)
+		return "", 0, false
+	}
+
+	exists, err := h.translationsRepo.EntityExists(c, entityType, id)
+	if errors.Is(err, repositories.ErrUnknownTranslationType) {
+		respondValidationError(c, models.NewApiErrorDetail("type", models.DetailCodeUnsupported))
+		return "", 0, false
+	}
+	if err != nil {
+		respondInternalError(c, err)
+		return "", 0, false
+	}
+	if !exists {
+		respondError(c, http.StatusNotFound, models.ErrCodeNotFound)
+		return "", 0, false
+	}
+
+	return entityType, id, true
+}
+
+func translationLocale(c *gin.Context) (string, bool) {
+	locale := c.Param("locale")
+	if !slices.Contains(models.SupportedLocales, locale) {
+		respondValidationError(c, models.NewApiErrorDetail("locale", models.DetailCodeUnsupported))
+		return "", false
+	}
+	if locale == config.Config.DefaultLocale {
+		respondValidationError(c, models.NewApiErrorDetail("locale", models.DetailCodeNotAllowed))
+		return "", false
+	}
+
+	return locale, true
+}
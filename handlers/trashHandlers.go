@@ -0,0 +1,87 @@
+package handlers
+
+import (
+	"errors"
+	"goozinshe/models"
+	"goozinshe/repositories"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5"
+)
+
+type TrashHandlers struct {
+	trashRepo *repositories.TrashRepository
+	auditRepo *repositories.AuditRepository
+}
+
+func NewTrashHandlers(trashRepo *repositories.TrashRepository, auditRepo *repositories.AuditRepository) *TrashHandlers {
+	return &TrashHandlers{trashRepo: trashRepo, auditRepo: auditRepo}
+}
+
+// FindAll godoc
+// @Tags         trash
+// @Summary      List deleted catalog entities
+// @Accept       json
+// @Produce      json
+// @Param type query string false "movie, genre, category, age, allseries or person"
+// @Success      200  {array} models.TrashItem "OK"
+// @Failure   	 400  {object} models.ApiError "Unknown type"
+// @Failure   	 500  {object} models.ApiError
+// @Router       /admin/trash [get]
+// @Security Bearer
+func (h *TrashHandlers) FindAll(c *gin.Context) {
+	items, err := h.trashRepo.FindAll(c, c.Query("type"))
+	if errors.Is(err, repositories.ErrUnknownTrashType) {
+		respondValidationError(c, models.NewApiErrorDetail("type", models.DetailCodeUnsupported))
+		return
+	}
+	if err != nil {
+		respondInternalError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, items)
+}
+
+// Restore godoc
+// @Tags         trash
+// @Summary      Restore deleted catalog entity
+// @Accept       json
+// @Produce      json
+// @Param type path string true "movie, genre, category, age, allseries or person"
+// @Param id path int true "Entity id"
+// @Success      200  "OK"
+// @Failure   	 400  {object} models.ApiError "Invalid type or id"
+// @Failure   	 404  {object} models.ApiError "Entity is not in trash"
+// @Failure   	 500  {object} models.ApiError
+// @Router       /admin/trash/{type}/{id}/restore [post]
+// @Security Bearer
+func (h *TrashHandlers) Restore(c *gin.Context) {
+	entityType := c.Param("type")
+
+	idStr := c.Param("id")
+	id, err := strconv.Atoi(idStr)
+	if err != nil {
+		respondError(c, http.StatusBadRequest, models.ErrCodeInvalidId)
+		return
+	}
+
+	err = h.trashRepo.Restore(c, entityType, id)
+	switch {
+	case errors.Is(err, repositories.ErrUnknownTrashType):
+		respondValidationError(c, models.NewApiErrorDetail("type", models.DetailCodeUnsupported))
+		return
+	case errors.Is(err, pgx.ErrNoRows):
+		respondError(c, http.StatusNotFound, models.ErrCodeNotInTrash)
+		return
+	case err != nil:
+		respondInternalError(c, err)
+		return
+	}
+
+	recordAudit(c, h.auditRepo, models.AuditActionRestore, entityType, id, nil, nil)
+
+	c.Status(http.StatusOK)
+}
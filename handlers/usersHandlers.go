@@ -12,30 +12,32 @@ import (
 )
 
 type UsersHandlers struct {
-	userRepo *repositories.UsersRepository
+	userRepo  *repositories.UsersRepository
+	auditRepo *repositories.AuditRepository
+	validator *RequestValidator
 }
 
-func NewUsersHandlers(userRepo *repositories.UsersRepository) *UsersHandlers {
-	return &UsersHandlers{userRepo: userRepo}
+func NewUsersHandlers(userRepo *repositories.UsersRepository, auditRepo *repositories.AuditRepository, validator *RequestValidator) *UsersHandlers {
+	return &UsersHandlers{userRepo: userRepo, auditRepo: auditRepo, validator: validator}
 }
 
 type createUserRequest struct {
-	Name        string
-	Email       string
-	Password    string
-	PhoneNumber *int
-	Birthday    *time.Time
+	Name        string     `validate:"required,max=255"`
+	Email       string     `validate:"required,email"`
+	Password    string     `validate:"required,min=6,max=72"`
+	PhoneNumber *int       `validate:"omitempty,gt=0"`
+	Birthday    *time.Time `validate:"omitempty,lt"`
 }
 
 type updateUserRequest struct {
-	Name        string
-	Email       string
-	PhoneNumber *int
-	Birthday    *time.Time
+	Name        string     `validate:"required,max=255"`
+	Email       string     `validate:"required,email"`
+	PhoneNumber *int       `validate:"omitempty,gt=0"`
+	Birthday    *time.Time `validate:"omitempty,lt"`
 }
 
 type changePasswordRequest struct {
-	Password string
+	Password string `validate:"required,min=6,max=72"`
 }
 
 type userResponse struct {
@@ -46,6 +48,20 @@ type userResponse struct {
 	Birthday    *time.Time `json:"birthday"`
 }
 
+// auditUser — данные пользователя для журнала аудита, без хэша пароля
+func auditUser(user models.User) userResponse {
+	return userResponse{
+		Id:          user.Id,
+		Name:        user.Name,
+		Email:       user.Email,
+		PhoneNumber: user.PhoneNumber,
+		Birthday:    user.Birthday,
+	}
+}
+
+// в журнал аудита попадает только факт смены пароля
+var auditPasswordChanged = gin.H{"passwordChanged": true}
+
 // FindById godoc
 // @Tags users
 // @Summary      Find users by id
@@ -61,13 +77,13 @@ func (h *UsersHandlers) FindById(c *gin.Context) {
 	idStr := c.Param("id")
 	id, err := strconv.Atoi(idStr)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, models.NewApiError("Invalid user Id"))
+		respondError(c, http.StatusBadRequest, models.ErrCodeInvalidId)
 		return
 	}
 
 	user, err := h.userRepo.FindById(c, id)
 	if err != nil {
-		c.JSON(http.StatusNotFound, models.NewApiError("User not found"))
+		respondError(c, http.StatusNotFound, models.ErrCodeNotFound)
 		return
 	}
 
@@ -93,7 +109,7 @@ func (h *UsersHandlers) FindById(c *gin.Context) {
 func (h *UsersHandlers) FindAll(c *gin.Context) {
 	users, err := h.userRepo.FindAll(c)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, models.NewApiError("could not load users"))
+		respondInternalError(c, err)
 		return
 	}
 
@@ -120,19 +136,24 @@ func (h *UsersHandlers) FindAll(c *gin.Context) {
 // @Param request body handlers.createUserRequest true "User data"
 // @Success      200  {object} object{id=int} "OK"
 // @Failure   	 400  {object} models.ApiError "Invalid data"
+// @Failure   	 422  {object} models.ApiError "Validation failed"
 // @Failure   	 500  {object} models.ApiError
 // @Router       /users [post]
 func (h *UsersHandlers) Create(c *gin.Context) {
 	var request createUserRequest
 	err := c.BindJSON(&request)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, models.NewApiError("Invalid payload"))
+		respondError(c, http.StatusBadRequest, models.ErrCodeInvalidPayload)
+		return
+	}
+
+	if !h.validator.Validate(c, &request) {
 		return
 	}
 
 	passwordHash, err := bcrypt.GenerateFromPassword([]byte(request.Password), bcrypt.DefaultCost)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, models.NewApiError("Failed to hash password"))
+		respondInternalError(c, err)
 		return
 	}
 
@@ -146,10 +167,13 @@ func (h *UsersHandlers) Create(c *gin.Context) {
 
 	id, err := h.userRepo.Create(c, user)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, models.NewApiError("could not create user"))
+		respondInternalError(c, err)
 		return
 	}
 
+	user.Id = id
+	recordAudit(c, h.auditRepo, models.AuditActionCreate, models.AuditEntityUser, id, nil, auditUser(user))
+
 	c.JSON(http.StatusOK, gin.H{"id": id})
 }
 
@@ -163,28 +187,35 @@ func (h *UsersHandlers) Create(c *gin.Context) {
 // @Success      200  {object} object{id=int} "OK"
 // @Failure   	 400  {object} models.ApiError "Invalid data"
 // @Failure   	 404  {object} models.ApiError "User not found"
+// @Failure   	 422  {object} models.ApiError "Validation failed"
 // @Failure   	 500  {object} models.ApiError
 // @Router       /users/{id} [put]
 func (h *UsersHandlers) Update(c *gin.Context) {
 	idStr := c.Param("id")
 	id, err := strconv.Atoi(idStr)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, models.NewApiError("Invalid user Id"))
+		respondError(c, http.StatusBadRequest, models.ErrCodeInvalidId)
 		return
 	}
 
 	var request updateUserRequest
 	if err := c.BindJSON(&request); err != nil {
-		c.JSON(http.StatusBadRequest, models.NewApiError("Invalid request payload"))
+		respondError(c, http.StatusBadRequest, models.ErrCodeInvalidPayload)
+		return
+	}
+
+	if !h.validator.Validate(c, &request) {
 		return
 	}
 
 	user, err := h.userRepo.FindById(c, id)
 	if err != nil {
-		c.JSON(http.StatusNotFound, models.NewApiError("User not found"))
+		respondError(c, http.StatusNotFound, models.ErrCodeNotFound)
 		return
 	}
 
+	before := auditUser(user)
+
 	user.Name = request.Name
 	user.Email = request.Email
 	user.PhoneNumber = request.PhoneNumber
@@ -192,10 +223,12 @@ func (h *UsersHandlers) Update(c *gin.Context) {
 
 	err = h.userRepo.Update(c, id, user)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, models.NewApiError(err.Error()))
+		respondInternalError(c, err)
 		return
 	}
 
+	recordAudit(c, h.auditRepo, models.AuditActionUpdate, models.AuditEntityUser, id, before, auditUser(user))
+
 	c.Status(http.StatusOK)
 }
 
@@ -214,22 +247,24 @@ func (h *UsersHandlers) Delete(c *gin.Context) {
 	idStr := c.Param("id")
 	id, err := strconv.Atoi(idStr)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, models.NewApiError("Invalid user Id"))
+		respondError(c, http.StatusBadRequest, models.ErrCodeInvalidId)
 		return
 	}
 
-	_, err = h.userRepo.FindById(c, id)
+	user, err := h.userRepo.FindById(c, id)
 	if err != nil {
-		c.JSON(http.StatusNotFound, models.NewApiError("User not found"))
+		respondError(c, http.StatusNotFound, models.ErrCodeNotFound)
 		return
 	}
 
 	err = h.userRepo.Delete(c, id)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, models.NewApiError(err.Error()))
+		respondInternalError(c, err)
 		return
 	}
 
+	recordAudit(c, h.auditRepo, models.AuditActionDelete, models.AuditEntityUser, id, auditUser(user), nil)
+
 	c.Status(http.StatusOK)
 }
 
@@ -243,31 +278,36 @@ func (h *UsersHandlers) Delete(c *gin.Context) {
 // @Success      200  "OK"
 // @Failure   	 400  {object} models.ApiError "Invalid data"
 // @Failure   	 404  {object} models.ApiError "User not found"
+// @Failure   	 422  {object} models.ApiError "Validation failed"
 // @Failure   	 500  {object} models.ApiError
 // @Router       /users/{id}/changePassword [patch]
 func (h *UsersHandlers) ChangePassword(c *gin.Context) {
 	idStr := c.Param("id")
 	id, err := strconv.Atoi(idStr)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, models.NewApiError("Invalid user Id"))
+		respondError(c, http.StatusBadRequest, models.ErrCodeInvalidId)
 		return
 	}
 
 	var request changePasswordRequest
 	if err := c.BindJSON(&request); err != nil {
-		c.JSON(http.StatusBadRequest, models.NewApiError("Invalid request payload"))
+		respondError(c, http.StatusBadRequest, models.ErrCodeInvalidPayload)
+		return
+	}
+
+	if !h.validator.Validate(c, &request) {
 		return
 	}
 
 	passwordHash, err := bcrypt.GenerateFromPassword([]byte(request.Password), bcrypt.DefaultCost)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, models.NewApiError("Failed to hash password"))
+		respondInternalError(c, err)
 		return
 	}
 
 	user, err := h.userRepo.FindById(c, id)
 	if err != nil {
-		c.JSON(http.StatusNotFound, models.NewApiError("User not found"))
+		respondError(c, http.StatusNotFound, models.ErrCodeNotFound)
 		return
 	}
 
@@ -275,9 +315,82 @@ func (h *UsersHandlers) ChangePassword(c *gin.Context) {
 
 	err = h.userRepo.Update(c, id, user)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, models.NewApiError(err.Error()))
+		respondInternalError(c, err)
 		return
 	}
 
+	recordAudit(c, h.auditRepo, models.AuditActionUpdate, models.AuditEntityUser, id, nil, auditPasswordChanged)
+
 	c.Status(http.StatusOK)
 }
+
+type userPatchDocument struct {
+	Name        string     `json:"name" validate:"required,max=255"`
+	Email       string     `json:"email" validate:"required,email"`
+	PhoneNumber *int       `json:"phoneNumber" validate:"omitempty,gt=0"`
+	Birthday    *time.Time `json:"birthday" validate:"omitempty,lt"`
+}
+
+// Patch godoc
+// @Tags users
+// @Summary      Partially update user
+// @Description  JSON Merge Patch (RFC 7396): меняются только переданные поля
+// @Accept       application/merge-patch+json
+// @Produce      json
+// @Param id path int true "User id"
+// @Param request body handlers.userPatchDocument true "Merge patch"
+// @Success      200  {object} handlers.userResponse "OK"
+// @Failure   	 400  {object} models.ApiError "Invalid data"
+// @Failure   	 404  {object} models.ApiError "User not found"
+// @Failure   	 415  {object} models.ApiError "Unsupported Content-Type"
+// @Failure   	 422  {object} models.ApiError "Validation failed"
+// @Failure   	 500  {object} models.ApiError
+// @Router       /users/{id} [patch]
+func (h *UsersHandlers) Patch(c *gin.Context) {
+	idStr := c.Param("id")
+	id, err := strconv.Atoi(idStr)
+	if err != nil {
+		respondError(c, http.StatusBadRequest, models.ErrCodeInvalidId)
+		return
+	}
+
+	user, err := h.userRepo.FindById(c, id)
+	if err != nil {
+		respondError(c, http.StatusNotFound, models.ErrCodeNotFound)
+		return
+	}
+
+	before := auditUser(user)
+
+	document := userPatchDocument{
+		Name:        user.Name,
+		Email:       user.Email,
+		PhoneNumber: user.PhoneNumber,
+		Birthday:    user.Birthday,
+	}
+	err = applyMergePatch(c, &document)
+	if err != nil {
+		respondPatchError(c, err)
+		return
+	}
+
+	if !h.validator.Validate(c, &document) {
+		return
+	}
+
+	user.Name = document.Name
+	user.Email = document.Email
+	user.PhoneNumber = document.PhoneNumber
+	user.Birthday = document.Birthday
+
+	err = h.userRepo.Update(c, id, user)
+	if err != nil {
+		respondInternalError(c, err)
+		return
+	}
+
+	after := auditUser(user)
+	recordAudit(c, h.auditRepo, models.AuditActionUpdate, models.AuditEntityUser, id, before, after)
+
+	c.JSON(http.StatusOK, after)
+}
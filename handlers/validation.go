@@ -0,0 +1,183 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"goozinshe/models"
+	"goozinshe/repositories"
+	"net/http"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/validator/v10"
+)
+
+// самый ранний год, который принимаем как год выхода фильма
+const minReleaseYear = 1888
+
+// RequestValidator проверяет тела запросов по тегам validate.
+// Кроме стандартных правил есть year, future, trailer, permission и exists=<таблица> — ссылка на существующую запись
+type RequestValidator struct {
+	validate *validator.Validate
+	refsRepo *repositories.ReferencesRepository
+}
+
+func NewRequestValidator(refsRepo *repositories.ReferencesRepository) *RequestValidator {
+	v := &RequestValidator{
+		validate: validator.New(validator.WithRequiredStructEnabled()),
+		refsRepo: refsRepo,
+	}
+
+	// в ошибке поле называется так же, как в запросе
+	v.validate.RegisterTagNameFunc(requestFieldName)
+	v.validate.RegisterValidation("year", validateYear)
+	v.validate.RegisterValidation("future", validateFuture)
+	v.validate.RegisterValidation("trailer", validateTrailer)
+	v.validate.RegisterValidation("permission", validatePermission)
+	v.validate.RegisterValidationCtx("exists", v.validateExists)
+
+	return v
+}
+
+// Validate отвечает 422 со всеми ошибками полей сразу и возвращает false, если запрос невалиден.
+// Срез запросов проверяется поэлементно, поля получают префикс [i]
+func (v *RequestValidator) Validate(c *gin.Context, request any) bool {
+	details := make([]models.ApiErrorDetail, 0)
+
+	value := reflect.Indirect(reflect.ValueOf(request))
+	if value.Kind() == reflect.Slice {
+		for i := 0; i < value.Len(); i++ {
+			itemDetails, err := v.check(c, value.Index(i).Interface(), fmt.Sprintf("[%d].", i))
+			if err != nil {
+				respondInternalError(c, err)
+				return false
+			}
+			details = append(details, itemDetails...)
+		}
+	} else {
+		itemDetails, err := v.check(c, request, "")
+		if err != nil {
+			respondInternalError(c, err)
+			return false
+		}
+		details = itemDetails
+	}
+
+	if len(details) > 0 {
+		respondError(c, http.StatusUnprocessableEntity, models.ErrCodeValidation, details...)
+		return false
+	}
+
+	return true
+}
+
+func (v *RequestValidator) check(c context.Context, request any, prefix string) ([]models.ApiErrorDetail, error) {
+	err := v.validate.StructCtx(c, request)
+	if err == nil {
+		return nil, nil
+	}
+
+	var fieldErrors validator.ValidationErrors
+	if !errors.As(err, &fieldErrors) {
+		return nil, err
+	}
+
+	details := make([]models.ApiErrorDetail, 0, len(fieldErrors))
+	for _, fieldError := range fieldErrors {
+		// createMovieRequest.genreIds[1] -> genreIds[1]
+		_, field, _ := strings.Cut(fieldError.Namespace(), ".")
+		details = append(details, models.NewApiErrorDetail(prefix+field, detailCode(fieldError)))
+	}
+
+	return details, nil
+}
+
+// detailCode переводит правило validator в код ошибки поля
+func detailCode(fieldError validator.FieldError) string {
+	kind := fieldError.Kind()
+	switch fieldError.Tag() {
+	case "required", "required_with", "required_without":
+		return models.DetailCodeRequired
+	case "min":
+		if kind == reflect.Slice {
+			return models.DetailCodeRequired
+		}
+		if kind == reflect.String {
+			return models.DetailCodeTooShort
+		}
+		return models.DetailCodeOutOfRange
+	case "max":
+		if kind == reflect.String {
+			return models.DetailCodeTooLong
+		}
+		return models.DetailCodeOutOfRange
+	case "gt", "gte", "lt", "lte", "year":
+		return models.DetailCodeOutOfRange
+	case "url", "http_url", "email", "bcp47_language_tag", "len", "numeric", "permission":
+		return models.DetailCodeFormat
+	case "oneof", "trailer":
+		return models.DetailCodeUnsupported
+	case "future":
+		return models.DetailCodeInPast
+	case "unique":
+		return models.DetailCodeDuplicate
+	case "exists":
+		return models.DetailCodeNotFound
+	}
+
+	return models.DetailCodeInvalid
+}
+
+func requestFieldName(field reflect.StructField) string {
+	for _, tag := range []string{"json", "form"} {
+		name, _, _ := strings.Cut(field.Tag.Get(tag), ",")
+		if name == "-" {
+			return ""
+		}
+		if name != "" {
+			return name
+		}
+	}
+
+	// поля без тегов биндятся из JSON без учёта регистра, показываем их в camelCase
+	return strings.ToLower(field.Name[:1]) + field.Name[1:]
+}
+
+func validateYear(fl validator.FieldLevel) bool {
+	year := fl.Field().Int()
+	return year >= minReleaseYear && year <= int64(time.Now().Year()+5)
+}
+
+func validateFuture(fl validator.FieldLevel) bool {
+	value, ok := fl.Field().Interface().(time.Time)
+	return ok && value.After(time.Now())
+}
+
+// validateTrailer пропускает только ссылки YouTube, Vimeo и прямые MP4/HLS
+func validateTrailer(fl validator.FieldLevel) bool {
+	_, err := models.ParseTrailerUrl(fl.Field().String())
+	return err == nil
+}
+
+func validatePermission(fl validator.FieldLevel) bool {
+	return models.IsValidPermission(fl.Field().String())
+}
+
+// validateExists проверяет ссылку на запись; 0 не проверяется, его ловят required и gt
+func (v *RequestValidator) validateExists(c context.Context, fl validator.FieldLevel) bool {
+	id := int(fl.Field().Int())
+	if id <= 0 {
+		return true
+	}
+
+	exists, err := v.refsRepo.Exists(c, fl.Param(), id)
+	if err != nil {
+		// ошибку базы покажет следующий запрос обработчика, здесь не выдаём её за отсутствие записи
+		return true
+	}
+
+	return exists
+}
@@ -0,0 +1,62 @@
+package handlers
+
+import (
+	"goozinshe/models"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// currentUserId — пользователь, от имени которого пришёл запрос. У API ключей пользователя нет,
+// поэтому личные эндпоинты (/me/...) для них недоступны: отвечаем 403 и возвращаем false
+func currentUserId(c *gin.Context) (int, bool) {
+	userId, exists := c.Get("userId")
+	if !exists {
+		respondError(c, http.StatusForbidden, models.ErrCodeForbidden)
+		return 0, false
+	}
+
+	return userId.(int), true
+}
+
+// viewerUserId — пользователь запроса или nil для API ключей; в отличие от currentUserId ничего не отвечает
+func viewerUserId(c *gin.Context) *int {
+	userId, exists := c.Get("userId")
+	if !exists {
+		return nil
+	}
+
+	id := userId.(int)
+	return &id
+}
+
+// currentProfile — профиль, выбранный заголовком X-Profile-Id или claim pid токена
+func currentProfile(c *gin.Context) (models.Profile, bool) {
+	profile, exists := c.Get(models.ProfileKey)
+	if !exists {
+		return models.Profile{}, false
+	}
+
+	return profile.(models.Profile), true
+}
+
+// currentProfileId — id выбранного профиля; nil, если запрос идёт от аккаунта целиком
+func currentProfileId(c *gin.Context) *int {
+	profile, ok := currentProfile(c)
+	if !ok {
+		return nil
+	}
+
+	return &profile.Id
+}
+
+// ageLimit — действующий лимит родительского контроля; nil — ограничений нет или они сняты PIN
+func ageLimit(c *gin.Context) *int {
+	limit, exists := c.Get(models.AgeLimitKey)
+	if !exists {
+		return nil
+	}
+
+	value := limit.(int)
+	return &value
+}
@@ -0,0 +1,123 @@
+package handlers
+
+import (
+	"archive/zip"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// xlsxWriter пишет минимальную книгу XLSX потоково: листы идут в zip по очереди,
+// строки сразу уходят в ответ, текст хранится inline без общей таблицы строк
+type xlsxWriter struct {
+	zip    *zip.Writer
+	sheets []string
+	sheet  io.Writer
+}
+
+func newXlsxWriter(w io.Writer) *xlsxWriter {
+	return &xlsxWriter{zip: zip.NewWriter(w)}
+}
+
+func (x *xlsxWriter) StartSheet(name string) error {
+	err := x.endSheet()
+	if err != nil {
+		return err
+	}
+
+	x.sheets = append(x.sheets, name)
+	x.sheet, err = x.zip.Create(fmt.Sprintf("xl/worksheets/sheet%d.xml", len(x.sheets)))
+	if err != nil {
+		return err
+	}
+
+	_, err = io.WriteString(x.sheet, xml.Header+`<worksheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main"><sheetData>`)
+	return err
+}
+
+// WriteRow пишет целые числа числовыми ячейками, остальное — строками
+func (x *xlsxWriter) WriteRow(values []any) error {
+	var row strings.Builder
+	row.WriteString("<row>")
+	for _, value := range values {
+		if number, ok := value.(*int); ok && number != nil {
+			value = *number
+		}
+		if number, ok := value.(int); ok {
+			fmt.Fprintf(&row, "<c><v>%d</v></c>", number)
+			continue
+		}
+		row.WriteString(`<c t="inlineStr"><is><t xml:space="preserve">`)
+		xml.EscapeText(&row, []byte(exportCell(value)))
+		row.WriteString("</t></is></c>")
+	}
+	row.WriteString("</row>")
+
+	_, err := io.WriteString(x.sheet, row.String())
+	return err
+}
+
+func (x *xlsxWriter) endSheet() error {
+	if x.sheet == nil {
+		return nil
+	}
+
+	_, err := io.WriteString(x.sheet, "</sheetData></worksheet>")
+	x.sheet = nil
+	return err
+}
+
+// Close дописывает служебные части книги и закрывает архив
+func (x *xlsxWriter) Close() error {
+	err := x.endSheet()
+	if err != nil {
+		return err
+	}
+
+	var sheets, sheetRels, overrides strings.Builder
+	for i, name := range x.sheets {
+		n := i + 1
+		fmt.Fprintf(&sheets, `<sheet name="%s" sheetId="%d" r:id="rId%d"/>`, xmlAttr(name), n, n)
+		fmt.Fprintf(&sheetRels, `<Relationship Id="rId%d" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/worksheet" Target="worksheets/sheet%d.xml"/>`, n, n)
+		fmt.Fprintf(&overrides, `<Override PartName="/xl/worksheets/sheet%d.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.worksheet+xml"/>`, n)
+	}
+
+	parts := []struct {
+		name    string
+		content string
+	}{
+		{"[Content_Types].xml", `<Types xmlns="http://schemas.openxmlformats.org/package/2006/content-types">` +
+			`<Default Extension="rels" ContentType="application/vnd.openxmlformats-package.relationships+xml"/>` +
+			`<Default Extension="xml" ContentType="application/xml"/>` +
+			`<Override PartName="/xl/workbook.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.sheet.main+xml"/>` +
+			overrides.String() + `</Types>`},
+		{"_rels/.rels", `<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">` +
+			`<Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/officeDocument" Target="xl/workbook.xml"/>` +
+			`</Relationships>`},
+		{"xl/workbook.xml", `<workbook xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main" xmlns:r="http://schemas.openxmlformats.org/officeDocument/2006/relationships">` +
+			`<sheets>` + sheets.String() + `</sheets></workbook>`},
+		{"xl/_rels/workbook.xml.rels", `<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">` +
+			sheetRels.String() + `</Relationships>`},
+	}
+
+	for _, part := range parts {
+		w, err := x.zip.Create(part.name)
+		if err != nil {
+			return err
+		}
+
+		_, err = io.WriteString(w, xml.Header+part.content)
+		if err != nil {
+			return err
+		}
+	}
+
+	return x.zip.Close()
+}
+
+func xmlAttr(value string) string {
+	var escaped strings.Builder
+	xml.EscapeText(&escaped, []byte(value))
+	return escaped.String()
+}
@@ -0,0 +1,759 @@
+package jobs
+
+import (
+	"archive/zip"
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"goozinshe/logger"
+	"goozinshe/models"
+	"goozinshe/repositories"
+	"io"
+	"mime"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"go.uber.org/zap"
+)
+
+const (
+	importQueueSize      = 16
+	importPosterMaxBytes = 10 << 20
+	importPosterTimeout  = 30 * time.Second
+	importRefsSeparator  = ";"
+)
+
+var ErrImportQueueFull = errors.New("import queue is full")
+
+var errImportConflict = errors.New("movie was changed during import, retry the row")
+
+// ImportTask — загруженный файл импорта и необязательный zip-архив с постерами.
+// Ревизии и аудит фильмов пишутся от имени того, кто запустил импорт
+type ImportTask struct {
+	JobId         int
+	Format        string
+	Data          []byte
+	Archive       []byte
+	ActorUserId   *int
+	ActorApiKeyId *int
+}
+
+type CatalogImporter struct {
+	moviesRepo      *repositories.MoviesRepository
+	genresRepo      *repositories.GenresRepository
+	categoryRepo    *repositories.CategoryRepository
+	ageRepo         *repositories.AgeRepository
+	importJobsRepo  *repositories.ImportJobsRepository
+	moviesAdminRepo *repositories.MoviesAdminRepository
+	auditRepo       *repositories.AuditRepository
+	client          *http.Client
+	tasks           chan ImportTask
+}
+
+func NewCatalogImporter(
+	moviesRepo *repositories.MoviesRepository,
+	genresRepo *repositories.GenresRepository,
+	categoryRepo *repositories.CategoryRepository,
+	ageRepo *repositories.AgeRepository,
+	importJobsRepo *repositories.ImportJobsRepository,
+	moviesAdminRepo *repositories.MoviesAdminRepository,
+	auditRepo *repositories.AuditRepository) *CatalogImporter {
+	return &CatalogImporter{
+		moviesRepo:      moviesRepo,
+		genresRepo:      genresRepo,
+		categoryRepo:    categoryRepo,
+		ageRepo:         ageRepo,
+		importJobsRepo:  importJobsRepo,
+		moviesAdminRepo: moviesAdminRepo,
+		auditRepo:       auditRepo,
+		client:          newPosterClient(),
+		tasks:           make(chan ImportTask, importQueueSize),
+	}
+}
+
+// Enqueue ставит задачу в очередь, не блокируясь, если очередь заполнена
+func (i *CatalogImporter) Enqueue(task ImportTask) error {
+	select {
+	case i.tasks <- task:
+		return nil
+	default:
+		return ErrImportQueueFull
+	}
+}
+
+// StartCatalogImport по одной выполняет задачи импорта из очереди
+func StartCatalogImport(c context.Context, importer *CatalogImporter) {
+	l := logger.GetLogger()
+
+	interrupted, err := importer.importJobsRepo.FailUnfinished(c)
+	if err != nil {
+		l.Error("Could not fail unfinished imports", zap.String("err", err.Error()))
+	} else if interrupted > 0 {
+		l.Warn("Unfinished imports marked as failed", zap.Int64("count", interrupted))
+	}
+
+	for {
+		select {
+		case <-c.Done():
+			return
+		case task := <-importer.tasks:
+			importer.run(c, task)
+		}
+	}
+}
+
+func (i *CatalogImporter) run(c context.Context, task ImportTask) {
+	l := logger.GetLogger()
+
+	err := i.importJobsRepo.Start(c, task.JobId)
+	if err != nil {
+		l.Error("Could not start import", zap.Int("job_id", task.JobId), zap.String("err", err.Error()))
+		return
+	}
+
+	job := i.process(c, task)
+
+	err = i.importJobsRepo.Finish(c, job)
+	if err != nil {
+		l.Error("Could not finish import", zap.Int("job_id", task.JobId), zap.String("err", err.Error()))
+		return
+	}
+
+	l.Info("Import finished",
+		zap.Int("job_id", job.Id),
+		zap.String("status", job.Status),
+		zap.Int("created", job.CreatedRows),
+		zap.Int("updated", job.UpdatedRows),
+		zap.Int("failed", job.FailedRows))
+}
+
+func (i *CatalogImporter) process(c context.Context, task ImportTask) models.ImportJob {
+	job := models.ImportJob{
+		Id:        task.JobId,
+		Status:    models.ImportStatusCompleted,
+		RowErrors: make([]models.ImportRowError, 0),
+	}
+
+	fail := func(err error) models.ImportJob {
+		message := err.Error()
+		job.Status = models.ImportStatusFailed
+		job.Error = &message
+		return job
+	}
+
+	rows, err := parseImportRows(task.Format, task.Data)
+	if err != nil {
+		return fail(err)
+	}
+
+	archive, err := openPosterArchive(task.Archive)
+	if err != nil {
+		return fail(err)
+	}
+
+	refs, err := i.loadRefs(c)
+	if err != nil {
+		return fail(err)
+	}
+
+	job.TotalRows = len(rows)
+	for _, row := range rows {
+		created, err := i.importRow(c, task, row, refs, archive)
+		if err != nil {
+			job.FailedRows++
+			job.RowErrors = append(job.RowErrors, models.ImportRowError{
+				Row:         row.Line,
+				ExternalKey: row.ExternalKey,
+				Message:     err.Error(),
+			})
+			continue
+		}
+
+		if created {
+			job.CreatedRows++
+		} else {
+			job.UpdatedRows++
+		}
+	}
+
+	return job
+}
+
+func (i *CatalogImporter) importRow(c context.Context, task ImportTask, row importRow, refs catalogRefs, archive map[string]*zip.File) (bool, error) {
+	if row.Err != nil {
+		return false, row.Err
+	}
+
+	movie, err := row.toMovie(refs)
+	if err != nil {
+		return false, err
+	}
+
+	// постер качаем только для строк, прошедших проверку
+	if row.Poster != "" {
+		movie.PosterUrl, err = i.savePoster(c, row.Poster, archive)
+		if err != nil {
+			return false, fmt.Errorf("poster: %w", err)
+		}
+	}
+
+	id, err := i.moviesRepo.FindIdByExternalKey(c, row.ExternalKey)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return true, i.createMovie(c, task, row.ExternalKey, movie)
+	}
+	if errors.Is(err, repositories.ErrMovieInTrash) {
+		return false, err
+	}
+	if err != nil {
+		return false, errors.New("could not save movie")
+	}
+
+	return false, i.updateMovie(c, task, id, movie)
+}
+
+// createMovie создаёт фильм черновиком (draft) и пишет его в аудит
+func (i *CatalogImporter) createMovie(c context.Context, task ImportTask, externalKey string, movie models.Movie) error {
+	id, err := i.moviesRepo.CreateWithExternalKey(c, externalKey, movie)
+	if errors.Is(err, repositories.ErrVersionConflict) {
+		return errImportConflict
+	}
+	if err != nil {
+		return errors.New("could not save movie")
+	}
+
+	after, err := i.moviesAdminRepo.FindById(c, id)
+	if err == nil {
+		i.recordAudit(c, task, models.AuditActionCreate, id, nil, after)
+	}
+
+	return nil
+}
+
+// updateMovie обновляет фильм так же, как PUT /movies/{id}: с проверкой версии, ревизией и аудитом.
+// Статус фильма не меняется, пустой постер не затирает уже загруженный
+func (i *CatalogImporter) updateMovie(c context.Context, task ImportTask, id int, movie models.Movie) error {
+	before, err := i.moviesAdminRepo.FindById(c, id)
+	if err != nil {
+		return errors.New("could not load movie")
+	}
+
+	if movie.PosterUrl == "" {
+		movie.PosterUrl = before.PosterUrl
+	}
+	movie.Version = before.Version
+
+	revision := models.MovieRevision{MovieId: id, Snapshot: models.Movie(before), CreatedBy: task.ActorUserId}
+	err = i.moviesRepo.Update(c, id, movie, revision)
+	if errors.Is(err, repositories.ErrVersionConflict) {
+		return errImportConflict
+	}
+	if err != nil {
+		return errors.New("could not save movie")
+	}
+
+	after, err := i.moviesAdminRepo.FindById(c, id)
+	if err == nil {
+		i.recordAudit(c, task, models.AuditActionUpdate, id, before, after)
+	}
+
+	return nil
+}
+
+// recordAudit — как в обработчиках, только без запроса: IP нет, автор — тот, кто запустил импорт.
+// Ошибка записи только логируется, фильм уже сохранён
+func (i *CatalogImporter) recordAudit(c context.Context, task ImportTask, action string, movieId int, before any, after any) {
+	l := logger.GetLogger()
+
+	beforeJson, afterJson, err := models.AuditDiff(before, after)
+	if err != nil {
+		l.Error("Could not build audit diff", zap.String("err", err.Error()))
+	}
+
+	err = i.auditRepo.Record(c, models.AuditEvent{
+		ActorUserId:   task.ActorUserId,
+		ActorApiKeyId: task.ActorApiKeyId,
+		Action:        action,
+		EntityType:    models.AuditEntityMovie,
+		EntityId:      movieId,
+		Before:        beforeJson,
+		After:         afterJson,
+	})
+	if err != nil {
+		l.Error("Could not record audit event",
+			zap.String("action", action),
+			zap.Int("job_id", task.JobId),
+			zap.Int("entity_id", movieId))
+	}
+}
+
+// catalogRefs — справочники жанров, категорий и возрастов для поиска по id или названию
+type catalogRefs struct {
+	genres     map[string]models.Genre
+	categories map[string]models.Category
+	ages       map[string]models.Age
+}
+
+func (i *CatalogImporter) loadRefs(c context.Context) (catalogRefs, error) {
+	refs := catalogRefs{
+		genres:     make(map[string]models.Genre),
+		categories: make(map[string]models.Category),
+		ages:       make(map[string]models.Age),
+	}
+
+	genres, err := i.genresRepo.FindAll(c)
+	if err != nil {
+		return refs, err
+	}
+	for _, genre := range genres {
+		refs.genres[strconv.Itoa(genre.Id)] = genre
+		refs.genres[refKey(genre.Title)] = genre
+	}
+
+	categories, err := i.categoryRepo.FindAll(c)
+	if err != nil {
+		return refs, err
+	}
+	for _, category := range categories {
+		refs.categories[strconv.Itoa(category.Id)] = category
+		refs.categories[refKey(category.Title)] = category
+	}
+
+	ages, err := i.ageRepo.FindAll(c)
+	if err != nil {
+		return refs, err
+	}
+	for _, age := range ages {
+		refs.ages[strconv.Itoa(age.Id)] = age
+		refs.ages[refKey(age.Age)] = age
+	}
+
+	return refs, nil
+}
+
+func refKey(title string) string {
+	return strings.ToLower(strings.TrimSpace(title))
+}
+
+// importRefs — ссылки на справочник: id или название. В JSON — массив строк и чисел либо строка через ";"
+type importRefs []string
+
+func (r *importRefs) UnmarshalJSON(data []byte) error {
+	var joined string
+	if json.Unmarshal(data, &joined) == nil {
+		*r = splitRefs(joined)
+		return nil
+	}
+
+	var values []any
+	err := json.Unmarshal(data, &values)
+	if err != nil {
+		return errors.New("expected an array of ids or titles")
+	}
+
+	refs := make(importRefs, 0, len(values))
+	for _, value := range values {
+		switch v := value.(type) {
+		case string:
+			refs = append(refs, v)
+		case float64:
+			refs = append(refs, strconv.FormatFloat(v, 'f', -1, 64))
+		default:
+			return errors.New("expected an array of ids or titles")
+		}
+	}
+	*r = refs
+
+	return nil
+}
+
+func splitRefs(value string) importRefs {
+	refs := make(importRefs, 0)
+	for _, ref := range strings.Split(value, importRefsSeparator) {
+		if strings.TrimSpace(ref) != "" {
+			refs = append(refs, ref)
+		}
+	}
+
+	return refs
+}
+
+type importRow struct {
+	Line        int        `json:"-"`
+	Err         error      `json:"-"`
+	ExternalKey string     `json:"externalKey"`
+	Title       string     `json:"title"`
+	Description string     `json:"description"`
+	ReleaseYear int        `json:"releaseYear"`
+	Director    string     `json:"director"`
+	TrailerUrl  string     `json:"trailerUrl"`
+	Poster      string     `json:"poster"`
+	Genres      importRefs `json:"genres"`
+	Categories  importRefs `json:"categories"`
+	Ages        importRefs `json:"ages"`
+}
+
+// toMovie проверяет строку целиком и собирает все ошибки, а не только первую
+func (row importRow) toMovie(refs catalogRefs) (models.Movie, error) {
+	problems := make([]string, 0)
+
+	if strings.TrimSpace(row.ExternalKey) == "" {
+		problems = append(problems, "externalKey is required")
+	} else if len(row.ExternalKey) > 255 {
+		problems = append(problems, "externalKey is longer than 255 characters")
+	}
+	if strings.TrimSpace(row.Title) == "" {
+		problems = append(problems, "title is required")
+	}
+	if row.ReleaseYear < 1888 || row.ReleaseYear > time.Now().Year()+10 {
+		problems = append(problems, fmt.Sprintf("releaseYear %d is out of range", row.ReleaseYear))
+	}
+	if row.TrailerUrl != "" {
+		if _, err := models.ParseTrailerUrl(row.TrailerUrl); err != nil {
+			problems = append(problems, "trailerUrl: "+err.Error())
+		}
+	}
+
+	movie := models.Movie{
+		Title:       strings.TrimSpace(row.Title),
+		Description: row.Description,
+		ReleaseYear: row.ReleaseYear,
+		Director:    row.Director,
+		TrailerUrl:  models.NormalizeTrailerUrl(row.TrailerUrl),
+	}
+
+	for _, ref := range row.Genres {
+		genre, ok := refs.genres[refKey(ref)]
+		if !ok {
+			problems = append(problems, fmt.Sprintf("unknown genre %q", ref))
+			continue
+		}
+		movie.Genres = append(movie.Genres, genre)
+	}
+
+	for _, ref := range row.Categories {
+		category, ok := refs.categories[refKey(ref)]
+		if !ok {
+			problems = append(problems, fmt.Sprintf("unknown category %q", ref))
+			continue
+		}
+		movie.Category = append(movie.Category, category)
+	}
+
+	for _, ref := range row.Ages {
+		age, ok := refs.ages[refKey(ref)]
+		if !ok {
+			problems = append(problems, fmt.Sprintf("unknown age %q", ref))
+			continue
+		}
+		movie.Ages = append(movie.Ages, age)
+	}
+
+	// как и в API: без жанра, категории или возраста фильм не попадёт ни в один список зрителя
+	if len(row.Genres) == 0 {
+		problems = append(problems, "at least one genre is required")
+	}
+	if len(row.Categories) == 0 {
+		problems = append(problems, "at least one category is required")
+	}
+	if len(row.Ages) == 0 {
+		problems = append(problems, "at least one age is required")
+	}
+
+	if len(problems) > 0 {
+		return movie, errors.New(strings.Join(problems, "; "))
+	}
+
+	return movie, nil
+}
+
+func isHttpUrl(value string) bool {
+	u, err := url.Parse(value)
+	return err == nil && (u.Scheme == "http" || u.Scheme == "https") && u.Host != ""
+}
+
+// parseImportRows разбирает файл целиком. Ошибка возвращается только если файл не читается вообще,
+// ошибки отдельных строк попадают в importRow.Err
+func parseImportRows(format string, data []byte) ([]importRow, error) {
+	switch format {
+	case models.ImportFormatCsv:
+		return parseCsvRows(data)
+	case models.ImportFormatNdjson:
+		return parseNdjsonRows(data)
+	default:
+		return nil, fmt.Errorf("unsupported import format %q", format)
+	}
+}
+
+var importCsvColumns = []string{"externalKey", "title", "description", "releaseYear", "director", "trailerUrl", "poster", "genres", "categories", "ages"}
+
+func parseCsvRows(data []byte) ([]importRow, error) {
+	reader := csv.NewReader(bytes.NewReader(bytes.TrimPrefix(data, []byte("\xef\xbb\xbf"))))
+	reader.FieldsPerRecord = -1
+
+	header, err := reader.Read()
+	if err != nil {
+		return nil, fmt.Errorf("could not read csv header: %w", err)
+	}
+
+	columns := make(map[string]int)
+	for index, name := range header {
+		name = strings.TrimSpace(name)
+		known := false
+		for _, column := range importCsvColumns {
+			if strings.EqualFold(column, name) {
+				columns[column] = index
+				known = true
+			}
+		}
+		if !known {
+			return nil, fmt.Errorf("unknown csv column %q", name)
+		}
+	}
+
+	for _, required := range []string{"externalKey", "title"} {
+		if _, ok := columns[required]; !ok {
+			return nil, fmt.Errorf("csv column %q is required", required)
+		}
+	}
+
+	rows := make([]importRow, 0)
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+
+		if err != nil {
+			var parseErr *csv.ParseError
+			if !errors.As(err, &parseErr) {
+				return nil, err
+			}
+			rows = append(rows, importRow{Line: parseErr.StartLine, Err: parseErr.Err})
+			continue
+		}
+
+		line, _ := reader.FieldPos(0)
+		row := importRow{Line: line}
+
+		value := func(column string) string {
+			index, ok := columns[column]
+			if !ok || index >= len(record) {
+				return ""
+			}
+			return strings.TrimSpace(record[index])
+		}
+
+		row.ExternalKey = value("externalKey")
+		row.Title = value("title")
+		row.Description = value("description")
+		row.Director = value("director")
+		row.TrailerUrl = value("trailerUrl")
+		row.Poster = value("poster")
+		row.Genres = splitRefs(value("genres"))
+		row.Categories = splitRefs(value("categories"))
+		row.Ages = splitRefs(value("ages"))
+
+		if year := value("releaseYear"); year != "" {
+			row.ReleaseYear, err = strconv.Atoi(year)
+			if err != nil {
+				row.Err = fmt.Errorf("invalid releaseYear %q", year)
+			}
+		}
+
+		rows = append(rows, row)
+	}
+
+	return rows, nil
+}
+
+func parseNdjsonRows(data []byte) ([]importRow, error) {
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	scanner.Buffer(make([]byte, 0, 64*1024), 1<<20)
+
+	rows := make([]importRow, 0)
+	line := 0
+	for scanner.Scan() {
+		line++
+		text := bytes.TrimSpace(scanner.Bytes())
+		if len(text) == 0 {
+			continue
+		}
+
+		row := importRow{}
+		decoder := json.NewDecoder(bytes.NewReader(text))
+		decoder.DisallowUnknownFields()
+		err := decoder.Decode(&row)
+		if err != nil {
+			row = importRow{Err: fmt.Errorf("invalid json: %w", err)}
+		}
+		row.Line = line
+
+		rows = append(rows, row)
+	}
+
+	return rows, scanner.Err()
+}
+
+// openPosterArchive индексирует файлы zip-архива по имени без каталогов
+func openPosterArchive(data []byte) (map[string]*zip.File, error) {
+	files := make(map[string]*zip.File)
+	if len(data) == 0 {
+		return files, nil
+	}
+
+	reader, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		return nil, fmt.Errorf("invalid poster archive: %w", err)
+	}
+
+	for _, file := range reader.File {
+		if file.FileInfo().IsDir() {
+			continue
+		}
+		files[path.Base(file.Name)] = file
+	}
+
+	return files, nil
+}
+
+// savePoster сохраняет постер по URL или из архива в images/ и возвращает имя файла
+func (i *CatalogImporter) savePoster(c context.Context, poster string, archive map[string]*zip.File) (string, error) {
+	if isHttpUrl(poster) {
+		return i.downloadPoster(c, poster)
+	}
+
+	file, ok := archive[path.Base(poster)]
+	if !ok {
+		return "", fmt.Errorf("file %q not found in archive", poster)
+	}
+
+	reader, err := file.Open()
+	if err != nil {
+		return "", err
+	}
+	defer reader.Close()
+
+	return writePoster(reader, filepath.Ext(file.Name))
+}
+
+var errPosterAddress = errors.New("poster address is not public")
+
+// newPosterClient — клиент для постеров по URL из файла импорта. Адрес проверяется при соединении,
+// поэтому во внутреннюю сеть не ведут ни имена, ни редиректы; прокси из окружения не используется
+func newPosterClient() *http.Client {
+	dialer := &net.Dialer{
+		Timeout: importPosterTimeout,
+		Control: func(network string, address string, _ syscall.RawConn) error {
+			host, _, err := net.SplitHostPort(address)
+			if err != nil {
+				return err
+			}
+			if !isPublicAddress(net.ParseIP(host)) {
+				return errPosterAddress
+			}
+			return nil
+		},
+	}
+
+	return &http.Client{
+		Timeout: importPosterTimeout,
+		Transport: &http.Transport{
+			Proxy:                 nil,
+			DialContext:           dialer.DialContext,
+			TLSHandshakeTimeout:   10 * time.Second,
+			ResponseHeaderTimeout: importPosterTimeout,
+		},
+		CheckRedirect: func(request *http.Request, via []*http.Request) error {
+			if len(via) >= 5 {
+				return errors.New("too many redirects")
+			}
+			if !isHttpUrl(request.URL.String()) {
+				return errPosterAddress
+			}
+			return nil
+		},
+	}
+}
+
+// isPublicAddress — адрес не локальный, не из частных сетей и не служебный
+func isPublicAddress(ip net.IP) bool {
+	if ip == nil || ip.IsLoopback() || ip.IsPrivate() || ip.IsUnspecified() ||
+		ip.IsLinkLocalUnicast() || ip.IsLinkLocalMulticast() || ip.IsInterfaceLocalMulticast() || ip.IsMulticast() {
+		return false
+	}
+
+	// 100.64.0.0/10 — CGNAT, внутри облаков тоже ведёт в служебные сети
+	if ip4 := ip.To4(); ip4 != nil && ip4[0] == 100 && ip4[1]&0xc0 == 64 {
+		return false
+	}
+
+	return true
+}
+
+func (i *CatalogImporter) downloadPoster(c context.Context, posterUrl string) (string, error) {
+	if !isHttpUrl(posterUrl) {
+		return "", errors.New("poster URL must be http or https")
+	}
+
+	request, err := http.NewRequestWithContext(c, http.MethodGet, posterUrl, nil)
+	if err != nil {
+		return "", err
+	}
+
+	response, err := i.client.Do(request)
+	if errors.Is(err, errPosterAddress) {
+		return "", err
+	}
+	if err != nil {
+		return "", errors.New("could not download")
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("download returned status %d", response.StatusCode)
+	}
+
+	ext := path.Ext(request.URL.Path)
+	if ext == "" {
+		if exts, _ := mime.ExtensionsByType(response.Header.Get("Content-Type")); len(exts) > 0 {
+			ext = exts[0]
+		}
+	}
+
+	return writePoster(response.Body, ext)
+}
+
+func writePoster(reader io.Reader, ext string) (string, error) {
+	content, err := io.ReadAll(io.LimitReader(reader, importPosterMaxBytes+1))
+	if err != nil {
+		return "", err
+	}
+	if len(content) > importPosterMaxBytes {
+		return "", errors.New("file is larger than 10MB")
+	}
+	if !strings.HasPrefix(http.DetectContentType(content), "image/") {
+		return "", errors.New("file is not an image")
+	}
+
+	filename := fmt.Sprintf("%s%s", uuid.NewString(), ext)
+	err = os.WriteFile(fmt.Sprintf("images/%s", filename), content, 0644)
+	if err != nil {
+		return "", err
+	}
+
+	return filename, nil
+}
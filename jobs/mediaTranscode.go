@@ -0,0 +1,355 @@
+package jobs
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"goozinshe/logger"
+	"goozinshe/models"
+	"goozinshe/repositories"
+	"goozinshe/storage"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"go.uber.org/zap"
+)
+
+const (
+	mediaPollInterval   = 30 * time.Second
+	mediaSegmentSeconds = "6"
+	mediaErrorMaxLength = 2000
+)
+
+// демультиплексоры, которым доверяем загруженный файл. hls, concat и подобные форматы-списки
+// открывают другие файлы и URL, поэтому не допускаются ни в ffprobe, ни в ffmpeg
+var mediaInputFormats = []string{"mov", "matroska", "avi", "mpegts", "mpeg"}
+
+// hlsRendition — ступень HLS-лесенки
+type hlsRendition struct {
+	Name         string
+	Height       int
+	VideoBitrate string
+	MaxRate      string
+	BufSize      string
+	AudioBitrate string
+}
+
+// лесенка от большего к меньшему; ступени выше исходного видео не делаем
+var hlsLadder = []hlsRendition{
+	{Name: "1080p", Height: 1080, VideoBitrate: "5000k", MaxRate: "5350k", BufSize: "7500k", AudioBitrate: "192k"},
+	{Name: "720p", Height: 720, VideoBitrate: "2800k", MaxRate: "2996k", BufSize: "4200k", AudioBitrate: "128k"},
+	{Name: "480p", Height: 480, VideoBitrate: "1400k", MaxRate: "1498k", BufSize: "2100k", AudioBitrate: "128k"},
+	{Name: "360p", Height: 360, VideoBitrate: "800k", MaxRate: "856k", BufSize: "1200k", AudioBitrate: "96k"},
+}
+
+// MediaTranscoder нарезает загруженные видео в HLS с помощью локального ffmpeg.
+// Исходники лежат в uploadDir, результат — в хранилище под ключом media/<id>
+type MediaTranscoder struct {
+	mediaRepo   *repositories.MediaAssetsRepository
+	storage     *storage.LocalStorage
+	uploadDir   string
+	ffmpegPath  string
+	ffprobePath string
+	wake        chan struct{}
+}
+
+func NewMediaTranscoder(
+	mediaRepo *repositories.MediaAssetsRepository,
+	storage *storage.LocalStorage,
+	uploadDir string,
+	ffmpegPath string,
+	ffprobePath string) *MediaTranscoder {
+	return &MediaTranscoder{
+		mediaRepo:   mediaRepo,
+		storage:     storage,
+		uploadDir:   uploadDir,
+		ffmpegPath:  ffmpegPath,
+		ffprobePath: ffprobePath,
+		wake:        make(chan struct{}, 1),
+	}
+}
+
+// UploadPath — файл, в который дописываются куски загрузки
+func (t *MediaTranscoder) UploadPath(assetId int) string {
+	return filepath.Join(t.uploadDir, fmt.Sprintf("%d.upload", assetId))
+}
+
+// StorageKey — каталог с плейлистами и сегментами видео в хранилище
+func (t *MediaTranscoder) StorageKey(assetId int) string {
+	return fmt.Sprintf("media/%d", assetId)
+}
+
+// Notify будит свободного воркера, не дожидаясь очередного опроса очереди
+func (t *MediaTranscoder) Notify() {
+	select {
+	case t.wake <- struct{}{}:
+	default:
+	}
+}
+
+// StartMediaTranscode запускает воркеров; очередь хранится в базе, поэтому переживает перезапуск
+func StartMediaTranscode(c context.Context, transcoder *MediaTranscoder, workers int) {
+	l := logger.GetLogger()
+
+	err := os.MkdirAll(transcoder.uploadDir, 0755)
+	if err != nil {
+		l.Error("Could not create media upload dir", zap.String("err", err.Error()))
+		return
+	}
+
+	requeued, err := transcoder.mediaRepo.RequeueInterrupted(c)
+	if err != nil {
+		l.Error("Could not requeue interrupted media", zap.String("err", err.Error()))
+	} else if requeued > 0 {
+		l.Warn("Interrupted media returned to queue", zap.Int64("count", requeued))
+	}
+
+	for i := 0; i < max(workers, 1); i++ {
+		go transcoder.work(c)
+	}
+}
+
+func (t *MediaTranscoder) work(c context.Context) {
+	l := logger.GetLogger()
+	ticker := time.NewTicker(mediaPollInterval)
+	defer ticker.Stop()
+
+	for {
+		// разбираем очередь до конца, потом ждём сигнала или следующего опроса
+		for {
+			asset, err := t.mediaRepo.ClaimNext(c)
+			if errors.Is(err, pgx.ErrNoRows) {
+				break
+			}
+			if err != nil {
+				l.Error("Could not claim media", zap.String("err", err.Error()))
+				break
+			}
+
+			t.process(c, asset)
+		}
+
+		select {
+		case <-c.Done():
+			return
+		case <-t.wake:
+		case <-ticker.C:
+		}
+	}
+}
+
+func (t *MediaTranscoder) process(c context.Context, asset models.MediaAsset) {
+	l := logger.GetLogger()
+	started := time.Now()
+
+	renditions, err := t.transcode(c, asset)
+	if err != nil {
+		l.Error("Media transcoding failed", zap.Int("media_id", asset.Id), zap.String("err", err.Error()))
+		t.mediaRepo.MarkFailed(c, asset.Id, truncateError(err.Error()))
+		os.Remove(t.UploadPath(asset.Id))
+		return
+	}
+
+	playbackUrl := fmt.Sprintf("/%s/master.m3u8", t.StorageKey(asset.Id))
+	err = t.mediaRepo.MarkReady(c, asset.Id, renditions, playbackUrl)
+	if err != nil {
+		return
+	}
+
+	os.Remove(t.UploadPath(asset.Id))
+	l.Info("Media is ready",
+		zap.Int("media_id", asset.Id),
+		zap.Strings("renditions", renditions),
+		zap.Duration("took", time.Since(started)))
+}
+
+// transcode собирает все ступени одним запуском ffmpeg во временный каталог
+// и переносит результат в хранилище только целиком
+func (t *MediaTranscoder) transcode(c context.Context, asset models.MediaAsset) ([]string, error) {
+	input := t.UploadPath(asset.Id)
+
+	height, hasAudio, format, err := t.probe(c, input)
+	if err != nil {
+		return nil, err
+	}
+
+	ladder := selectLadder(height)
+
+	outDir, err := t.storage.TempDir("media-")
+	if err != nil {
+		return nil, err
+	}
+	defer os.RemoveAll(outDir)
+
+	cmd := exec.CommandContext(c, t.ffmpegPath, hlsArgs(input, format, outDir, ladder, hasAudio)...)
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+
+	err = cmd.Run()
+	if err != nil {
+		return nil, fmt.Errorf("ffmpeg: %w: %s", err, tail(stderr.String()))
+	}
+
+	err = t.storage.Put(t.StorageKey(asset.Id), outDir)
+	if err != nil {
+		return nil, err
+	}
+
+	names := make([]string, 0, len(ladder))
+	for _, rendition := range ladder {
+		names = append(names, rendition.Name)
+	}
+
+	return names, nil
+}
+
+// probe узнаёт высоту кадра, есть ли в файле звук и демультиплексор контейнера.
+// Файл читается только как локальный и только разрешёнными демультиплексорами
+func (t *MediaTranscoder) probe(c context.Context, input string) (int, bool, string, error) {
+	cmd := exec.CommandContext(c, t.ffprobePath,
+		"-v", "error",
+		"-protocol_whitelist", "file",
+		"-format_whitelist", strings.Join(mediaInputFormats, ","),
+		"-show_entries", "stream=codec_type,height:format=format_name",
+		"-of", "json",
+		"file:"+input)
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+
+	output, err := cmd.Output()
+	if err != nil {
+		return 0, false, "", fmt.Errorf("ffprobe: %w: %s", err, tail(stderr.String()))
+	}
+
+	var probe struct {
+		Streams []struct {
+			CodecType string `json:"codec_type"`
+			Height    int    `json:"height"`
+		} `json:"streams"`
+		Format struct {
+			FormatName string `json:"format_name"`
+		} `json:"format"`
+	}
+	err = json.Unmarshal(output, &probe)
+	if err != nil {
+		return 0, false, "", fmt.Errorf("ffprobe: %w", err)
+	}
+
+	// "mov,mp4,m4a,3gp,3g2,mj2" -> mov
+	format, _, _ := strings.Cut(probe.Format.FormatName, ",")
+	if !slices.Contains(mediaInputFormats, format) {
+		return 0, false, "", fmt.Errorf("unsupported container %q", probe.Format.FormatName)
+	}
+
+	height, hasAudio := 0, false
+	for _, stream := range probe.Streams {
+		switch stream.CodecType {
+		case "video":
+			height = max(height, stream.Height)
+		case "audio":
+			hasAudio = true
+		}
+	}
+	if height == 0 {
+		return 0, false, "", errors.New("file has no video stream")
+	}
+
+	return height, hasAudio, format, nil
+}
+
+func selectLadder(height int) []hlsRendition {
+	ladder := make([]hlsRendition, 0, len(hlsLadder))
+	for _, rendition := range hlsLadder {
+		if rendition.Height <= height {
+			ladder = append(ladder, rendition)
+		}
+	}
+
+	// видео меньше самой низкой ступени всё равно нарезаем в неё
+	if len(ladder) == 0 {
+		ladder = append(ladder, hlsLadder[len(hlsLadder)-1])
+	}
+
+	return ladder
+}
+
+// hlsArgs: одна декодировка, split на все ступени, плейлист на ступень и общий master.m3u8.
+// Демультиплексор входа закреплён тем, что определил probe, и ffmpeg не открывает ничего, кроме локальных файлов
+func hlsArgs(input string, format string, outDir string, ladder []hlsRendition, hasAudio bool) []string {
+	filters := make([]string, 0, len(ladder)+1)
+	split := fmt.Sprintf("[0:v]split=%d", len(ladder))
+	for i := range ladder {
+		split += fmt.Sprintf("[v%d]", i)
+	}
+	filters = append(filters, split)
+	for i, rendition := range ladder {
+		filters = append(filters, fmt.Sprintf("[v%d]scale=-2:%d[v%dout]", i, rendition.Height, i))
+	}
+
+	args := []string{
+		"-hide_banner", "-y",
+		"-protocol_whitelist", "file",
+		"-f", format,
+		"-i", "file:" + input,
+		"-filter_complex", strings.Join(filters, ";"),
+	}
+
+	streamMap := make([]string, 0, len(ladder))
+	for i, rendition := range ladder {
+		args = append(args,
+			"-map", fmt.Sprintf("[v%dout]", i),
+			fmt.Sprintf("-c:v:%d", i), "libx264",
+			fmt.Sprintf("-b:v:%d", i), rendition.VideoBitrate,
+			fmt.Sprintf("-maxrate:v:%d", i), rendition.MaxRate,
+			fmt.Sprintf("-bufsize:v:%d", i), rendition.BufSize)
+
+		stream := fmt.Sprintf("v:%d", i)
+		if hasAudio {
+			args = append(args,
+				"-map", "a:0",
+				fmt.Sprintf("-c:a:%d", i), "aac",
+				fmt.Sprintf("-b:a:%d", i), rendition.AudioBitrate,
+				fmt.Sprintf("-ac:a:%d", i), "2")
+			stream += fmt.Sprintf(",a:%d", i)
+		}
+		streamMap = append(streamMap, stream+",name:"+rendition.Name)
+	}
+
+	return append(args,
+		"-preset", "veryfast",
+		// ключевой кадр на границе каждого сегмента
+		"-force_key_frames", "expr:gte(t,n_forced*"+mediaSegmentSeconds+")",
+		"-sc_threshold", "0",
+		"-f", "hls",
+		"-hls_time", mediaSegmentSeconds,
+		"-hls_playlist_type", "vod",
+		"-hls_flags", "independent_segments",
+		"-hls_segment_filename", filepath.Join(outDir, "%v", "segment_%03d.ts"),
+		"-master_pl_name", "master.m3u8",
+		"-var_stream_map", strings.Join(streamMap, " "),
+		filepath.Join(outDir, "%v", "index.m3u8"),
+	)
+}
+
+// в ошибку попадает только хвост вывода ffmpeg — там причина
+func tail(output string) string {
+	output = strings.TrimSpace(output)
+	if len(output) > mediaErrorMaxLength {
+		return output[len(output)-mediaErrorMaxLength:]
+	}
+	return output
+}
+
+func truncateError(message string) string {
+	if len(message) > mediaErrorMaxLength {
+		return message[:mediaErrorMaxLength]
+	}
+	return message
+}
@@ -0,0 +1,338 @@
+package jobs
+
+import (
+	"bufio"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"goozinshe/logger"
+	"goozinshe/models"
+	"goozinshe/repositories"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// файлы дампов в каталоге METADATA_DUMP_DIR, каждый может быть сжат gzip (.gz)
+const (
+	imdbBasicsFile = "title.basics.tsv"
+	imdbCrewFile   = "title.crew.tsv"
+	imdbNamesFile  = "name.basics.tsv"
+	tmdbMoviesFile = "tmdb_movies.json"
+
+	imdbNull = `\N`
+)
+
+// в индекс попадают только фильмы и сериалы
+var imdbTitleTypes = map[string]bool{
+	"movie":        true,
+	"tvMovie":      true,
+	"tvSeries":     true,
+	"tvMiniSeries": true,
+}
+
+var ErrMetadataIndexRunning = errors.New("metadata index is already being rebuilt")
+
+type MetadataIndexer struct {
+	metadataRepo *repositories.MetadataRepository
+	dumpDir      string
+
+	mu     sync.Mutex
+	status models.MetadataIndexStatus
+}
+
+func NewMetadataIndexer(metadataRepo *repositories.MetadataRepository, dumpDir string) *MetadataIndexer {
+	return &MetadataIndexer{metadataRepo: metadataRepo, dumpDir: dumpDir}
+}
+
+func (i *MetadataIndexer) Status() models.MetadataIndexStatus {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+
+	return i.status
+}
+
+// Start запускает перестроение индекса в фоне; одновременно идёт только одно
+func (i *MetadataIndexer) Start(source string) error {
+	if source != models.MetadataSourceImdb && source != models.MetadataSourceTmdb {
+		return fmt.Errorf("unknown metadata source %q", source)
+	}
+
+	i.mu.Lock()
+	defer i.mu.Unlock()
+
+	if i.status.Running {
+		return ErrMetadataIndexRunning
+	}
+
+	now := time.Now()
+	i.status = models.MetadataIndexStatus{Running: true, Source: source, StartedAt: &now}
+
+	go i.rebuild(context.Background(), source)
+
+	return nil
+}
+
+func (i *MetadataIndexer) rebuild(c context.Context, source string) {
+	l := logger.GetLogger()
+
+	var rows int64
+	var err error
+	if source == models.MetadataSourceImdb {
+		rows, err = i.rebuildImdb(c)
+	} else {
+		rows, err = i.rebuildTmdb(c)
+	}
+
+	i.mu.Lock()
+	defer i.mu.Unlock()
+
+	now := time.Now()
+	i.status.Running = false
+	i.status.FinishedAt = &now
+	i.status.Rows = rows
+
+	if err != nil {
+		message := err.Error()
+		i.status.Error = &message
+		l.Error("Metadata index rebuild failed", zap.String("source", source), zap.String("err", message))
+		return
+	}
+
+	l.Info("Metadata index rebuilt", zap.String("source", source), zap.Int64("rows", rows))
+}
+
+func (i *MetadataIndexer) rebuildImdb(c context.Context) (int64, error) {
+	basicsFile, err := openDump(i.dumpDir, imdbBasicsFile)
+	if err != nil {
+		return 0, err
+	}
+	defer basicsFile.Close()
+
+	crewFile, err := openDump(i.dumpDir, imdbCrewFile)
+	if err != nil {
+		return 0, err
+	}
+	defer crewFile.Close()
+
+	namesFile, err := openDump(i.dumpDir, imdbNamesFile)
+	if err != nil {
+		return 0, err
+	}
+	defer namesFile.Close()
+
+	// tconst titleType primaryTitle originalTitle isAdult startYear endYear runtimeMinutes genres
+	basics := newTsvSource(basicsFile, 9, func(fields []string) ([]any, bool) {
+		if !imdbTitleTypes[fields[1]] || fields[4] == "1" {
+			return nil, false
+		}
+		return []any{fields[0], fields[1], fields[2], fields[3], imdbYear(fields[5]), imdbList(fields[8])}, true
+	})
+
+	// tconst directors writers
+	crew := newTsvSource(crewFile, 3, func(fields []string) ([]any, bool) {
+		if fields[1] == imdbNull {
+			return nil, false
+		}
+		return []any{fields[0], imdbList(fields[1])}, true
+	})
+
+	// nconst primaryName ...
+	names := newTsvSource(namesFile, 2, func(fields []string) ([]any, bool) {
+		return []any{fields[0], fields[1]}, true
+	})
+
+	return i.metadataRepo.ReplaceImdb(c, basics, crew, names)
+}
+
+// tmdbMovie — строка NDJSON-дампа TMDb с деталями фильма и append_to_response=credits
+type tmdbMovie struct {
+	Id            int    `json:"id"`
+	Title         string `json:"title"`
+	OriginalTitle string `json:"original_title"`
+	ReleaseDate   string `json:"release_date"`
+	Overview      string `json:"overview"`
+	Adult         bool   `json:"adult"`
+	Genres        []struct {
+		Name string `json:"name"`
+	} `json:"genres"`
+	Credits struct {
+		Crew []struct {
+			Job  string `json:"job"`
+			Name string `json:"name"`
+		} `json:"crew"`
+	} `json:"credits"`
+}
+
+func (i *MetadataIndexer) rebuildTmdb(c context.Context) (int64, error) {
+	file, err := openDump(i.dumpDir, tmdbMoviesFile)
+	if err != nil {
+		return 0, err
+	}
+	defer file.Close()
+
+	titles := newLineSource(file, func(line string) ([]any, bool, error) {
+		var movie tmdbMovie
+		err := json.Unmarshal([]byte(line), &movie)
+		if err != nil {
+			return nil, false, fmt.Errorf("invalid tmdb dump line: %w", err)
+		}
+		if movie.Adult || movie.Id == 0 {
+			return nil, false, nil
+		}
+
+		var year *int
+		if len(movie.ReleaseDate) >= 4 {
+			if value, err := strconv.Atoi(movie.ReleaseDate[:4]); err == nil {
+				year = &value
+			}
+		}
+
+		directors := make([]string, 0)
+		for _, member := range movie.Credits.Crew {
+			if member.Job == "Director" {
+				directors = append(directors, member.Name)
+			}
+		}
+
+		genres := make([]string, 0, len(movie.Genres))
+		for _, genre := range movie.Genres {
+			genres = append(genres, genre.Name)
+		}
+
+		return []any{
+			models.MetadataSourceTmdb,
+			strconv.Itoa(movie.Id),
+			"movie",
+			movie.Title,
+			movie.OriginalTitle,
+			year,
+			directors,
+			genres,
+			movie.Overview,
+		}, true, nil
+	})
+
+	return i.metadataRepo.ReplaceTmdb(c, titles)
+}
+
+func imdbYear(value string) *int {
+	year, err := strconv.Atoi(value)
+	if err != nil {
+		return nil
+	}
+	return &year
+}
+
+func imdbList(value string) []string {
+	if value == imdbNull || value == "" {
+		return []string{}
+	}
+	return strings.Split(value, ",")
+}
+
+type dumpFile struct {
+	io.Reader
+	closers []io.Closer
+}
+
+func (f *dumpFile) Close() error {
+	for i := len(f.closers) - 1; i >= 0; i-- {
+		f.closers[i].Close()
+	}
+	return nil
+}
+
+// openDump открывает name.gz, а если его нет — name
+func openDump(dir string, name string) (*dumpFile, error) {
+	path := filepath.Join(dir, name)
+
+	file, err := os.Open(path + ".gz")
+	if err == nil {
+		reader, err := gzip.NewReader(file)
+		if err != nil {
+			file.Close()
+			return nil, fmt.Errorf("%s.gz: %w", path, err)
+		}
+		return &dumpFile{Reader: reader, closers: []io.Closer{file, reader}}, nil
+	}
+
+	file, err = os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("dump %s not found", path)
+	}
+
+	return &dumpFile{Reader: file, closers: []io.Closer{file}}, nil
+}
+
+// lineSource читает дамп построчно и отдаёт строки в pgx.CopyFrom, не загружая файл целиком
+type lineSource struct {
+	scanner *bufio.Scanner
+	parse   func(line string) ([]any, bool, error)
+	values  []any
+	err     error
+}
+
+func newLineSource(reader io.Reader, parse func(line string) ([]any, bool, error)) *lineSource {
+	scanner := bufio.NewScanner(reader)
+	scanner.Buffer(make([]byte, 0, 64*1024), 4<<20)
+
+	return &lineSource{scanner: scanner, parse: parse}
+}
+
+func (s *lineSource) Next() bool {
+	for s.scanner.Scan() {
+		line := s.scanner.Text()
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+
+		values, ok, err := s.parse(line)
+		if err != nil {
+			s.err = err
+			return false
+		}
+		if ok {
+			s.values = values
+			return true
+		}
+	}
+
+	s.err = s.scanner.Err()
+	return false
+}
+
+func (s *lineSource) Values() ([]any, error) {
+	return s.values, nil
+}
+
+func (s *lineSource) Err() error {
+	return s.err
+}
+
+// newTsvSource разбирает TSV IMDb: первая строка — заголовок, кавычки не экранируются
+func newTsvSource(reader io.Reader, minFields int, parse func(fields []string) ([]any, bool)) *lineSource {
+	header := true
+	return newLineSource(reader, func(line string) ([]any, bool, error) {
+		if header {
+			header = false
+			return nil, false, nil
+		}
+
+		fields := strings.Split(line, "\t")
+		if len(fields) < minFields {
+			return nil, false, nil
+		}
+
+		values, ok := parse(fields)
+		return values, ok, nil
+	})
+}
@@ -0,0 +1,33 @@
+package jobs
+
+import (
+	"context"
+	"goozinshe/logger"
+	"goozinshe/repositories"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+const publishCheckInterval = time.Minute
+
+// StartPublishScheduler раз в минуту публикует фильмы, у которых наступило время publish_at
+func StartPublishScheduler(c context.Context, moviesAdminRepo *repositories.MoviesAdminRepository) {
+	l := logger.GetLogger()
+	ticker := time.NewTicker(publishCheckInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-c.Done():
+			return
+		case <-ticker.C:
+			published, err := moviesAdminRepo.PublishScheduled(c)
+			if err != nil {
+				l.Error("Could not publish scheduled movies", zap.String("err", err.Error()))
+			} else if published > 0 {
+				l.Info("Scheduled movies published", zap.Int64("published", published))
+			}
+		}
+	}
+}
@@ -0,0 +1,61 @@
+package jobs
+
+import (
+	"context"
+	"goozinshe/logger"
+	"goozinshe/repositories"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+const (
+	defaultRecommendationsInterval = time.Hour
+	recommendationsPerViewer       = 50
+)
+
+// StartRecommendationsRefresh пересчитывает рекомендации всех зрителей сразу при старте и затем раз в interval
+func StartRecommendationsRefresh(c context.Context, recommendationsRepo *repositories.RecommendationsRepository, interval time.Duration) {
+	if interval <= 0 {
+		interval = defaultRecommendationsInterval
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		refreshRecommendations(c, recommendationsRepo)
+
+		select {
+		case <-c.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func refreshRecommendations(c context.Context, recommendationsRepo *repositories.RecommendationsRepository) {
+	l := logger.GetLogger()
+	viewers, err := recommendationsRepo.FindViewers(c)
+	if err != nil {
+		l.Error("Could not find viewers for recommendations", zap.String("err", err.Error()))
+		return
+	}
+
+	refreshed := 0
+	for _, viewer := range viewers {
+		if c.Err() != nil {
+			return
+		}
+
+		// ошибка одного зрителя не останавливает пересчёт остальных
+		err := recommendationsRepo.Refresh(c, viewer, recommendationsPerViewer)
+		if err != nil {
+			l.Error("Could not refresh recommendations", zap.Int("userId", viewer.UserId), zap.String("err", err.Error()))
+			continue
+		}
+		refreshed++
+	}
+
+	l.Info("Recommendations refreshed", zap.Int("viewers", refreshed))
+}
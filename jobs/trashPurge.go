@@ -0,0 +1,34 @@
+package jobs
+
+import (
+	"context"
+	"goozinshe/logger"
+	"goozinshe/repositories"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+const trashPurgeInterval = time.Hour
+
+// StartTrashPurge раз в час окончательно удаляет записи, пролежавшие в корзине дольше retention
+func StartTrashPurge(c context.Context, trashRepo *repositories.TrashRepository, retention time.Duration) {
+	l := logger.GetLogger()
+	ticker := time.NewTicker(trashPurgeInterval)
+	defer ticker.Stop()
+
+	for {
+		purged, err := trashRepo.Purge(c, time.Now().Add(-retention))
+		if err != nil {
+			l.Error("Could not purge trash", zap.String("err", err.Error()))
+		} else if purged > 0 {
+			l.Info("Trash purged", zap.Int64("purged", purged))
+		}
+
+		select {
+		case <-c.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
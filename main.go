@@ -5,9 +5,12 @@ import (
 	"goozinshe/config"
 	"goozinshe/docs"
 	"goozinshe/handlers"
+	"goozinshe/jobs"
 	"goozinshe/logger"
 	"goozinshe/middlewares"
 	"goozinshe/repositories"
+	"goozinshe/storage"
+	"goozinshe/tokens"
 	"time"
 
 	"github.com/gin-contrib/cors"
@@ -17,12 +20,15 @@ import (
 	"github.com/spf13/viper"
 	swaggerfiles "github.com/swaggo/files"
 	swagger "github.com/swaggo/gin-swagger"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
 )
 
 //
 // @title           OZINSHE	API
 // @version         1.0
 // @description     This is a sample server celler server.
+// @description     Ошибки приходят в формате models.ApiError: стабильный code, message на языке из Accept-Language или ?lang= (kk, ru, en), details с ошибками полей и requestId — тот же, что в заголовке X-Request-Id.
 // @termsOfService  http://swagger.io/terms/
 
 // @contact.name   API Support
@@ -45,14 +51,22 @@ func main() {
 
 	logger := logger.GetLogger()
 	r.Use(
-		ginzap.Ginzap(logger, time.RFC3339, true),
-		ginzap.RecoveryWithZap(logger, true),
+		middlewares.RequestContext(),
+		ginzap.GinzapWithConfig(logger, &ginzap.Config{
+			TimeFormat: time.RFC3339,
+			UTC:        true,
+			Context: func(c *gin.Context) []zapcore.Field {
+				return []zapcore.Field{zap.String("requestId", middlewares.RequestId(c))}
+			},
+		}),
+		ginzap.CustomRecoveryWithZap(logger, true, middlewares.RecoverWithApiError),
 	)
 
 	corsConfig := cors.Config{
 		AllowAllOrigins: true,
 		AllowHeaders:    []string{"*"},
 		AllowMethods:    []string{"*"},
+		ExposeHeaders:   []string{"ETag", "Content-Language", middlewares.RequestIdHeader, "Location", "Upload-Offset", "Upload-Length"},
 	}
 	r.Use(cors.New(corsConfig))
 
@@ -75,12 +89,74 @@ func main() {
 	allseriesRepository := repositories.NewAllSeriesRepository(conn)
 	selectedRepository := repositories.NewSelectedlistRepository(conn)
 	rolesRepository := repositories.NewRolesRepository(conn)
+	apiKeysRepository := repositories.NewApiKeysRepository(conn)
+	signingKeysRepository := repositories.NewSigningKeysRepository(conn)
+	sessionsRepository := repositories.NewSessionsRepository(conn)
+	auditRepository := repositories.NewAuditRepository(conn)
+	trashRepository := repositories.NewTrashRepository(conn)
+	movieRevisionsRepository := repositories.NewMovieRevisionsRepository(conn)
+	importJobsRepository := repositories.NewImportJobsRepository(conn)
+	metadataRepository := repositories.NewMetadataRepository(conn)
+	peopleRepository := repositories.NewPeopleRepository(conn)
+	translationsRepository := repositories.NewTranslationsRepository(conn)
+	referencesRepository := repositories.NewReferencesRepository(conn)
+	mediaAssetsRepository := repositories.NewMediaAssetsRepository(conn)
+	subtitleTracksRepository := repositories.NewSubtitleTracksRepository(conn)
+	watchProgressRepository := repositories.NewWatchProgressRepository(conn)
+	profilesRepository := repositories.NewProfilesRepository(conn)
+	parentalControlsRepository := repositories.NewParentalControlsRepository(conn)
+	favoritesRepository := repositories.NewFavoritesRepository(conn)
+	reviewsRepository := repositories.NewReviewsRepository(conn)
+	badWordsRepository := repositories.NewBadWordsRepository(conn)
+	recommendationsRepository := repositories.NewRecommendationsRepository(conn)
+
+	imagesStorage := storage.NewLocalStorage("images")
+
+	keyManager, err := tokens.NewKeyManager(
+		signingKeysRepository,
+		config.Config.JwtSigningMethod,
+		config.Config.JwtKeyRotation,
+		config.Config.JwtExpiresIn,
+		config.Config.JwtKeyEncryption,
+	)
+	if err != nil {
+		panic(err)
+	}
+
+	err = keyManager.Init(context.Background())
+	if err != nil {
+		panic(err)
+	}
+	go keyManager.StartRotation(context.Background())
+	go jobs.StartTrashPurge(context.Background(), trashRepository, config.Config.TrashRetention)
+	go jobs.StartPublishScheduler(context.Background(), moviesAdminRepository)
+	go jobs.StartRecommendationsRefresh(context.Background(), recommendationsRepository, config.Config.RecommendRefresh)
+
+	catalogImporter := jobs.NewCatalogImporter(moviesRepository, genresRepostiroy, categoryRepository, ageRepository, importJobsRepository, moviesAdminRepository, auditRepository)
+	go jobs.StartCatalogImport(context.Background(), catalogImporter)
+	metadataIndexer := jobs.NewMetadataIndexer(metadataRepository, config.Config.MetadataDumpDir)
+	mediaTranscoder := jobs.NewMediaTranscoder(
+		mediaAssetsRepository,
+		imagesStorage,
+		config.Config.MediaUploadDir,
+		config.Config.FfmpegPath,
+		config.Config.FfprobePath,
+	)
+	go jobs.StartMediaTranscode(context.Background(), mediaTranscoder, config.Config.MediaWorkers)
+	requestValidator := handlers.NewRequestValidator(referencesRepository)
 
 	moviesHandler := handlers.NewMoviesHandler(
 		moviesRepository,
 		genresRepostiroy,
 		categoryRepository,
 		ageRepository,
+		auditRepository,
+		translationsRepository,
+		requestValidator,
+		mediaAssetsRepository,
+		subtitleTracksRepository,
+		parentalControlsRepository,
+		moviesAdminRepository,
 	)
 
 	movieAdminResponseHandler := handlers.NewMovieAdminResponseHandler(
@@ -89,30 +165,74 @@ func main() {
 		categoryRepository,
 		ageRepository,
 		allseriesRepository,
+		auditRepository,
+		requestValidator,
+		mediaAssetsRepository,
+		subtitleTracksRepository,
 	)
 
-	selectedHandlers := handlers.NewSelectedlistHandler(moviesRepository, selectedRepository)
+	selectedHandlers := handlers.NewSelectedlistHandler(moviesRepository, selectedRepository, translationsRepository)
 	rolesHandlers := handlers.NewRolesHandlers(rolesRepository, usersRepository, moviesAdminRepository,
 		genresRepostiroy,
 		categoryRepository,
 		ageRepository,
-		allseriesRepository)
-	genresHandler := handlers.NewGenreHanlers(genresRepostiroy)
-	imageHandlers := handlers.NewImageHandlers()
-	categoryHandlers := handlers.NewCategoryHandlers(categoryRepository)
-	agesHandlers := handlers.NewAgeHandler(ageRepository)
-	usersHandlers := handlers.NewUsersHandlers(usersRepository)
-	authHandlers := handlers.NewAuthHandlers(usersRepository)
-	allseriesHandlers := handlers.NewAllSeriesHandlers(allseriesRepository)
+		allseriesRepository,
+		auditRepository,
+		requestValidator)
+	genresHandler := handlers.NewGenreHanlers(genresRepostiroy, auditRepository, translationsRepository, requestValidator)
+	imageHandlers := handlers.NewImageHandlers(imagesStorage)
+	categoryHandlers := handlers.NewCategoryHandlers(categoryRepository, auditRepository, translationsRepository, requestValidator)
+	agesHandlers := handlers.NewAgeHandler(ageRepository, auditRepository, translationsRepository, requestValidator)
+	usersHandlers := handlers.NewUsersHandlers(usersRepository, auditRepository, requestValidator)
+	authHandlers := handlers.NewAuthHandlers(usersRepository, sessionsRepository, keyManager)
+	allseriesHandlers := handlers.NewAllSeriesHandlers(allseriesRepository, auditRepository, requestValidator, subtitleTracksRepository, parentalControlsRepository)
+	apiKeysHandlers := handlers.NewApiKeysHandlers(apiKeysRepository, requestValidator)
+	jwksHandlers := handlers.NewJwksHandlers(keyManager)
+	sessionsHandlers := handlers.NewSessionsHandlers(sessionsRepository, usersRepository)
+	auditHandlers := handlers.NewAuditHandlers(auditRepository)
+	trashHandlers := handlers.NewTrashHandlers(trashRepository, auditRepository)
+	movieRevisionsHandlers := handlers.NewMovieRevisionsHandlers(moviesRepository, movieRevisionsRepository, auditRepository, moviesAdminRepository)
+	importHandlers := handlers.NewImportHandlers(importJobsRepository, auditRepository, catalogImporter)
+	exportHandlers := handlers.NewExportHandlers(moviesAdminRepository, genresRepostiroy, categoryRepository, ageRepository)
+	metadataHandlers := handlers.NewMetadataHandlers(metadataRepository, genresRepostiroy, metadataIndexer, requestValidator)
+	peopleHandlers := handlers.NewPeopleHandlers(peopleRepository, moviesAdminRepository, auditRepository, requestValidator, parentalControlsRepository, moviesRepository)
+	translationsHandlers := handlers.NewTranslationsHandlers(translationsRepository, auditRepository)
+	mediaHandlers := handlers.NewMediaHandlers(mediaAssetsRepository, moviesAdminRepository, auditRepository, mediaTranscoder, imagesStorage, requestValidator, parentalControlsRepository)
+	profilesHandlers := handlers.NewProfilesHandlers(profilesRepository, sessionsRepository, keyManager, requestValidator, parentalControlsRepository)
+	progressHandlers := handlers.NewProgressHandlers(watchProgressRepository, translationsRepository, requestValidator)
+	parentalControlsHandlers := handlers.NewParentalControlsHandlers(parentalControlsRepository, requestValidator)
+	favoritesHandlers := handlers.NewFavoritesHandlers(favoritesRepository, moviesRepository, parentalControlsRepository, translationsRepository)
+	reviewsHandlers := handlers.NewReviewsHandlers(reviewsRepository, badWordsRepository, moviesRepository, parentalControlsRepository, requestValidator)
+	recommendationsHandlers := handlers.NewRecommendationsHandlers(recommendationsRepository, moviesRepository, parentalControlsRepository, translationsRepository)
+	reviewModerationHandlers := handlers.NewReviewModerationHandlers(reviewsRepository, badWordsRepository, auditRepository, requestValidator)
+	subtitlesHandlers := handlers.NewSubtitlesHandlers(subtitleTracksRepository, moviesAdminRepository, auditRepository, imagesStorage, requestValidator, parentalControlsRepository)
 
 	authorized := r.Group("")
-	authorized.Use(middlewares.AuthMiddleware)
+	authorized.Use(middlewares.AuthMiddleware(apiKeysRepository, sessionsRepository, keyManager, profilesRepository, parentalControlsRepository))
 
 	authorized.GET("/movies/:id", moviesHandler.FindById) //http://localhost:8081/movies/:id
 	authorized.GET("/movies", moviesHandler.FindAll)      //http://localhost:8081/movies/
 	authorized.POST("/movies", moviesHandler.Create)
 	authorized.PUT("/movies/:id", moviesHandler.Update)
+	authorized.PATCH("/movies/:id", moviesHandler.Patch)
+	authorized.PUT("/movies/:id/poster", moviesHandler.UpdatePoster)
 	authorized.DELETE("/movies/:id", moviesHandler.Delete)
+	authorized.GET("/movies/:id/revisions", movieRevisionsHandlers.FindAll)
+	authorized.GET("/movies/:id/revisions/diff", movieRevisionsHandlers.Diff)
+	authorized.POST("/movies/:id/revisions/:rev/restore", movieRevisionsHandlers.Restore)
+	authorized.GET("/movies/:id/credits", peopleHandlers.FindMovieCredits)
+	authorized.PUT("/movies/:id/credits", peopleHandlers.ReplaceMovieCredits)
+	authorized.GET("/movies/:id/media", mediaHandlers.FindByMovie)
+	authorized.GET("/media/:mediaId/*file", mediaHandlers.HandleGetMediaFile)
+	authorized.GET("/movies/:id/subtitles", subtitlesHandlers.FindByMovie)
+	authorized.GET("/subtitles/:file", subtitlesHandlers.HandleGetSubtitleFile)
+
+	authorized.GET("/people", peopleHandlers.FindAll)
+	authorized.GET("/people/:id", peopleHandlers.FindById)
+	authorized.POST("/people", peopleHandlers.Create)
+	authorized.PUT("/people/:id", peopleHandlers.Update)
+	authorized.PUT("/people/:id/photo", peopleHandlers.UpdatePhoto)
+	authorized.DELETE("/people/:id", peopleHandlers.Delete)
 
 	authorized.GET("/moviesAdmin/:id", movieAdminResponseHandler.FindById) //http://localhost:8081/movies/:id
 	authorized.GET("/moviesAdmin", movieAdminResponseHandler.FindAll)      //http://localhost:8081/movies/
@@ -120,11 +240,14 @@ func main() {
 	authorized.PUT("/moviesAdmin/:id", movieAdminResponseHandler.Update)
 	authorized.DELETE("/moviesAdmin/:id", movieAdminResponseHandler.Delete)
 	authorized.PATCH("/moviesAdmin/:movieId/setWatched", movieAdminResponseHandler.HandleSetWatched)
+	authorized.PATCH("/moviesAdmin/:movieId/status", movieAdminResponseHandler.HandleSetStatus)
 
 	authorized.GET("/genres/:id", genresHandler.FindById) //http://localhost:8081/genres/:id
 	authorized.GET("/genres", genresHandler.FindAll)      //http://localhost:8081/genres/
 	authorized.POST("/genres", genresHandler.Create)
 	authorized.PUT("/genres/:id", genresHandler.Update)
+	authorized.PATCH("/genres/:id", genresHandler.Patch)
+	authorized.PUT("/genres/:id/poster", genresHandler.UpdatePoster)
 	authorized.DELETE("/genres/:id", genresHandler.Delete)
 
 	authorized.POST("/categories", categoryHandlers.Create)       //http://localhost:8081/categories/
@@ -132,11 +255,15 @@ func main() {
 	authorized.GET("/categories", categoryHandlers.FindAll)
 	authorized.GET("/categories/:id", categoryHandlers.FindById)
 	authorized.PUT("/categories/:id", categoryHandlers.Update)
+	authorized.PATCH("/categories/:id", categoryHandlers.Patch)
+	authorized.PUT("/categories/:id/poster", categoryHandlers.UpdatePoster)
 
 	authorized.POST("/ages", agesHandlers.HandleAddAge) //http://localhost:8081/ages/
 	authorized.GET("/ages", agesHandlers.FindAll)       //http://localhost:8081/ages/:id
 	authorized.GET("/ages/:id", agesHandlers.FindById)
 	authorized.PUT("/ages/:id", agesHandlers.Update)
+	authorized.PATCH("/ages/:id", agesHandlers.Patch)
+	authorized.PUT("/ages/:id/poster", agesHandlers.UpdatePoster)
 	authorized.DELETE("/ages/:id", agesHandlers.Delete)
 
 	authorized.GET("/users", usersHandlers.FindAll)      //http://localhost:8081/users/
@@ -144,12 +271,14 @@ func main() {
 	authorized.PATCH("/users/:id/changePassword", usersHandlers.ChangePassword)
 	authorized.POST("/users", usersHandlers.Create)
 	authorized.PUT("/users/:id", usersHandlers.Update)
+	authorized.PATCH("/users/:id", usersHandlers.Patch)
 	authorized.DELETE("/users/:id", usersHandlers.Delete)
 
 	authorized.POST("/allseries", allseriesHandlers.Create)
 	authorized.GET("/allseries/:id", allseriesHandlers.FindById)
 	authorized.GET("/allseries", allseriesHandlers.FindAll)    //http://localhost:8081/allseries/
 	authorized.PUT("/allseries/:id", allseriesHandlers.Update) //http://localhost:8081/allseries/:id
+	authorized.PATCH("/allseries/:id", allseriesHandlers.Patch)
 	authorized.DELETE("/allseries/:id", allseriesHandlers.Delete)
 
 	authorized.GET("/roles", rolesHandlers.FindAll) //
@@ -167,16 +296,97 @@ func main() {
 	authorized.DELETE("/rolesmovie/:id", rolesHandlers.DeleteUser)
 	authorized.PUT("/rolesmovie/:id", rolesHandlers.UpdateUser)
 
-
 	authorized.POST("/selected/:movieId", selectedHandlers.HandleAddMovie)
 	authorized.GET("/selected", selectedHandlers.HandleGetMoviesAndSeries) //http://localhost:8081/moviesandseries/
 
+	authorized.GET("/admin/apiKeys", apiKeysHandlers.FindAll)
+	authorized.GET("/admin/apiKeys/:id", apiKeysHandlers.FindById)
+	authorized.POST("/admin/apiKeys", apiKeysHandlers.Create)
+	authorized.DELETE("/admin/apiKeys/:id", apiKeysHandlers.Revoke)
+
+	authorized.GET("/me/sessions", sessionsHandlers.FindMine)
+	authorized.DELETE("/me/sessions/:id", sessionsHandlers.TerminateMine)
+	authorized.GET("/me/profiles", profilesHandlers.FindAll)
+	authorized.GET("/me/profiles/:id", profilesHandlers.FindById)
+	authorized.POST("/me/profiles", profilesHandlers.Create)
+	authorized.PUT("/me/profiles/:id", profilesHandlers.Update)
+	authorized.PUT("/me/profiles/:id/avatar", profilesHandlers.UpdateAvatar)
+	authorized.DELETE("/me/profiles/:id", profilesHandlers.Delete)
+	authorized.POST("/me/profiles/:id/token", profilesHandlers.Select)
+	authorized.PUT("/me/progress", progressHandlers.Save)
+	authorized.GET("/me/progress/:movieId", progressHandlers.FindByMovie)
+	authorized.GET("/me/continueWatching", progressHandlers.ContinueWatching)
+	authorized.GET("/me/favorites", favoritesHandlers.FindAll)
+	authorized.PUT("/me/favorites/:movieId", favoritesHandlers.Add)
+	authorized.DELETE("/me/favorites/:movieId", favoritesHandlers.Remove)
+	authorized.GET("/movies/:id/reviews", reviewsHandlers.FindByMovie)
+	authorized.GET("/me/reviews", reviewsHandlers.FindMine)
+	authorized.POST("/reviews", reviewsHandlers.Create)
+	authorized.GET("/reviews/:id", reviewsHandlers.FindById)
+	authorized.PUT("/reviews/:id", reviewsHandlers.Update)
+	authorized.DELETE("/reviews/:id", reviewsHandlers.Delete)
+	authorized.GET("/reviews/:id/replies", reviewsHandlers.FindReplies)
+	authorized.PUT("/reviews/:id/helpful", reviewsHandlers.Vote)
+	authorized.DELETE("/reviews/:id/helpful", reviewsHandlers.Unvote)
+	authorized.POST("/reviews/:id/reports", reviewsHandlers.Report)
+	authorized.GET("/movies/:id/similar", recommendationsHandlers.FindSimilar)
+	authorized.GET("/me/recommendations", recommendationsHandlers.FindForViewer)
+	authorized.GET("/me/parentalControls", parentalControlsHandlers.Find)
+	authorized.PUT("/me/parentalControls", parentalControlsHandlers.Update)
+	authorized.POST("/me/parentalControls/unlock", parentalControlsHandlers.Unlock)
+	authorized.POST("/me/parentalControls/lock", parentalControlsHandlers.Lock)
+	authorized.GET("/admin/users/:id/sessions", sessionsHandlers.FindByUser)
+	authorized.DELETE("/admin/users/:id/sessions", sessionsHandlers.TerminateAllByUser)
+	authorized.DELETE("/admin/users/:id/sessions/:sessionId", sessionsHandlers.TerminateByUser)
+
+	authorized.GET("/admin/audit", auditHandlers.FindAll)
+
+	authorized.GET("/admin/trash", trashHandlers.FindAll)
+	authorized.POST("/admin/trash/:type/:id/restore", trashHandlers.Restore)
+
+	authorized.POST("/admin/import", importHandlers.Create)
+	authorized.GET("/admin/import", importHandlers.FindAll)
+	authorized.GET("/admin/import/:id", importHandlers.FindById)
+	authorized.GET("/admin/import/:id/report", importHandlers.Report)
+
+	authorized.GET("/admin/export", exportHandlers.Export)
+
+	authorized.POST("/admin/metadata/index", metadataHandlers.RebuildIndex)
+	authorized.GET("/admin/metadata/index", metadataHandlers.IndexStatus)
+	authorized.GET("/admin/metadata/search", metadataHandlers.Search)
+	authorized.POST("/admin/metadata/apply", metadataHandlers.Apply)
+	authorized.GET("/admin/metadata/genres", metadataHandlers.FindGenreMappings)
+	authorized.PUT("/admin/metadata/genres", metadataHandlers.SetGenreMapping)
+
+	authorized.GET("/admin/translations/:type/:id", translationsHandlers.FindByEntity)
+	authorized.PUT("/admin/translations/:type/:id/:locale", translationsHandlers.Set)
+	authorized.DELETE("/admin/translations/:type/:id/:locale", translationsHandlers.Delete)
+
+	authorized.POST("/admin/media/uploads", mediaHandlers.CreateUpload)
+	authorized.HEAD("/admin/media/uploads/:id", mediaHandlers.UploadOffset)
+	authorized.PATCH("/admin/media/uploads/:id", mediaHandlers.UploadChunk)
+	authorized.GET("/admin/media/:id", mediaHandlers.FindById)
+	authorized.DELETE("/admin/media/:id", mediaHandlers.Delete)
+
+	authorized.POST("/admin/subtitles", subtitlesHandlers.Create)
+	authorized.GET("/admin/subtitles/:id", subtitlesHandlers.FindById)
+	authorized.PUT("/admin/subtitles/:id", subtitlesHandlers.Update)
+	authorized.DELETE("/admin/subtitles/:id", subtitlesHandlers.Delete)
+	authorized.GET("/admin/reviews", reviewModerationHandlers.FindQueue)
+	authorized.GET("/admin/reviews/:id", reviewModerationHandlers.FindById)
+	authorized.GET("/admin/reviews/:id/reports", reviewModerationHandlers.FindReports)
+	authorized.PUT("/admin/reviews/:id/moderation", reviewModerationHandlers.Moderate)
+	authorized.GET("/admin/badWords", reviewModerationHandlers.FindBadWords)
+	authorized.POST("/admin/badWords", reviewModerationHandlers.CreateBadWord)
+	authorized.DELETE("/admin/badWords/:id", reviewModerationHandlers.DeleteBadWord)
+
 	authorized.POST("/auth/signOut", authHandlers.SignOut)     //http://localhost:8081/auth/signOut
 	authorized.GET("/auth/userInfo", authHandlers.GetUserInfo) //http://localhost:8081/auth/userInfo
 
 	unauthorized := r.Group("")
 	unauthorized.GET("/images/:imageId", imageHandlers.HandleGetImageById)
 	unauthorized.POST("/auth/signIn", authHandlers.SignIn) //http://localhost:8081/auth/signIn
+	unauthorized.GET("/.well-known/jwks.json", jwksHandlers.HandleGetJwks)
 
 	docs.SwaggerInfo.BasePath = "/"
 	unauthorized.GET("/swagger/*any", swagger.WrapHandler(swaggerfiles.Handler))
@@ -187,6 +397,8 @@ func main() {
 
 func loadConfig() error {
 	viper.SetConfigFile(".env")
+	// переменные окружения перекрывают .env: так в прод передаются секреты вроде JWT_KEY_ENCRYPTION_KEY
+	viper.AutomaticEnv()
 	err := viper.ReadInConfig()
 	if err != nil {
 		return err
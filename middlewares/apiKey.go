@@ -0,0 +1,49 @@
+package middlewares
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"net/http"
+	"strings"
+)
+
+// ApiKeyPrefix отличает API ключи от JWT в заголовке "Authorization: Bearer ..."
+const ApiKeyPrefix = "ozk_"
+
+// GenerateApiKey возвращает новый ключ и его короткий префикс для отображения в админке
+func GenerateApiKey() (key string, prefix string, err error) {
+	secret := make([]byte, 32)
+	_, err = rand.Read(secret)
+	if err != nil {
+		return "", "", err
+	}
+
+	key = ApiKeyPrefix + base64.RawURLEncoding.EncodeToString(secret)
+	prefix = key[:len(ApiKeyPrefix)+8]
+
+	return key, prefix, nil
+}
+
+// HashApiKey — в базе хранится только sha256 от ключа
+func HashApiKey(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(sum[:])
+}
+
+// requiredPermission выводит право из маршрута: GET /movies/:id -> "movies:read", POST /movies -> "movies:write"
+func requiredPermission(method string, fullPath string) string {
+	segments := strings.Split(strings.TrimPrefix(fullPath, "/"), "/")
+	resource := segments[0]
+	if resource == "admin" && len(segments) > 1 {
+		resource = "admin/" + segments[1]
+	}
+
+	switch method {
+	case http.MethodGet, http.MethodHead:
+		return resource + ":read"
+	default:
+		return resource + ":write"
+	}
+}
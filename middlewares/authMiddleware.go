@@ -1,8 +1,9 @@
 package middlewares
 
 import (
-	"goozinshe/config"
 	"goozinshe/models"
+	"goozinshe/repositories"
+	"goozinshe/tokens"
 	"net/http"
 	"strconv"
 	"strings"
@@ -11,32 +12,172 @@ import (
 	"github.com/golang-jwt/jwt/v5"
 )
 
-func AuthMiddleware(c *gin.Context) {
-	authHeader := c.GetHeader("Authorization")
-	if authHeader == "" {
-		c.JSON(http.StatusUnauthorized, models.NewApiError("authorization header required"))
-		c.Abort()
-		return
+func AuthMiddleware(
+	apiKeysRepo *repositories.ApiKeysRepository,
+	sessionsRepo *repositories.SessionsRepository,
+	keyManager *tokens.KeyManager,
+	profilesRepo *repositories.ProfilesRepository,
+	parentalRepo *repositories.ParentalControlsRepository,
+) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		apiKey := c.GetHeader("X-Api-Key")
+		authHeader := c.GetHeader("Authorization")
+		tokenString, hasBearer := strings.CutPrefix(authHeader, "Bearer ")
+
+		if apiKey == "" && hasBearer && strings.HasPrefix(tokenString, ApiKeyPrefix) {
+			apiKey = tokenString
+		}
+
+		if apiKey != "" {
+			authorizeApiKey(c, apiKeysRepo, apiKey)
+			return
+		}
+
+		if authHeader == "" {
+			abortWithError(c, http.StatusUnauthorized, models.ErrCodeUnauthorized)
+			return
+		}
+
+		if !hasBearer {
+			abortWithError(c, http.StatusUnauthorized, models.ErrCodeInvalidToken)
+			return
+		}
+
+		token, err := jwt.Parse(tokenString, keyManager.Keyfunc, jwt.WithValidMethods(tokens.ValidMethods))
+		if err != nil || !token.Valid {
+			abortWithError(c, http.StatusUnauthorized, models.ErrCodeInvalidToken)
+			return
+		}
+
+		subject, err := token.Claims.GetSubject()
+		if err != nil {
+			abortWithError(c, http.StatusUnauthorized, models.ErrCodeInvalidToken)
+			return
+		}
+
+		userId, _ := strconv.Atoi(subject)
+
+		claims, _ := token.Claims.(jwt.MapClaims)
+		sessionId, _ := claims["jti"].(string)
+		session, err := sessionsRepo.FindById(c, sessionId)
+		if err != nil || session.UserId != userId || session.TerminatedAt != nil {
+			abortWithError(c, http.StatusUnauthorized, models.ErrCodeSessionTerminated)
+			return
+		}
+
+		sessionsRepo.Touch(c, sessionId, c.ClientIP())
+
+		c.Set("userId", userId)
+		c.Set("sessionId", sessionId)
+
+		if !selectProfile(c, profilesRepo, sessionsRepo, session, claims) {
+			return
+		}
+
+		if !setAgeLimit(c, parentalRepo, userId, sessionId) {
+			return
+		}
+
+		c.Next()
 	}
+}
 
-	tokenString := strings.Split(authHeader, "Bearer ")[1]
-	token, err := jwt.Parse(tokenString, func(token *jwt.Token) (interface{}, error) {
-		return []byte(config.Config.JwtSecretKey), nil
-	})
-	if err != nil || !token.Valid {
-		c.JSON(http.StatusUnauthorized, models.NewApiError("invalid token"))
-		c.Abort()
-		return
+// selectProfile кладёт в контекст профиль из claim pid токена, а если его нет — из X-Profile-Id.
+// Первый выбранный профиль закрепляется за сессией, и дальше он действует и для токенов без профиля;
+// сменить его можно только через POST /me/profiles/:id/token. Заголовок или pid с другим профилем,
+// как и профиль другого пользователя, — 403; язык профиля используется, если клиент не выбрал язык сам
+func selectProfile(c *gin.Context, profilesRepo *repositories.ProfilesRepository, sessionsRepo *repositories.SessionsRepository, session models.Session, claims jwt.MapClaims) bool {
+	profileId := 0
+	pid, hasClaim := claims[models.ProfileIdClaim].(float64)
+	if hasClaim {
+		profileId = int(pid)
+	}
+
+	requested := hasClaim
+	if value := c.GetHeader(models.ProfileIdHeader); value != "" {
+		headerId, err := strconv.Atoi(value)
+		if err != nil || (hasClaim && headerId != profileId) {
+			abortWithError(c, http.StatusForbidden, models.ErrCodeInvalidProfile)
+			return false
+		}
+		profileId = headerId
+		requested = true
+	}
+
+	if session.ProfileId != nil {
+		if requested && profileId != *session.ProfileId {
+			abortWithError(c, http.StatusForbidden, models.ErrCodeInvalidProfile)
+			return false
+		}
+		profileId = *session.ProfileId
+	} else if !requested {
+		return true
+	}
+
+	profile, err := profilesRepo.FindById(c, profileId)
+	if err != nil || profile.UserId != session.UserId {
+		abortWithError(c, http.StatusForbidden, models.ErrCodeInvalidProfile)
+		return false
 	}
 
-	subject, err := token.Claims.GetSubject()
+	if session.ProfileId == nil {
+		err = sessionsRepo.SelectProfile(c, session.Id, profile.Id)
+		if err != nil {
+			abortWithError(c, http.StatusInternalServerError, models.ErrCodeInternal)
+			return false
+		}
+	}
+
+	c.Set(models.ProfileKey, profile)
+	if c.Query("lang") == "" && c.GetHeader("Accept-Language") == "" {
+		c.Set(localeKey, profile.Language)
+	}
+
+	return true
+}
+
+// setAgeLimit кладёт в контекст лимит возраста аккаунта и выбранного профиля, если он не снят PIN в этой сессии
+func setAgeLimit(c *gin.Context, parentalRepo *repositories.ParentalControlsRepository, userId int, sessionId string) bool {
+	var profileId *int
+	if profile, ok := c.Get(models.ProfileKey); ok {
+		id := profile.(models.Profile).Id
+		profileId = &id
+	}
+
+	limit, err := parentalRepo.AgeLimit(c, userId, profileId, sessionId)
 	if err != nil {
-		c.JSON(http.StatusUnauthorized, models.NewApiError("error while getting subject"))
-		c.Abort()
+		abortWithError(c, http.StatusInternalServerError, models.ErrCodeInternal)
+		return false
+	}
+
+	if limit != nil {
+		c.Set(models.AgeLimitKey, *limit)
+	}
+
+	return true
+}
+
+func authorizeApiKey(c *gin.Context, apiKeysRepo *repositories.ApiKeysRepository, apiKey string) {
+	key, err := apiKeysRepo.FindActiveByHash(c, HashApiKey(apiKey))
+	if err != nil {
+		abortWithError(c, http.StatusUnauthorized, models.ErrCodeInvalidApiKey)
+		return
+	}
+
+	principal := models.ServicePrincipal{
+		ApiKeyId:    key.Id,
+		Name:        key.Name,
+		Permissions: key.Permissions,
+	}
+
+	permission := requiredPermission(c.Request.Method, c.FullPath())
+	if !principal.HasPermission(permission) {
+		abortWithError(c, http.StatusForbidden, models.ErrCodeForbidden)
 		return
 	}
 
-	userId, _ := strconv.Atoi(subject)
-	c.Set("userId", userId)
+	apiKeysRepo.TouchLastUsed(c, key.Id)
+
+	c.Set(models.ServicePrincipalKey, principal)
 	c.Next()
 }
@@ -0,0 +1,110 @@
+package middlewares
+
+import (
+	"goozinshe/config"
+	"goozinshe/models"
+	"net/http"
+	"regexp"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+const (
+	RequestIdHeader = "X-Request-Id"
+
+	requestIdKey = "requestId"
+	localeKey    = "locale"
+)
+
+// присланный клиентом id принимаем, только если он похож на идентификатор
+var requestIdPattern = regexp.MustCompile(`^[A-Za-z0-9._-]{1,64}$`)
+
+// RequestContext выдаёт каждому запросу id (X-Request-Id) и выбирает язык ответа
+func RequestContext() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		requestId := c.GetHeader(RequestIdHeader)
+		if !requestIdPattern.MatchString(requestId) {
+			requestId = uuid.NewString()
+		}
+
+		c.Set(requestIdKey, requestId)
+		c.Set(localeKey, negotiateLocale(c))
+		c.Header(RequestIdHeader, requestId)
+		c.Next()
+	}
+}
+
+func RequestId(c *gin.Context) string {
+	return c.GetString(requestIdKey)
+}
+
+// Locale — язык ответа, выбранный RequestContext
+func Locale(c *gin.Context) string {
+	if locale := c.GetString(localeKey); locale != "" {
+		return locale
+	}
+	return negotiateLocale(c)
+}
+
+// NewApiError — ошибка на языке запроса с его id
+func NewApiError(c *gin.Context, code string, details ...models.ApiErrorDetail) models.ApiError {
+	apiError := models.NewApiError(Locale(c), code, details...)
+	apiError.RequestId = RequestId(c)
+
+	return apiError
+}
+
+func abortWithError(c *gin.Context, status int, code string) {
+	c.AbortWithStatusJSON(status, NewApiError(c, code))
+}
+
+// RecoverWithApiError отвечает на панику обработчика в общем формате ошибок
+func RecoverWithApiError(c *gin.Context, err any) {
+	abortWithError(c, http.StatusInternalServerError, models.ErrCodeInternal)
+}
+
+// negotiateLocale выбирает язык ответа: ?lang= важнее Accept-Language, иначе язык по умолчанию
+func negotiateLocale(c *gin.Context) string {
+	if lang := strings.ToLower(c.Query("lang")); slices.Contains(models.SupportedLocales, lang) {
+		return lang
+	}
+
+	type weighted struct {
+		locale string
+		q      float64
+	}
+
+	candidates := make([]weighted, 0)
+	for _, part := range strings.Split(c.GetHeader("Accept-Language"), ",") {
+		tag, params, _ := strings.Cut(strings.TrimSpace(part), ";")
+		q := 1.0
+		if value, ok := strings.CutPrefix(strings.TrimSpace(params), "q="); ok {
+			parsed, err := strconv.ParseFloat(value, 64)
+			if err != nil {
+				continue
+			}
+			q = parsed
+		}
+
+		// kk-KZ -> kk
+		primary, _, _ := strings.Cut(strings.ToLower(tag), "-")
+		if q > 0 && slices.Contains(models.SupportedLocales, primary) {
+			candidates = append(candidates, weighted{locale: primary, q: q})
+		}
+	}
+
+	sort.SliceStable(candidates, func(i, j int) bool {
+		return candidates[i].q > candidates[j].q
+	})
+
+	if len(candidates) > 0 {
+		return candidates[0].locale
+	}
+
+	return config.Config.DefaultLocale
+}
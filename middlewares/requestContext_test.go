@@ -0,0 +1,52 @@
+package middlewares
+
+import (
+	"goozinshe/config"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestNegotiateLocale(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	previous := config.Config
+	config.Config = &config.MapConfig{DefaultLocale: "kk"}
+	t.Cleanup(func() { config.Config = previous })
+
+	tests := []struct {
+		name           string
+		query          string
+		acceptLanguage string
+		want           string
+	}{
+		{"default without preferences", "", "", "kk"},
+		{"query parameter", "lang=en", "", "en"},
+		{"query parameter is case insensitive", "lang=RU", "", "ru"},
+		{"query parameter wins over header", "lang=en", "ru", "en"},
+		{"unsupported query falls back to header", "lang=de", "ru", "ru"},
+		{"region is dropped", "", "en-US", "en"},
+		{"highest quality wins", "", "en;q=0.5, ru;q=0.9", "ru"},
+		{"missing quality means 1", "", "ru;q=0.9, en", "en"},
+		{"equal quality keeps header order", "", "ru, en", "ru"},
+		{"unsupported locales are skipped", "", "fr-FR, de;q=0.9, en;q=0.1", "en"},
+		{"zero quality means not acceptable", "", "ru;q=0", "kk"},
+		{"invalid quality is skipped", "", "en;q=high, ru;q=0.2", "ru"},
+		{"wildcard is ignored", "", "*", "kk"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, _ := gin.CreateTestContext(httptest.NewRecorder())
+			c.Request = httptest.NewRequest(http.MethodGet, "/?"+tt.query, nil)
+			if tt.acceptLanguage != "" {
+				c.Request.Header.Set("Accept-Language", tt.acceptLanguage)
+			}
+
+			if got := negotiateLocale(c); got != tt.want {
+				t.Errorf("negotiateLocale(lang=%q, Accept-Language=%q) = %q, want %q", tt.query, tt.acceptLanguage, got, tt.want)
+			}
+		})
+	}
+}
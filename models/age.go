@@ -1,7 +1,23 @@
 package models
 
+import (
+	"regexp"
+	"strconv"
+)
+
+// MinAge — минимальный возраст зрителя числом, по нему сравниваются рейтинги ("16+" -> 16)
 type Age struct {
 	Id        int
 	Age       string
+	MinAge    int
 	PosterUrl string
+	Version   int `json:"-"`
+}
+
+var minAgePattern = regexp.MustCompile(`\d+`)
+
+// ParseMinAge достаёт возраст из подписи рейтинга: "18+" -> 18, "0+" и подписи без числа -> 0
+func ParseMinAge(label string) int {
+	minAge, _ := strconv.Atoi(minAgePattern.FindString(label))
+	return minAge
 }
@@ -0,0 +1,49 @@
+package models
+
+import (
+	"regexp"
+	"strings"
+	"time"
+)
+
+const ServicePrincipalKey = "servicePrincipal"
+
+// право ключа: "*" или "<ресурс>:read|write|*", где ресурс — первый сегмент маршрута или admin/<раздел>
+var permissionPattern = regexp.MustCompile(`^(\*|[a-zA-Z]+(/[a-zA-Z]+)?:(read|write|\*))$`)
+
+type ApiKey struct {
+	Id          int
+	Name        string
+	Prefix      string
+	KeyHash     string
+	Permissions []string
+	CreatedBy   *int
+	CreatedAt   time.Time
+	ExpiresAt   *time.Time
+	LastUsedAt  *time.Time
+	RevokedAt   *time.Time
+}
+
+// ServicePrincipal описывает машинного клиента, авторизованного по API ключу
+type ServicePrincipal struct {
+	ApiKeyId    int
+	Name        string
+	Permissions []string
+}
+
+// HasPermission проверяет право вида "movies:read"; "*" и "movies:*" дают полный доступ
+func (p ServicePrincipal) HasPermission(permission string) bool {
+	resource, _, _ := strings.Cut(permission, ":")
+	for _, granted := range p.Permissions {
+		if granted == "*" || granted == permission || granted == resource+":*" {
+			return true
+		}
+	}
+
+	return false
+}
+
+// IsValidPermission проверяет, что право записано в том же виде, в котором его требует маршрут
+func IsValidPermission(permission string) bool {
+	return permissionPattern.MatchString(permission)
+}
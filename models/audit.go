@@ -0,0 +1,110 @@
+package models
+
+import (
+	"encoding/json"
+	"reflect"
+	"time"
+)
+
+const (
+	AuditActionCreate  = "create"
+	AuditActionUpdate  = "update"
+	AuditActionDelete  = "delete"
+	AuditActionRestore = "restore"
+
+	AuditEntityMovie     = "movie"
+	AuditEntityGenre     = "genre"
+	AuditEntityCategory  = "category"
+	AuditEntityAge       = "age"
+	AuditEntityAllSeries = "allseries"
+	AuditEntityUser      = "user"
+	AuditEntityRole      = "role"
+	AuditEntityImport    = "import"
+	AuditEntityPerson    = "person"
+	AuditEntityMedia     = "media"
+	AuditEntitySubtitle  = "subtitle"
+	AuditEntityReview    = "review"
+	AuditEntityBadWord   = "bad_word"
+)
+
+type AuditEvent struct {
+	Id            int64           `json:"id"`
+	ActorUserId   *int            `json:"actorUserId"`
+	ActorApiKeyId *int            `json:"actorApiKeyId"`
+	Action        string          `json:"action"`
+	EntityType    string          `json:"entityType"`
+	EntityId      int             `json:"entityId"`
+	Before        json.RawMessage `json:"before" swaggertype:"object"`
+	After         json.RawMessage `json:"after" swaggertype:"object"`
+	Ip            string          `json:"ip"`
+	CreatedAt     time.Time       `json:"createdAt"`
+}
+
+type AuditFilters struct {
+	ActorUserId *int
+	Action      string
+	EntityType  string
+	EntityId    *int
+	From        *time.Time
+	To          *time.Time
+	Limit       int
+	Offset      int
+}
+
+// AuditDiff готовит before/after для журнала: если есть оба состояния, остаются только изменившиеся поля
+func AuditDiff(before any, after any) (json.RawMessage, json.RawMessage, error) {
+	beforeMap, err := toJsonMap(before)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	afterMap, err := toJsonMap(after)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if beforeMap != nil && afterMap != nil {
+		for key, value := range beforeMap {
+			if reflect.DeepEqual(value, afterMap[key]) {
+				delete(beforeMap, key)
+				delete(afterMap, key)
+			}
+		}
+	}
+
+	beforeJson, err := marshalJsonMap(beforeMap)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	afterJson, err := marshalJsonMap(afterMap)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return beforeJson, afterJson, nil
+}
+
+func toJsonMap(value any) (map[string]any, error) {
+	if value == nil {
+		return nil, nil
+	}
+
+	raw, err := json.Marshal(value)
+	if err != nil {
+		return nil, err
+	}
+
+	var result map[string]any
+	err = json.Unmarshal(raw, &result)
+
+	return result, err
+}
+
+func marshalJsonMap(value map[string]any) (json.RawMessage, error) {
+	if value == nil {
+		return nil, nil
+	}
+
+	return json.Marshal(value)
+}
@@ -4,5 +4,6 @@ type Category struct {
 	Id    int
 	Title string
 	PosterUrl string
-	
+	Version   int `json:"-"`
+
 }
@@ -1,9 +1,287 @@
 package models
 
+// ApiError — тело любого ответа с ошибкой. Code стабилен и не переводится,
+// Message отдаётся на языке запроса (kk/ru/en)
 type ApiError struct {
-	Error string
+	Code      string           `json:"code" example:"not_found"`
+	Message   string           `json:"message" example:"Record not found"`
+	Details   []ApiErrorDetail `json:"details,omitempty"`
+	RequestId string           `json:"requestId,omitempty" example:"5f0c6f0e-3a4b-4d7c-9a43-2a5e0f6b1c2d"`
 }
 
-func NewApiError(msg string) ApiError {
-	return ApiError{msg}
+// ApiErrorDetail — ошибка конкретного поля запроса
+type ApiErrorDetail struct {
+	Field   string `json:"field" example:"releaseYear"`
+	Code    string `json:"code" example:"invalid"`
+	Message string `json:"message" example:"Invalid value"`
+}
+
+// коды ошибок ответа
+const (
+	ErrCodeInvalidId          = "invalid_id"
+	ErrCodeInvalidPayload     = "invalid_payload"
+	ErrCodeValidation         = "validation_failed"
+	ErrCodeNotFound           = "not_found"
+	ErrCodeFileRequired       = "file_required"
+	ErrCodeFileTooLarge       = "file_too_large"
+	ErrCodeUnsupportedMedia   = "unsupported_media_type"
+	ErrCodeIfMatchRequired    = "if_match_required"
+	ErrCodeInvalidIfMatch     = "invalid_if_match"
+	ErrCodeVersionConflict    = "version_conflict"
+	ErrCodeInvalidTransition  = "invalid_status_transition"
+	ErrCodeNotInTrash         = "not_in_trash"
+	ErrCodeUploadOffset       = "upload_offset_mismatch"
+	ErrCodeBusy               = "busy"
+	ErrCodeProfileLimit       = "profile_limit_reached"
+	ErrCodeInvalidProfile     = "invalid_profile"
+	ErrCodeParentalLocked     = "parental_locked"
+	ErrCodeInvalidPin         = "invalid_pin"
+	ErrCodePinBlocked         = "pin_blocked"
+	ErrCodeOwnReview          = "own_review"
+	ErrCodeUnauthorized       = "unauthorized"
+	ErrCodeInvalidToken       = "invalid_token"
+	ErrCodeInvalidCredentials = "invalid_credentials"
+	ErrCodeSessionTerminated  = "session_terminated"
+	ErrCodeInvalidApiKey      = "invalid_api_key"
+	ErrCodeForbidden          = "forbidden"
+	ErrCodeInternal           = "internal_error"
+)
+
+// коды ошибок полей
+const (
+	DetailCodeRequired    = "required"
+	DetailCodeInvalid     = "invalid"
+	DetailCodeNotFound    = "not_found"
+	DetailCodeDuplicate   = "duplicate"
+	DetailCodeNotAllowed  = "not_allowed"
+	DetailCodeTooShort    = "too_short"
+	DetailCodeTooLong     = "too_long"
+	DetailCodeOutOfRange  = "out_of_range"
+	DetailCodeFormat      = "invalid_format"
+	DetailCodeUnsupported = "unsupported"
+	DetailCodeInPast      = "in_past"
+)
+
+var errorMessages = map[string]map[string]string{
+	ErrCodeInvalidId: {
+		LocaleEn: "Invalid id",
+		LocaleRu: "Некорректный идентификатор",
+		LocaleKk: "Идентификатор қате",
+	},
+	ErrCodeInvalidPayload: {
+		LocaleEn: "Invalid request payload",
+		LocaleRu: "Некорректное тело запроса",
+		LocaleKk: "Сұраныс денесі қате",
+	},
+	ErrCodeValidation: {
+		LocaleEn: "Request validation failed",
+		LocaleRu: "Запрос не прошёл проверку",
+		LocaleKk: "Сұраныс тексерістен өтпеді",
+	},
+	ErrCodeNotFound: {
+		LocaleEn: "Record not found",
+		LocaleRu: "Запись не найдена",
+		LocaleKk: "Жазба табылмады",
+	},
+	ErrCodeFileRequired: {
+		LocaleEn: "File is required",
+		LocaleRu: "Нужно приложить файл",
+		LocaleKk: "Файл қажет",
+	},
+	ErrCodeFileTooLarge: {
+		LocaleEn: "File is too large",
+		LocaleRu: "Файл слишком большой",
+		LocaleKk: "Файл тым үлкен",
+	},
+	ErrCodeUnsupportedMedia: {
+		LocaleEn: "Unsupported Content-Type",
+		LocaleRu: "Неподдерживаемый Content-Type",
+		LocaleKk: "Content-Type қолдау көрсетілмейді",
+	},
+	ErrCodeIfMatchRequired: {
+		LocaleEn: "If-Match header required",
+		LocaleRu: "Нужен заголовок If-Match",
+		LocaleKk: "If-Match тақырыбы қажет",
+	},
+	ErrCodeInvalidIfMatch: {
+		LocaleEn: "Invalid If-Match header",
+		LocaleRu: "Некорректный заголовок If-Match",
+		LocaleKk: "If-Match тақырыбы қате",
+	},
+	ErrCodeVersionConflict: {
+		LocaleEn: "Resource was modified by someone else, reload it and retry",
+		LocaleRu: "Запись уже изменил кто-то другой, обновите её и повторите",
+		LocaleKk: "Жазбаны басқа біреу өзгертті, оны жаңартып, қайталаңыз",
+	},
+	ErrCodeInvalidTransition: {
+		LocaleEn: "Status transition is not allowed",
+		LocaleRu: "Такой переход статуса запрещён",
+		LocaleKk: "Мұндай күй ауысуына рұқсат жоқ",
+	},
+	ErrCodeNotInTrash: {
+		LocaleEn: "Record is not in trash",
+		LocaleRu: "Запись не в корзине",
+		LocaleKk: "Жазба себетте жоқ",
+	},
+	ErrCodeUploadOffset: {
+		LocaleEn: "Upload offset does not match uploaded bytes",
+		LocaleRu: "Смещение не совпадает с уже загруженными байтами",
+		LocaleKk: "Ығысу жүктелген байттармен сәйкес келмейді",
+	},
+	ErrCodeBusy: {
+		LocaleEn: "Operation is already running, try again later",
+		LocaleRu: "Операция уже выполняется, повторите позже",
+		LocaleKk: "Операция орындалып жатыр, кейінірек қайталаңыз",
+	},
+	ErrCodeProfileLimit: {
+		LocaleEn: "Profile limit reached",
+		LocaleRu: "Достигнуто максимальное число профилей",
+		LocaleKk: "Профильдердің ең көп саны жетті",
+	},
+	ErrCodeInvalidProfile: {
+		LocaleEn: "Profile does not belong to the user",
+		LocaleRu: "Профиль не принадлежит пользователю",
+		LocaleKk: "Профиль пайдаланушыға тиесілі емес",
+	},
+	ErrCodeParentalLocked: {
+		LocaleEn: "Enter the parental control PIN first",
+		LocaleRu: "Сначала введите PIN родительского контроля",
+		LocaleKk: "Алдымен ата-ана бақылауының PIN кодын енгізіңіз",
+	},
+	ErrCodeInvalidPin: {
+		LocaleEn: "Invalid PIN",
+		LocaleRu: "Неверный PIN",
+		LocaleKk: "PIN коды қате",
+	},
+	ErrCodePinBlocked: {
+		LocaleEn: "Too many wrong PIN attempts, try again later",
+		LocaleRu: "Слишком много неверных попыток PIN, повторите позже",
+		LocaleKk: "PIN коды тым көп рет қате енгізілді, кейінірек қайталаңыз",
+	},
+	ErrCodeOwnReview: {
+		LocaleEn: "You cannot vote for or report your own review",
+		LocaleRu: "Нельзя голосовать за свой отзыв или жаловаться на него",
+		LocaleKk: "Өз пікіріңізге дауыс беруге немесе шағымдануға болмайды",
+	},
+	ErrCodeUnauthorized: {
+		LocaleEn: "Authorization required",
+		LocaleRu: "Требуется авторизация",
+		LocaleKk: "Авторизация қажет",
+	},
+	ErrCodeInvalidToken: {
+		LocaleEn: "Invalid token",
+		LocaleRu: "Некорректный токен",
+		LocaleKk: "Токен қате",
+	},
+	ErrCodeInvalidCredentials: {
+		LocaleEn: "Invalid email or password",
+		LocaleRu: "Неверный email или пароль",
+		LocaleKk: "Email немесе құпиясөз қате",
+	},
+	ErrCodeSessionTerminated: {
+		LocaleEn: "Session terminated",
+		LocaleRu: "Сессия завершена",
+		LocaleKk: "Сессия аяқталды",
+	},
+	ErrCodeInvalidApiKey: {
+		LocaleEn: "Invalid api key",
+		LocaleRu: "Некорректный API-ключ",
+		LocaleKk: "API кілті қате",
+	},
+	ErrCodeForbidden: {
+		LocaleEn: "Not enough permissions",
+		LocaleRu: "Недостаточно прав",
+		LocaleKk: "Құқық жеткіліксіз",
+	},
+	ErrCodeInternal: {
+		LocaleEn: "Internal server error",
+		LocaleRu: "Внутренняя ошибка сервера",
+		LocaleKk: "Сервердің ішкі қатесі",
+	},
+}
+
+var detailMessages = map[string]map[string]string{
+	DetailCodeRequired: {
+		LocaleEn: "Value is required",
+		LocaleRu: "Обязательное поле",
+		LocaleKk: "Міндетті өріс",
+	},
+	DetailCodeInvalid: {
+		LocaleEn: "Invalid value",
+		LocaleRu: "Некорректное значение",
+		LocaleKk: "Мәні қате",
+	},
+	DetailCodeNotFound: {
+		LocaleEn: "Referenced record not found",
+		LocaleRu: "Связанная запись не найдена",
+		LocaleKk: "Байланысты жазба табылмады",
+	},
+	DetailCodeDuplicate: {
+		LocaleEn: "Duplicate value",
+		LocaleRu: "Значение повторяется",
+		LocaleKk: "Мән қайталанады",
+	},
+	DetailCodeNotAllowed: {
+		LocaleEn: "Value is not allowed here",
+		LocaleRu: "Здесь это значение недопустимо",
+		LocaleKk: "Бұл жерде мұндай мәнге рұқсат жоқ",
+	},
+	DetailCodeTooShort: {
+		LocaleEn: "Value is too short",
+		LocaleRu: "Слишком короткое значение",
+		LocaleKk: "Мәні тым қысқа",
+	},
+	DetailCodeTooLong: {
+		LocaleEn: "Value is too long",
+		LocaleRu: "Слишком длинное значение",
+		LocaleKk: "Мәні тым ұзын",
+	},
+	DetailCodeOutOfRange: {
+		LocaleEn: "Value is out of range",
+		LocaleRu: "Значение вне допустимого диапазона",
+		LocaleKk: "Мәні рұқсат етілген аралықтан тыс",
+	},
+	DetailCodeFormat: {
+		LocaleEn: "Invalid format",
+		LocaleRu: "Неверный формат",
+		LocaleKk: "Пішімі қате",
+	},
+	DetailCodeUnsupported: {
+		LocaleEn: "Unsupported value",
+		LocaleRu: "Неподдерживаемое значение",
+		LocaleKk: "Мәнге қолдау көрсетілмейді",
+	},
+	DetailCodeInPast: {
+		LocaleEn: "Date must be in the future",
+		LocaleRu: "Дата должна быть в будущем",
+		LocaleKk: "Күні болашақта болуы керек",
+	},
+}
+
+// NewApiError собирает ошибку на нужном языке; незаполненные сообщения полей берутся из каталога
+func NewApiError(locale string, code string, details ...ApiErrorDetail) ApiError {
+	apiError := ApiError{Code: code, Message: localizeMessage(errorMessages, code, locale)}
+	for _, detail := range details {
+		if detail.Message == "" {
+			detail.Message = localizeMessage(detailMessages, detail.Code, locale)
+		}
+		apiError.Details = append(apiError.Details, detail)
+	}
+
+	return apiError
+}
+
+func NewApiErrorDetail(field string, code string) ApiErrorDetail {
+	return ApiErrorDetail{Field: field, Code: code}
+}
+
+func localizeMessage(catalog map[string]map[string]string, code string, locale string) string {
+	messages, ok := catalog[code]
+	if !ok {
+		return code
+	}
+	if message, ok := messages[locale]; ok {
+		return message
+	}
+	return messages[LocaleEn]
 }
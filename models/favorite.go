@@ -0,0 +1,13 @@
+package models
+
+import "time"
+
+// FavoriteItem — фильм в избранном аккаунта или профиля
+type FavoriteItem struct {
+	MovieId       int       `json:"movieId"`
+	Title         string    `json:"title"`
+	PosterUrl     string    `json:"posterUrl"`
+	ReleaseYear   int       `json:"releaseYear"`
+	FavoriteCount int       `json:"favoriteCount"`
+	AddedAt       time.Time `json:"addedAt"`
+}
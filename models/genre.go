@@ -4,4 +4,5 @@ type Genre struct {
 	Id    int
 	Title string
 	PosterUrl   string
+	Version     int `json:"-"`
 }
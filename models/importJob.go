@@ -0,0 +1,36 @@
+package models
+
+import "time"
+
+const (
+	ImportStatusPending   = "pending"
+	ImportStatusRunning   = "running"
+	ImportStatusCompleted = "completed"
+	ImportStatusFailed    = "failed"
+
+	ImportFormatCsv    = "csv"
+	ImportFormatNdjson = "ndjson"
+)
+
+type ImportJob struct {
+	Id          int              `json:"id"`
+	Status      string           `json:"status"`
+	Format      string           `json:"format"`
+	TotalRows   int              `json:"totalRows"`
+	CreatedRows int              `json:"createdRows"`
+	UpdatedRows int              `json:"updatedRows"`
+	FailedRows  int              `json:"failedRows"`
+	Error       *string          `json:"error"`
+	RowErrors   []ImportRowError `json:"-"`
+	CreatedBy   *int             `json:"createdBy"`
+	CreatedAt   time.Time        `json:"createdAt"`
+	StartedAt   *time.Time       `json:"startedAt"`
+	FinishedAt  *time.Time       `json:"finishedAt"`
+}
+
+// ImportRowError — строка отчёта об ошибках импорта
+type ImportRowError struct {
+	Row         int    `json:"row"`
+	ExternalKey string `json:"externalKey"`
+	Message     string `json:"message"`
+}
@@ -0,0 +1,28 @@
+package models
+
+const (
+	LocaleKk = "kk"
+	LocaleRu = "ru"
+	LocaleEn = "en"
+)
+
+var SupportedLocales = []string{LocaleKk, LocaleRu, LocaleEn}
+
+// TranslatableFields — переводимые поля по типу записи; значение на языке по умолчанию хранится в самой таблице
+var TranslatableFields = map[string][]string{
+	AuditEntityMovie:    {"title", "description"},
+	AuditEntityGenre:    {"title"},
+	AuditEntityCategory: {"title"},
+	AuditEntityAge:      {"age"},
+}
+
+type Translation struct {
+	Locale string `json:"locale"`
+	Field  string `json:"field"`
+	Value  string `json:"value"`
+}
+
+type TranslationKey struct {
+	EntityType string
+	EntityId   int
+}
@@ -0,0 +1,31 @@
+package models
+
+import "time"
+
+// статусы видео: загрузка по частям -> очередь -> нарезка HLS -> готово или ошибка
+const (
+	MediaStatusUploading  = "uploading"
+	MediaStatusQueued     = "queued"
+	MediaStatusProcessing = "processing"
+	MediaStatusReady      = "ready"
+	MediaStatusFailed     = "failed"
+)
+
+// MediaAsset — видео фильма или серии (AllSeriesId задан) и состояние его обработки.
+// PlaybackUrl заполняется, когда HLS готов
+type MediaAsset struct {
+	Id            int        `json:"id"`
+	MovieId       int        `json:"movieId"`
+	AllSeriesId   *int       `json:"allSeriesId"`
+	Status        string     `json:"status"`
+	Filename      string     `json:"filename"`
+	Size          int64      `json:"size"`
+	UploadedBytes int64      `json:"uploadedBytes"`
+	Renditions    []string   `json:"renditions"`
+	PlaybackUrl   *string    `json:"playbackUrl"`
+	Error         *string    `json:"error"`
+	CreatedBy     *int       `json:"createdBy"`
+	CreatedAt     time.Time  `json:"createdAt"`
+	UpdatedAt     time.Time  `json:"updatedAt"`
+	ReadyAt       *time.Time `json:"readyAt"`
+}
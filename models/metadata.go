@@ -0,0 +1,36 @@
+package models
+
+import "time"
+
+const (
+	MetadataSourceImdb = "imdb"
+	MetadataSourceTmdb = "tmdb"
+)
+
+// MetadataTitle — запись локального индекса, построенного из дампов IMDb или TMDb
+type MetadataTitle struct {
+	Source        string   `json:"source"`
+	ExternalId    string   `json:"externalId"`
+	TitleType     string   `json:"titleType"`
+	Title         string   `json:"title"`
+	OriginalTitle string   `json:"originalTitle"`
+	Year          *int     `json:"year"`
+	Directors     []string `json:"directors"`
+	Genres        []string `json:"genres"`
+	Description   string   `json:"description"`
+}
+
+// MetadataGenreMapping сопоставляет жанр из дампа жанру из нашей таблицы genres
+type MetadataGenreMapping struct {
+	SourceGenre string `json:"sourceGenre"`
+	GenreId     int    `json:"genreId"`
+}
+
+type MetadataIndexStatus struct {
+	Running    bool       `json:"running"`
+	Source     string     `json:"source"`
+	StartedAt  *time.Time `json:"startedAt"`
+	FinishedAt *time.Time `json:"finishedAt"`
+	Rows       int64      `json:"rows"`
+	Error      *string    `json:"error"`
+}
@@ -1,10 +1,35 @@
 package models
 
+import "time"
+
+// статусы публикации фильма
+const (
+	MovieStatusDraft     = "draft"
+	MovieStatusInReview  = "in_review"
+	MovieStatusPublished = "published"
+	MovieStatusArchived  = "archived"
+)
+
+// MovieStatusTransitions — в какие статусы можно перевести фильм из текущего
+var MovieStatusTransitions = map[string][]string{
+	MovieStatusDraft:     {MovieStatusInReview, MovieStatusPublished, MovieStatusArchived},
+	MovieStatusInReview:  {MovieStatusDraft, MovieStatusPublished, MovieStatusArchived},
+	MovieStatusPublished: {MovieStatusDraft, MovieStatusArchived},
+	MovieStatusArchived:  {MovieStatusDraft, MovieStatusPublished},
+}
+
 type MovieFilters struct {
 	SearchTerm string
 	GenreId    string
 	IsWatched  string
+	Status     string
+	PersonId   *int
 	Sort       string
+	// лимит родительского контроля; nil — без ограничений
+	MaxAge *int
+	// зритель, для которого отмечаются IsFavorite и IsWatched
+	UserId    *int
+	ProfileId *int
 }
 
 type Movie struct {
@@ -16,11 +41,21 @@ type Movie struct {
 	Rating      int         `form:"rating"`
 	IsWatched   bool        `form:"is_watched"`
 	TrailerUrl  string      `form:"trailer_url"`
+	Trailer     *Trailer    `form:"-"`
 	PosterUrl   string      `form:"poster_url"`
+	Status      string      `form:"status"`
+	PublishAt   *time.Time  `form:"publish_at"`
+	Version     int         `json:"-"`
 	Genres      []Genre     `form:"genres"`
 	Category    []Category  `form:"categories"`
 	Ages        []Age       `form:"ages"`
 	AllSeries   []AllSeries `form:"allseries"`
+	Credits     []MovieCredit
+	Media       []MediaAsset    `form:"-"`
+	Subtitles   []SubtitleTrack `form:"-"`
+	// IsFavorite — фильм в избранном текущего зрителя, FavoriteCount — у скольких зрителей он в избранном
+	IsFavorite    bool `form:"-"`
+	FavoriteCount int  `form:"-"`
 }
 
 type MovieAdminResponse struct {
@@ -32,9 +67,19 @@ type MovieAdminResponse struct {
 	Rating      int         `form:"rating"`
 	IsWatched   bool        `form:"is_watched"`
 	TrailerUrl  string      `form:"trailer_url"`
+	Trailer     *Trailer    `form:"-"`
 	PosterUrl   string      `form:"poster_url"`
+	Status      string      `form:"status"`
+	PublishAt   *time.Time  `form:"publish_at"`
+	Version     int         `json:"-"`
 	Genres      []Genre     `form:"genres"`
 	Category    []Category  `form:"categories"`
 	Ages        []Age       `form:"ages"`
 	AllSeries   []AllSeries `form:"allseries"`
+	Credits     []MovieCredit
+	Media       []MediaAsset    `form:"-"`
+	Subtitles   []SubtitleTrack `form:"-"`
+	// в админке не заполняются; поля нужны, чтобы структура приводилась к Movie
+	IsFavorite    bool `form:"-" json:"-"`
+	FavoriteCount int  `form:"-" json:"-"`
 }
@@ -0,0 +1,13 @@
+package models
+
+import "time"
+
+// MovieRevision — снимок фильма (вместе со связями на жанры, категории и возрасты) до очередного изменения
+type MovieRevision struct {
+	Id        int       `json:"id"`
+	MovieId   int       `json:"movieId"`
+	Revision  int       `json:"revision"`
+	Snapshot  Movie     `json:"snapshot"`
+	CreatedBy *int      `json:"createdBy"`
+	CreatedAt time.Time `json:"createdAt"`
+}
@@ -0,0 +1,33 @@
+package models
+
+import "time"
+
+const (
+	// AgeLimitKey — ключ действующего возрастного лимита в контексте запроса
+	AgeLimitKey = "ageLimit"
+
+	// после MaxParentalPinFailures неверных PIN подряд ввод блокируется на ParentalPinBlock
+	MaxParentalPinFailures = 5
+	ParentalPinBlock       = 15 * time.Minute
+)
+
+// ParentalControls — родительский контроль аккаунта. MaxAgeId ограничивает все профили аккаунта и запросы без профиля;
+// PIN снимает ограничение в текущей сессии до UnlockedUntil
+type ParentalControls struct {
+	MaxAgeId      *int       `json:"maxAgeId"`
+	HasPin        bool       `json:"hasPin"`
+	UnlockedUntil *time.Time `json:"unlockedUntil"`
+	PinHash       *string    `json:"-"`
+	PinFailures   int        `json:"-"`
+	BlockedUntil  *time.Time `json:"blockedUntil"`
+}
+
+// IsUnlocked — ограничение снято PIN в этой сессии
+func (p ParentalControls) IsUnlocked() bool {
+	return p.UnlockedUntil != nil && p.UnlockedUntil.After(time.Now())
+}
+
+// IsBlocked — PIN слишком много раз ввели неверно
+func (p ParentalControls) IsBlocked() bool {
+	return p.BlockedUntil != nil && p.BlockedUntil.After(time.Now())
+}
@@ -0,0 +1,56 @@
+package models
+
+import "time"
+
+// роли человека в фильме или серии
+const (
+	PersonRoleDirector   = "director"
+	PersonRoleActor      = "actor"
+	PersonRoleWriter     = "writer"
+	PersonRoleVoiceActor = "voice_actor"
+)
+
+var PersonRoles = []string{PersonRoleDirector, PersonRoleActor, PersonRoleWriter, PersonRoleVoiceActor}
+
+type Person struct {
+	Id           int
+	Name         string
+	OriginalName string
+	Bio          string
+	BirthDate    *time.Time
+	PhotoUrl     string
+	Version      int `json:"-"`
+}
+
+type PersonFilters struct {
+	SearchTerm string
+	Role       string
+}
+
+// MovieCredit — участие человека в фильме; AllSeriesId задан, если только в конкретной серии
+type MovieCredit struct {
+	PersonId    int
+	Name        string
+	PhotoUrl    string
+	Role        string
+	Character   string
+	Order       int
+	AllSeriesId *int
+}
+
+type FilmographyItem struct {
+	MovieId      int
+	Title        string
+	ReleaseYear  int
+	PosterUrl    string
+	AllSeriesId  *int
+	EpisodeTitle *string
+	Role         string
+	Character    string
+	Order        int
+}
+
+type PersonDetails struct {
+	Person
+	Filmography []FilmographyItem
+}
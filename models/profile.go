@@ -0,0 +1,29 @@
+package models
+
+import "time"
+
+const (
+	// ProfileIdHeader — выбор профиля на запрос для токена без claim pid; при pid должен с ним совпадать
+	ProfileIdHeader = "X-Profile-Id"
+	// ProfileIdClaim — профиль, выбранный при выпуске токена
+	ProfileIdClaim = "pid"
+	// ProfileKey — ключ выбранного профиля в контексте запроса
+	ProfileKey = "profile"
+
+	MaxProfilesPerUser = 5
+)
+
+// Profile — профиль зрителя внутри одного аккаунта. Просмотры и прогресс ведутся по профилю.
+// MaxAgeId — самый высокий возрастной рейтинг, который можно смотреть; nil — без ограничений
+type Profile struct {
+	Id        int       `json:"id"`
+	UserId    int       `json:"userId"`
+	Name      string    `json:"name"`
+	AvatarUrl *string   `json:"avatarUrl"`
+	Language  string    `json:"language"`
+	MaxAgeId  *int      `json:"maxAgeId"`
+	IsKids    bool      `json:"isKids"`
+	Version   int       `json:"-"`
+	CreatedAt time.Time `json:"createdAt"`
+	UpdatedAt time.Time `json:"updatedAt"`
+}
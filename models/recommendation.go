@@ -0,0 +1,17 @@
+package models
+
+// RecommendedMovie — фильм в похожих или в рекомендациях; Score — вес совпадения, больше — ближе
+type RecommendedMovie struct {
+	MovieId     int     `json:"movieId"`
+	Title       string  `json:"title"`
+	PosterUrl   string  `json:"posterUrl"`
+	ReleaseYear int     `json:"releaseYear"`
+	Rating      int     `json:"rating"`
+	Score       float64 `json:"score"`
+}
+
+// Viewer — аккаунт или его профиль, для которого считаются рекомендации
+type Viewer struct {
+	UserId    int
+	ProfileId *int
+}
@@ -0,0 +1,73 @@
+package models
+
+import "time"
+
+const (
+	ReviewStatusPending  = "pending"
+	ReviewStatusApproved = "approved"
+	ReviewStatusRejected = "rejected"
+
+	ReviewSortNewest  = "newest"
+	ReviewSortHelpful = "helpful"
+
+	// одобренный отзыв с таким числом нерассмотренных жалоб возвращается в очередь модерации
+	ReviewReportsToRequeue = 3
+)
+
+// Review — отзыв на фильм или серию (AllSeriesId задан), ответ в ветке — с ParentId.
+// Удалённый отзыв, на который есть ответы, остаётся в ветке с пустым текстом и IsDeleted = true
+type Review struct {
+	Id             int       `json:"id"`
+	MovieId        int       `json:"movieId"`
+	AllSeriesId    *int      `json:"allSeriesId"`
+	ParentId       *int      `json:"parentId"`
+	UserId         int       `json:"-"`
+	ProfileId      *int      `json:"-"`
+	AuthorName     string    `json:"authorName"`
+	IsOwn          bool      `json:"isOwn"`
+	Body           string    `json:"body"`
+	Status         string    `json:"status"`
+	FlaggedWords   []string  `json:"flaggedWords,omitempty"`
+	ModerationNote *string   `json:"moderationNote,omitempty"`
+	HelpfulCount   int       `json:"helpfulCount"`
+	ReplyCount     int       `json:"replyCount"`
+	ReportCount    int       `json:"reportCount,omitempty"`
+	IsDeleted      bool      `json:"isDeleted"`
+	Version        int       `json:"-"`
+	CreatedAt      time.Time `json:"createdAt"`
+	UpdatedAt      time.Time `json:"updatedAt"`
+}
+
+type ReviewFilters struct {
+	MovieId     int
+	AllSeriesId *int
+	Sort        string
+	Limit       int
+	Offset      int
+}
+
+// ModerationFilters — очередь модерации. Reported — только отзывы с нерассмотренными жалобами
+type ModerationFilters struct {
+	Status   string
+	Reported bool
+	Limit    int
+	Offset   int
+}
+
+type ReviewReport struct {
+	Id         int        `json:"id"`
+	ReviewId   int        `json:"reviewId"`
+	UserId     int        `json:"userId"`
+	Reason     string     `json:"reason"`
+	Comment    *string    `json:"comment"`
+	CreatedAt  time.Time  `json:"createdAt"`
+	ResolvedAt *time.Time `json:"resolvedAt"`
+}
+
+// BadWord — слово фильтра отзывов. Со * в конце ловит все слова с этим началом
+type BadWord struct {
+	Id        int       `json:"id"`
+	Language  string    `json:"language"`
+	Word      string    `json:"word"`
+	CreatedAt time.Time `json:"createdAt"`
+}
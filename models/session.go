@@ -0,0 +1,16 @@
+package models
+
+import "time"
+
+type Session struct {
+	Id           string
+	UserId       int
+	UserAgent    string
+	Ip           string
+	CreatedAt    time.Time
+	LastSeenAt   time.Time
+	ExpiresAt    time.Time
+	TerminatedAt *time.Time
+	// ProfileId — профиль, выбранный в сессии; nil — профиль ещё не выбирали
+	ProfileId *int
+}
@@ -0,0 +1,28 @@
+package models
+
+import "time"
+
+type SigningKey struct {
+	Kid        string
+	Algorithm  string
+	PrivateKey string
+	PublicKey  string
+	CreatedAt  time.Time
+	RetiredAt  *time.Time
+}
+
+// JsonWebKey — публичный ключ в формате RFC 7517 для /.well-known/jwks.json
+type JsonWebKey struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Use string `json:"use"`
+	Alg string `json:"alg"`
+	Crv string `json:"crv,omitempty"`
+	X   string `json:"x,omitempty"`
+	N   string `json:"n,omitempty"`
+	E   string `json:"e,omitempty"`
+}
+
+type JsonWebKeySet struct {
+	Keys []JsonWebKey `json:"keys"`
+}
@@ -0,0 +1,27 @@
+package models
+
+import (
+	"fmt"
+	"time"
+)
+
+// SubtitleTrack — дорожка субтитров фильма или серии (AllSeriesId задан).
+// Файл хранится в WebVTT без сдвига, OffsetMs применяется при отдаче по Url
+type SubtitleTrack struct {
+	Id          int       `json:"id"`
+	MovieId     int       `json:"movieId"`
+	AllSeriesId *int      `json:"allSeriesId"`
+	Language    string    `json:"language"`
+	Label       string    `json:"label"`
+	IsDefault   bool      `json:"isDefault"`
+	OffsetMs    int       `json:"offsetMs"`
+	Url         string    `json:"url"`
+	Version     int       `json:"-"`
+	CreatedAt   time.Time `json:"createdAt"`
+	UpdatedAt   time.Time `json:"updatedAt"`
+}
+
+// SubtitleTrackUrl — постоянный адрес файла дорожки, не меняется при сдвиге времени
+func SubtitleTrackUrl(id int) string {
+	return fmt.Sprintf("/subtitles/%d.vtt", id)
+}
@@ -0,0 +1,159 @@
+package models
+
+import (
+	"errors"
+	"net/url"
+	"path"
+	"regexp"
+	"strings"
+)
+
+// источники трейлеров
+const (
+	TrailerProviderYoutube = "youtube"
+	TrailerProviderVimeo   = "vimeo"
+	TrailerProviderMp4     = "mp4"
+	TrailerProviderHls     = "hls"
+)
+
+var (
+	ErrTrailerUrlInvalid     = errors.New("trailer url is not a valid http(s) url")
+	ErrTrailerUrlUnsupported = errors.New("trailer url provider is not supported")
+)
+
+var (
+	youtubeIdPattern = regexp.MustCompile(`^[A-Za-z0-9_-]{11}$`)
+	vimeoIdPattern   = regexp.MustCompile(`^[0-9]+$`)
+	vimeoHashPattern = regexp.MustCompile(`^[0-9a-f]+$`)
+)
+
+// Trailer — разобранная ссылка на трейлер. WatchUrl — каноническая ссылка, она же хранится в базе,
+// EmbedUrl — ссылка для плеера на странице. У прямых ссылок VideoId пустой
+type Trailer struct {
+	Provider string
+	VideoId  string
+	WatchUrl string
+	EmbedUrl string
+}
+
+// ParseTrailerUrl разбирает ссылку YouTube, Vimeo или прямую ссылку на MP4/HLS.
+// Трекинговые параметры (si, pp, ab_channel и т.п.) отбрасываются
+func ParseTrailerUrl(raw string) (Trailer, error) {
+	u, err := url.Parse(strings.TrimSpace(raw))
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return Trailer{}, ErrTrailerUrlInvalid
+	}
+
+	host := strings.TrimPrefix(strings.ToLower(u.Hostname()), "www.")
+	segments := strings.Split(strings.Trim(u.Path, "/"), "/")
+
+	switch host {
+	case "youtube.com", "m.youtube.com", "music.youtube.com", "youtube-nocookie.com":
+		id := u.Query().Get("v")
+		// /embed/ID, /shorts/ID, /live/ID, /v/ID
+		if len(segments) == 2 && (segments[0] == "embed" || segments[0] == "shorts" || segments[0] == "live" || segments[0] == "v") {
+			id = segments[1]
+		}
+		return youtubeTrailer(id)
+	case "youtu.be":
+		return youtubeTrailer(segments[0])
+	case "vimeo.com", "player.vimeo.com":
+		return vimeoTrailer(host, segments, u.Query().Get("h"))
+	}
+
+	switch strings.ToLower(path.Ext(u.Path)) {
+	case ".mp4":
+		return directTrailer(TrailerProviderMp4, u), nil
+	case ".m3u8":
+		return directTrailer(TrailerProviderHls, u), nil
+	}
+
+	return Trailer{}, ErrTrailerUrlUnsupported
+}
+
+// TrailerFromUrl — трейлер для ответа; для пустой или неразборчивой ссылки nil
+func TrailerFromUrl(raw string) *Trailer {
+	if raw == "" {
+		return nil
+	}
+
+	trailer, err := ParseTrailerUrl(raw)
+	if err != nil {
+		return nil
+	}
+
+	return &trailer
+}
+
+// NormalizeTrailerUrl возвращает каноническую ссылку; то, что не разбирается, остаётся как есть
+func NormalizeTrailerUrl(raw string) string {
+	trailer, err := ParseTrailerUrl(raw)
+	if err != nil {
+		return raw
+	}
+
+	return trailer.WatchUrl
+}
+
+func youtubeTrailer(id string) (Trailer, error) {
+	if !youtubeIdPattern.MatchString(id) {
+		return Trailer{}, ErrTrailerUrlUnsupported
+	}
+
+	return Trailer{
+		Provider: TrailerProviderYoutube,
+		VideoId:  id,
+		WatchUrl: "https://www.youtube.com/watch?v=" + id,
+		EmbedUrl: "https://www.youtube.com/embed/" + id,
+	}, nil
+}
+
+// vimeoTrailer понимает vimeo.com/ID, vimeo.com/ID/HASH (скрытые видео), vimeo.com/channels/x/ID
+// и player.vimeo.com/video/ID?h=HASH
+func vimeoTrailer(host string, segments []string, hash string) (Trailer, error) {
+	id := ""
+	if host == "player.vimeo.com" {
+		if len(segments) == 2 && segments[0] == "video" {
+			id = segments[1]
+		}
+	} else {
+		for i, segment := range segments {
+			if vimeoIdPattern.MatchString(segment) {
+				id = segment
+				if hash == "" && i+1 < len(segments) {
+					hash = segments[i+1]
+				}
+				break
+			}
+		}
+	}
+
+	if !vimeoIdPattern.MatchString(id) {
+		return Trailer{}, ErrTrailerUrlUnsupported
+	}
+
+	trailer := Trailer{
+		Provider: TrailerProviderVimeo,
+		VideoId:  id,
+		WatchUrl: "https://vimeo.com/" + id,
+		EmbedUrl: "https://player.vimeo.com/video/" + id,
+	}
+	if vimeoHashPattern.MatchString(hash) {
+		trailer.WatchUrl += "/" + hash
+		trailer.EmbedUrl += "?h=" + hash
+	}
+
+	return trailer, nil
+}
+
+// у прямых ссылок query оставляем — в нём бывает подпись CDN
+func directTrailer(provider string, u *url.URL) Trailer {
+	u.Fragment = ""
+	u.Host = strings.ToLower(u.Host)
+
+	return Trailer{
+		Provider: provider,
+		WatchUrl: u.String(),
+		EmbedUrl: u.String(),
+	}
+}
@@ -0,0 +1,113 @@
+package models
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestParseTrailerUrl(t *testing.T) {
+	tests := []struct {
+		name    string
+		raw     string
+		want    Trailer
+		wantErr error
+	}{
+		{
+			name: "youtube watch with tracking params",
+			raw:  "https://www.youtube.com/watch?v=dQw4w9WgXcQ&ab_channel=Rick&si=abc",
+			want: Trailer{Provider: TrailerProviderYoutube, VideoId: "dQw4w9WgXcQ", WatchUrl: "https://www.youtube.com/watch?v=dQw4w9WgXcQ", EmbedUrl: "https://www.youtube.com/embed/dQw4w9WgXcQ"},
+		},
+		{
+			name: "youtube short link",
+			raw:  "https://youtu.be/dQw4w9WgXcQ?si=xyz",
+			want: Trailer{Provider: TrailerProviderYoutube, VideoId: "dQw4w9WgXcQ", WatchUrl: "https://www.youtube.com/watch?v=dQw4w9WgXcQ", EmbedUrl: "https://www.youtube.com/embed/dQw4w9WgXcQ"},
+		},
+		{
+			name: "youtube shorts on mobile host",
+			raw:  "https://m.youtube.com/shorts/dQw4w9WgXcQ",
+			want: Trailer{Provider: TrailerProviderYoutube, VideoId: "dQw4w9WgXcQ", WatchUrl: "https://www.youtube.com/watch?v=dQw4w9WgXcQ", EmbedUrl: "https://www.youtube.com/embed/dQw4w9WgXcQ"},
+		},
+		{
+			name: "youtube embed with spaces and upper case host",
+			raw:  "  http://WWW.YouTube.com/embed/dQw4w9WgXcQ  ",
+			want: Trailer{Provider: TrailerProviderYoutube, VideoId: "dQw4w9WgXcQ", WatchUrl: "https://www.youtube.com/watch?v=dQw4w9WgXcQ", EmbedUrl: "https://www.youtube.com/embed/dQw4w9WgXcQ"},
+		},
+		{
+			name:    "youtube without id",
+			raw:     "https://www.youtube.com/watch",
+			wantErr: ErrTrailerUrlUnsupported,
+		},
+		{
+			name:    "youtube with malformed id",
+			raw:     "https://youtu.be/short",
+			wantErr: ErrTrailerUrlUnsupported,
+		},
+		{
+			name: "vimeo",
+			raw:  "https://vimeo.com/76979871",
+			want: Trailer{Provider: TrailerProviderVimeo, VideoId: "76979871", WatchUrl: "https://vimeo.com/76979871", EmbedUrl: "https://player.vimeo.com/video/76979871"},
+		},
+		{
+			name: "vimeo unlisted with hash",
+			raw:  "https://vimeo.com/76979871/8272103f6e",
+			want: Trailer{Provider: TrailerProviderVimeo, VideoId: "76979871", WatchUrl: "https://vimeo.com/76979871/8272103f6e", EmbedUrl: "https://player.vimeo.com/video/76979871?h=8272103f6e"},
+		},
+		{
+			name: "vimeo channel",
+			raw:  "https://vimeo.com/channels/staffpicks/76979871",
+			want: Trailer{Provider: TrailerProviderVimeo, VideoId: "76979871", WatchUrl: "https://vimeo.com/76979871", EmbedUrl: "https://player.vimeo.com/video/76979871"},
+		},
+		{
+			name: "vimeo player with hash",
+			raw:  "https://player.vimeo.com/video/76979871?h=8272103f6e&autoplay=1",
+			want: Trailer{Provider: TrailerProviderVimeo, VideoId: "76979871", WatchUrl: "https://vimeo.com/76979871/8272103f6e", EmbedUrl: "https://player.vimeo.com/video/76979871?h=8272103f6e"},
+		},
+		{
+			name:    "vimeo without id",
+			raw:     "https://vimeo.com/about",
+			wantErr: ErrTrailerUrlUnsupported,
+		},
+		{
+			name: "direct mp4 keeps signature",
+			raw:  "https://CDN.example.com/trailers/movie.MP4?token=abc#t=10",
+			want: Trailer{Provider: TrailerProviderMp4, WatchUrl: "https://cdn.example.com/trailers/movie.MP4?token=abc", EmbedUrl: "https://cdn.example.com/trailers/movie.MP4?token=abc"},
+		},
+		{
+			name: "direct hls",
+			raw:  "https://cdn.example.com/trailers/master.m3u8",
+			want: Trailer{Provider: TrailerProviderHls, WatchUrl: "https://cdn.example.com/trailers/master.m3u8", EmbedUrl: "https://cdn.example.com/trailers/master.m3u8"},
+		},
+		{
+			name:    "unknown provider",
+			raw:     "https://example.com/trailer",
+			wantErr: ErrTrailerUrlUnsupported,
+		},
+		{
+			name:    "not a url",
+			raw:     "trailer.mp4",
+			wantErr: ErrTrailerUrlInvalid,
+		},
+		{
+			name:    "unsupported scheme",
+			raw:     "ftp://example.com/trailer.mp4",
+			wantErr: ErrTrailerUrlInvalid,
+		},
+		{
+			name:    "empty",
+			raw:     "",
+			wantErr: ErrTrailerUrlInvalid,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseTrailerUrl(tt.raw)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("ParseTrailerUrl(%q) error = %v, want %v", tt.raw, err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("ParseTrailerUrl(%q) = %+v, want %+v", tt.raw, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,12 @@
+package models
+
+import "time"
+
+// TrashItem — удалённая запись каталога, которую ещё можно восстановить.
+// Type совпадает с AuditEntity* (movie, genre, category, age, allseries)
+type TrashItem struct {
+	Type      string    `json:"type"`
+	Id        int       `json:"id"`
+	Title     string    `json:"title"`
+	DeletedAt time.Time `json:"deletedAt"`
+}
@@ -0,0 +1,32 @@
+package models
+
+import "time"
+
+// WatchProgress — место, на котором пользователь или его профиль остановился в фильме или серии (AllSeriesId задан).
+// Completed пересчитывается с каждым heartbeat, WatchedAt — когда просмотр впервые дошёл до конца
+type WatchProgress struct {
+	UserId          int        `json:"-"`
+	ProfileId       *int       `json:"profileId"`
+	MovieId         int        `json:"movieId"`
+	AllSeriesId     *int       `json:"allSeriesId"`
+	PositionSeconds int        `json:"positionSeconds"`
+	DurationSeconds int        `json:"durationSeconds"`
+	Completed       bool       `json:"completed"`
+	WatchedAt       *time.Time `json:"watchedAt"`
+	UpdatedAt       time.Time  `json:"updatedAt"`
+}
+
+// ContinueWatchingItem — что продолжить смотреть. Если предыдущая серия досмотрена,
+// IsNextEpisode = true и просмотр следующей начинается с нуля
+type ContinueWatchingItem struct {
+	MovieId         int       `json:"movieId"`
+	Title           string    `json:"title"`
+	PosterUrl       string    `json:"posterUrl"`
+	AllSeriesId     *int      `json:"allSeriesId"`
+	Series          *int      `json:"series"`
+	EpisodeTitle    *string   `json:"episodeTitle"`
+	PositionSeconds int       `json:"positionSeconds"`
+	DurationSeconds int       `json:"durationSeconds"`
+	IsNextEpisode   bool      `json:"isNextEpisode"`
+	UpdatedAt       time.Time `json:"updatedAt"`
+}
@@ -0,0 +1,98 @@
+// Package moderation ищет запрещённые слова в пользовательских текстах на казахском и русском
+package moderation
+
+import (
+	"strings"
+	"unicode"
+)
+
+// PrefixSuffix в конце слова фильтра означает "все слова, начинающиеся так": "бля*" ловит и "блять"
+const PrefixSuffix = "*"
+
+// латиница, цифры и символы, которыми подменяют похожие кириллические буквы: "xyйня", "3абань", "@дмин"
+var lookalikes = map[rune]rune{
+	'a': 'а', 'b': 'в', 'c': 'с', 'e': 'е', 'h': 'н', 'i': 'і', 'k': 'к', 'm': 'м',
+	'o': 'о', 'p': 'р', 't': 'т', 'x': 'х', 'y': 'у',
+	'0': 'о', '3': 'з', '4': 'ч', '6': 'б', '@': 'а', '$': 'с',
+	'ё': 'е',
+}
+
+type entry struct {
+	word   string
+	stem   string
+	prefix bool
+}
+
+// Filter — набор запрещённых слов. Слова без * совпадают только целиком
+type Filter struct {
+	entries []entry
+}
+
+func NewFilter(words []string) *Filter {
+	filter := &Filter{entries: make([]entry, 0, len(words))}
+	for _, word := range words {
+		stem, prefix := strings.CutSuffix(strings.TrimSpace(word), PrefixSuffix)
+		stem = normalize(stem)
+		if stem == "" {
+			continue
+		}
+		filter.entries = append(filter.entries, entry{word: word, stem: stem, prefix: prefix})
+	}
+
+	return filter
+}
+
+// Match возвращает слова фильтра, найденные в тексте, без повторов и в порядке фильтра
+func (f *Filter) Match(text string) []string {
+	tokens := make(map[string]bool)
+	for _, token := range tokenize(text) {
+		tokens[normalize(token)] = true
+	}
+
+	matched := make([]string, 0)
+	for _, e := range f.entries {
+		for token := range tokens {
+			if token == e.stem || (e.prefix && strings.HasPrefix(token, e.stem)) {
+				matched = append(matched, e.word)
+				break
+			}
+		}
+	}
+
+	return matched
+}
+
+// IsValidWord — слово фильтра должно быть одним словом, необязательно с * в конце
+func IsValidWord(word string) bool {
+	stem, _ := strings.CutSuffix(word, PrefixSuffix)
+	tokens := tokenize(stem)
+	return len(tokens) == 1 && tokens[0] == stem
+}
+
+func tokenize(text string) []string {
+	return strings.FieldsFunc(text, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r) && r != '@' && r != '$'
+	})
+}
+
+// normalize приводит слово к нижнему регистру, схлопывает повторы букв ("нууу" -> "ну")
+// и, если в слове есть кириллица, заменяет похожие латинские буквы и цифры кириллическими
+func normalize(token string) string {
+	token = strings.ToLower(token)
+	cyrillic := strings.IndexFunc(token, func(r rune) bool { return unicode.Is(unicode.Cyrillic, r) }) >= 0
+
+	var builder strings.Builder
+	var last rune
+	for _, r := range token {
+		if mapped, ok := lookalikes[r]; ok && (cyrillic || r == 'ё') {
+			r = mapped
+		}
+		if !unicode.IsLetter(r) || r == last {
+			continue
+		}
+		builder.WriteRune(r)
+		last = r
+	}
+
+	return builder.String()
+}
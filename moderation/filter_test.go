@@ -0,0 +1,64 @@
+package moderation
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestFilterMatch(t *testing.T) {
+	filter := NewFilter([]string{"дурак", "идиот*", "spam", "ёж", "  ", PrefixSuffix})
+
+	tests := []struct {
+		name string
+		text string
+		want []string
+	}{
+		{"whole word", "Ты дурак", []string{"дурак"}},
+		{"case and punctuation", "ДУРАК!!!", []string{"дурак"}},
+		{"repeated letters", "дураааак", []string{"дурак"}},
+		{"latin lookalikes in cyrillic word", "дypaк", []string{"дурак"}},
+		{"symbol lookalikes", "идиот и дур@к", []string{"дурак", "идиот*"}},
+		{"word without star matches only whole", "дураки", []string{}},
+		{"prefix word", "идиотский фильм", []string{"идиот*"}},
+		{"prefix with lookalikes", "идиoтизм", []string{"идиот*"}},
+		{"latin word", "no SPAM please", []string{"spam"}},
+		{"yo is e", "ЕЖ и ёж", []string{"ёж"}},
+		{"filter order without repeats", "идиот, дурак, дурак", []string{"дурак", "идиот*"}},
+		{"separated letters", "д у р а к", []string{}},
+		{"empty text", "", []string{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := filter.Match(tt.text); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Match(%q) = %q, want %q", tt.text, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestIsValidWord(t *testing.T) {
+	tests := []struct {
+		word string
+		want bool
+	}{
+		{"дурак", true},
+		{"идиот*", true},
+		{"@дмин", true},
+		{"spam", true},
+		{"", false},
+		{PrefixSuffix, false},
+		{"два слова", false},
+		{"слово!", false},
+		{"*слово", false},
+		{"сло*во", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.word, func(t *testing.T) {
+			if got := IsValidWord(tt.word); got != tt.want {
+				t.Errorf("IsValidWord(%q) = %v, want %v", tt.word, got, tt.want)
+			}
+		})
+	}
+}
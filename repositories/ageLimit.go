@@ -0,0 +1,19 @@
+package repositories
+
+import "fmt"
+
+// ageLimitCondition — условие "рейтинг фильма m не выше лимита param".
+// Фильм без рейтинга при действующем лимите не показывается
+func ageLimitCondition(param string) string {
+	return fmt.Sprintf(
+		"(select max(al.min_age) from movies_ages mal join ages al on al.id = mal.age_id where mal.movie_id = m.id) <= %s",
+		param)
+}
+
+// episodeAgeLimitCondition — условие "серия s входит в фильмы, и рейтинг каждого не выше лимита param".
+// Серия без фильма, как и фильм без рейтинга, при действующем лимите не показывается
+func episodeAgeLimitCondition(param string) string {
+	return fmt.Sprintf(
+		"exists(select 1 from movies_allseries mas where mas.allserie_id = s.id) and not exists(select 1 from movies_allseries mas join movies m on m.id = mas.movie_id where mas.allserie_id = s.id and not coalesce(%s, false))",
+		ageLimitCondition(param))
+}
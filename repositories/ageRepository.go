@@ -21,7 +21,7 @@ func (r *AgeRepository) Create(c context.Context, age models.Age) (int, error) {
 	l := logger.GetLogger()
 	var id int
 	l.Info(fmt.Sprintf("добавить возраст %s, перетащите картинку или загрузите %s", age.Age, age.PosterUrl))
-	row := r.db.QueryRow(c, "insert into ages (age, poster_url) values($1, $2) returning id", age.Age, age.PosterUrl)
+	row := r.db.QueryRow(c, "insert into ages (age, min_age, poster_url) values($1, $2, $3) returning id", age.Age, age.MinAge, age.PosterUrl)
 	err := row.Scan(&id)
 	if err != nil {
 		return 0, nil
@@ -32,7 +32,7 @@ func (r *AgeRepository) Create(c context.Context, age models.Age) (int, error) {
 }
 
 func (r *AgeRepository) FindAll(c context.Context) ([]models.Age, error) {
-	rows, err := r.db.Query(c, "select id, age, poster_url from ages")
+	rows, err := r.db.Query(c, "select id, age, min_age, poster_url from ages where deleted_at is null")
 	defer rows.Close()
 	if err != nil {
 		return nil, err
@@ -42,7 +42,7 @@ func (r *AgeRepository) FindAll(c context.Context) ([]models.Age, error) {
 
 	for rows.Next() {
 		var age models.Age
-		err := rows.Scan(&age.Id, &age.Age, &age.PosterUrl)
+		err := rows.Scan(&age.Id, &age.Age, &age.MinAge, &age.PosterUrl)
 		if err != nil {
 			return nil, err
 		}
@@ -53,8 +53,8 @@ func (r *AgeRepository) FindAll(c context.Context) ([]models.Age, error) {
 
 func (r *AgeRepository) FindById(c context.Context, id int) (models.Age, error) {
 	var age models.Age
-	row := r.db.QueryRow(c, "select id, age, poster_url from ages where id = $1", id)
-	err := row.Scan(&age.Id, &age.Age, &age.PosterUrl)
+	row := r.db.QueryRow(c, "select id, age, min_age, poster_url, version from ages where id = $1 and deleted_at is null", id)
+	err := row.Scan(&age.Id, &age.Age, &age.MinAge, &age.PosterUrl, &age.Version)
 	if err != nil {
 		return models.Age{}, err
 	}
@@ -63,7 +63,7 @@ func (r *AgeRepository) FindById(c context.Context, id int) (models.Age, error)
 }
 
 func (r *AgeRepository) FindAllByIds(c context.Context, ids []int) ([]models.Age, error) {
-	rows, err := r.db.Query(c, "select id, age, poster_url from ages where id = any($1)", ids)
+	rows, err := r.db.Query(c, "select id, age, min_age, poster_url from ages where id = any($1) and deleted_at is null", ids)
 	defer rows.Close()
 	if err != nil {
 		return nil, err
@@ -73,7 +73,7 @@ func (r *AgeRepository) FindAllByIds(c context.Context, ids []int) ([]models.Age
 
 	for rows.Next() {
 		var age models.Age
-		err := rows.Scan(&age.Id, &age.Age, &age.PosterUrl)
+		err := rows.Scan(&age.Id, &age.Age, &age.MinAge, &age.PosterUrl)
 		if err != nil {
 			return nil, err
 		}
@@ -82,20 +82,23 @@ func (r *AgeRepository) FindAllByIds(c context.Context, ids []int) ([]models.Age
 	return ages, nil
 }
 
+// Update применяется, только если версия совпадает с updateage.Version
 func (r *AgeRepository) Update(c context.Context, id int, updateage models.Age) error {
-	_, err := r.db.Exec(c, "update ages set age = $1, poster_url = $2 where id = $3", updateage.Age, updateage.PosterUrl, id)
+	tag, err := r.db.Exec(c,
+		"update ages set age = $1, min_age = $2, poster_url = $3, version = version + 1 where id = $4 and version = $5 and deleted_at is null",
+		updateage.Age, updateage.MinAge, updateage.PosterUrl, id, updateage.Version)
 	if err != nil {
 		return err
 	}
 
-	return nil
+	return checkVersion(tag)
 }
 
-func (r *AgeRepository) Delete(c context.Context, id int) error {
+func (r *AgeRepository) Delete(c context.Context, id int, version int) error {
 	l := logger.GetLogger()
 
 	var ageTitle string
-	row := r.db.QueryRow(c, "select age from ages where id = $1", id)
+	row := r.db.QueryRow(c, "select age from ages where id = $1 and deleted_at is null", id)
 	err := row.Scan(&ageTitle)
 	if err != nil {
 		return err
@@ -103,12 +106,17 @@ func (r *AgeRepository) Delete(c context.Context, id int) error {
 
 	l.Warn(fmt.Sprintf("Вы действительно хотите удалить %s возраст?", ageTitle))
 
-	_, err = r.db.Exec(c, "delete from ages where id = $1", id)
+	tag, err := r.db.Exec(c, "update ages set deleted_at = now(), version = version + 1 where id = $1 and version = $2", id, version)
+	if err != nil {
+		return err
+	}
+
+	err = checkVersion(tag)
 	if err != nil {
 		return err
 	}
 
-	l.Info(fmt.Sprintf("возраст %s удален", ageTitle))
+	l.Info(fmt.Sprintf("возраст %s перемещен в корзину", ageTitle))
 
 	return nil
 }
@@ -18,7 +18,7 @@ func NewAllSeriesRepository(conn *pgxpool.Pool) *AllSeriesRepository {
 }
 
 func (r *AllSeriesRepository) FindAllByIds(c context.Context, ids []int) ([]models.AllSeries, error) {
-	rows, err := r.db.Query(c, "select id, series,  title, description, release_year, director, rating, trailer_url from allseries where id = any($1)", ids)
+	rows, err := r.db.Query(c, "select id, series,  title, description, release_year, director, rating, trailer_url from allseries where id = any($1) and deleted_at is null", ids)
 	defer rows.Close()
 	if err != nil {
 		l := logger.GetLogger()
@@ -36,6 +36,7 @@ func (r *AllSeriesRepository) FindAllByIds(c context.Context, ids []int) ([]mode
 			l.Error(err.Error())
 			return nil, err
 		}
+		allserie.Trailer = episodeTrailer(allserie.TrailerUrl)
 
 		allseries = append(allseries, allserie)
 	}
@@ -59,7 +60,7 @@ func (r *AllSeriesRepository) Create(c context.Context, serie models.AllSeries)
 
 func (r *AllSeriesRepository) FindById(c context.Context, id int) (models.AllSeries, error) {
 	var allserie models.AllSeries
-	row := r.db.QueryRow(c, "select id, series,  title, description, release_year, director, rating, trailer_url from allseries where id = $1", id)
+	row := r.db.QueryRow(c, "select id, series,  title, description, release_year, director, rating, trailer_url, version from allseries where id = $1 and deleted_at is null", id)
 	err := row.Scan(&allserie.Id,
 		&allserie.Series,
 		&allserie.Title,
@@ -67,17 +68,20 @@ func (r *AllSeriesRepository) FindById(c context.Context, id int) (models.AllSer
 		&allserie.ReleaseYear,
 		&allserie.Director,
 		&allserie.Rating,
-		&allserie.TrailerUrl)
+		&allserie.TrailerUrl,
+		&allserie.Version)
 	if err != nil {
 		l := logger.GetLogger()
 		l.Error(err.Error())
 		return models.AllSeries{}, err
 	}
+	allserie.Trailer = episodeTrailer(allserie.TrailerUrl)
 	return allserie, nil
 }
 
-func (r *AllSeriesRepository) FindAll(c context.Context) ([]models.AllSeries, error) {
-	rows, err := r.db.Query(c, "select id, series, title, description, release_year, director, rating, trailer_url from allseries")
+// FindAll — все серии; при maxAge только серии фильмов с рейтингом не выше лимита
+func (r *AllSeriesRepository) FindAll(c context.Context, maxAge *int) ([]models.AllSeries, error) {
+	rows, err := r.db.Query(c, "select s.id, s.series, s.title, s.description, s.release_year, s.director, s.rating, s.trailer_url from allseries s where s.deleted_at is null and ($1::int is null or "+episodeAgeLimitCondition("$1")+")", maxAge)
 	defer rows.Close()
 	if err != nil {
 		l := logger.GetLogger()
@@ -102,6 +106,7 @@ func (r *AllSeriesRepository) FindAll(c context.Context) ([]models.AllSeries, er
 			l.Error(err.Error())
 			return nil, err
 		}
+		allserie.Trailer = episodeTrailer(allserie.TrailerUrl)
 
 		allseries = append(allseries, allserie)
 	}
@@ -109,16 +114,18 @@ func (r *AllSeriesRepository) FindAll(c context.Context) ([]models.AllSeries, er
 	return allseries, nil
 }
 
+// Update применяется, только если версия совпадает с allserie.Version
 func (r *AllSeriesRepository) Update(c context.Context, id int, allserie models.AllSeries) error {
-	_, err := r.db.Exec(c, `update allseries set 
+	tag, err := r.db.Exec(c, `update allseries set 
 							series = $1 ,
 							title = $2, 
 							description = $3, 
 							release_year = $4, 
 							director = $5, 
 							rating = $6, 
-							trailer_url = $7
-							where id = $8`,
+							trailer_url = $7,
+							version = version + 1
+							where id = $8 and version = $9 and deleted_at is null`,
 		&allserie.Series,
 		&allserie.Title,
 		&allserie.Description,
@@ -126,23 +133,24 @@ func (r *AllSeriesRepository) Update(c context.Context, id int, allserie models.
 		&allserie.Director,
 		&allserie.Rating,
 		&allserie.TrailerUrl,
-		id)
+		id,
+		allserie.Version)
 	if err != nil {
 		l := logger.GetLogger()
 		l.Error(err.Error())
 		return err
 	}
 
-	return nil
+	return checkVersion(tag)
 }
 
 //series, title, description, release_year, director, rating, trailer_url
 
-func (r *AllSeriesRepository) Delete(c context.Context, id int) error {
+func (r *AllSeriesRepository) Delete(c context.Context, id int, version int) error {
 	l := logger.GetLogger()
 
 	var serieTitle string
-	row := r.db.QueryRow(c, "select title from allseries where id = $1", id)
+	row := r.db.QueryRow(c, "select title from allseries where id = $1 and deleted_at is null", id)
 	err := row.Scan(&serieTitle)
 	if err != nil {
 		return err
@@ -150,12 +158,26 @@ func (r *AllSeriesRepository) Delete(c context.Context, id int) error {
 
 	l.Warn(fmt.Sprintf("Вы действительно хотите удалить %s серию?", serieTitle))
 
-	_, err = r.db.Exec(c, "delete from allseries where id = $1", id)
+	tag, err := r.db.Exec(c, "update allseries set deleted_at = now(), version = version + 1 where id = $1 and version = $2", id, version)
+	if err != nil {
+		return err
+	}
+
+	err = checkVersion(tag)
 	if err != nil {
 		return err
 	}
 
-	l.Info(fmt.Sprintf("серия %s удалена", serieTitle))
+	l.Info(fmt.Sprintf("серия %s перемещена в корзину", serieTitle))
 
 	return nil
 }
+
+// episodeTrailer — разобранный трейлер серии, если ссылка задана
+func episodeTrailer(trailerUrl *string) *models.Trailer {
+	if trailerUrl == nil {
+		return nil
+	}
+
+	return models.TrailerFromUrl(*trailerUrl)
+}
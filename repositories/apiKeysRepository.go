@@ -0,0 +1,124 @@
+package repositories
+
+import (
+	"context"
+	"fmt"
+	"goozinshe/logger"
+	"goozinshe/models"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type ApiKeysRepository struct {
+	db *pgxpool.Pool
+}
+
+func NewApiKeysRepository(conn *pgxpool.Pool) *ApiKeysRepository {
+	return &ApiKeysRepository{db: conn}
+}
+
+func (r *ApiKeysRepository) Create(c context.Context, key models.ApiKey) (int, error) {
+	l := logger.GetLogger()
+	var id int
+
+	row := r.db.QueryRow(c,
+		`
+	insert into api_keys(name, key_prefix, key_hash, permissions, created_by, expires_at)
+	values($1, $2, $3, $4, $5, $6)
+	returning id
+	`,
+		key.Name,
+		key.Prefix,
+		key.KeyHash,
+		key.Permissions,
+		key.CreatedBy,
+		key.ExpiresAt)
+
+	err := row.Scan(&id)
+	if err != nil {
+		l.Error(err.Error())
+		return 0, err
+	}
+
+	l.Info(fmt.Sprintf("API ключ %s создан", key.Name))
+
+	return id, nil
+}
+
+func (r *ApiKeysRepository) FindById(c context.Context, id int) (models.ApiKey, error) {
+	row := r.db.QueryRow(c, `
+	select id, name, key_prefix, key_hash, permissions, created_by, created_at, expires_at, last_used_at, revoked_at
+	from api_keys
+	where id = $1`, id)
+
+	var key models.ApiKey
+	err := row.Scan(&key.Id, &key.Name, &key.Prefix, &key.KeyHash, &key.Permissions, &key.CreatedBy, &key.CreatedAt, &key.ExpiresAt, &key.LastUsedAt, &key.RevokedAt)
+
+	return key, err
+}
+
+// FindActiveByHash ищет неотозванный и непросроченный ключ по его хэшу
+func (r *ApiKeysRepository) FindActiveByHash(c context.Context, keyHash string) (models.ApiKey, error) {
+	row := r.db.QueryRow(c, `
+	select id, name, key_prefix, key_hash, permissions, created_by, created_at, expires_at, last_used_at, revoked_at
+	from api_keys
+	where key_hash = $1
+	  and revoked_at is null
+	  and (expires_at is null or expires_at > now())`, keyHash)
+
+	var key models.ApiKey
+	err := row.Scan(&key.Id, &key.Name, &key.Prefix, &key.KeyHash, &key.Permissions, &key.CreatedBy, &key.CreatedAt, &key.ExpiresAt, &key.LastUsedAt, &key.RevokedAt)
+
+	return key, err
+}
+
+func (r *ApiKeysRepository) FindAll(c context.Context) ([]models.ApiKey, error) {
+	rows, err := r.db.Query(c, `
+	select id, name, key_prefix, key_hash, permissions, created_by, created_at, expires_at, last_used_at, revoked_at
+	from api_keys
+	order by id`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	keys := make([]models.ApiKey, 0)
+	for rows.Next() {
+		var key models.ApiKey
+		err := rows.Scan(&key.Id, &key.Name, &key.Prefix, &key.KeyHash, &key.Permissions, &key.CreatedBy, &key.CreatedAt, &key.ExpiresAt, &key.LastUsedAt, &key.RevokedAt)
+		if err != nil {
+			return nil, err
+		}
+
+		keys = append(keys, key)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return keys, nil
+}
+
+func (r *ApiKeysRepository) TouchLastUsed(c context.Context, id int) error {
+	_, err := r.db.Exec(c, "update api_keys set last_used_at = now() where id = $1", id)
+	if err != nil {
+		l := logger.GetLogger()
+		l.Error(err.Error())
+	}
+
+	return err
+}
+
+func (r *ApiKeysRepository) Revoke(c context.Context, id int) error {
+	l := logger.GetLogger()
+
+	_, err := r.db.Exec(c, "update api_keys set revoked_at = now() where id = $1 and revoked_at is null", id)
+	if err != nil {
+		l.Error(err.Error())
+		return err
+	}
+
+	l.Info(fmt.Sprintf("API ключ %d отозван", id))
+
+	return nil
+}
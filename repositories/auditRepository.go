@@ -0,0 +1,119 @@
+package repositories
+
+import (
+	"context"
+	"fmt"
+	"goozinshe/logger"
+	"goozinshe/models"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type AuditRepository struct {
+	db *pgxpool.Pool
+}
+
+func NewAuditRepository(conn *pgxpool.Pool) *AuditRepository {
+	return &AuditRepository{db: conn}
+}
+
+func (r *AuditRepository) Record(c context.Context, event models.AuditEvent) error {
+	_, err := r.db.Exec(c,
+		`
+	insert into audit_events(actor_user_id, actor_api_key_id, action, entity_type, entity_id, before, after, ip)
+	values($1, $2, $3, $4, $5, $6, $7, $8)
+	`,
+		event.ActorUserId,
+		event.ActorApiKeyId,
+		event.Action,
+		event.EntityType,
+		event.EntityId,
+		event.Before,
+		event.After,
+		event.Ip)
+	if err != nil {
+		l := logger.GetLogger()
+		l.Error(err.Error())
+	}
+
+	return err
+}
+
+func (r *AuditRepository) FindAll(c context.Context, filters models.AuditFilters) ([]models.AuditEvent, error) {
+	sql := `
+	select id, actor_user_id, actor_api_key_id, action, entity_type, entity_id, before, after, ip, created_at
+	from audit_events
+	where 1 = 1
+	`
+
+	params := pgx.NamedArgs{}
+
+	if filters.ActorUserId != nil {
+		sql = fmt.Sprintf("%s and actor_user_id = @actorUserId", sql)
+		params["actorUserId"] = *filters.ActorUserId
+	}
+	if filters.Action != "" {
+		sql = fmt.Sprintf("%s and action = @action", sql)
+		params["action"] = filters.Action
+	}
+	if filters.EntityType != "" {
+		sql = fmt.Sprintf("%s and entity_type = @entityType", sql)
+		params["entityType"] = filters.EntityType
+	}
+	if filters.EntityId != nil {
+		sql = fmt.Sprintf("%s and entity_id = @entityId", sql)
+		params["entityId"] = *filters.EntityId
+	}
+	if filters.From != nil {
+		sql = fmt.Sprintf("%s and created_at >= @from", sql)
+		params["from"] = *filters.From
+	}
+	if filters.To != nil {
+		sql = fmt.Sprintf("%s and created_at < @to", sql)
+		params["to"] = *filters.To
+	}
+
+	sql = fmt.Sprintf("%s order by id desc limit @limit offset @offset", sql)
+	params["limit"] = filters.Limit
+	params["offset"] = filters.Offset
+
+	l := logger.GetLogger()
+	rows, err := r.db.Query(c, sql, params)
+	if err != nil {
+		l.Error(err.Error())
+		return nil, err
+	}
+	defer rows.Close()
+
+	events := make([]models.AuditEvent, 0)
+	for rows.Next() {
+		var event models.AuditEvent
+		err := rows.Scan(
+			&event.Id,
+			&event.ActorUserId,
+			&event.ActorApiKeyId,
+			&event.Action,
+			&event.EntityType,
+			&event.EntityId,
+			&event.Before,
+			&event.After,
+			&event.Ip,
+			&event.CreatedAt,
+		)
+		if err != nil {
+			l.Error(err.Error())
+			return nil, err
+		}
+
+		events = append(events, event)
+	}
+
+	err = rows.Err()
+	if err != nil {
+		l.Error(err.Error())
+		return nil, err
+	}
+
+	return events, nil
+}
@@ -0,0 +1,82 @@
+package repositories
+
+import (
+	"context"
+	"goozinshe/logger"
+	"goozinshe/models"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type BadWordsRepository struct {
+	db *pgxpool.Pool
+}
+
+func NewBadWordsRepository(conn *pgxpool.Pool) *BadWordsRepository {
+	return &BadWordsRepository{db: conn}
+}
+
+func (r *BadWordsRepository) FindAll(c context.Context) ([]models.BadWord, error) {
+	l := logger.GetLogger()
+	rows, err := r.db.Query(c, "select id, language, word, created_at from bad_words order by language, word")
+	if err != nil {
+		l.Error(err.Error())
+		return nil, err
+	}
+	defer rows.Close()
+
+	words := make([]models.BadWord, 0)
+	for rows.Next() {
+		var word models.BadWord
+		err := rows.Scan(&word.Id, &word.Language, &word.Word, &word.CreatedAt)
+		if err != nil {
+			l.Error(err.Error())
+			return nil, err
+		}
+		words = append(words, word)
+	}
+
+	return words, rows.Err()
+}
+
+func (r *BadWordsRepository) FindById(c context.Context, id int) (models.BadWord, error) {
+	var word models.BadWord
+	err := r.db.QueryRow(c, "select id, language, word, created_at from bad_words where id = $1", id).
+		Scan(&word.Id, &word.Language, &word.Word, &word.CreatedAt)
+	if err != nil {
+		l := logger.GetLogger()
+		l.Error(err.Error())
+		return models.BadWord{}, err
+	}
+
+	return word, nil
+}
+
+// Create добавляет слово; если такое слово для языка уже есть, возвращает pgx.ErrNoRows
+func (r *BadWordsRepository) Create(c context.Context, word models.BadWord) (models.BadWord, error) {
+	err := r.db.QueryRow(c,
+		`
+	insert into bad_words(language, word) values($1, $2)
+	on conflict (language, lower(word)) do nothing
+	returning id, language, word, created_at
+	`,
+		word.Language,
+		word.Word).Scan(&word.Id, &word.Language, &word.Word, &word.CreatedAt)
+	if err != nil {
+		l := logger.GetLogger()
+		l.Error(err.Error())
+		return models.BadWord{}, err
+	}
+
+	return word, nil
+}
+
+func (r *BadWordsRepository) Delete(c context.Context, id int) error {
+	_, err := r.db.Exec(c, "delete from bad_words where id = $1", id)
+	if err != nil {
+		l := logger.GetLogger()
+		l.Error(err.Error())
+	}
+
+	return err
+}
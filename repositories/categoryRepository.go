@@ -18,7 +18,7 @@ func NewCategoryRepository(conn *pgxpool.Pool) *CategoryRepository {
 }
 
 func (r *CategoryRepository) FindAllByIds(c context.Context, ids []int) ([]models.Category, error) {
-	rows, err := r.db.Query(c, "select id, title, poster_url from categories where id = any($1)", ids)
+	rows, err := r.db.Query(c, "select id, title, poster_url from categories where id = any($1) and deleted_at is null", ids)
 	defer rows.Close()
 	if err != nil {
 		return nil, err
@@ -55,8 +55,8 @@ func (r *CategoryRepository) Create(c context.Context, category models.Category)
 
 func (r *CategoryRepository) FindById(c context.Context, id int) (models.Category, error) {
 	var category models.Category
-	row := r.db.QueryRow(c, "select id, title, poster_url from categories where id = $1", id)
-	err := row.Scan(&category.Id, &category.Title, &category.PosterUrl)
+	row := r.db.QueryRow(c, "select id, title, poster_url, version from categories where id = $1 and deleted_at is null", id)
+	err := row.Scan(&category.Id, &category.Title, &category.PosterUrl, &category.Version)
 	if err != nil {
 		return models.Category{}, err
 	}
@@ -64,7 +64,7 @@ func (r *CategoryRepository) FindById(c context.Context, id int) (models.Categor
 }
 
 func (r *CategoryRepository) FindAll(c context.Context) ([]models.Category, error) {
-	rows, err := r.db.Query(c, "select id, title, poster_url from categories")
+	rows, err := r.db.Query(c, "select id, title, poster_url from categories where deleted_at is null")
 	defer rows.Close()
 	if err != nil {
 		return nil, err
@@ -84,32 +84,40 @@ func (r *CategoryRepository) FindAll(c context.Context) ([]models.Category, erro
 	return categories, nil
 }
 
+// Update применяется, только если версия категории совпадает с updatedcategory.Version
 func (r *CategoryRepository) Update(c context.Context, id int, updatedcategory models.Category) error {
-	_, err := r.db.Exec(c, "update categories set title = $1, poster_url = $2 where id = $3", updatedcategory.Title, updatedcategory.PosterUrl, id)
+	tag, err := r.db.Exec(c,
+		"update categories set title = $1, poster_url = $2, version = version + 1 where id = $3 and version = $4 and deleted_at is null",
+		updatedcategory.Title, updatedcategory.PosterUrl, id, updatedcategory.Version)
 	if err != nil {
 		return err
 	}
 
-	return nil
+	return checkVersion(tag)
 }
 
-func (r *CategoryRepository) Delete(c context.Context, id int) error {
+func (r *CategoryRepository) Delete(c context.Context, id int, version int) error {
 	l := logger.GetLogger()
 
 	var categoryTitle string
-	row := r.db.QueryRow(c, "select title from categories where id = $1", id)
+	row := r.db.QueryRow(c, "select title from categories where id = $1 and deleted_at is null", id)
 	err := row.Scan(&categoryTitle)
 	if err != nil {
 		return err
 	}
 
 	l.Warn(fmt.Sprintf("Вы действительно хотите удалить %s категорию?", categoryTitle))
-	_, err = r.db.Exec(c, "delete from categories where id = $1", id)
+	tag, err := r.db.Exec(c, "update categories set deleted_at = now(), version = version + 1 where id = $1 and version = $2", id, version)
+	if err != nil {
+		return err
+	}
+
+	err = checkVersion(tag)
 	if err != nil {
 		return err
 	}
 
-	l.Info(fmt.Sprintf("категория %s удалена", categoryTitle))
+	l.Info(fmt.Sprintf("категория %s перемещена в корзину", categoryTitle))
 
 	return nil
 }
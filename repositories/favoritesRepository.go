@@ -0,0 +1,97 @@
+package repositories
+
+import (
+	"context"
+	"goozinshe/logger"
+	"goozinshe/models"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type FavoritesRepository struct {
+	db *pgxpool.Pool
+}
+
+func NewFavoritesRepository(conn *pgxpool.Pool) *FavoritesRepository {
+	return &FavoritesRepository{db: conn}
+}
+
+// Add добавляет фильм в избранное; повторное добавление ничего не меняет
+func (r *FavoritesRepository) Add(c context.Context, userId int, profileId *int, movieId int) error {
+	_, err := r.db.Exec(c,
+		`
+	insert into favorites(user_id, profile_id, movie_id)
+	values($1, $2, $3)
+	on conflict (user_id, coalesce(profile_id, 0), movie_id) do nothing
+	`,
+		userId,
+		profileId,
+		movieId)
+	if err != nil {
+		l := logger.GetLogger()
+		l.Error(err.Error())
+	}
+
+	return err
+}
+
+func (r *FavoritesRepository) Remove(c context.Context, userId int, profileId *int, movieId int) error {
+	_, err := r.db.Exec(c,
+		"delete from favorites where user_id = $1 and profile_id is not distinct from $2 and movie_id = $3",
+		userId,
+		profileId,
+		movieId)
+	if err != nil {
+		l := logger.GetLogger()
+		l.Error(err.Error())
+	}
+
+	return err
+}
+
+// FindByUser — избранное, недавно добавленное первым. maxAge — лимит родительского контроля
+func (r *FavoritesRepository) FindByUser(c context.Context, userId int, profileId *int, limit int, offset int, maxAge *int) ([]models.FavoriteItem, error) {
+	sql :=
+		`
+	select
+		m.id,
+		m.title,
+		m.poster_url,
+		m.release_year,
+		(select count(*) from favorites fc where fc.movie_id = m.id),
+		f.created_at
+	from favorites f
+	join movies m on m.id = f.movie_id and m.deleted_at is null and m.status = 'published'
+	where f.user_id = $1 and f.profile_id is not distinct from $2
+		and ($5::int is null or ` + ageLimitCondition("$5") + `)
+	order by f.created_at desc, m.id
+	limit $3 offset $4
+	`
+
+	l := logger.GetLogger()
+	rows, err := r.db.Query(c, sql, userId, profileId, limit, offset, maxAge)
+	if err != nil {
+		l.Error(err.Error())
+		return nil, err
+	}
+	defer rows.Close()
+
+	items := make([]models.FavoriteItem, 0)
+	for rows.Next() {
+		var item models.FavoriteItem
+		err := rows.Scan(
+			&item.MovieId,
+			&item.Title,
+			&item.PosterUrl,
+			&item.ReleaseYear,
+			&item.FavoriteCount,
+			&item.AddedAt)
+		if err != nil {
+			l.Error(err.Error())
+			return nil, err
+		}
+		items = append(items, item)
+	}
+
+	return items, rows.Err()
+}
@@ -19,8 +19,8 @@ func NewGenresRepository(conn *pgxpool.Pool) *GenresRepository {
 
 func (r *GenresRepository) FindById(c context.Context, id int) (models.Genre, error) {
 	var genre models.Genre
-	row := r.db.QueryRow(c, "select id, title, poster_url from genres where id = $1", id)
-	err := row.Scan(&genre.Id, &genre.Title, &genre.PosterUrl)
+	row := r.db.QueryRow(c, "select id, title, poster_url, version from genres where id = $1 and deleted_at is null", id)
+	err := row.Scan(&genre.Id, &genre.Title, &genre.PosterUrl, &genre.Version)
 	if err != nil {
 		return models.Genre{}, err
 	}
@@ -29,7 +29,7 @@ func (r *GenresRepository) FindById(c context.Context, id int) (models.Genre, er
 }
 
 func (r *GenresRepository) FindAll(c context.Context) ([]models.Genre, error) {
-	rows, err := r.db.Query(c, "select id, title, poster_url from genres")
+	rows, err := r.db.Query(c, "select id, title, poster_url from genres where deleted_at is null")
 	defer rows.Close()
 	if err != nil {
 		return nil, err
@@ -51,7 +51,7 @@ func (r *GenresRepository) FindAll(c context.Context) ([]models.Genre, error) {
 }
 
 func (r *GenresRepository) FindAllByIds(c context.Context, ids []int) ([]models.Genre, error) {
-	rows, err := r.db.Query(c, "select id, title, poster_url from genres where id = any($1)", ids)
+	rows, err := r.db.Query(c, "select id, title, poster_url from genres where id = any($1) and deleted_at is null", ids)
 	defer rows.Close()
 	if err != nil {
 		return nil, err
@@ -83,33 +83,41 @@ func (r *GenresRepository) Create(c context.Context, genre models.Genre) (int, e
 	return id, nil
 }
 
+// Update применяется, только если версия жанра совпадает с updategenre.Version
 func (r *GenresRepository) Update(c context.Context, id int, updategenre models.Genre) error {
-	_, err := r.db.Exec(c, "update genres set title = $1, poster_url = $2 where id = $3", updategenre.Title, updategenre.PosterUrl, id)
+	tag, err := r.db.Exec(c,
+		"update genres set title = $1, poster_url = $2, version = version + 1 where id = $3 and version = $4 and deleted_at is null",
+		updategenre.Title, updategenre.PosterUrl, id, updategenre.Version)
 	if err != nil {
 		return err
 	}
 
-	return nil
+	return checkVersion(tag)
 }
 
-func (r *GenresRepository) Delete(c context.Context, id int) error {
+func (r *GenresRepository) Delete(c context.Context, id int, version int) error {
 	l := logger.GetLogger()
 
 	var genreTitle string
-	row := r.db.QueryRow(c, "select title from genres where id = $1", id)
+	row := r.db.QueryRow(c, "select title from genres where id = $1 and deleted_at is null", id)
 	err := row.Scan(&genreTitle)
 	if err != nil {
 		return err
 	}
 
 	l.Warn(fmt.Sprintf("Вы действительно хотите удалить %s жанр?", genreTitle))
-	_, err = r.db.Exec(c, "delete from genres where id = $1", id)
+	tag, err := r.db.Exec(c, "update genres set deleted_at = now(), version = version + 1 where id = $1 and version = $2", id, version)
 	if err != nil {
 		l.Error(err.Error())
 		return err
 	}
 
-	l.Info(fmt.Sprintf("Жанр %s удален:", genreTitle))
+	err = checkVersion(tag)
+	if err != nil {
+		return err
+	}
+
+	l.Info(fmt.Sprintf("Жанр %s перемещен в корзину", genreTitle))
 
 	return nil
 }
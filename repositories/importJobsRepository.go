@@ -0,0 +1,139 @@
+package repositories
+
+import (
+	"context"
+	"goozinshe/logger"
+	"goozinshe/models"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+const importJobColumns = `id, status, format, total_rows, created_rows, updated_rows, failed_rows, error, row_errors, created_by, created_at, started_at, finished_at`
+
+type ImportJobsRepository struct {
+	db *pgxpool.Pool
+}
+
+func NewImportJobsRepository(conn *pgxpool.Pool) *ImportJobsRepository {
+	return &ImportJobsRepository{db: conn}
+}
+
+func (r *ImportJobsRepository) Create(c context.Context, job models.ImportJob) (int, error) {
+	var id int
+	row := r.db.QueryRow(c, "insert into import_jobs(status, format, created_by) values($1, $2, $3) returning id", job.Status, job.Format, job.CreatedBy)
+	err := row.Scan(&id)
+	if err != nil {
+		l := logger.GetLogger()
+		l.Error(err.Error())
+		return 0, err
+	}
+
+	return id, nil
+}
+
+func (r *ImportJobsRepository) FindById(c context.Context, id int) (models.ImportJob, error) {
+	row := r.db.QueryRow(c, "select "+importJobColumns+" from import_jobs where id = $1", id)
+
+	job, err := scanImportJob(row)
+	if err != nil {
+		l := logger.GetLogger()
+		l.Error(err.Error())
+		return models.ImportJob{}, err
+	}
+
+	return job, nil
+}
+
+func (r *ImportJobsRepository) FindAll(c context.Context) ([]models.ImportJob, error) {
+	l := logger.GetLogger()
+	rows, err := r.db.Query(c, "select "+importJobColumns+" from import_jobs order by id desc limit 100")
+	if err != nil {
+		l.Error(err.Error())
+		return nil, err
+	}
+	defer rows.Close()
+
+	jobs := make([]models.ImportJob, 0)
+	for rows.Next() {
+		job, err := scanImportJob(rows)
+		if err != nil {
+			l.Error(err.Error())
+			return nil, err
+		}
+		jobs = append(jobs, job)
+	}
+
+	return jobs, rows.Err()
+}
+
+func (r *ImportJobsRepository) Start(c context.Context, id int) error {
+	_, err := r.db.Exec(c, "update import_jobs set status = $1, started_at = now() where id = $2", models.ImportStatusRunning, id)
+	if err != nil {
+		l := logger.GetLogger()
+		l.Error(err.Error())
+	}
+
+	return err
+}
+
+func (r *ImportJobsRepository) Finish(c context.Context, job models.ImportJob) error {
+	_, err := r.db.Exec(c,
+		`
+	update import_jobs
+	set status = $1, total_rows = $2, created_rows = $3, updated_rows = $4, failed_rows = $5,
+		error = $6, row_errors = $7, finished_at = now()
+	where id = $8
+	`,
+		job.Status,
+		job.TotalRows,
+		job.CreatedRows,
+		job.UpdatedRows,
+		job.FailedRows,
+		job.Error,
+		job.RowErrors,
+		job.Id)
+	if err != nil {
+		l := logger.GetLogger()
+		l.Error(err.Error())
+	}
+
+	return err
+}
+
+// FailUnfinished помечает упавшими задачи, прерванные перезапуском сервера: данные импорта хранятся только в памяти
+func (r *ImportJobsRepository) FailUnfinished(c context.Context) (int64, error) {
+	tag, err := r.db.Exec(c,
+		"update import_jobs set status = $1, error = $2, finished_at = now() where status in ($3, $4)",
+		models.ImportStatusFailed,
+		"interrupted by server restart",
+		models.ImportStatusPending,
+		models.ImportStatusRunning)
+	if err != nil {
+		l := logger.GetLogger()
+		l.Error(err.Error())
+		return 0, err
+	}
+
+	return tag.RowsAffected(), nil
+}
+
+func scanImportJob(row pgx.Row) (models.ImportJob, error) {
+	var job models.ImportJob
+	err := row.Scan(
+		&job.Id,
+		&job.Status,
+		&job.Format,
+		&job.TotalRows,
+		&job.CreatedRows,
+		&job.UpdatedRows,
+		&job.FailedRows,
+		&job.Error,
+		&job.RowErrors,
+		&job.CreatedBy,
+		&job.CreatedAt,
+		&job.StartedAt,
+		&job.FinishedAt)
+
+	return job, err
+}
@@ -0,0 +1,271 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"goozinshe/logger"
+	"goozinshe/models"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+const mediaAssetColumns = `id, movie_id, allserie_id, status, filename, size, uploaded_bytes, renditions, playback_url, error, created_by, created_at, updated_at, ready_at`
+
+type MediaAssetsRepository struct {
+	db *pgxpool.Pool
+}
+
+func NewMediaAssetsRepository(conn *pgxpool.Pool) *MediaAssetsRepository {
+	return &MediaAssetsRepository{db: conn}
+}
+
+func (r *MediaAssetsRepository) Create(c context.Context, asset models.MediaAsset) (models.MediaAsset, error) {
+	row := r.db.QueryRow(c,
+		"insert into media_assets(movie_id, allserie_id, status, filename, size, created_by) values($1, $2, $3, $4, $5, $6) returning "+mediaAssetColumns,
+		asset.MovieId,
+		asset.AllSeriesId,
+		models.MediaStatusUploading,
+		asset.Filename,
+		asset.Size,
+		asset.CreatedBy)
+
+	created, err := scanMediaAsset(row)
+	if err != nil {
+		l := logger.GetLogger()
+		l.Error(err.Error())
+		return models.MediaAsset{}, err
+	}
+
+	return created, nil
+}
+
+func (r *MediaAssetsRepository) FindById(c context.Context, id int) (models.MediaAsset, error) {
+	row := r.db.QueryRow(c, "select "+mediaAssetColumns+" from media_assets where id = $1", id)
+
+	asset, err := scanMediaAsset(row)
+	if err != nil {
+		l := logger.GetLogger()
+		l.Error(err.Error())
+		return models.MediaAsset{}, err
+	}
+
+	return asset, nil
+}
+
+// FindPublished — видео, если его фильм опубликован и не в корзине; иначе pgx.ErrNoRows
+func (r *MediaAssetsRepository) FindPublished(c context.Context, id int) (models.MediaAsset, error) {
+	row := r.db.QueryRow(c,
+		"select "+mediaAssetColumns+" from media_assets where id = $1 and exists(select 1 from movies m where m.id = media_assets.movie_id and m.deleted_at is null and m.status = 'published')",
+		id)
+
+	asset, err := scanMediaAsset(row)
+	if err != nil {
+		l := logger.GetLogger()
+		l.Error(err.Error())
+		return models.MediaAsset{}, err
+	}
+
+	return asset, nil
+}
+
+// FindByMovie — видео фильма и всех его серий, новые первыми
+func (r *MediaAssetsRepository) FindByMovie(c context.Context, movieId int) ([]models.MediaAsset, error) {
+	l := logger.GetLogger()
+	rows, err := r.db.Query(c, "select "+mediaAssetColumns+" from media_assets where movie_id = $1 order by id desc", movieId)
+	if err != nil {
+		l.Error(err.Error())
+		return nil, err
+	}
+	defer rows.Close()
+
+	assets := make([]models.MediaAsset, 0)
+	for rows.Next() {
+		asset, err := scanMediaAsset(rows)
+		if err != nil {
+			l.Error(err.Error())
+			return nil, err
+		}
+		assets = append(assets, asset)
+	}
+
+	return assets, rows.Err()
+}
+
+var (
+	// ErrUploadBusy — в эту загрузку прямо сейчас пишет другой запрос
+	ErrUploadBusy = errors.New("upload chunk is being written")
+	// ErrUploadOffset — offset куска не совпал с уже загруженным объёмом
+	ErrUploadOffset = errors.New("upload offset mismatch")
+	// ErrUploadFinished — загрузка уже завершена или отменена
+	ErrUploadFinished = errors.New("upload is not in progress")
+)
+
+// код Postgres lock_not_available для for update nowait
+const lockNotAvailable = "55P03"
+
+// AppendUpload блокирует строку загрузки на всё время записи куска, поэтому куски одной загрузки пишутся строго по очереди:
+// параллельный запрос сразу получает ErrUploadBusy. Под блокировкой сверяется offset, write пишет кусок в файл,
+// а записанные байты засчитываются в той же транзакции. Последний кусок переводит видео в очередь на обработку.
+// При ErrUploadOffset возвращается текущее состояние загрузки
+func (r *MediaAssetsRepository) AppendUpload(c context.Context, id int, offset int64, write func(asset models.MediaAsset) (int64, error)) (models.MediaAsset, error) {
+	l := logger.GetLogger()
+	tx, err := r.db.Begin(c)
+	if err != nil {
+		l.Error(err.Error())
+		return models.MediaAsset{}, err
+	}
+
+	defer func() {
+		if err != nil {
+			tx.Rollback(c) // Если ошибка, откатываем транзакцию
+		}
+	}()
+
+	asset, err := scanMediaAsset(tx.QueryRow(c, "select "+mediaAssetColumns+" from media_assets where id = $1 for update nowait", id))
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) && pgErr.Code == lockNotAvailable {
+		err = ErrUploadBusy
+		return models.MediaAsset{}, err
+	}
+	if err != nil {
+		return models.MediaAsset{}, err
+	}
+
+	if asset.Status != models.MediaStatusUploading {
+		err = ErrUploadFinished
+		return asset, err
+	}
+	if asset.UploadedBytes != offset {
+		err = ErrUploadOffset
+		return asset, err
+	}
+
+	written, err := write(asset)
+	if err != nil {
+		return models.MediaAsset{}, err
+	}
+
+	row := tx.QueryRow(c,
+		`
+	update media_assets
+	set uploaded_bytes = $2,
+		status = case when $2 = size then $3 else status end,
+		updated_at = now()
+	where id = $1
+	returning `+mediaAssetColumns,
+		id,
+		offset+written,
+		models.MediaStatusQueued)
+
+	asset, err = scanMediaAsset(row)
+	if err != nil {
+		l.Error(err.Error())
+		return models.MediaAsset{}, err
+	}
+
+	err = tx.Commit(c)
+	if err != nil {
+		l.Error(err.Error())
+		return models.MediaAsset{}, err
+	}
+
+	return asset, nil
+}
+
+// ClaimNext забирает в обработку самое старое видео из очереди; если очередь пуста — pgx.ErrNoRows
+func (r *MediaAssetsRepository) ClaimNext(c context.Context) (models.MediaAsset, error) {
+	row := r.db.QueryRow(c,
+		`
+	update media_assets
+	set status = $1, error = null, updated_at = now()
+	where id = (
+		select id from media_assets
+		where status = $2
+		order by id
+		limit 1
+		for update skip locked
+	)
+	returning `+mediaAssetColumns,
+		models.MediaStatusProcessing,
+		models.MediaStatusQueued)
+
+	return scanMediaAsset(row)
+}
+
+func (r *MediaAssetsRepository) MarkReady(c context.Context, id int, renditions []string, playbackUrl string) error {
+	_, err := r.db.Exec(c,
+		"update media_assets set status = $1, renditions = $2, playback_url = $3, error = null, updated_at = now(), ready_at = now() where id = $4",
+		models.MediaStatusReady,
+		renditions,
+		playbackUrl,
+		id)
+	if err != nil {
+		l := logger.GetLogger()
+		l.Error(err.Error())
+	}
+
+	return err
+}
+
+func (r *MediaAssetsRepository) MarkFailed(c context.Context, id int, message string) error {
+	_, err := r.db.Exec(c,
+		"update media_assets set status = $1, error = $2, updated_at = now() where id = $3",
+		models.MediaStatusFailed,
+		message,
+		id)
+	if err != nil {
+		l := logger.GetLogger()
+		l.Error(err.Error())
+	}
+
+	return err
+}
+
+// RequeueInterrupted возвращает в очередь видео, обработку которых прервал перезапуск сервера;
+// загруженный файл остаётся на диске, поэтому их можно нарезать заново
+func (r *MediaAssetsRepository) RequeueInterrupted(c context.Context) (int64, error) {
+	tag, err := r.db.Exec(c,
+		"update media_assets set status = $1, updated_at = now() where status = $2",
+		models.MediaStatusQueued,
+		models.MediaStatusProcessing)
+	if err != nil {
+		l := logger.GetLogger()
+		l.Error(err.Error())
+		return 0, err
+	}
+
+	return tag.RowsAffected(), nil
+}
+
+func (r *MediaAssetsRepository) Delete(c context.Context, id int) error {
+	_, err := r.db.Exec(c, "delete from media_assets where id = $1", id)
+	if err != nil {
+		l := logger.GetLogger()
+		l.Error(err.Error())
+	}
+
+	return err
+}
+
+func scanMediaAsset(row pgx.Row) (models.MediaAsset, error) {
+	var asset models.MediaAsset
+	err := row.Scan(
+		&asset.Id,
+		&asset.MovieId,
+		&asset.AllSeriesId,
+		&asset.Status,
+		&asset.Filename,
+		&asset.Size,
+		&asset.UploadedBytes,
+		&asset.Renditions,
+		&asset.PlaybackUrl,
+		&asset.Error,
+		&asset.CreatedBy,
+		&asset.CreatedAt,
+		&asset.UpdatedAt,
+		&asset.ReadyAt)
+
+	return asset, err
+}
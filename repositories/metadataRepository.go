@@ -0,0 +1,265 @@
+package repositories
+
+import (
+	"context"
+	"goozinshe/logger"
+	"goozinshe/models"
+	"strings"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+var metadataTitleColumns = []string{"source", "external_id", "title_type", "title", "original_title", "year", "directors", "genres", "description"}
+
+type MetadataRepository struct {
+	db *pgxpool.Pool
+}
+
+func NewMetadataRepository(conn *pgxpool.Pool) *MetadataRepository {
+	return &MetadataRepository{db: conn}
+}
+
+// ReplaceImdb перестраивает индекс IMDb из трёх дампов. Режиссёры из title.crew
+// сопоставляются с именами из name.basics уже в базе, чтобы не держать миллионы строк в памяти.
+func (r *MetadataRepository) ReplaceImdb(c context.Context, basics pgx.CopyFromSource, crew pgx.CopyFromSource, names pgx.CopyFromSource) (int64, error) {
+	l := logger.GetLogger()
+
+	tx, err := r.db.Begin(c)
+	if err != nil {
+		l.Error(err.Error())
+		return 0, err
+	}
+
+	defer func() {
+		if err != nil {
+			tx.Rollback(c) // Если ошибка, откатываем транзакцию
+		}
+	}()
+
+	_, err = tx.Exec(c, `
+	create temp table imdb_basics(tconst text, title_type text, title text, original_title text, year int, genres text[]) on commit drop;
+	create temp table imdb_crew(tconst text, directors text[]) on commit drop;
+	create temp table imdb_names(nconst text, name text) on commit drop;
+	`)
+	if err != nil {
+		l.Error(err.Error())
+		return 0, err
+	}
+
+	_, err = tx.CopyFrom(c, pgx.Identifier{"imdb_basics"}, []string{"tconst", "title_type", "title", "original_title", "year", "genres"}, basics)
+	if err != nil {
+		l.Error(err.Error())
+		return 0, err
+	}
+
+	_, err = tx.CopyFrom(c, pgx.Identifier{"imdb_crew"}, []string{"tconst", "directors"}, crew)
+	if err != nil {
+		l.Error(err.Error())
+		return 0, err
+	}
+
+	_, err = tx.CopyFrom(c, pgx.Identifier{"imdb_names"}, []string{"nconst", "name"}, names)
+	if err != nil {
+		l.Error(err.Error())
+		return 0, err
+	}
+
+	_, err = tx.Exec(c, `
+	create index on imdb_crew(tconst);
+	create index on imdb_names(nconst);
+	analyze imdb_basics;
+	analyze imdb_crew;
+	analyze imdb_names;
+	`)
+	if err != nil {
+		l.Error(err.Error())
+		return 0, err
+	}
+
+	_, err = tx.Exec(c, "delete from metadata_titles where source = $1", models.MetadataSourceImdb)
+	if err != nil {
+		l.Error(err.Error())
+		return 0, err
+	}
+
+	tag, err := tx.Exec(c,
+		`
+	insert into metadata_titles(source, external_id, title_type, title, original_title, year, directors, genres, description)
+	select
+		$1,
+		b.tconst,
+		b.title_type,
+		b.title,
+		b.original_title,
+		b.year,
+		coalesce((
+			select array_agg(n.name order by d.ord)
+			from unnest(cr.directors) with ordinality d(nconst, ord)
+			join imdb_names n on n.nconst = d.nconst
+		), '{}'),
+		b.genres,
+		''
+	from imdb_basics b
+	left join imdb_crew cr on cr.tconst = b.tconst
+	`, models.MetadataSourceImdb)
+	if err != nil {
+		l.Error(err.Error())
+		return 0, err
+	}
+
+	err = tx.Commit(c)
+	if err != nil {
+		l.Error(err.Error())
+		return 0, err
+	}
+
+	return tag.RowsAffected(), nil
+}
+
+// ReplaceTmdb перестраивает индекс TMDb: в дампе уже есть всё, поэтому строки копируются напрямую
+func (r *MetadataRepository) ReplaceTmdb(c context.Context, titles pgx.CopyFromSource) (int64, error) {
+	l := logger.GetLogger()
+
+	tx, err := r.db.Begin(c)
+	if err != nil {
+		l.Error(err.Error())
+		return 0, err
+	}
+
+	defer func() {
+		if err != nil {
+			tx.Rollback(c) // Если ошибка, откатываем транзакцию
+		}
+	}()
+
+	_, err = tx.Exec(c, "delete from metadata_titles where source = $1", models.MetadataSourceTmdb)
+	if err != nil {
+		l.Error(err.Error())
+		return 0, err
+	}
+
+	count, err := tx.CopyFrom(c, pgx.Identifier{"metadata_titles"}, metadataTitleColumns, titles)
+	if err != nil {
+		l.Error(err.Error())
+		return 0, err
+	}
+
+	err = tx.Commit(c)
+	if err != nil {
+		l.Error(err.Error())
+		return 0, err
+	}
+
+	return count, nil
+}
+
+// Search ищет по началу названия или оригинального названия, точные совпадения идут первыми
+func (r *MetadataRepository) Search(c context.Context, q string, year int, limit int) ([]models.MetadataTitle, error) {
+	sql := `
+	select source, external_id, title_type, title, original_title, year, directors, genres, description
+	from metadata_titles
+	where (lower(title) like @prefix or lower(original_title) like @prefix)
+	`
+
+	params := pgx.NamedArgs{
+		"q":      strings.ToLower(q),
+		"prefix": escapeLike(strings.ToLower(q)) + "%",
+		"limit":  limit,
+	}
+
+	if year != 0 {
+		sql += " and year = @year"
+		params["year"] = year
+	}
+
+	sql += " order by lower(title) = @q desc, year desc nulls last limit @limit"
+
+	l := logger.GetLogger()
+	rows, err := r.db.Query(c, sql, params)
+	if err != nil {
+		l.Error(err.Error())
+		return nil, err
+	}
+	defer rows.Close()
+
+	titles := make([]models.MetadataTitle, 0)
+	for rows.Next() {
+		title, err := scanMetadataTitle(rows)
+		if err != nil {
+			l.Error(err.Error())
+			return nil, err
+		}
+		titles = append(titles, title)
+	}
+
+	return titles, rows.Err()
+}
+
+func (r *MetadataRepository) FindByExternalId(c context.Context, source string, externalId string) (models.MetadataTitle, error) {
+	row := r.db.QueryRow(c,
+		`
+	select source, external_id, title_type, title, original_title, year, directors, genres, description
+	from metadata_titles
+	where source = $1 and external_id = $2
+	`, source, externalId)
+
+	return scanMetadataTitle(row)
+}
+
+func (r *MetadataRepository) FindGenreMappings(c context.Context) ([]models.MetadataGenreMapping, error) {
+	l := logger.GetLogger()
+	rows, err := r.db.Query(c, "select source_genre, genre_id from metadata_genre_mappings order by source_genre")
+	if err != nil {
+		l.Error(err.Error())
+		return nil, err
+	}
+	defer rows.Close()
+
+	mappings := make([]models.MetadataGenreMapping, 0)
+	for rows.Next() {
+		var mapping models.MetadataGenreMapping
+		err := rows.Scan(&mapping.SourceGenre, &mapping.GenreId)
+		if err != nil {
+			l.Error(err.Error())
+			return nil, err
+		}
+		mappings = append(mappings, mapping)
+	}
+
+	return mappings, rows.Err()
+}
+
+func (r *MetadataRepository) SetGenreMapping(c context.Context, mapping models.MetadataGenreMapping) error {
+	_, err := r.db.Exec(c,
+		`
+	insert into metadata_genre_mappings(source_genre, genre_id) values(lower($1), $2)
+	on conflict (source_genre) do update set genre_id = excluded.genre_id
+	`, mapping.SourceGenre, mapping.GenreId)
+	if err != nil {
+		l := logger.GetLogger()
+		l.Error(err.Error())
+	}
+
+	return err
+}
+
+func scanMetadataTitle(row pgx.Row) (models.MetadataTitle, error) {
+	var title models.MetadataTitle
+	err := row.Scan(
+		&title.Source,
+		&title.ExternalId,
+		&title.TitleType,
+		&title.Title,
+		&title.OriginalTitle,
+		&title.Year,
+		&title.Directors,
+		&title.Genres,
+		&title.Description)
+
+	return title, err
+}
+
+func escapeLike(value string) string {
+	return strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`).Replace(value)
+}
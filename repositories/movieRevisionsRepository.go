@@ -0,0 +1,110 @@
+package repositories
+
+import (
+	"context"
+	"goozinshe/logger"
+	"goozinshe/models"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type MovieRevisionsRepository struct {
+	db *pgxpool.Pool
+}
+
+func NewMovieRevisionsRepository(conn *pgxpool.Pool) *MovieRevisionsRepository {
+	return &MovieRevisionsRepository{db: conn}
+}
+
+// insertMovieRevision сохраняет снимок фильма под следующим по счёту номером ревизии.
+// Вызывается в транзакции изменения фильма после update с проверкой версии: строка фильма уже заблокирована,
+// поэтому при конфликте версий ревизия не появляется, а параллельные сохранения не получают один номер
+func insertMovieRevision(c context.Context, tx pgx.Tx, movieId int, revision models.MovieRevision) error {
+	_, err := tx.Exec(c,
+		`
+	insert into movie_revisions(movie_id, revision, snapshot, created_by)
+	select $1, coalesce(max(revision), 0) + 1, $2, $3
+	from movie_revisions
+	where movie_id = $1
+	`,
+		movieId,
+		revision.Snapshot,
+		revision.CreatedBy)
+	if err != nil {
+		l := logger.GetLogger()
+		l.Error(err.Error())
+		return err
+	}
+
+	return nil
+}
+
+func (r *MovieRevisionsRepository) FindAllByMovie(c context.Context, movieId int) ([]models.MovieRevision, error) {
+	l := logger.GetLogger()
+	rows, err := r.db.Query(c,
+		`
+	select id, movie_id, revision, snapshot, created_by, created_at
+	from movie_revisions
+	where movie_id = $1
+	order by revision desc
+	`,
+		movieId)
+	if err != nil {
+		l.Error(err.Error())
+		return nil, err
+	}
+	defer rows.Close()
+
+	revisions := make([]models.MovieRevision, 0)
+	for rows.Next() {
+		var revision models.MovieRevision
+		err := rows.Scan(
+			&revision.Id,
+			&revision.MovieId,
+			&revision.Revision,
+			&revision.Snapshot,
+			&revision.CreatedBy,
+			&revision.CreatedAt,
+		)
+		if err != nil {
+			l.Error(err.Error())
+			return nil, err
+		}
+
+		revisions = append(revisions, revision)
+	}
+
+	err = rows.Err()
+	if err != nil {
+		l.Error(err.Error())
+		return nil, err
+	}
+
+	return revisions, nil
+}
+
+func (r *MovieRevisionsRepository) FindByRevision(c context.Context, movieId int, revision int) (models.MovieRevision, error) {
+	var result models.MovieRevision
+	row := r.db.QueryRow(c,
+		`
+	select id, movie_id, revision, snapshot, created_by, created_at
+	from movie_revisions
+	where movie_id = $1 and revision = $2
+	`,
+		movieId,
+		revision)
+	err := row.Scan(
+		&result.Id,
+		&result.MovieId,
+		&result.Revision,
+		&result.Snapshot,
+		&result.CreatedBy,
+		&result.CreatedAt,
+	)
+	if err != nil {
+		return models.MovieRevision{}, err
+	}
+
+	return result, nil
+}
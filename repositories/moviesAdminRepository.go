@@ -6,6 +6,7 @@ import (
 	"goozinshe/logger"
 	"goozinshe/models"
 	"strconv"
+	"time"
 
 	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgxpool"
@@ -33,6 +34,9 @@ SELECT
         m.is_watched,
         m.trailer_url,
         m.poster_url,
+        m.status,
+        m.publish_at,
+        m.version,
         g.id,
         g.title,
         g.poster_url,
@@ -41,6 +45,7 @@ SELECT
         c.poster_url,
         a.id,
         a.age,
+        a.min_age,
         a.poster_url,
         e.id, 
         e.series, 
@@ -52,14 +57,14 @@ SELECT
         e.trailer_url
     FROM movies m
     JOIN movies_genres mg ON mg.movie_id = m.id
-    JOIN genres g ON mg.genre_id = g.id
+    JOIN genres g ON mg.genre_id = g.id and g.deleted_at is null
     JOIN movies_categories mc ON mc.movie_id = m.id
-    JOIN categories c ON mc.categorie_id = c.id
+    JOIN categories c ON mc.categorie_id = c.id and c.deleted_at is null
     JOIN movies_ages ma ON ma.movie_id = m.id
-	JOIN ages a ON ma.age_id = a.id
+	JOIN ages a ON ma.age_id = a.id and a.deleted_at is null
     left JOIN movies_allseries me ON me.movie_id = m.id
-    left JOIN allseries e ON me.allserie_id = e.id
-where m.id = $1
+    left JOIN allseries e ON me.allserie_id = e.id and e.deleted_at is null
+where m.id = $1 and m.deleted_at is null
 	`
 
 	logger := logger.GetLogger()
@@ -101,6 +106,9 @@ where m.id = $1
 			&m.IsWatched,
 			&m.TrailerUrl,
 			&m.PosterUrl,
+			&m.Status,
+			&m.PublishAt,
+			&m.Version,
 			&g.Id,
 			&g.Title,
 			&g.PosterUrl,
@@ -109,6 +117,7 @@ where m.id = $1
 			&c.PosterUrl,
 			&a.Id,
 			&a.Age,
+			&a.MinAge,
 			&a.PosterUrl,
 			&e.Id,
 			&e.Series,
@@ -122,6 +131,8 @@ where m.id = $1
 		if err != nil {
 			return models.MovieAdminResponse{}, err
 		}
+		m.Trailer = models.TrailerFromUrl(m.TrailerUrl)
+		e.Trailer = episodeTrailer(e.TrailerUrl)
 
 		if movie == nil {
 			movie = &m
@@ -154,6 +165,9 @@ where m.id = $1
 	if err != nil {
 		return models.MovieAdminResponse{}, err
 	}
+	if movie == nil {
+		return models.MovieAdminResponse{}, pgx.ErrNoRows
+	}
 
 	var categories []models.Category
 	for _, cat := range category {
@@ -180,10 +194,32 @@ where m.id = $1
 	movie.Ages = ages
 	movie.AllSeries = allseries
 
+	movie.Credits, err = findMovieCredits(c, r.db, id)
+	if err != nil {
+		logger.Error("Could not query credits", zap.String("db_msg", err.Error()))
+		return models.MovieAdminResponse{}, err
+	}
+
 	return *movie, nil
 }
 
 func (r *MoviesAdminRepository) FindAll(c context.Context, filters models.MovieFilters) ([]models.Movie, error) {
+	movies := make([]models.Movie, 0)
+
+	err := r.Stream(c, filters, func(movie models.Movie) error {
+		movies = append(movies, movie)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return movies, nil
+}
+
+// Stream отдаёт фильмы по одному, не собирая весь каталог в памяти.
+// Строки одного фильма идут подряд благодаря сортировке по m.id.
+func (r *MoviesAdminRepository) Stream(c context.Context, filters models.MovieFilters, fn func(models.Movie) error) error {
 	sql := ` 
     SELECT 
         m.id,
@@ -195,6 +231,9 @@ func (r *MoviesAdminRepository) FindAll(c context.Context, filters models.MovieF
         m.is_watched,
         m.trailer_url,
         m.poster_url,
+        m.status,
+        m.publish_at,
+        m.version,
         g.id,
         g.title,
         g.poster_url,
@@ -203,6 +242,7 @@ func (r *MoviesAdminRepository) FindAll(c context.Context, filters models.MovieF
         c.poster_url,
         a.id,
         a.age,
+        a.min_age,
         a.poster_url,
         e.id, 
         e.series, 
@@ -214,13 +254,14 @@ func (r *MoviesAdminRepository) FindAll(c context.Context, filters models.MovieF
         e.trailer_url
     FROM movies m
     JOIN movies_genres mg ON mg.movie_id = m.id
-    JOIN genres g ON mg.genre_id = g.id
+    JOIN genres g ON mg.genre_id = g.id and g.deleted_at is null
     JOIN movies_categories mc ON mc.movie_id = m.id
-    JOIN categories c ON mc.categorie_id = c.id
+    JOIN categories c ON mc.categorie_id = c.id and c.deleted_at is null
     JOIN movies_ages ma ON ma.movie_id = m.id
-    JOIN ages a ON ma.age_id = a.id
+    JOIN ages a ON ma.age_id = a.id and a.deleted_at is null
     left JOIN movies_allseries me ON me.movie_id = m.id
-    left JOIN allseries e ON me.allserie_id = e.id
+    left JOIN allseries e ON me.allserie_id = e.id and e.deleted_at is null
+    where m.deleted_at is null
     `
 
 	params := pgx.NamedArgs{}
@@ -240,22 +281,30 @@ func (r *MoviesAdminRepository) FindAll(c context.Context, filters models.MovieF
 		sql = fmt.Sprintf("%s and m.is_watched = @isWatched", sql)
 		params["isWatched"] = isWatched
 	}
+	if filters.Status != "" {
+		sql = fmt.Sprintf("%s and m.status = @status", sql)
+		params["status"] = filters.Status
+	}
+	if filters.PersonId != nil {
+		sql = fmt.Sprintf("%s and exists (select 1 from movie_credits cr where cr.movie_id = m.id and cr.person_id = @personId)", sql)
+		params["personId"] = *filters.PersonId
+	}
 	if filters.Sort != "" {
 		identifier := pgx.Identifier{filters.Sort}
-		sql = fmt.Sprintf("%s order by m.%s", sql, identifier.Sanitize())
+		sql = fmt.Sprintf("%s order by m.%s, m.id", sql, identifier.Sanitize())
+	} else {
+		sql = fmt.Sprintf("%s order by m.id", sql)
 	}
 
 	l := logger.GetLogger()
-	rows, err := r.db.Query(c, sql)
+	rows, err := r.db.Query(c, sql, params)
 	if err != nil {
 		l.Error(err.Error())
-		return nil, err
+		return err
 	}
+	defer rows.Close()
 
-	movies := make([]*models.Movie, 0)
-	moviesMap := make(map[int]*models.Movie)
-
-	allseriesMap := make(map[int]*models.AllSeries, 0)
+	var current *models.Movie
 
 	for rows.Next() {
 		var m models.Movie
@@ -274,6 +323,9 @@ func (r *MoviesAdminRepository) FindAll(c context.Context, filters models.MovieF
 			&m.IsWatched,
 			&m.TrailerUrl,
 			&m.PosterUrl,
+			&m.Status,
+			&m.PublishAt,
+			&m.Version,
 			&g.Id,
 			&g.Title,
 			&g.PosterUrl,
@@ -282,6 +334,7 @@ func (r *MoviesAdminRepository) FindAll(c context.Context, filters models.MovieF
 			&c.PosterUrl,
 			&a.Id,
 			&a.Age,
+			&a.MinAge,
 			&a.PosterUrl,
 			&e.Id,
 			&e.Series,
@@ -293,55 +346,68 @@ func (r *MoviesAdminRepository) FindAll(c context.Context, filters models.MovieF
 			&e.TrailerUrl,
 		)
 		if err != nil {
-			return nil, err
+			return err
 		}
-
-		if _, exists := moviesMap[m.Id]; !exists {
-			moviesMap[m.Id] = &m
-			movies = append(movies, &m)
+		m.Trailer = models.TrailerFromUrl(m.TrailerUrl)
+		e.Trailer = episodeTrailer(e.TrailerUrl)
+
+		if current == nil || current.Id != m.Id {
+			if current != nil {
+				err = fn(*current)
+				if err != nil {
+					return err
+				}
+			}
+			current = &m
 		}
 
 		// Обработка жанров
 		genreExists := false
-		for _, existingGenres := range moviesMap[m.Id].Genres {
+		for _, existingGenres := range current.Genres {
 			if existingGenres.Id == g.Id {
 				genreExists = true
 				break
 			}
 		}
 		if !genreExists {
-			moviesMap[m.Id].Genres = append(moviesMap[m.Id].Genres, g)
+			current.Genres = append(current.Genres, g)
 		}
 
 		// Обработка категорий
 		categoryExists := false
-		for _, existingCategory := range moviesMap[m.Id].Category {
+		for _, existingCategory := range current.Category {
 			if existingCategory.Id == c.Id {
 				categoryExists = true
 				break
 			}
 		}
 		if !categoryExists {
-			moviesMap[m.Id].Category = append(moviesMap[m.Id].Category, c)
+			current.Category = append(current.Category, c)
 		}
 
 		// Обработка возрастных категорий
 		ageExists := false
-		for _, existingAge := range moviesMap[m.Id].Ages {
+		for _, existingAge := range current.Ages {
 			if existingAge.Id == a.Id {
 				ageExists = true
 				break
 			}
 		}
 		if !ageExists {
-			moviesMap[m.Id].Ages = append(moviesMap[m.Id].Ages, a)
+			current.Ages = append(current.Ages, a)
 		}
-		// Обработка сериалов
 
+		// Обработка сериалов
 		if e.Id != nil {
-			if _, exists := allseriesMap[*e.Id]; !exists {
-				allseriesMap[*e.Id] = &e
-				moviesMap[m.Id].AllSeries = append(moviesMap[m.Id].AllSeries, e)
+			allserieExists := false
+			for _, existingAllserie := range current.AllSeries {
+				if *existingAllserie.Id == *e.Id {
+					allserieExists = true
+					break
+				}
+			}
+			if !allserieExists {
+				current.AllSeries = append(current.AllSeries, e)
 			}
 		}
 	}
@@ -349,15 +415,14 @@ func (r *MoviesAdminRepository) FindAll(c context.Context, filters models.MovieF
 	err = rows.Err()
 	if err != nil {
 		l.Error(err.Error())
-		return nil, err
+		return err
 	}
 
-	concreteMovies := make([]models.Movie, 0, len(movies))
-	for _, v := range movies {
-		concreteMovies = append(concreteMovies, *v)
+	if current != nil {
+		return fn(*current)
 	}
 
-	return concreteMovies, nil
+	return nil
 }
 
 // allseriesExists := false
@@ -448,7 +513,9 @@ func (r *MoviesAdminRepository) Create(c context.Context, movie models.MovieAdmi
 	return id, nil
 }
 
-func (r *MoviesAdminRepository) Update(c context.Context, id int, updatedMovie models.MovieAdminResponse) error {
+// Update применяется, только если версия фильма совпадает с updatedMovie.Version.
+// В той же транзакции сохраняется revision — состояние фильма до изменения
+func (r *MoviesAdminRepository) Update(c context.Context, id int, updatedMovie models.MovieAdminResponse, revision models.MovieRevision) error {
 	l := logger.GetLogger()
 	tx, err := r.db.Begin(c)
 	if err != nil {
@@ -462,7 +529,7 @@ func (r *MoviesAdminRepository) Update(c context.Context, id int, updatedMovie m
 		}
 	}()
 
-	_, err = tx.Exec(
+	tag, err := tx.Exec(
 		c,
 		`
         update movies
@@ -472,8 +539,9 @@ func (r *MoviesAdminRepository) Update(c context.Context, id int, updatedMovie m
             release_year = $3,
             director = $4,
             trailer_url = $5,
-            poster_url = $6
-        where id = $7
+            poster_url = $6,
+            version = version + 1
+        where id = $7 and version = $8 and deleted_at is null
         `,
 		updatedMovie.Title,
 		updatedMovie.Description,
@@ -481,13 +549,24 @@ func (r *MoviesAdminRepository) Update(c context.Context, id int, updatedMovie m
 		updatedMovie.Director,
 		updatedMovie.TrailerUrl,
 		updatedMovie.PosterUrl,
-		id)
+		id,
+		updatedMovie.Version)
 
 	if err != nil {
 		l.Error(err.Error())
 		return err
 	}
 
+	err = checkVersion(tag)
+	if err != nil {
+		return err
+	}
+
+	err = insertMovieRevision(c, tx, id, revision)
+	if err != nil {
+		return err
+	}
+
 	_, err = tx.Exec(c, "DELETE FROM movies_genres WHERE movie_id = $1", id)
 	if err != nil {
 		l.Error(err.Error())
@@ -539,77 +618,74 @@ func (r *MoviesAdminRepository) Update(c context.Context, id int, updatedMovie m
 	return nil
 }
 
-func (r *MoviesAdminRepository) Delete(c context.Context, id int) error {
+func (r *MoviesAdminRepository) Delete(c context.Context, id int, version int) error {
 	l := logger.GetLogger()
-	tx, err := r.db.Begin(c)
-	if err != nil {
-		l.Error(err.Error())
-		return err
-	}
 
 	var movieTitle string
-	row := r.db.QueryRow(c, "select title from movies where id = $1", id)
-	err = row.Scan(&movieTitle)
+	row := r.db.QueryRow(c, "select title from movies where id = $1 and deleted_at is null", id)
+	err := row.Scan(&movieTitle)
 	if err != nil {
 		return err
 	}
 
 	l.Warn(fmt.Sprintf("Вы действительно хотите удалить %s?", movieTitle))
 
-	_, err = tx.Exec(c, "DELETE FROM movies_genres WHERE movie_id = $1", id)
+	// связи с жанрами, категориями и сериями остаются, чтобы фильм можно было восстановить из корзины
+	tag, err := r.db.Exec(c, "update movies set deleted_at = now(), version = version + 1 where id = $1 and version = $2", id, version)
 	if err != nil {
 		l.Error(err.Error())
-		tx.Rollback(c)
 		return err
 	}
 
-	_, err = tx.Exec(c, "DELETE FROM movies_categories WHERE movie_id = $1", id)
+	err = checkVersion(tag)
 	if err != nil {
-		l.Error(err.Error())
-		tx.Rollback(c)
 		return err
 	}
 
-	_, err = tx.Exec(c, "DELETE FROM movies_ages WHERE movie_id = $1", id)
-	if err != nil {
-		l.Error(err.Error())
-		tx.Rollback(c)
-		return err
-	}
+	l.Info(fmt.Sprintf("Фильм %s перемещен в корзину", movieTitle))
 
-	_, err = tx.Exec(c, "DELETE FROM movies_allseries WHERE movie_id = $1", id)
-	if err != nil {
-		l.Error(err.Error())
-		tx.Rollback(c)
-		return err
-	}
+	return nil
+}
 
-	_, err = tx.Exec(c, "DELETE FROM movies WHERE id = $1", id)
+func (r *MoviesAdminRepository) SetWatched(c context.Context, id int, isWatched bool, version int) error {
+	tag, err := r.db.Exec(c,
+		"update movies set is_watched = $1, version = version + 1 where id = $2 and version = $3 and deleted_at is null",
+		isWatched, id, version)
 	if err != nil {
+		l := logger.GetLogger()
 		l.Error(err.Error())
-		tx.Rollback(c)
 		return err
 	}
 
-	l.Info(fmt.Sprintf("Фильм %s удален:", movieTitle))
+	return checkVersion(tag)
+}
 
-	// Фиксируем транзакцию
-	err = tx.Commit(c)
+func (r *MoviesAdminRepository) SetStatus(c context.Context, id int, status string, publishAt *time.Time, version int) error {
+	tag, err := r.db.Exec(c,
+		"update movies set status = $1, publish_at = $2, version = version + 1 where id = $3 and version = $4 and deleted_at is null",
+		status, publishAt, id, version)
 	if err != nil {
+		l := logger.GetLogger()
 		l.Error(err.Error())
 		return err
 	}
 
-	return nil
+	return checkVersion(tag)
 }
 
-func (r *MoviesAdminRepository) SetWatched(c context.Context, id int, isWatched bool) error {
-	_, err := r.db.Exec(c, "update movies set is_watched = $1 where id = $2", isWatched, id)
+// PublishScheduled публикует фильмы, у которых наступило время publish_at
+func (r *MoviesAdminRepository) PublishScheduled(c context.Context) (int64, error) {
+	tag, err := r.db.Exec(c,
+		`
+	update movies
+	set status = 'published', version = version + 1
+	where status in ('draft', 'in_review') and publish_at <= now() and deleted_at is null
+	`)
 	if err != nil {
 		l := logger.GetLogger()
 		l.Error(err.Error())
-		return err
+		return 0, err
 	}
 
-	return nil
+	return tag.RowsAffected(), nil
 }
@@ -2,6 +2,7 @@ package repositories
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"goozinshe/logger"
 	"goozinshe/models"
@@ -21,6 +22,11 @@ func NewMoviesRepository(conn *pgxpool.Pool) *MoviesRepository {
 }
 
 func (r *MoviesRepository) FindById(c context.Context, id int) (models.Movie, error) {
+	return r.FindByIdForViewer(c, id, nil, nil)
+}
+
+// FindByIdForViewer — фильм с отметками isFavorite и isWatched для пользователя userId и профиля profileId
+func (r *MoviesRepository) FindByIdForViewer(c context.Context, id int, userId *int, profileId *int) (models.Movie, error) {
 	sql :=
 		`
 SELECT 
@@ -30,9 +36,14 @@ SELECT
         m.release_year,
         m.director,
         m.rating,
-        m.is_watched,
+        ` + watchedCondition("$2", "$3") + `,
         m.trailer_url,
         m.poster_url,
+        m.status,
+        m.publish_at,
+        m.version,
+        fav.favorite_count,
+        fav.is_favorite,
         g.id,
         g.title,
         g.poster_url,
@@ -41,6 +52,7 @@ SELECT
         c.poster_url,
         a.id,
         a.age,
+        a.min_age,
         a.poster_url,
         e.id, 
         e.series, 
@@ -51,20 +63,26 @@ SELECT
         e.rating,         
         e.trailer_url
     FROM movies m
+    left join lateral (
+        select count(*) as favorite_count,
+            coalesce(bool_or(f.user_id = $2 and f.profile_id is not distinct from $3), false) as is_favorite
+        from favorites f
+        where f.movie_id = m.id
+    ) fav on true
     JOIN movies_genres mg ON mg.movie_id = m.id
-    JOIN genres g ON mg.genre_id = g.id
+    JOIN genres g ON mg.genre_id = g.id and g.deleted_at is null
     JOIN movies_categories mc ON mc.movie_id = m.id
-    JOIN categories c ON mc.categorie_id = c.id
+    JOIN categories c ON mc.categorie_id = c.id and c.deleted_at is null
     JOIN movies_ages ma ON ma.movie_id = m.id
-	JOIN ages a ON ma.age_id = a.id
+	JOIN ages a ON ma.age_id = a.id and a.deleted_at is null
     left JOIN movies_allseries me ON me.movie_id = m.id
-    left JOIN allseries e ON me.allserie_id = e.id
-where m.id = $1
+    left JOIN allseries e ON me.allserie_id = e.id and e.deleted_at is null
+where m.id = $1 and m.deleted_at is null and m.status = 'published'
 	`
 
 	logger := logger.GetLogger()
 
-	rows, err := r.db.Query(c, sql, id)
+	rows, err := r.db.Query(c, sql, id, userId, profileId)
 	defer rows.Close()
 	if err != nil {
 		logger.Error("Could not query database", zap.String("db_msg", err.Error()))
@@ -101,6 +119,11 @@ where m.id = $1
 			&m.IsWatched,
 			&m.TrailerUrl,
 			&m.PosterUrl,
+			&m.Status,
+			&m.PublishAt,
+			&m.Version,
+			&m.FavoriteCount,
+			&m.IsFavorite,
 			&g.Id,
 			&g.Title,
 			&g.PosterUrl,
@@ -109,6 +132,7 @@ where m.id = $1
 			&c.PosterUrl,
 			&a.Id,
 			&a.Age,
+			&a.MinAge,
 			&a.PosterUrl,
 			&e.Id,
 			&e.Series,
@@ -122,6 +146,8 @@ where m.id = $1
 		if err != nil {
 			return models.Movie{}, err
 		}
+		m.Trailer = models.TrailerFromUrl(m.TrailerUrl)
+		e.Trailer = episodeTrailer(e.TrailerUrl)
 
 		if movie == nil {
 			movie = &m
@@ -154,6 +180,9 @@ where m.id = $1
 	if err != nil {
 		return models.Movie{}, err
 	}
+	if movie == nil {
+		return models.Movie{}, pgx.ErrNoRows
+	}
 
 	var categories []models.Category
 	for _, cat := range category {
@@ -180,6 +209,12 @@ where m.id = $1
 	movie.Ages = ages
 	movie.AllSeries = allseries
 
+	movie.Credits, err = findMovieCredits(c, r.db, id)
+	if err != nil {
+		logger.Error("Could not query credits", zap.String("db_msg", err.Error()))
+		return models.Movie{}, err
+	}
+
 	return *movie, nil
 }
 
@@ -192,9 +227,14 @@ func (r *MoviesRepository) FindAll(c context.Context, filters models.MovieFilter
         m.release_year,
         m.director,
         m.rating,
-        m.is_watched,
+        ` + watchedCondition("@userId", "@profileId") + `,
         m.trailer_url,
         m.poster_url,
+        m.status,
+        m.publish_at,
+        m.version,
+        fav.favorite_count,
+        fav.is_favorite,
         g.id,
         g.title,
         g.poster_url,
@@ -203,6 +243,7 @@ func (r *MoviesRepository) FindAll(c context.Context, filters models.MovieFilter
         c.poster_url,
         a.id,
         a.age,
+        a.min_age,
         a.poster_url,
         e.id, 
         e.series, 
@@ -213,17 +254,27 @@ func (r *MoviesRepository) FindAll(c context.Context, filters models.MovieFilter
         e.rating,         
         e.trailer_url
     FROM movies m
+    left join lateral (
+        select count(*) as favorite_count,
+            coalesce(bool_or(f.user_id = @userId and f.profile_id is not distinct from @profileId), false) as is_favorite
+        from favorites f
+        where f.movie_id = m.id
+    ) fav on true
     JOIN movies_genres mg ON mg.movie_id = m.id
-    JOIN genres g ON mg.genre_id = g.id
+    JOIN genres g ON mg.genre_id = g.id and g.deleted_at is null
     JOIN movies_categories mc ON mc.movie_id = m.id
-    JOIN categories c ON mc.categorie_id = c.id
+    JOIN categories c ON mc.categorie_id = c.id and c.deleted_at is null
     JOIN movies_ages ma ON ma.movie_id = m.id
-    JOIN ages a ON ma.age_id = a.id
+    JOIN ages a ON ma.age_id = a.id and a.deleted_at is null
     left JOIN movies_allseries me ON me.movie_id = m.id
-    left JOIN allseries e ON me.allserie_id = e.id
+    left JOIN allseries e ON me.allserie_id = e.id and e.deleted_at is null
+    where m.deleted_at is null and m.status = 'published'
     `
 
-	params := pgx.NamedArgs{}
+	params := pgx.NamedArgs{
+		"userId":    filters.UserId,
+		"profileId": filters.ProfileId,
+	}
 
 	if filters.SearchTerm != "" {
 		// '%%%s%%' => '%поиск%'
@@ -237,9 +288,17 @@ func (r *MoviesRepository) FindAll(c context.Context, filters models.MovieFilter
 	if filters.IsWatched != "" {
 		isWatched, _ := strconv.ParseBool(filters.IsWatched)
 
-		sql = fmt.Sprintf("%s and m.is_watched = @isWatched", sql)
+		sql = fmt.Sprintf("%s and %s = @isWatched", sql, watchedCondition("@userId", "@profileId"))
 		params["isWatched"] = isWatched
 	}
+	if filters.PersonId != nil {
+		sql = fmt.Sprintf("%s and exists (select 1 from movie_credits cr where cr.movie_id = m.id and cr.person_id = @personId)", sql)
+		params["personId"] = *filters.PersonId
+	}
+	if filters.MaxAge != nil {
+		sql = fmt.Sprintf("%s and %s", sql, ageLimitCondition("@maxAge"))
+		params["maxAge"] = *filters.MaxAge
+	}
 	if filters.Sort != "" {
 		identifier := pgx.Identifier{filters.Sort}
 		sql = fmt.Sprintf("%s order by m.%s", sql, identifier.Sanitize())
@@ -274,6 +333,11 @@ func (r *MoviesRepository) FindAll(c context.Context, filters models.MovieFilter
 			&m.IsWatched,
 			&m.TrailerUrl,
 			&m.PosterUrl,
+			&m.Status,
+			&m.PublishAt,
+			&m.Version,
+			&m.FavoriteCount,
+			&m.IsFavorite,
 			&g.Id,
 			&g.Title,
 			&g.PosterUrl,
@@ -282,6 +346,7 @@ func (r *MoviesRepository) FindAll(c context.Context, filters models.MovieFilter
 			&c.PosterUrl,
 			&a.Id,
 			&a.Age,
+			&a.MinAge,
 			&a.PosterUrl,
 			&e.Id,
 			&e.Series,
@@ -295,6 +360,8 @@ func (r *MoviesRepository) FindAll(c context.Context, filters models.MovieFilter
 		if err != nil {
 			return nil, err
 		}
+		m.Trailer = models.TrailerFromUrl(m.TrailerUrl)
+		e.Trailer = episodeTrailer(e.TrailerUrl)
 
 		if _, exists := moviesMap[m.Id]; !exists {
 			moviesMap[m.Id] = &m
@@ -437,7 +504,106 @@ func (r *MoviesRepository) Create(c context.Context, movie models.Movie) (int, e
 	return id, nil
 }
 
-func (r *MoviesRepository) Update(c context.Context, id int, updatedMovie models.Movie) error {
+// ErrMovieInTrash — фильм с таким внешним ключом лежит в корзине, импорт его не воскрешает
+var ErrMovieInTrash = errors.New("movie with this external key is in trash")
+
+// FindIdByExternalKey ищет фильм по внешнему ключу импорта: pgx.ErrNoRows — такого нет, ErrMovieInTrash — он в корзине
+func (r *MoviesRepository) FindIdByExternalKey(c context.Context, externalKey string) (int, error) {
+	var id int
+	var deleted bool
+	row := r.db.QueryRow(c, "select id, deleted_at is not null from movies where external_key = $1", externalKey)
+	err := row.Scan(&id, &deleted)
+	if err != nil {
+		return 0, err
+	}
+
+	if deleted {
+		return 0, ErrMovieInTrash
+	}
+
+	return id, nil
+}
+
+// CreateWithExternalKey создаёт фильм из импорта черновиком (draft).
+// Если фильм с таким ключом успели создать параллельно, возвращает ErrVersionConflict
+func (r *MoviesRepository) CreateWithExternalKey(c context.Context, externalKey string, movie models.Movie) (int, error) {
+	l := logger.GetLogger()
+	var id int
+
+	tx, err := r.db.Begin(c)
+	if err != nil {
+		l.Error(err.Error())
+		return 0, err
+	}
+
+	defer func() {
+		if err != nil {
+			tx.Rollback(c) // Если ошибка, откатываем транзакцию
+		}
+	}()
+
+	row := tx.QueryRow(c,
+		`
+    insert into movies(external_key, title, description, release_year, director, trailer_url, poster_url, status)
+    values($1, $2, $3, $4, $5, $6, $7, $8)
+    on conflict (external_key) do nothing
+    returning id
+    `,
+		externalKey,
+		movie.Title,
+		movie.Description,
+		movie.ReleaseYear,
+		movie.Director,
+		movie.TrailerUrl,
+		movie.PosterUrl,
+		models.MovieStatusDraft)
+
+	err = row.Scan(&id)
+	if err == pgx.ErrNoRows {
+		err = ErrVersionConflict
+		return 0, err
+	}
+	if err != nil {
+		l.Error(err.Error())
+		return 0, err
+	}
+
+	for _, genre := range movie.Genres {
+		_, err = tx.Exec(c, "insert into movies_genres(movie_id, genre_id) values($1, $2)", id, genre.Id)
+		if err != nil {
+			l.Error(err.Error())
+			return 0, err
+		}
+	}
+
+	for _, category := range movie.Category {
+		_, err = tx.Exec(c, "insert into movies_categories(movie_id, categorie_id) values($1, $2)", id, category.Id)
+		if err != nil {
+			l.Error(err.Error())
+			return 0, err
+		}
+	}
+
+	for _, age := range movie.Ages {
+		_, err = tx.Exec(c, "insert into movies_ages(movie_id, age_id) values($1, $2)", id, age.Id)
+		if err != nil {
+			l.Error(err.Error())
+			return 0, err
+		}
+	}
+
+	err = tx.Commit(c)
+	if err != nil {
+		l.Error(err.Error())
+		return 0, err
+	}
+
+	return id, nil
+}
+
+// Update применяется, только если версия фильма совпадает с updatedMovie.Version.
+// В той же транзакции сохраняется revision — состояние фильма до изменения
+func (r *MoviesRepository) Update(c context.Context, id int, updatedMovie models.Movie, revision models.MovieRevision) error {
 	l := logger.GetLogger()
 	tx, err := r.db.Begin(c)
 	if err != nil {
@@ -451,7 +617,7 @@ func (r *MoviesRepository) Update(c context.Context, id int, updatedMovie models
 		}
 	}()
 
-	_, err = tx.Exec(
+	tag, err := tx.Exec(
 		c,
 		`
         update movies
@@ -461,8 +627,9 @@ func (r *MoviesRepository) Update(c context.Context, id int, updatedMovie models
             release_year = $3,
             director = $4,
             trailer_url = $5,
-            poster_url = $6
-        where id = $7
+            poster_url = $6,
+            version = version + 1
+        where id = $7 and version = $8 and deleted_at is null
         `,
 		updatedMovie.Title,
 		updatedMovie.Description,
@@ -470,13 +637,24 @@ func (r *MoviesRepository) Update(c context.Context, id int, updatedMovie models
 		updatedMovie.Director,
 		updatedMovie.TrailerUrl,
 		updatedMovie.PosterUrl,
-		id)
+		id,
+		updatedMovie.Version)
 
 	if err != nil {
 		l.Error(err.Error())
 		return err
 	}
 
+	err = checkVersion(tag)
+	if err != nil {
+		return err
+	}
+
+	err = insertMovieRevision(c, tx, id, revision)
+	if err != nil {
+		return err
+	}
+
 	_, err = tx.Exec(c, "DELETE FROM movies_genres WHERE movie_id = $1", id)
 	if err != nil {
 		l.Error(err.Error())
@@ -528,66 +706,31 @@ func (r *MoviesRepository) Update(c context.Context, id int, updatedMovie models
 	return nil
 }
 
-func (r *MoviesRepository) Delete(c context.Context, id int) error {
+func (r *MoviesRepository) Delete(c context.Context, id int, version int) error {
 	l := logger.GetLogger()
-	tx, err := r.db.Begin(c)
-	if err != nil {
-		l.Error(err.Error())
-		return err
-	}
 
 	var movieTitle string
-	row := r.db.QueryRow(c, "select title from movies where id = $1", id)
-	err = row.Scan(&movieTitle)
+	row := r.db.QueryRow(c, "select title from movies where id = $1 and deleted_at is null", id)
+	err := row.Scan(&movieTitle)
 	if err != nil {
 		return err
 	}
 
 	l.Warn(fmt.Sprintf("Вы действительно хотите удалить %s?", movieTitle))
 
-	_, err = tx.Exec(c, "DELETE FROM movies_genres WHERE movie_id = $1", id)
-	if err != nil {
-		l.Error(err.Error())
-		tx.Rollback(c)
-		return err
-	}
-
-	_, err = tx.Exec(c, "DELETE FROM movies_categories WHERE movie_id = $1", id)
+	// связи с жанрами, категориями и сериями остаются, чтобы фильм можно было восстановить из корзины
+	tag, err := r.db.Exec(c, "update movies set deleted_at = now(), version = version + 1 where id = $1 and version = $2", id, version)
 	if err != nil {
 		l.Error(err.Error())
-		tx.Rollback(c)
 		return err
 	}
 
-	_, err = tx.Exec(c, "DELETE FROM movies_ages WHERE movie_id = $1", id)
+	err = checkVersion(tag)
 	if err != nil {
-		l.Error(err.Error())
-		tx.Rollback(c)
 		return err
 	}
 
-	_, err = tx.Exec(c, "DELETE FROM movies_allseries WHERE movie_id = $1", id)
-	if err != nil {
-		l.Error(err.Error())
-		tx.Rollback(c)
-		return err
-	}
-
-	_, err = tx.Exec(c, "DELETE FROM movies WHERE id = $1", id)
-	if err != nil {
-		l.Error(err.Error())
-		tx.Rollback(c)
-		return err
-	}
-
-	l.Info(fmt.Sprintf("Фильм %s удален:", movieTitle))
-
-	// Фиксируем транзакцию
-	err = tx.Commit(c)
-	if err != nil {
-		l.Error(err.Error())
-		return err
-	}
+	l.Info(fmt.Sprintf("Фильм %s перемещен в корзину", movieTitle))
 
 	return nil
 }
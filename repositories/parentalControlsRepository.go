@@ -0,0 +1,177 @@
+package repositories
+
+import (
+	"context"
+	"goozinshe/logger"
+	"goozinshe/models"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type ParentalControlsRepository struct {
+	db *pgxpool.Pool
+}
+
+func NewParentalControlsRepository(conn *pgxpool.Pool) *ParentalControlsRepository {
+	return &ParentalControlsRepository{db: conn}
+}
+
+// Find — настройки аккаунта и то, снято ли ограничение в сессии
+func (r *ParentalControlsRepository) Find(c context.Context, userId int, sessionId string) (models.ParentalControls, error) {
+	row := r.db.QueryRow(c,
+		`
+	select u.max_age_id, u.parental_pin_hash, u.parental_pin_failures, u.parental_blocked_until, s.parental_unlocked_until
+	from users u
+	left join user_sessions s on s.id = $2 and s.user_id = u.id
+	where u.id = $1
+	`,
+		userId,
+		sessionId)
+
+	var controls models.ParentalControls
+	err := row.Scan(
+		&controls.MaxAgeId,
+		&controls.PinHash,
+		&controls.PinFailures,
+		&controls.BlockedUntil,
+		&controls.UnlockedUntil)
+	if err != nil {
+		l := logger.GetLogger()
+		l.Error(err.Error())
+		return models.ParentalControls{}, err
+	}
+	controls.HasPin = controls.PinHash != nil
+
+	return controls, nil
+}
+
+// AgeLimit — действующий лимит возраста: строже из лимитов аккаунта и профиля.
+// nil — ограничений нет или они сняты PIN в этой сессии
+func (r *ParentalControlsRepository) AgeLimit(c context.Context, userId int, profileId *int, sessionId string) (*int, error) {
+	row := r.db.QueryRow(c,
+		`
+	select case when s.parental_unlocked_until > now() then null else least(ua.min_age, pa.min_age) end
+	from users u
+	left join user_sessions s on s.id = $3 and s.user_id = u.id
+	left join ages ua on ua.id = u.max_age_id
+	left join profiles p on p.id = $2 and p.user_id = u.id
+	left join ages pa on pa.id = p.max_age_id
+	where u.id = $1
+	`,
+		userId,
+		profileId,
+		sessionId)
+
+	var limit *int
+	err := row.Scan(&limit)
+	if err != nil {
+		l := logger.GetLogger()
+		l.Error(err.Error())
+		return nil, err
+	}
+
+	return limit, nil
+}
+
+// IsMovieAllowed — можно ли смотреть фильм при лимите maxAge. Несуществующий фильм не ограничен: его 404 — забота вызывающего
+func (r *ParentalControlsRepository) IsMovieAllowed(c context.Context, movieId int, maxAge int) (bool, error) {
+	var allowed bool
+	err := r.db.QueryRow(c,
+		"select not exists(select 1 from movies m where m.id = $1 and not coalesce("+ageLimitCondition("$2")+", false))",
+		movieId,
+		maxAge).Scan(&allowed)
+	if err != nil {
+		l := logger.GetLogger()
+		l.Error(err.Error())
+	}
+
+	return allowed, err
+}
+
+// IsEpisodeAllowed — можно ли смотреть серию при лимите maxAge. Несуществующая серия не ограничена, как и в IsMovieAllowed
+func (r *ParentalControlsRepository) IsEpisodeAllowed(c context.Context, allserieId int, maxAge int) (bool, error) {
+	var allowed bool
+	err := r.db.QueryRow(c,
+		"select not exists(select 1 from allseries s where s.id = $1 and not ("+episodeAgeLimitCondition("$2")+"))",
+		allserieId,
+		maxAge).Scan(&allowed)
+	if err != nil {
+		l := logger.GetLogger()
+		l.Error(err.Error())
+	}
+
+	return allowed, err
+}
+
+// Update меняет лимит аккаунта; pinHash == nil оставляет прежний PIN
+func (r *ParentalControlsRepository) Update(c context.Context, userId int, maxAgeId *int, pinHash *string) error {
+	_, err := r.db.Exec(c,
+		`
+	update users
+	set max_age_id = $2,
+		parental_pin_hash = coalesce($3, parental_pin_hash),
+		parental_pin_failures = 0,
+		parental_blocked_until = null
+	where id = $1
+	`,
+		userId,
+		maxAgeId,
+		pinHash)
+	if err != nil {
+		l := logger.GetLogger()
+		l.Error(err.Error())
+	}
+
+	return err
+}
+
+// Unlock снимает ограничение в сессии до until и сбрасывает счётчик неверных PIN
+func (r *ParentalControlsRepository) Unlock(c context.Context, userId int, sessionId string, until time.Time) error {
+	_, err := r.db.Exec(c,
+		`
+	with reset as (
+		update users set parental_pin_failures = 0, parental_blocked_until = null where id = $1
+	)
+	update user_sessions set parental_unlocked_until = $3 where id = $2 and user_id = $1
+	`,
+		userId,
+		sessionId,
+		until)
+	if err != nil {
+		l := logger.GetLogger()
+		l.Error(err.Error())
+	}
+
+	return err
+}
+
+func (r *ParentalControlsRepository) Lock(c context.Context, sessionId string) error {
+	_, err := r.db.Exec(c, "update user_sessions set parental_unlocked_until = null where id = $1", sessionId)
+	if err != nil {
+		l := logger.GetLogger()
+		l.Error(err.Error())
+	}
+
+	return err
+}
+
+// RecordPinFailure считает неверный PIN; на maxFailures-й попытке блокирует ввод на blockFor
+func (r *ParentalControlsRepository) RecordPinFailure(c context.Context, userId int, maxFailures int, blockFor time.Duration) error {
+	_, err := r.db.Exec(c,
+		`
+	update users
+	set parental_pin_failures = case when parental_pin_failures + 1 >= $2 then 0 else parental_pin_failures + 1 end,
+		parental_blocked_until = case when parental_pin_failures + 1 >= $2 then now() + make_interval(secs => $3) else parental_blocked_until end
+	where id = $1
+	`,
+		userId,
+		maxFailures,
+		blockFor.Seconds())
+	if err != nil {
+		l := logger.GetLogger()
+		l.Error(err.Error())
+	}
+
+	return err
+}
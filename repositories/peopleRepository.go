@@ -0,0 +1,267 @@
+package repositories
+
+import (
+	"context"
+	"fmt"
+	"goozinshe/logger"
+	"goozinshe/models"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type PeopleRepository struct {
+	db *pgxpool.Pool
+}
+
+func NewPeopleRepository(conn *pgxpool.Pool) *PeopleRepository {
+	return &PeopleRepository{db: conn}
+}
+
+func (r *PeopleRepository) FindById(c context.Context, id int) (models.Person, error) {
+	var person models.Person
+	row := r.db.QueryRow(c, "select id, name, original_name, bio, birth_date, photo_url, version from people where id = $1 and deleted_at is null", id)
+	err := row.Scan(&person.Id, &person.Name, &person.OriginalName, &person.Bio, &person.BirthDate, &person.PhotoUrl, &person.Version)
+	if err != nil {
+		return models.Person{}, err
+	}
+
+	return person, nil
+}
+
+func (r *PeopleRepository) FindAll(c context.Context, filters models.PersonFilters) ([]models.Person, error) {
+	sql := `
+	select p.id, p.name, p.original_name, p.bio, p.birth_date, p.photo_url, p.version
+	from people p
+	where p.deleted_at is null
+	`
+
+	params := pgx.NamedArgs{}
+
+	if filters.SearchTerm != "" {
+		sql = fmt.Sprintf("%s and (p.name ilike @s or p.original_name ilike @s)", sql)
+		params["s"] = fmt.Sprintf("%%%s%%", filters.SearchTerm)
+	}
+	if filters.Role != "" {
+		sql = fmt.Sprintf("%s and exists (select 1 from movie_credits cr where cr.person_id = p.id and cr.role = @role)", sql)
+		params["role"] = filters.Role
+	}
+
+	sql = fmt.Sprintf("%s order by p.name", sql)
+
+	l := logger.GetLogger()
+	rows, err := r.db.Query(c, sql, params)
+	if err != nil {
+		l.Error(err.Error())
+		return nil, err
+	}
+	defer rows.Close()
+
+	people := make([]models.Person, 0)
+	for rows.Next() {
+		var person models.Person
+		err := rows.Scan(&person.Id, &person.Name, &person.OriginalName, &person.Bio, &person.BirthDate, &person.PhotoUrl, &person.Version)
+		if err != nil {
+			l.Error(err.Error())
+			return nil, err
+		}
+		people = append(people, person)
+	}
+
+	return people, rows.Err()
+}
+
+func (r *PeopleRepository) FindAllByIds(c context.Context, ids []int) ([]models.Person, error) {
+	l := logger.GetLogger()
+	rows, err := r.db.Query(c, "select id, name, original_name, bio, birth_date, photo_url, version from people where id = any($1) and deleted_at is null", ids)
+	if err != nil {
+		l.Error(err.Error())
+		return nil, err
+	}
+	defer rows.Close()
+
+	people := make([]models.Person, 0)
+	for rows.Next() {
+		var person models.Person
+		err := rows.Scan(&person.Id, &person.Name, &person.OriginalName, &person.Bio, &person.BirthDate, &person.PhotoUrl, &person.Version)
+		if err != nil {
+			l.Error(err.Error())
+			return nil, err
+		}
+		people = append(people, person)
+	}
+
+	return people, rows.Err()
+}
+
+func (r *PeopleRepository) Create(c context.Context, person models.Person) (int, error) {
+	var id int
+	row := r.db.QueryRow(c,
+		"insert into people(name, original_name, bio, birth_date, photo_url) values($1, $2, $3, $4, $5) returning id",
+		person.Name,
+		person.OriginalName,
+		person.Bio,
+		person.BirthDate,
+		person.PhotoUrl)
+	err := row.Scan(&id)
+	if err != nil {
+		l := logger.GetLogger()
+		l.Error(err.Error())
+		return 0, err
+	}
+
+	return id, nil
+}
+
+func (r *PeopleRepository) Update(c context.Context, id int, person models.Person) error {
+	tag, err := r.db.Exec(c,
+		`
+	update people
+	set name = $1, original_name = $2, bio = $3, birth_date = $4, photo_url = $5, version = version + 1
+	where id = $6 and version = $7 and deleted_at is null
+	`,
+		person.Name,
+		person.OriginalName,
+		person.Bio,
+		person.BirthDate,
+		person.PhotoUrl,
+		id,
+		person.Version)
+	if err != nil {
+		l := logger.GetLogger()
+		l.Error(err.Error())
+		return err
+	}
+
+	return checkVersion(tag)
+}
+
+func (r *PeopleRepository) Delete(c context.Context, id int, version int) error {
+	tag, err := r.db.Exec(c, "update people set deleted_at = now(), version = version + 1 where id = $1 and version = $2 and deleted_at is null", id, version)
+	if err != nil {
+		l := logger.GetLogger()
+		l.Error(err.Error())
+		return err
+	}
+
+	return checkVersion(tag)
+}
+
+// FindFilmography — опубликованные фильмы и серии человека, новые первыми. maxAge — лимит родительского контроля
+func (r *PeopleRepository) FindFilmography(c context.Context, personId int, maxAge *int) ([]models.FilmographyItem, error) {
+	l := logger.GetLogger()
+	rows, err := r.db.Query(c,
+		`
+	select m.id, m.title, m.release_year, m.poster_url, cr.allserie_id, e.title, cr.role, cr.character, cr.billing_order
+	from movie_credits cr
+	join movies m on m.id = cr.movie_id and m.deleted_at is null and m.status = 'published'
+	left join allseries e on e.id = cr.allserie_id
+	where cr.person_id = $1 and (cr.allserie_id is null or e.deleted_at is null)
+		and ($2::int is null or `+ageLimitCondition("$2")+`)
+	order by m.release_year desc, m.id, e.series nulls first, cr.billing_order
+	`, personId, maxAge)
+	if err != nil {
+		l.Error(err.Error())
+		return nil, err
+	}
+	defer rows.Close()
+
+	items := make([]models.FilmographyItem, 0)
+	for rows.Next() {
+		var item models.FilmographyItem
+		err := rows.Scan(
+			&item.MovieId,
+			&item.Title,
+			&item.ReleaseYear,
+			&item.PosterUrl,
+			&item.AllSeriesId,
+			&item.EpisodeTitle,
+			&item.Role,
+			&item.Character,
+			&item.Order)
+		if err != nil {
+			l.Error(err.Error())
+			return nil, err
+		}
+		items = append(items, item)
+	}
+
+	return items, rows.Err()
+}
+
+func (r *PeopleRepository) FindCreditsByMovie(c context.Context, movieId int) ([]models.MovieCredit, error) {
+	return findMovieCredits(c, r.db, movieId)
+}
+
+// ReplaceCredits заменяет весь состав фильма и его серий одним списком
+func (r *PeopleRepository) ReplaceCredits(c context.Context, movieId int, credits []models.MovieCredit) error {
+	l := logger.GetLogger()
+	tx, err := r.db.Begin(c)
+	if err != nil {
+		l.Error(err.Error())
+		return err
+	}
+
+	defer func() {
+		if err != nil {
+			tx.Rollback(c) // Если ошибка, откатываем транзакцию
+		}
+	}()
+
+	_, err = tx.Exec(c, "delete from movie_credits where movie_id = $1", movieId)
+	if err != nil {
+		l.Error(err.Error())
+		return err
+	}
+
+	for _, credit := range credits {
+		_, err = tx.Exec(c,
+			"insert into movie_credits(movie_id, allserie_id, person_id, role, character, billing_order) values($1, $2, $3, $4, $5, $6)",
+			movieId,
+			credit.AllSeriesId,
+			credit.PersonId,
+			credit.Role,
+			credit.Character,
+			credit.Order)
+		if err != nil {
+			l.Error(err.Error())
+			return err
+		}
+	}
+
+	err = tx.Commit(c)
+	if err != nil {
+		l.Error(err.Error())
+		return err
+	}
+
+	return nil
+}
+
+// findMovieCredits общий для карточек фильма и PeopleRepository: состав по порядку в титрах
+func findMovieCredits(c context.Context, db *pgxpool.Pool, movieId int) ([]models.MovieCredit, error) {
+	rows, err := db.Query(c,
+		`
+	select p.id, p.name, p.photo_url, cr.role, cr.character, cr.billing_order, cr.allserie_id
+	from movie_credits cr
+	join people p on p.id = cr.person_id and p.deleted_at is null
+	where cr.movie_id = $1
+	order by cr.allserie_id nulls first, cr.billing_order, p.name
+	`, movieId)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	credits := make([]models.MovieCredit, 0)
+	for rows.Next() {
+		var credit models.MovieCredit
+		err := rows.Scan(&credit.PersonId, &credit.Name, &credit.PhotoUrl, &credit.Role, &credit.Character, &credit.Order, &credit.AllSeriesId)
+		if err != nil {
+			return nil, err
+		}
+		credits = append(credits, credit)
+	}
+
+	return credits, rows.Err()
+}
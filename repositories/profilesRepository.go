@@ -0,0 +1,132 @@
+package repositories
+
+import (
+	"context"
+	"goozinshe/logger"
+	"goozinshe/models"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+const profileColumns = `id, user_id, name, avatar_url, language, max_age_id, is_kids, version, created_at, updated_at`
+
+type ProfilesRepository struct {
+	db *pgxpool.Pool
+}
+
+func NewProfilesRepository(conn *pgxpool.Pool) *ProfilesRepository {
+	return &ProfilesRepository{db: conn}
+}
+
+// Create добавляет профиль, если у пользователя их меньше limit; иначе pgx.ErrNoRows
+func (r *ProfilesRepository) Create(c context.Context, profile models.Profile, limit int) (models.Profile, error) {
+	row := r.db.QueryRow(c,
+		`
+	insert into profiles(user_id, name, avatar_url, language, max_age_id, is_kids)
+	select $1, $2, $3, $4, $5, $6
+	where (select count(*) from profiles where user_id = $1) < $7
+	returning `+profileColumns,
+		profile.UserId,
+		profile.Name,
+		profile.AvatarUrl,
+		profile.Language,
+		profile.MaxAgeId,
+		profile.IsKids,
+		limit)
+
+	created, err := scanProfile(row)
+	if err != nil {
+		l := logger.GetLogger()
+		l.Error(err.Error())
+		return models.Profile{}, err
+	}
+
+	return created, nil
+}
+
+func (r *ProfilesRepository) FindById(c context.Context, id int) (models.Profile, error) {
+	row := r.db.QueryRow(c, "select "+profileColumns+" from profiles where id = $1", id)
+
+	profile, err := scanProfile(row)
+	if err != nil {
+		l := logger.GetLogger()
+		l.Error(err.Error())
+		return models.Profile{}, err
+	}
+
+	return profile, nil
+}
+
+func (r *ProfilesRepository) FindByUser(c context.Context, userId int) ([]models.Profile, error) {
+	l := logger.GetLogger()
+	rows, err := r.db.Query(c, "select "+profileColumns+" from profiles where user_id = $1 order by id", userId)
+	if err != nil {
+		l.Error(err.Error())
+		return nil, err
+	}
+	defer rows.Close()
+
+	profiles := make([]models.Profile, 0)
+	for rows.Next() {
+		profile, err := scanProfile(rows)
+		if err != nil {
+			l.Error(err.Error())
+			return nil, err
+		}
+		profiles = append(profiles, profile)
+	}
+
+	return profiles, rows.Err()
+}
+
+func (r *ProfilesRepository) Update(c context.Context, id int, profile models.Profile) error {
+	tag, err := r.db.Exec(c,
+		`
+	update profiles
+	set name = $1, avatar_url = $2, language = $3, max_age_id = $4, is_kids = $5, version = version + 1, updated_at = now()
+	where id = $6 and version = $7
+	`,
+		profile.Name,
+		profile.AvatarUrl,
+		profile.Language,
+		profile.MaxAgeId,
+		profile.IsKids,
+		id,
+		profile.Version)
+	if err != nil {
+		l := logger.GetLogger()
+		l.Error(err.Error())
+		return err
+	}
+
+	return checkVersion(tag)
+}
+
+func (r *ProfilesRepository) Delete(c context.Context, id int, version int) error {
+	tag, err := r.db.Exec(c, "delete from profiles where id = $1 and version = $2", id, version)
+	if err != nil {
+		l := logger.GetLogger()
+		l.Error(err.Error())
+		return err
+	}
+
+	return checkVersion(tag)
+}
+
+func scanProfile(row pgx.Row) (models.Profile, error) {
+	var profile models.Profile
+	err := row.Scan(
+		&profile.Id,
+		&profile.UserId,
+		&profile.Name,
+		&profile.AvatarUrl,
+		&profile.Language,
+		&profile.MaxAgeId,
+		&profile.IsKids,
+		&profile.Version,
+		&profile.CreatedAt,
+		&profile.UpdatedAt)
+
+	return profile, err
+}
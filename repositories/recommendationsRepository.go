@@ -0,0 +1,272 @@
+package repositories
+
+import (
+	"context"
+	"goozinshe/logger"
+	"goozinshe/models"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// веса сигналов: общий жанр, категория, человек в составе; совместные просмотры — с логарифмом,
+// чтобы популярные фильмы не забивали всё остальное. movie_credits и watch_progress читаются
+// только по людям и зрителям фильма $1, а не целиком
+const similarScores = `
+	select mg2.movie_id, 3.0 as score
+	from movies_genres mg1
+	join movies_genres mg2 on mg2.genre_id = mg1.genre_id and mg2.movie_id <> mg1.movie_id
+	join genres g on g.id = mg1.genre_id and g.deleted_at is null
+	where mg1.movie_id = $1
+	union all
+	select mc2.movie_id, 2.0
+	from movies_categories mc1
+	join movies_categories mc2 on mc2.categorie_id = mc1.categorie_id and mc2.movie_id <> mc1.movie_id
+	join categories c on c.id = mc1.categorie_id and c.deleted_at is null
+	where mc1.movie_id = $1
+	union all
+	select cr2.movie_id, 2.0
+	from (
+		select distinct cr.movie_id, cr.person_id
+		from movie_credits cr
+		where cr.movie_id <> $1 and cr.person_id in (select cr1.person_id from movie_credits cr1 where cr1.movie_id = $1)
+	) cr2
+	union all
+	select w2.movie_id, 4.0 * ln(1 + count(*))
+	from (
+		select distinct w.user_id, coalesce(w.profile_id, 0) as profile_id, w.movie_id
+		from watch_progress w
+		where w.movie_id <> $1 and exists(
+			select 1 from watch_progress w1
+			where w1.movie_id = $1 and w1.user_id = w.user_id and w1.profile_id is not distinct from w.profile_id)
+	) w2
+	group by w2.movie_id
+	`
+
+type RecommendationsRepository struct {
+	db *pgxpool.Pool
+}
+
+func NewRecommendationsRepository(conn *pgxpool.Pool) *RecommendationsRepository {
+	return &RecommendationsRepository{db: conn}
+}
+
+// FindSimilar — фильмы, похожие на movieId по жанрам, категориям, составу и совместным просмотрам
+func (r *RecommendationsRepository) FindSimilar(c context.Context, movieId int, limit int, maxAge *int) ([]models.RecommendedMovie, error) {
+	sql := `
+	with scores as (` + similarScores + `)
+	select m.id, m.title, m.poster_url, m.release_year, m.rating, sum(s.score)
+	from scores s
+	join movies m on m.id = s.movie_id and m.deleted_at is null and m.status = 'published'
+	where $3::int is null or ` + ageLimitCondition("$3") + `
+	group by m.id
+	order by sum(s.score) desc, m.rating desc, m.id
+	limit $2
+	`
+
+	return r.findAll(c, sql, movieId, limit, maxAge)
+}
+
+// FindViewers — аккаунты и профили, у которых есть избранное, просмотры или отзывы
+func (r *RecommendationsRepository) FindViewers(c context.Context) ([]models.Viewer, error) {
+	l := logger.GetLogger()
+	rows, err := r.db.Query(c, `
+	select user_id, profile_id from favorites
+	union
+	select user_id, profile_id from watch_progress
+	union
+	select user_id, profile_id from reviews where deleted_at is null
+	`)
+	if err != nil {
+		l.Error(err.Error())
+		return nil, err
+	}
+	defer rows.Close()
+
+	viewers := make([]models.Viewer, 0)
+	for rows.Next() {
+		var viewer models.Viewer
+		err := rows.Scan(&viewer.UserId, &viewer.ProfileId)
+		if err != nil {
+			l.Error(err.Error())
+			return nil, err
+		}
+		viewers = append(viewers, viewer)
+	}
+
+	return viewers, rows.Err()
+}
+
+// Refresh пересчитывает рекомендации зрителя. Основа — фильмы, с которыми он уже знаком: избранное весит больше
+// досмотренного, досмотренное — больше начатого или отрецензированного. Сами эти фильмы в рекомендации не попадают.
+// Совместные просмотры и состав берутся только по зрителям и людям этих фильмов
+func (r *RecommendationsRepository) Refresh(c context.Context, viewer models.Viewer, limit int) error {
+	l := logger.GetLogger()
+	tx, err := r.db.Begin(c)
+	if err != nil {
+		l.Error(err.Error())
+		return err
+	}
+	defer func() {
+		if err != nil {
+			tx.Rollback(c) // Если ошибка, откатываем транзакцию
+		}
+	}()
+
+	_, err = tx.Exec(c,
+		"delete from recommendations where user_id = $1 and profile_id is not distinct from $2",
+		viewer.UserId,
+		viewer.ProfileId)
+	if err != nil {
+		l.Error(err.Error())
+		return err
+	}
+
+	_, err = tx.Exec(c,
+		`
+	with seeds as (
+		select movie_id, max(weight) as weight
+		from (
+			select movie_id, 3.0 as weight from favorites where user_id = $1 and profile_id is not distinct from $2
+			union all
+			select movie_id, case when completed then 2.0 else 1.0 end from watch_progress where user_id = $1 and profile_id is not distinct from $2
+			union all
+			select movie_id, 1.0 from reviews where user_id = $1 and profile_id is not distinct from $2 and deleted_at is null
+		) s
+		group by movie_id
+	),
+	co_viewers as (
+		select distinct w.movie_id as seed_id, w.user_id, coalesce(w.profile_id, 0) as profile_id
+		from watch_progress w
+		join seeds s on s.movie_id = w.movie_id
+		where not (w.user_id = $1 and w.profile_id is not distinct from $2)
+	),
+	co_viewings as (
+		select distinct v.seed_id, v.user_id, v.profile_id, w.movie_id
+		from co_viewers v
+		join watch_progress w on w.user_id = v.user_id and coalesce(w.profile_id, 0) = v.profile_id
+	),
+	credits as (
+		select distinct sc.seed_id, cr.movie_id, cr.person_id
+		from (
+			select distinct cr1.movie_id as seed_id, cr1.person_id
+			from movie_credits cr1
+			join seeds s on s.movie_id = cr1.movie_id
+		) sc
+		join movie_credits cr on cr.person_id = sc.person_id
+	),
+	candidates as (
+		select mg2.movie_id, s.weight * 3 as score
+		from seeds s
+		join movies_genres mg1 on mg1.movie_id = s.movie_id
+		join genres g on g.id = mg1.genre_id and g.deleted_at is null
+		join movies_genres mg2 on mg2.genre_id = mg1.genre_id
+		union all
+		select mc2.movie_id, s.weight * 2
+		from seeds s
+		join movies_categories mc1 on mc1.movie_id = s.movie_id
+		join categories c on c.id = mc1.categorie_id and c.deleted_at is null
+		join movies_categories mc2 on mc2.categorie_id = mc1.categorie_id
+		union all
+		select cr.movie_id, s.weight * 2
+		from seeds s
+		join credits cr on cr.seed_id = s.movie_id
+		union all
+		select v.movie_id, s.weight * 4 * ln(1 + count(*))
+		from seeds s
+		join co_viewings v on v.seed_id = s.movie_id
+		group by s.movie_id, s.weight, v.movie_id
+	)
+	insert into recommendations(user_id, profile_id, movie_id, score)
+	select $1, $2, cand.movie_id, sum(cand.score)
+	from candidates cand
+	join movies m on m.id = cand.movie_id and m.deleted_at is null and m.status = 'published'
+	where not exists(select 1 from seeds s where s.movie_id = cand.movie_id)
+	group by cand.movie_id
+	order by sum(cand.score) desc
+	limit $3
+	`,
+		viewer.UserId,
+		viewer.ProfileId,
+		limit)
+	if err != nil {
+		l.Error(err.Error())
+		return err
+	}
+
+	err = tx.Commit(c)
+	if err != nil {
+		l.Error(err.Error())
+	}
+
+	return err
+}
+
+// FindForViewer — сохранённые рекомендации без фильмов, которые зритель успел начать или добавить в избранное после расчёта
+func (r *RecommendationsRepository) FindForViewer(c context.Context, viewer models.Viewer, limit int, maxAge *int) ([]models.RecommendedMovie, error) {
+	sql := `
+	select m.id, m.title, m.poster_url, m.release_year, m.rating, rc.score
+	from recommendations rc
+	join movies m on m.id = rc.movie_id and m.deleted_at is null and m.status = 'published'
+	where rc.user_id = $1 and rc.profile_id is not distinct from $2
+		and not exists(select 1 from watch_progress w where w.user_id = $1 and w.profile_id is not distinct from $2 and w.movie_id = m.id)
+		and not exists(select 1 from favorites f where f.user_id = $1 and f.profile_id is not distinct from $2 and f.movie_id = m.id)
+		and ($4::int is null or ` + ageLimitCondition("$4") + `)
+	order by rc.score desc, m.id
+	limit $3
+	`
+
+	return r.findAll(c, sql, viewer.UserId, viewer.ProfileId, limit, maxAge)
+}
+
+// FindPopular — самые популярные непросмотренные фильмы: для зрителей, которым ещё нечего рекомендовать
+func (r *RecommendationsRepository) FindPopular(c context.Context, viewer models.Viewer, limit int, maxAge *int) ([]models.RecommendedMovie, error) {
+	sql := `
+	select m.id, m.title, m.poster_url, m.release_year, m.rating,
+		((select count(*) from favorites f where f.movie_id = m.id)
+			+ (select count(distinct (w.user_id, coalesce(w.profile_id, 0))) from watch_progress w where w.movie_id = m.id))::float8
+	from movies m
+	where m.deleted_at is null and m.status = 'published'
+		and not exists(select 1 from watch_progress w where w.user_id = $1 and w.profile_id is not distinct from $2 and w.movie_id = m.id)
+		and ($4::int is null or ` + ageLimitCondition("$4") + `)
+	order by 6 desc, m.rating desc, m.id
+	limit $3
+	`
+
+	return r.findAll(c, sql, viewer.UserId, viewer.ProfileId, limit, maxAge)
+}
+
+func (r *RecommendationsRepository) findAll(c context.Context, sql string, args ...any) ([]models.RecommendedMovie, error) {
+	l := logger.GetLogger()
+	rows, err := r.db.Query(c, sql, args...)
+	if err != nil {
+		l.Error(err.Error())
+		return nil, err
+	}
+	defer rows.Close()
+
+	movies := make([]models.RecommendedMovie, 0)
+	for rows.Next() {
+		movie, err := scanRecommendedMovie(rows)
+		if err != nil {
+			l.Error(err.Error())
+			return nil, err
+		}
+		movies = append(movies, movie)
+	}
+
+	return movies, rows.Err()
+}
+
+func scanRecommendedMovie(row pgx.Row) (models.RecommendedMovie, error) {
+	var movie models.RecommendedMovie
+	err := row.Scan(
+		&movie.MovieId,
+		&movie.Title,
+		&movie.PosterUrl,
+		&movie.ReleaseYear,
+		&movie.Rating,
+		&movie.Score)
+
+	return movie, err
+}
@@ -0,0 +1,47 @@
+package repositories
+
+import (
+	"context"
+	"fmt"
+	"goozinshe/logger"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// таблицы, на записи которых могут ссылаться запросы; все с мягким удалением
+var referenceTables = map[string]bool{
+	"movies":     true,
+	"genres":     true,
+	"categories": true,
+	"ages":       true,
+	"allseries":  true,
+	"people":     true,
+	"reviews":    true,
+}
+
+type ReferencesRepository struct {
+	db *pgxpool.Pool
+}
+
+func NewReferencesRepository(conn *pgxpool.Pool) *ReferencesRepository {
+	return &ReferencesRepository{db: conn}
+}
+
+// Exists проверяет, что запись есть и не лежит в корзине
+func (r *ReferencesRepository) Exists(c context.Context, table string, id int) (bool, error) {
+	if !referenceTables[table] {
+		return false, fmt.Errorf("unknown reference table %q", table)
+	}
+
+	var exists bool
+	err := r.db.QueryRow(c,
+		fmt.Sprintf("select exists(select 1 from %s where id = $1 and deleted_at is null)", table),
+		id).Scan(&exists)
+	if err != nil {
+		l := logger.GetLogger()
+		l.Error(err.Error())
+		return false, err
+	}
+
+	return exists, nil
+}
@@ -0,0 +1,389 @@
+package repositories
+
+import (
+	"context"
+	"fmt"
+	"goozinshe/logger"
+	"goozinshe/models"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// автор — профиль, если отзыв написан из профиля; текст удалённого отзыва не отдаётся
+const reviewSelect = `
+	select
+		r.id,
+		r.movie_id,
+		r.allserie_id,
+		r.parent_id,
+		r.user_id,
+		r.profile_id,
+		coalesce(p.name, u.name),
+		case when r.deleted_at is null then r.body else '' end,
+		r.status,
+		r.flagged_words,
+		r.moderation_note,
+		r.helpful_count,
+		(select count(*) from reviews ch where ch.parent_id = r.id and ch.status = 'approved' and ch.deleted_at is null),
+		(select count(*) from review_reports rr where rr.review_id = r.id and rr.resolved_at is null),
+		r.deleted_at is not null,
+		r.version,
+		r.created_at,
+		r.updated_at
+	from reviews r
+	join users u on u.id = r.user_id
+	left join profiles p on p.id = r.profile_id
+	`
+
+// в публичных списках: одобренные отзывы и удалённые, у которых остались видимые ответы
+const reviewVisible = `r.status = 'approved' and (r.deleted_at is null or exists(
+		select 1 from reviews ch where ch.parent_id = r.id and ch.status = 'approved' and ch.deleted_at is null))`
+
+type ReviewsRepository struct {
+	db *pgxpool.Pool
+}
+
+func NewReviewsRepository(conn *pgxpool.Pool) *ReviewsRepository {
+	return &ReviewsRepository{db: conn}
+}
+
+func (r *ReviewsRepository) Create(c context.Context, review models.Review) (models.Review, error) {
+	var id int
+	err := r.db.QueryRow(c,
+		`
+	insert into reviews(movie_id, allserie_id, parent_id, user_id, profile_id, body, status, flagged_words)
+	values($1, $2, $3, $4, $5, $6, $7, $8)
+	returning id
+	`,
+		review.MovieId,
+		review.AllSeriesId,
+		review.ParentId,
+		review.UserId,
+		review.ProfileId,
+		review.Body,
+		review.Status,
+		review.FlaggedWords).Scan(&id)
+	if err != nil {
+		l := logger.GetLogger()
+		l.Error(err.Error())
+		return models.Review{}, err
+	}
+
+	return r.FindById(c, id)
+}
+
+// FindById находит отзыв в любом статусе, в том числе удалённый
+func (r *ReviewsRepository) FindById(c context.Context, id int) (models.Review, error) {
+	review, err := scanReview(r.db.QueryRow(c, reviewSelect+" where r.id = $1", id))
+	if err != nil {
+		l := logger.GetLogger()
+		l.Error(err.Error())
+		return models.Review{}, err
+	}
+
+	return review, nil
+}
+
+// FindByMovie — одобренные отзывы верхнего уровня на фильм или, если задан AllSeriesId, на серию
+func (r *ReviewsRepository) FindByMovie(c context.Context, filters models.ReviewFilters) ([]models.Review, error) {
+	order := "r.created_at desc, r.id desc"
+	if filters.Sort == models.ReviewSortHelpful {
+		order = "r.helpful_count desc, r.created_at desc, r.id desc"
+	}
+
+	sql := fmt.Sprintf(`%s
+	where r.movie_id = @movieId and r.allserie_id is not distinct from @allSeriesId and r.parent_id is null
+		and %s
+	order by %s
+	limit @limit offset @offset
+	`, reviewSelect, reviewVisible, order)
+
+	return r.findAll(c, sql, pgx.NamedArgs{
+		"movieId":     filters.MovieId,
+		"allSeriesId": filters.AllSeriesId,
+		"limit":       filters.Limit,
+		"offset":      filters.Offset,
+	})
+}
+
+// FindReplies — одобренные ответы на отзыв в порядке написания
+func (r *ReviewsRepository) FindReplies(c context.Context, parentId int, limit int, offset int) ([]models.Review, error) {
+	sql := fmt.Sprintf(`%s
+	where r.parent_id = @parentId and %s
+	order by r.created_at, r.id
+	limit @limit offset @offset
+	`, reviewSelect, reviewVisible)
+
+	return r.findAll(c, sql, pgx.NamedArgs{
+		"parentId": parentId,
+		"limit":    limit,
+		"offset":   offset,
+	})
+}
+
+// FindByUser — отзывы и ответы пользователя во всех статусах, новые первыми
+func (r *ReviewsRepository) FindByUser(c context.Context, userId int, limit int, offset int) ([]models.Review, error) {
+	sql := reviewSelect + `
+	where r.user_id = @userId and r.deleted_at is null
+	order by r.created_at desc, r.id desc
+	limit @limit offset @offset
+	`
+
+	return r.findAll(c, sql, pgx.NamedArgs{
+		"userId": userId,
+		"limit":  limit,
+		"offset": offset,
+	})
+}
+
+// FindForModeration — очередь модерации, старые первыми
+func (r *ReviewsRepository) FindForModeration(c context.Context, filters models.ModerationFilters) ([]models.Review, error) {
+	sql := reviewSelect + " where r.deleted_at is null and r.status = @status"
+	if filters.Reported {
+		sql += " and exists(select 1 from review_reports rr where rr.review_id = r.id and rr.resolved_at is null)"
+	}
+	sql += " order by r.created_at, r.id limit @limit offset @offset"
+
+	return r.findAll(c, sql, pgx.NamedArgs{
+		"status": filters.Status,
+		"limit":  filters.Limit,
+		"offset": filters.Offset,
+	})
+}
+
+func (r *ReviewsRepository) findAll(c context.Context, sql string, params pgx.NamedArgs) ([]models.Review, error) {
+	l := logger.GetLogger()
+	rows, err := r.db.Query(c, sql, params)
+	if err != nil {
+		l.Error(err.Error())
+		return nil, err
+	}
+	defer rows.Close()
+
+	reviews := make([]models.Review, 0)
+	for rows.Next() {
+		review, err := scanReview(rows)
+		if err != nil {
+			l.Error(err.Error())
+			return nil, err
+		}
+		reviews = append(reviews, review)
+	}
+
+	return reviews, rows.Err()
+}
+
+// UpdateBody меняет текст; статус и найденные слова пересчитываются заново, решение модератора сбрасывается
+func (r *ReviewsRepository) UpdateBody(c context.Context, id int, review models.Review) error {
+	tag, err := r.db.Exec(c,
+		`
+	update reviews
+	set body = $1, status = $2, flagged_words = $3, moderation_note = null, moderated_by = null, moderated_at = null,
+		version = version + 1, updated_at = now()
+	where id = $4 and version = $5 and deleted_at is null
+	`,
+		review.Body,
+		review.Status,
+		review.FlaggedWords,
+		id,
+		review.Version)
+	if err != nil {
+		l := logger.GetLogger()
+		l.Error(err.Error())
+		return err
+	}
+
+	return checkVersion(tag)
+}
+
+// Delete помечает отзыв удалённым: ветка ответов на него сохраняется
+func (r *ReviewsRepository) Delete(c context.Context, id int, version int) error {
+	tag, err := r.db.Exec(c,
+		"update reviews set deleted_at = now(), version = version + 1, updated_at = now() where id = $1 and version = $2 and deleted_at is null",
+		id,
+		version)
+	if err != nil {
+		l := logger.GetLogger()
+		l.Error(err.Error())
+		return err
+	}
+
+	return checkVersion(tag)
+}
+
+// Moderate ставит решение модератора и закрывает жалобы на отзыв
+func (r *ReviewsRepository) Moderate(c context.Context, id int, version int, status string, note *string, moderatorId *int) error {
+	l := logger.GetLogger()
+	tx, err := r.db.Begin(c)
+	if err != nil {
+		l.Error(err.Error())
+		return err
+	}
+	defer func() {
+		if err != nil {
+			tx.Rollback(c) // Если ошибка, откатываем транзакцию
+		}
+	}()
+
+	tag, err := tx.Exec(c,
+		`
+	update reviews
+	set status = $1, moderation_note = $2, moderated_by = $3, moderated_at = now(), version = version + 1, updated_at = now()
+	where id = $4 and version = $5 and deleted_at is null
+	`,
+		status,
+		note,
+		moderatorId,
+		id,
+		version)
+	if err != nil {
+		l.Error(err.Error())
+		return err
+	}
+	if err = checkVersion(tag); err != nil {
+		return err
+	}
+
+	_, err = tx.Exec(c, "update review_reports set resolved_at = now() where review_id = $1 and resolved_at is null", id)
+	if err != nil {
+		l.Error(err.Error())
+		return err
+	}
+
+	err = tx.Commit(c)
+	if err != nil {
+		l.Error(err.Error())
+	}
+
+	return err
+}
+
+// Vote отмечает отзыв полезным; повторный голос ничего не меняет
+func (r *ReviewsRepository) Vote(c context.Context, reviewId int, userId int) error {
+	_, err := r.db.Exec(c,
+		`
+	with vote as (
+		insert into review_votes(review_id, user_id) values($1, $2)
+		on conflict do nothing
+		returning review_id
+	)
+	update reviews set helpful_count = helpful_count + 1 where id = (select review_id from vote)
+	`,
+		reviewId,
+		userId)
+	if err != nil {
+		l := logger.GetLogger()
+		l.Error(err.Error())
+	}
+
+	return err
+}
+
+func (r *ReviewsRepository) Unvote(c context.Context, reviewId int, userId int) error {
+	_, err := r.db.Exec(c,
+		`
+	with vote as (
+		delete from review_votes where review_id = $1 and user_id = $2
+		returning review_id
+	)
+	update reviews set helpful_count = helpful_count - 1 where id = (select review_id from vote)
+	`,
+		reviewId,
+		userId)
+	if err != nil {
+		l := logger.GetLogger()
+		l.Error(err.Error())
+	}
+
+	return err
+}
+
+// Report сохраняет жалобу, одну от пользователя на отзыв. Если у одобренного отзыва набралось
+// requeueAt нерассмотренных жалоб, он возвращается в очередь модерации
+func (r *ReviewsRepository) Report(c context.Context, report models.ReviewReport, requeueAt int) error {
+	_, err := r.db.Exec(c,
+		`
+	with report as (
+		insert into review_reports(review_id, user_id, reason, comment) values($1, $2, $3, $4)
+		on conflict (review_id, user_id) do nothing
+		returning review_id
+	)
+	update reviews set status = 'pending', updated_at = now()
+	where id = (select review_id from report) and status = 'approved'
+		and (select count(*) from review_reports rr where rr.review_id = reviews.id and rr.resolved_at is null) + 1 >= $5
+	`,
+		report.ReviewId,
+		report.UserId,
+		report.Reason,
+		report.Comment,
+		requeueAt)
+	if err != nil {
+		l := logger.GetLogger()
+		l.Error(err.Error())
+	}
+
+	return err
+}
+
+func (r *ReviewsRepository) FindReports(c context.Context, reviewId int) ([]models.ReviewReport, error) {
+	l := logger.GetLogger()
+	rows, err := r.db.Query(c,
+		"select id, review_id, user_id, reason, comment, created_at, resolved_at from review_reports where review_id = $1 order by id",
+		reviewId)
+	if err != nil {
+		l.Error(err.Error())
+		return nil, err
+	}
+	defer rows.Close()
+
+	reports := make([]models.ReviewReport, 0)
+	for rows.Next() {
+		var report models.ReviewReport
+		err := rows.Scan(
+			&report.Id,
+			&report.ReviewId,
+			&report.UserId,
+			&report.Reason,
+			&report.Comment,
+			&report.CreatedAt,
+			&report.ResolvedAt)
+		if err != nil {
+			l.Error(err.Error())
+			return nil, err
+		}
+		reports = append(reports, report)
+	}
+
+	return reports, rows.Err()
+}
+
+// IsMovieEpisode проверяет, что серия относится к фильму
+func (r *ReviewsRepository) IsMovieEpisode(c context.Context, movieId int, allserieId int) (bool, error) {
+	return isMovieEpisode(c, r.db, movieId, allserieId)
+}
+
+func scanReview(row pgx.Row) (models.Review, error) {
+	var review models.Review
+	err := row.Scan(
+		&review.Id,
+		&review.MovieId,
+		&review.AllSeriesId,
+		&review.ParentId,
+		&review.UserId,
+		&review.ProfileId,
+		&review.AuthorName,
+		&review.Body,
+		&review.Status,
+		&review.FlaggedWords,
+		&review.ModerationNote,
+		&review.HelpfulCount,
+		&review.ReplyCount,
+		&review.ReportCount,
+		&review.IsDeleted,
+		&review.Version,
+		&review.CreatedAt,
+		&review.UpdatedAt)
+
+	return review, err
+}
@@ -17,7 +17,9 @@ func NewSelectedlistRepository(db *pgxpool.Pool) *SelectedlistRepository {
 	return &SelectedlistRepository{db: db}
 }
 
-func (r *SelectedlistRepository) GetMoviesFromSelectedlist(c context.Context) ([]models.Movie, error) {
+// GetMoviesFromSelectedlist — подборка с лимитом возраста maxAge; nil — без ограничений.
+// isWatched отмечается для пользователя userId и профиля profileId
+func (r *SelectedlistRepository) GetMoviesFromSelectedlist(c context.Context, maxAge *int, userId *int, profileId *int) ([]models.Movie, error) {
 	sql :=
 		`
 SELECT 
@@ -27,7 +29,7 @@ SELECT
         m.release_year,
         m.director,
         m.rating,
-        m.is_watched,
+        ` + watchedCondition("$2", "$3") + `,
         m.trailer_url,
         m.poster_url,
         g.id,
@@ -38,6 +40,7 @@ SELECT
         c.poster_url,
         a.id,
         a.age,
+        a.min_age,
         a.poster_url,
         e.id, 
         e.series, 
@@ -48,21 +51,22 @@ SELECT
         e.rating,         
         e.trailer_url
     FROM selected sl
-	JOIN movies m on sl.movie_id = m.id
+	JOIN movies m on sl.movie_id = m.id and m.deleted_at is null and m.status = 'published'
     JOIN movies_genres mg ON mg.movie_id = m.id
-    JOIN genres g ON mg.genre_id = g.id
+    JOIN genres g ON mg.genre_id = g.id and g.deleted_at is null
     JOIN movies_categories mc ON mc.movie_id = m.id
-    JOIN categories c ON mc.categorie_id = c.id
+    JOIN categories c ON mc.categorie_id = c.id and c.deleted_at is null
     JOIN movies_ages ma ON ma.movie_id = m.id
-    JOIN ages a ON ma.age_id = a.id
+    JOIN ages a ON ma.age_id = a.id and a.deleted_at is null
     left JOIN movies_allseries me ON me.movie_id = m.id
-    left JOIN allseries e ON me.allserie_id = e.id
+    left JOIN allseries e ON me.allserie_id = e.id and e.deleted_at is null
+where $1::int is null or ` + ageLimitCondition("$1") + `
 order by sl.added_at
 
  `
 
 	l := logger.GetLogger()
-	rows, err := r.db.Query(c, sql)
+	rows, err := r.db.Query(c, sql, maxAge, userId, profileId)
 	if err != nil {
 		l.Error(err.Error())
 		return nil, err
@@ -97,6 +101,7 @@ order by sl.added_at
 			&c.PosterUrl,
 			&a.Id,
 			&a.Age,
+			&a.MinAge,
 			&a.PosterUrl,
 			&e.Id,
 			&e.Series,
@@ -110,6 +115,8 @@ order by sl.added_at
 		if err != nil {
 			return nil, err
 		}
+		m.Trailer = models.TrailerFromUrl(m.TrailerUrl)
+		e.Trailer = episodeTrailer(e.TrailerUrl)
 
 		if _, exists := moviesMap[m.Id]; !exists {
 			moviesMap[m.Id] = &m
@@ -0,0 +1,135 @@
+package repositories
+
+import (
+	"context"
+	"fmt"
+	"goozinshe/logger"
+	"goozinshe/models"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type SessionsRepository struct {
+	db *pgxpool.Pool
+}
+
+func NewSessionsRepository(conn *pgxpool.Pool) *SessionsRepository {
+	return &SessionsRepository{db: conn}
+}
+
+func (r *SessionsRepository) Create(c context.Context, session models.Session) error {
+	_, err := r.db.Exec(c,
+		`
+	insert into user_sessions(id, user_id, user_agent, ip, expires_at)
+	values($1, $2, $3, $4, $5)
+	`,
+		session.Id,
+		session.UserId,
+		session.UserAgent,
+		session.Ip,
+		session.ExpiresAt)
+	if err != nil {
+		l := logger.GetLogger()
+		l.Error(err.Error())
+	}
+
+	return err
+}
+
+func (r *SessionsRepository) FindById(c context.Context, id string) (models.Session, error) {
+	row := r.db.QueryRow(c, `
+	select id, user_id, user_agent, ip, created_at, last_seen_at, expires_at, terminated_at, profile_id
+	from user_sessions
+	where id = $1`, id)
+
+	var session models.Session
+	err := row.Scan(&session.Id, &session.UserId, &session.UserAgent, &session.Ip, &session.CreatedAt, &session.LastSeenAt, &session.ExpiresAt, &session.TerminatedAt, &session.ProfileId)
+
+	return session, err
+}
+
+// FindActiveByUser — незавершённые и непросроченные сессии пользователя, последние активные первыми
+func (r *SessionsRepository) FindActiveByUser(c context.Context, userId int) ([]models.Session, error) {
+	rows, err := r.db.Query(c, `
+	select id, user_id, user_agent, ip, created_at, last_seen_at, expires_at, terminated_at, profile_id
+	from user_sessions
+	where user_id = $1
+	  and terminated_at is null
+	  and expires_at > now()
+	order by last_seen_at desc`, userId)
+	if err != nil {
+		l := logger.GetLogger()
+		l.Error(err.Error())
+		return nil, err
+	}
+	defer rows.Close()
+
+	sessions := make([]models.Session, 0)
+	for rows.Next() {
+		var session models.Session
+		err := rows.Scan(&session.Id, &session.UserId, &session.UserAgent, &session.Ip, &session.CreatedAt, &session.LastSeenAt, &session.ExpiresAt, &session.TerminatedAt, &session.ProfileId)
+		if err != nil {
+			return nil, err
+		}
+
+		sessions = append(sessions, session)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return sessions, nil
+}
+
+// Touch обновляет last_seen_at не чаще раза в минуту, чтобы не писать в базу на каждый запрос
+func (r *SessionsRepository) Touch(c context.Context, id string, ip string) error {
+	_, err := r.db.Exec(c, `
+	update user_sessions
+	set last_seen_at = now(), ip = $2
+	where id = $1 and last_seen_at < now() - interval '1 minute'`, id, ip)
+	if err != nil {
+		l := logger.GetLogger()
+		l.Error(err.Error())
+	}
+
+	return err
+}
+
+// SelectProfile закрепляет профиль за сессией
+func (r *SessionsRepository) SelectProfile(c context.Context, id string, profileId int) error {
+	_, err := r.db.Exec(c, "update user_sessions set profile_id = $2 where id = $1", id, profileId)
+	if err != nil {
+		l := logger.GetLogger()
+		l.Error(err.Error())
+	}
+
+	return err
+}
+
+func (r *SessionsRepository) Terminate(c context.Context, id string) error {
+	l := logger.GetLogger()
+
+	_, err := r.db.Exec(c, "update user_sessions set terminated_at = now() where id = $1 and terminated_at is null", id)
+	if err != nil {
+		l.Error(err.Error())
+		return err
+	}
+
+	l.Info(fmt.Sprintf("сессия %s завершена", id))
+
+	return nil
+}
+
+func (r *SessionsRepository) TerminateAllByUser(c context.Context, userId int) error {
+	l := logger.GetLogger()
+
+	_, err := r.db.Exec(c, "update user_sessions set terminated_at = now() where user_id = $1 and terminated_at is null", userId)
+	if err != nil {
+		l.Error(err.Error())
+		return err
+	}
+
+	l.Info(fmt.Sprintf("все сессии пользователя %d завершены", userId))
+
+	return nil
+}
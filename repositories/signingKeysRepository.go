@@ -0,0 +1,105 @@
+package repositories
+
+import (
+	"context"
+	"fmt"
+	"goozinshe/logger"
+	"goozinshe/models"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type SigningKeysRepository struct {
+	db *pgxpool.Pool
+}
+
+func NewSigningKeysRepository(conn *pgxpool.Pool) *SigningKeysRepository {
+	return &SigningKeysRepository{db: conn}
+}
+
+// FindActive возвращает ключи, которыми ещё можно проверять токены; самый новый — первым
+func (r *SigningKeysRepository) FindActive(c context.Context) ([]models.SigningKey, error) {
+	rows, err := r.db.Query(c, `
+	select kid, algorithm, private_key, public_key, created_at, retired_at
+	from jwt_signing_keys
+	where retired_at is null or retired_at > now()
+	order by created_at desc`)
+	if err != nil {
+		l := logger.GetLogger()
+		l.Error(err.Error())
+		return nil, err
+	}
+	defer rows.Close()
+
+	keys := make([]models.SigningKey, 0)
+	for rows.Next() {
+		var key models.SigningKey
+		err := rows.Scan(&key.Kid, &key.Algorithm, &key.PrivateKey, &key.PublicKey, &key.CreatedAt, &key.RetiredAt)
+		if err != nil {
+			return nil, err
+		}
+
+		keys = append(keys, key)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return keys, nil
+}
+
+// UpdatePrivateKey заменяет приватный ключ, например зашифрованным вариантом того же ключа
+func (r *SigningKeysRepository) UpdatePrivateKey(c context.Context, kid string, privateKey string) error {
+	_, err := r.db.Exec(c, "update jwt_signing_keys set private_key = $2 where kid = $1", kid, privateKey)
+	if err != nil {
+		l := logger.GetLogger()
+		l.Error(err.Error())
+		return err
+	}
+
+	return nil
+}
+
+// Rotate добавляет новый ключ подписи, а прежние оставляет для проверки ещё на gracePeriod
+func (r *SigningKeysRepository) Rotate(c context.Context, key models.SigningKey, gracePeriod time.Duration) error {
+	l := logger.GetLogger()
+	tx, err := r.db.Begin(c)
+	if err != nil {
+		l.Error(err.Error())
+		return err
+	}
+
+	defer func() {
+		if err != nil {
+			tx.Rollback(c) // Если ошибка, откатываем транзакцию
+		}
+	}()
+
+	_, err = tx.Exec(c, "update jwt_signing_keys set retired_at = $1 where retired_at is null", time.Now().Add(gracePeriod))
+	if err != nil {
+		l.Error(err.Error())
+		return err
+	}
+
+	_, err = tx.Exec(c,
+		"insert into jwt_signing_keys(kid, algorithm, private_key, public_key) values($1, $2, $3, $4)",
+		key.Kid,
+		key.Algorithm,
+		key.PrivateKey,
+		key.PublicKey)
+	if err != nil {
+		l.Error(err.Error())
+		return err
+	}
+
+	err = tx.Commit(c)
+	if err != nil {
+		l.Error(err.Error())
+		return err
+	}
+
+	l.Info(fmt.Sprintf("новый ключ подписи %s (%s) создан", key.Kid, key.Algorithm))
+
+	return nil
+}
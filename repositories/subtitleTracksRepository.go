@@ -0,0 +1,225 @@
+package repositories
+
+import (
+	"context"
+	"goozinshe/logger"
+	"goozinshe/models"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+const subtitleTrackColumns = `id, movie_id, allserie_id, language, label, is_default, offset_ms, version, created_at, updated_at`
+
+type SubtitleTracksRepository struct {
+	db *pgxpool.Pool
+}
+
+func NewSubtitleTracksRepository(conn *pgxpool.Pool) *SubtitleTracksRepository {
+	return &SubtitleTracksRepository{db: conn}
+}
+
+func (r *SubtitleTracksRepository) Create(c context.Context, track models.SubtitleTrack) (models.SubtitleTrack, error) {
+	l := logger.GetLogger()
+
+	tx, err := r.db.Begin(c)
+	if err != nil {
+		l.Error(err.Error())
+		return models.SubtitleTrack{}, err
+	}
+
+	defer func() {
+		if err != nil {
+			tx.Rollback(c) // Если ошибка, откатываем транзакцию
+		}
+	}()
+
+	if track.IsDefault {
+		err = clearDefaultSubtitles(c, tx, track.MovieId, track.AllSeriesId, 0)
+		if err != nil {
+			l.Error(err.Error())
+			return models.SubtitleTrack{}, err
+		}
+	}
+
+	row := tx.QueryRow(c,
+		"insert into subtitle_tracks(movie_id, allserie_id, language, label, is_default, offset_ms) values($1, $2, $3, $4, $5, $6) returning "+subtitleTrackColumns,
+		track.MovieId,
+		track.AllSeriesId,
+		track.Language,
+		track.Label,
+		track.IsDefault,
+		track.OffsetMs)
+
+	created, err := scanSubtitleTrack(row)
+	if err != nil {
+		l.Error(err.Error())
+		return models.SubtitleTrack{}, err
+	}
+
+	err = tx.Commit(c)
+	if err != nil {
+		l.Error(err.Error())
+		return models.SubtitleTrack{}, err
+	}
+
+	return created, nil
+}
+
+func (r *SubtitleTracksRepository) FindById(c context.Context, id int) (models.SubtitleTrack, error) {
+	row := r.db.QueryRow(c, "select "+subtitleTrackColumns+" from subtitle_tracks where id = $1", id)
+
+	track, err := scanSubtitleTrack(row)
+	if err != nil {
+		l := logger.GetLogger()
+		l.Error(err.Error())
+		return models.SubtitleTrack{}, err
+	}
+
+	return track, nil
+}
+
+// FindPublished — дорожка, если её фильм опубликован и не в корзине; иначе pgx.ErrNoRows
+func (r *SubtitleTracksRepository) FindPublished(c context.Context, id int) (models.SubtitleTrack, error) {
+	row := r.db.QueryRow(c,
+		"select "+subtitleTrackColumns+" from subtitle_tracks where id = $1 and exists(select 1 from movies m where m.id = subtitle_tracks.movie_id and m.deleted_at is null and m.status = 'published')",
+		id)
+
+	track, err := scanSubtitleTrack(row)
+	if err != nil {
+		l := logger.GetLogger()
+		l.Error(err.Error())
+		return models.SubtitleTrack{}, err
+	}
+
+	return track, nil
+}
+
+// FindByMovie — дорожки фильма и всех его серий; дорожка по умолчанию первой
+func (r *SubtitleTracksRepository) FindByMovie(c context.Context, movieId int) ([]models.SubtitleTrack, error) {
+	return r.findAll(c, "where movie_id = $1", movieId)
+}
+
+func (r *SubtitleTracksRepository) FindByAllSeries(c context.Context, allserieId int) ([]models.SubtitleTrack, error) {
+	return r.findAll(c, "where allserie_id = $1", allserieId)
+}
+
+func (r *SubtitleTracksRepository) findAll(c context.Context, where string, args ...any) ([]models.SubtitleTrack, error) {
+	l := logger.GetLogger()
+	rows, err := r.db.Query(c, "select "+subtitleTrackColumns+" from subtitle_tracks "+where+" order by allserie_id nulls first, is_default desc, language, id", args...)
+	if err != nil {
+		l.Error(err.Error())
+		return nil, err
+	}
+	defer rows.Close()
+
+	tracks := make([]models.SubtitleTrack, 0)
+	for rows.Next() {
+		track, err := scanSubtitleTrack(rows)
+		if err != nil {
+			l.Error(err.Error())
+			return nil, err
+		}
+		tracks = append(tracks, track)
+	}
+
+	return tracks, rows.Err()
+}
+
+// Update сохраняет язык, название, сдвиг и флаг по умолчанию; флаг снимается с остальных дорожек того же фильма или серии
+func (r *SubtitleTracksRepository) Update(c context.Context, id int, track models.SubtitleTrack) error {
+	l := logger.GetLogger()
+
+	tx, err := r.db.Begin(c)
+	if err != nil {
+		l.Error(err.Error())
+		return err
+	}
+
+	defer func() {
+		if err != nil {
+			tx.Rollback(c) // Если ошибка, откатываем транзакцию
+		}
+	}()
+
+	if track.IsDefault {
+		err = clearDefaultSubtitles(c, tx, track.MovieId, track.AllSeriesId, id)
+		if err != nil {
+			l.Error(err.Error())
+			return err
+		}
+	}
+
+	tag, err := tx.Exec(c,
+		`
+	update subtitle_tracks
+	set language = $1, label = $2, is_default = $3, offset_ms = $4, version = version + 1, updated_at = now()
+	where id = $5 and version = $6
+	`,
+		track.Language,
+		track.Label,
+		track.IsDefault,
+		track.OffsetMs,
+		id,
+		track.Version)
+	if err != nil {
+		l.Error(err.Error())
+		return err
+	}
+
+	err = checkVersion(tag)
+	if err != nil {
+		return err
+	}
+
+	err = tx.Commit(c)
+	if err != nil {
+		l.Error(err.Error())
+	}
+
+	return err
+}
+
+func (r *SubtitleTracksRepository) Delete(c context.Context, id int, version int) error {
+	tag, err := r.db.Exec(c, "delete from subtitle_tracks where id = $1 and version = $2", id, version)
+	if err != nil {
+		l := logger.GetLogger()
+		l.Error(err.Error())
+		return err
+	}
+
+	return checkVersion(tag)
+}
+
+// clearDefaultSubtitles снимает флаг по умолчанию с дорожек фильма или серии, кроме exceptId
+func clearDefaultSubtitles(c context.Context, tx pgx.Tx, movieId int, allserieId *int, exceptId int) error {
+	_, err := tx.Exec(c,
+		`
+	update subtitle_tracks
+	set is_default = false, version = version + 1, updated_at = now()
+	where movie_id = $1 and allserie_id is not distinct from $2 and is_default and id <> $3
+	`,
+		movieId,
+		allserieId,
+		exceptId)
+
+	return err
+}
+
+func scanSubtitleTrack(row pgx.Row) (models.SubtitleTrack, error) {
+	var track models.SubtitleTrack
+	err := row.Scan(
+		&track.Id,
+		&track.MovieId,
+		&track.AllSeriesId,
+		&track.Language,
+		&track.Label,
+		&track.IsDefault,
+		&track.OffsetMs,
+		&track.Version,
+		&track.CreatedAt,
+		&track.UpdatedAt)
+	track.Url = models.SubtitleTrackUrl(track.Id)
+
+	return track, err
+}
@@ -0,0 +1,172 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"goozinshe/logger"
+	"goozinshe/models"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+var ErrUnknownTranslationType = errors.New("unknown translation entity type")
+
+// таблицы записей, у которых есть переводы
+var translationTables = map[string]string{
+	models.AuditEntityMovie:    "movies",
+	models.AuditEntityGenre:    "genres",
+	models.AuditEntityCategory: "categories",
+	models.AuditEntityAge:      "ages",
+}
+
+type TranslationsRepository struct {
+	db *pgxpool.Pool
+}
+
+func NewTranslationsRepository(conn *pgxpool.Pool) *TranslationsRepository {
+	return &TranslationsRepository{db: conn}
+}
+
+// FindByLocale одним запросом загружает переводы для набора записей разных типов
+func (r *TranslationsRepository) FindByLocale(c context.Context, locale string, refs []models.TranslationKey) (map[models.TranslationKey]map[string]string, error) {
+	translations := make(map[models.TranslationKey]map[string]string)
+	if len(refs) == 0 {
+		return translations, nil
+	}
+
+	types := make([]string, 0, len(refs))
+	ids := make([]int, 0, len(refs))
+	for _, ref := range refs {
+		types = append(types, ref.EntityType)
+		ids = append(ids, ref.EntityId)
+	}
+
+	l := logger.GetLogger()
+	rows, err := r.db.Query(c,
+		`
+	select entity_type, entity_id, field, value
+	from translations
+	where locale = $1 and (entity_type, entity_id) in (select * from unnest($2::text[], $3::int[]))
+	`, locale, types, ids)
+	if err != nil {
+		l.Error(err.Error())
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var key models.TranslationKey
+		var field, value string
+		err := rows.Scan(&key.EntityType, &key.EntityId, &field, &value)
+		if err != nil {
+			l.Error(err.Error())
+			return nil, err
+		}
+
+		if translations[key] == nil {
+			translations[key] = make(map[string]string)
+		}
+		translations[key][field] = value
+	}
+
+	return translations, rows.Err()
+}
+
+func (r *TranslationsRepository) FindByEntity(c context.Context, entityType string, entityId int) ([]models.Translation, error) {
+	l := logger.GetLogger()
+	rows, err := r.db.Query(c,
+		"select locale, field, value from translations where entity_type = $1 and entity_id = $2 order by locale, field",
+		entityType, entityId)
+	if err != nil {
+		l.Error(err.Error())
+		return nil, err
+	}
+	defer rows.Close()
+
+	translations := make([]models.Translation, 0)
+	for rows.Next() {
+		var translation models.Translation
+		err := rows.Scan(&translation.Locale, &translation.Field, &translation.Value)
+		if err != nil {
+			l.Error(err.Error())
+			return nil, err
+		}
+		translations = append(translations, translation)
+	}
+
+	return translations, rows.Err()
+}
+
+// EntityExists проверяет, что переводимая запись существует и не лежит в корзине
+func (r *TranslationsRepository) EntityExists(c context.Context, entityType string, entityId int) (bool, error) {
+	table, ok := translationTables[entityType]
+	if !ok {
+		return false, ErrUnknownTranslationType
+	}
+
+	var exists bool
+	row := r.db.QueryRow(c, fmt.Sprintf("select exists(select 1 from %s where id = $1 and deleted_at is null)", table), entityId)
+	err := row.Scan(&exists)
+	if err != nil {
+		l := logger.GetLogger()
+		l.Error(err.Error())
+	}
+
+	return exists, err
+}
+
+// Set сохраняет переводы одной локали; пустое значение удаляет перевод поля
+func (r *TranslationsRepository) Set(c context.Context, entityType string, entityId int, locale string, values map[string]string) error {
+	l := logger.GetLogger()
+	tx, err := r.db.Begin(c)
+	if err != nil {
+		l.Error(err.Error())
+		return err
+	}
+
+	defer func() {
+		if err != nil {
+			tx.Rollback(c) // Если ошибка, откатываем транзакцию
+		}
+	}()
+
+	for field, value := range values {
+		if value == "" {
+			_, err = tx.Exec(c,
+				"delete from translations where entity_type = $1 and entity_id = $2 and locale = $3 and field = $4",
+				entityType, entityId, locale, field)
+		} else {
+			_, err = tx.Exec(c,
+				`
+			insert into translations(entity_type, entity_id, locale, field, value) values($1, $2, $3, $4, $5)
+			on conflict (entity_type, entity_id, locale, field) do update set value = excluded.value
+			`,
+				entityType, entityId, locale, field, value)
+		}
+		if err != nil {
+			l.Error(err.Error())
+			return err
+		}
+	}
+
+	err = tx.Commit(c)
+	if err != nil {
+		l.Error(err.Error())
+		return err
+	}
+
+	return nil
+}
+
+func (r *TranslationsRepository) DeleteLocale(c context.Context, entityType string, entityId int, locale string) error {
+	_, err := r.db.Exec(c,
+		"delete from translations where entity_type = $1 and entity_id = $2 and locale = $3",
+		entityType, entityId, locale)
+	if err != nil {
+		l := logger.GetLogger()
+		l.Error(err.Error())
+	}
+
+	return err
+}
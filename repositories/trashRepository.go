@@ -0,0 +1,191 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"goozinshe/logger"
+	"goozinshe/models"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+var ErrUnknownTrashType = errors.New("unknown trash type")
+
+type trashTable struct {
+	table       string
+	titleColumn string
+	// таблица связей с фильмами и колонка со ссылкой на запись, чистятся перед окончательным удалением
+	linkTable  string
+	linkColumn string
+}
+
+var trashTables = map[string]trashTable{
+	models.AuditEntityMovie:     {table: "movies", titleColumn: "title"},
+	models.AuditEntityGenre:     {table: "genres", titleColumn: "title", linkTable: "movies_genres", linkColumn: "genre_id"},
+	models.AuditEntityCategory:  {table: "categories", titleColumn: "title", linkTable: "movies_categories", linkColumn: "categorie_id"},
+	models.AuditEntityAge:       {table: "ages", titleColumn: "age", linkTable: "movies_ages", linkColumn: "age_id"},
+	models.AuditEntityAllSeries: {table: "allseries", titleColumn: "title", linkTable: "movies_allseries", linkColumn: "allserie_id"},
+	models.AuditEntityPerson:    {table: "people", titleColumn: "name", linkTable: "movie_credits", linkColumn: "person_id"},
+}
+
+// порядок обхода фиксированный, чтобы корзина и очистка вели себя одинаково при каждом запуске
+var trashTypes = []string{
+	models.AuditEntityMovie,
+	models.AuditEntityGenre,
+	models.AuditEntityCategory,
+	models.AuditEntityAge,
+	models.AuditEntityAllSeries,
+	models.AuditEntityPerson,
+}
+
+// ссылки фильма на жанры, категории, возрасты, серии и состав
+var movieLinkTables = []string{"movies_genres", "movies_categories", "movies_ages", "movies_allseries", "movie_credits"}
+
+type TrashRepository struct {
+	db *pgxpool.Pool
+}
+
+func NewTrashRepository(conn *pgxpool.Pool) *TrashRepository {
+	return &TrashRepository{db: conn}
+}
+
+func (r *TrashRepository) FindAll(c context.Context, entityType string) ([]models.TrashItem, error) {
+	types := trashTypes
+	if entityType != "" {
+		if _, ok := trashTables[entityType]; !ok {
+			return nil, ErrUnknownTrashType
+		}
+		types = []string{entityType}
+	}
+
+	queries := make([]string, 0, len(types))
+	for _, t := range types {
+		table := trashTables[t]
+		queries = append(queries, fmt.Sprintf(
+			"select '%s', id, coalesce(%s, ''), deleted_at from %s where deleted_at is not null",
+			t, table.titleColumn, table.table))
+	}
+
+	sql := fmt.Sprintf("%s order by 4 desc", strings.Join(queries, " union all "))
+
+	l := logger.GetLogger()
+	rows, err := r.db.Query(c, sql)
+	if err != nil {
+		l.Error(err.Error())
+		return nil, err
+	}
+	defer rows.Close()
+
+	items := make([]models.TrashItem, 0)
+	for rows.Next() {
+		var item models.TrashItem
+		err := rows.Scan(&item.Type, &item.Id, &item.Title, &item.DeletedAt)
+		if err != nil {
+			l.Error(err.Error())
+			return nil, err
+		}
+
+		items = append(items, item)
+	}
+
+	err = rows.Err()
+	if err != nil {
+		l.Error(err.Error())
+		return nil, err
+	}
+
+	return items, nil
+}
+
+func (r *TrashRepository) Restore(c context.Context, entityType string, id int) error {
+	table, ok := trashTables[entityType]
+	if !ok {
+		return ErrUnknownTrashType
+	}
+
+	tag, err := r.db.Exec(c,
+		fmt.Sprintf("update %s set deleted_at = null where id = $1 and deleted_at is not null", table.table),
+		id)
+	if err != nil {
+		l := logger.GetLogger()
+		l.Error(err.Error())
+		return err
+	}
+
+	if tag.RowsAffected() == 0 {
+		return pgx.ErrNoRows
+	}
+
+	return nil
+}
+
+// Purge окончательно удаляет записи, которые лежат в корзине дольше deletedBefore
+func (r *TrashRepository) Purge(c context.Context, deletedBefore time.Time) (int64, error) {
+	l := logger.GetLogger()
+	tx, err := r.db.Begin(c)
+	if err != nil {
+		l.Error(err.Error())
+		return 0, err
+	}
+
+	defer func() {
+		if err != nil {
+			tx.Rollback(c) // Если ошибка, откатываем транзакцию
+		}
+	}()
+
+	for _, linkTable := range movieLinkTables {
+		_, err = tx.Exec(c,
+			fmt.Sprintf("delete from %s where movie_id in (select id from movies where deleted_at < $1)", linkTable),
+			deletedBefore)
+		if err != nil {
+			l.Error(err.Error())
+			return 0, err
+		}
+	}
+
+	var purged int64
+	for _, t := range trashTypes {
+		table := trashTables[t]
+		if table.linkTable != "" {
+			_, err = tx.Exec(c,
+				fmt.Sprintf("delete from %s where %s in (select id from %s where deleted_at < $1)", table.linkTable, table.linkColumn, table.table),
+				deletedBefore)
+			if err != nil {
+				l.Error(err.Error())
+				return 0, err
+			}
+		}
+
+		// переводы ссылаются на запись без внешнего ключа
+		_, err = tx.Exec(c,
+			fmt.Sprintf("delete from translations where entity_type = $2 and entity_id in (select id from %s where deleted_at < $1)", table.table),
+			deletedBefore, t)
+		if err != nil {
+			l.Error(err.Error())
+			return 0, err
+		}
+
+		var tag pgconn.CommandTag
+		tag, err = tx.Exec(c, fmt.Sprintf("delete from %s where deleted_at < $1", table.table), deletedBefore)
+		if err != nil {
+			l.Error(err.Error())
+			return 0, err
+		}
+
+		purged += tag.RowsAffected()
+	}
+
+	err = tx.Commit(c)
+	if err != nil {
+		l.Error(err.Error())
+		return 0, err
+	}
+
+	return purged, nil
+}
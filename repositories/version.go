@@ -0,0 +1,19 @@
+package repositories
+
+import (
+	"errors"
+
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+// ErrVersionConflict — запись изменили или удалили после того, как клиент её прочитал
+var ErrVersionConflict = errors.New("version conflict")
+
+// checkVersion: update с условием на version не затронул ни одной строки — значит версия устарела
+func checkVersion(tag pgconn.CommandTag) error {
+	if tag.RowsAffected() == 0 {
+		return ErrVersionConflict
+	}
+
+	return nil
+}
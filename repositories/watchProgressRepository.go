@@ -0,0 +1,181 @@
+package repositories
+
+import (
+	"context"
+	"goozinshe/logger"
+	"goozinshe/models"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+const watchProgressColumns = `user_id, profile_id, movie_id, allserie_id, position_seconds, duration_seconds, completed, watched_at, updated_at`
+
+type WatchProgressRepository struct {
+	db *pgxpool.Pool
+}
+
+func NewWatchProgressRepository(conn *pgxpool.Pool) *WatchProgressRepository {
+	return &WatchProgressRepository{db: conn}
+}
+
+// Save записывает heartbeat плеера; watched_at ставится один раз, при первом досмотре
+func (r *WatchProgressRepository) Save(c context.Context, progress models.WatchProgress) (models.WatchProgress, error) {
+	row := r.db.QueryRow(c,
+		`
+	insert into watch_progress(user_id, profile_id, movie_id, allserie_id, position_seconds, duration_seconds, completed, watched_at, updated_at)
+	values($1, $2, $3, $4, $5, $6, $7, case when $7 then now() end, now())
+	on conflict (user_id, coalesce(profile_id, 0), movie_id, coalesce(allserie_id, 0)) do update
+	set position_seconds = excluded.position_seconds,
+		duration_seconds = excluded.duration_seconds,
+		completed = excluded.completed,
+		watched_at = coalesce(watch_progress.watched_at, excluded.watched_at),
+		updated_at = now()
+	returning `+watchProgressColumns,
+		progress.UserId,
+		progress.ProfileId,
+		progress.MovieId,
+		progress.AllSeriesId,
+		progress.PositionSeconds,
+		progress.DurationSeconds,
+		progress.Completed)
+
+	saved, err := scanWatchProgress(row)
+	if err != nil {
+		l := logger.GetLogger()
+		l.Error(err.Error())
+		return models.WatchProgress{}, err
+	}
+
+	return saved, nil
+}
+
+// FindByMovie — прогресс пользователя (профиля) по фильму и всем его сериям
+func (r *WatchProgressRepository) FindByMovie(c context.Context, userId int, profileId *int, movieId int) ([]models.WatchProgress, error) {
+	l := logger.GetLogger()
+	rows, err := r.db.Query(c,
+		"select "+watchProgressColumns+" from watch_progress where user_id = $1 and profile_id is not distinct from $2 and movie_id = $3 order by allserie_id nulls first",
+		userId,
+		profileId,
+		movieId)
+	if err != nil {
+		l.Error(err.Error())
+		return nil, err
+	}
+	defer rows.Close()
+
+	progress := make([]models.WatchProgress, 0)
+	for rows.Next() {
+		item, err := scanWatchProgress(rows)
+		if err != nil {
+			l.Error(err.Error())
+			return nil, err
+		}
+		progress = append(progress, item)
+	}
+
+	return progress, rows.Err()
+}
+
+// FindContinueWatching берёт по каждому фильму последний просмотр: недосмотренное продолжаем с места остановки,
+// после досмотренной серии предлагаем следующую по номеру. Досмотренные фильмы и последние серии не попадают
+func (r *WatchProgressRepository) FindContinueWatching(c context.Context, userId int, profileId *int, limit int, maxAge *int) ([]models.ContinueWatchingItem, error) {
+	sql :=
+		`
+	with latest as (
+		select distinct on (p.movie_id) p.*
+		from watch_progress p
+		where p.user_id = $1 and p.profile_id is not distinct from $2
+		order by p.movie_id, p.updated_at desc
+	)
+	select
+		m.id,
+		m.title,
+		m.poster_url,
+		case when l.completed then n.id else l.allserie_id end,
+		case when l.completed then n.series else cur.series end,
+		case when l.completed then n.title else cur.title end,
+		case when l.completed then 0 else l.position_seconds end,
+		case when l.completed then 0 else l.duration_seconds end,
+		l.completed,
+		l.updated_at
+	from latest l
+	join movies m on m.id = l.movie_id and m.deleted_at is null and m.status = 'published'
+	left join allseries cur on cur.id = l.allserie_id
+	left join lateral (
+		select e.id, e.series, e.title
+		from movies_allseries me
+		join allseries e on e.id = me.allserie_id and e.deleted_at is null
+		where me.movie_id = l.movie_id and e.series > cur.series
+		order by e.series
+		limit 1
+	) n on l.completed
+	where ((not l.completed and l.position_seconds > 0) or n.id is not null)
+		and ($4::int is null or ` + ageLimitCondition("$4") + `)
+	order by l.updated_at desc
+	limit $3
+	`
+
+	l := logger.GetLogger()
+	rows, err := r.db.Query(c, sql, userId, profileId, limit, maxAge)
+	if err != nil {
+		l.Error(err.Error())
+		return nil, err
+	}
+	defer rows.Close()
+
+	items := make([]models.ContinueWatchingItem, 0)
+	for rows.Next() {
+		var item models.ContinueWatchingItem
+		err := rows.Scan(
+			&item.MovieId,
+			&item.Title,
+			&item.PosterUrl,
+			&item.AllSeriesId,
+			&item.Series,
+			&item.EpisodeTitle,
+			&item.PositionSeconds,
+			&item.DurationSeconds,
+			&item.IsNextEpisode,
+			&item.UpdatedAt)
+		if err != nil {
+			l.Error(err.Error())
+			return nil, err
+		}
+		items = append(items, item)
+	}
+
+	return items, rows.Err()
+}
+
+// IsMovieEpisode проверяет, что серия относится к фильму
+func (r *WatchProgressRepository) IsMovieEpisode(c context.Context, movieId int, allserieId int) (bool, error) {
+	return isMovieEpisode(c, r.db, movieId, allserieId)
+}
+
+func isMovieEpisode(c context.Context, db *pgxpool.Pool, movieId int, allserieId int) (bool, error) {
+	var exists bool
+	err := db.QueryRow(c, "select exists(select 1 from movies_allseries where movie_id = $1 and allserie_id = $2)", movieId, allserieId).Scan(&exists)
+	if err != nil {
+		l := logger.GetLogger()
+		l.Error(err.Error())
+	}
+
+	return exists, err
+}
+
+func scanWatchProgress(row pgx.Row) (models.WatchProgress, error) {
+	var progress models.WatchProgress
+	err := row.Scan(
+		&progress.UserId,
+		&progress.ProfileId,
+		&progress.MovieId,
+		&progress.AllSeriesId,
+		&progress.PositionSeconds,
+		&progress.DurationSeconds,
+		&progress.Completed,
+		&progress.WatchedAt,
+		&progress.UpdatedAt)
+
+	return progress, err
+}
@@ -0,0 +1,13 @@
+package repositories
+
+import "fmt"
+
+// watchedCondition — условие "фильм m досмотрен пользователем userParam в профиле profileParam".
+// Фильм досмотрен, когда досмотрен он сам, сериал — когда досмотрена последняя серия.
+// Без пользователя (API-ключ, аноним) условие ложно
+func watchedCondition(userParam string, profileParam string) string {
+	return fmt.Sprintf(
+		`exists(select 1 from watch_progress wp where wp.movie_id = m.id and wp.user_id = %s and wp.profile_id is not distinct from %s and wp.completed
+			and (wp.allserie_id is null or not exists(select 1 from movies_allseries wme join allseries we on we.id = wme.allserie_id and we.deleted_at is null join allseries wc on wc.id = wp.allserie_id where wme.movie_id = m.id and we.series > wc.series)))`,
+		userParam, profileParam)
+}
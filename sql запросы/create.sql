@@ -88,3 +88,366 @@ ALTER TABLE movies_allseries
 ADD CONSTRAINT fk_movies_allsesries
 FOREIGN KEY (movie_id) REFERENCES movie(id) ON DELETE CASCADE;
  ___________________________________________________________
+
+
+create table api_keys
+(
+    id           serial primary key,
+    name         text not null,
+    key_prefix   text not null,
+    key_hash     text not null unique,
+    permissions  text[] not null default '{}',
+    created_by   int references users(id) on delete set null,
+    created_at   timestamp not null default now(),
+    expires_at   timestamp,
+    last_used_at timestamp,
+    revoked_at   timestamp
+);
+
+
+create table jwt_signing_keys
+(
+    kid         text primary key,
+    algorithm   text not null,
+    private_key text not null,
+    public_key  text not null,
+    created_at  timestamp not null default now(),
+    retired_at  timestamp
+);
+
+
+-- профиль зрителя; создаётся раньше user_sessions, чтобы сессия могла на него ссылаться
+create table profiles
+(
+    id         serial primary key,
+    user_id    int not null references users(id) on delete cascade,
+    name       text not null,
+    avatar_url text,
+    language   text not null check (language in ('kk', 'ru', 'en')),
+    max_age_id int references ages(id) on delete set null,
+    is_kids    boolean not null default false,
+    version    int not null default 1,
+    created_at timestamp not null default now(),
+    updated_at timestamp not null default now()
+);
+
+create index profiles_user_idx on profiles(user_id);
+
+
+create table user_sessions
+(
+    id            uuid primary key,
+    user_id       int not null references users(id) on delete cascade,
+    user_agent    text,
+    ip            text,
+    created_at    timestamp not null default now(),
+    last_seen_at  timestamp not null default now(),
+    expires_at    timestamp not null,
+    terminated_at timestamp,
+    -- до какого момента в сессии сняты родительские ограничения
+    parental_unlocked_until timestamp,
+    -- выбранный в сессии профиль: его возрастной лимит действует для всех токенов сессии
+    profile_id    int references profiles(id) on delete set null
+);
+
+create index user_sessions_user_id_idx on user_sessions(user_id);
+
+
+create table audit_events
+(
+    id               bigserial primary key,
+    actor_user_id    int,
+    actor_api_key_id int,
+    action           text not null,
+    entity_type      text not null,
+    entity_id        int not null,
+    before           jsonb,
+    after            jsonb,
+    ip               text,
+    created_at       timestamp not null default now()
+);
+
+create index audit_events_entity_idx on audit_events(entity_type, entity_id);
+create index audit_events_created_at_idx on audit_events(created_at);
+
+-- журнал только дописывается
+create rule audit_events_no_update as on update to audit_events do instead nothing;
+create rule audit_events_no_delete as on delete to audit_events do instead nothing;
+
+
+alter table movies add column deleted_at timestamp;
+alter table genres add column deleted_at timestamp;
+alter table categories add column deleted_at timestamp;
+alter table ages add column deleted_at timestamp;
+alter table allseries add column deleted_at timestamp;
+
+
+create table movie_revisions
+(
+    id         serial primary key,
+    movie_id   int not null references movies(id) on delete cascade,
+    revision   int not null,
+    snapshot   jsonb not null,
+    created_by int references users(id) on delete set null,
+    created_at timestamp not null default now(),
+    unique (movie_id, revision)
+);
+
+
+-- уже существующие фильмы остаются опубликованными, новые создаются черновиками
+alter table movies add column status text not null default 'published'
+    check (status in ('draft', 'in_review', 'published', 'archived'));
+alter table movies alter column status set default 'draft';
+alter table movies add column publish_at timestamp;
+
+create index movies_scheduled_idx on movies(publish_at) where status in ('draft', 'in_review');
+
+
+alter table movies add column version int not null default 1;
+alter table genres add column version int not null default 1;
+alter table categories add column version int not null default 1;
+alter table ages add column version int not null default 1;
+alter table allseries add column version int not null default 1;
+
+
+alter table movies add column external_key varchar(255) unique;
+
+create table import_jobs
+(
+    id           serial primary key,
+    status       text not null default 'pending'
+        check (status in ('pending', 'running', 'completed', 'failed')),
+    format       text not null,
+    total_rows   int not null default 0,
+    created_rows int not null default 0,
+    updated_rows int not null default 0,
+    failed_rows  int not null default 0,
+    error        text,
+    row_errors   jsonb,
+    created_by   int,
+    created_at   timestamp not null default now(),
+    started_at   timestamp,
+    finished_at  timestamp
+);
+
+
+create table metadata_titles
+(
+    source         text not null,
+    external_id    text not null,
+    title_type     text not null,
+    title          text not null,
+    original_title text not null default '',
+    year           int,
+    directors      text[] not null default '{}',
+    genres         text[] not null default '{}',
+    description    text not null default '',
+    primary key (source, external_id)
+);
+
+create index metadata_titles_title_idx on metadata_titles(lower(title) text_pattern_ops);
+create index metadata_titles_original_title_idx on metadata_titles(lower(original_title) text_pattern_ops);
+
+create table metadata_genre_mappings
+(
+    source_genre text primary key,
+    genre_id     int not null references genres(id) on delete cascade
+);
+
+
+create table people
+(
+    id            serial primary key,
+    name          varchar(255) not null,
+    original_name varchar(255) not null default '',
+    bio           text not null default '',
+    birth_date    date,
+    photo_url     text not null default '',
+    version       int not null default 1,
+    deleted_at    timestamp
+);
+
+create table movie_credits
+(
+    id            serial primary key,
+    movie_id      int not null references movies(id) on delete cascade,
+    allserie_id   int references allseries(id) on delete cascade,
+    person_id     int not null references people(id) on delete cascade,
+    role          text not null
+        check (role in ('director', 'actor', 'writer', 'voice_actor')),
+    character     text not null default '',
+    billing_order int not null default 0
+);
+
+create unique index movie_credits_unique_idx on movie_credits(movie_id, coalesce(allserie_id, 0), person_id, role);
+create index movie_credits_person_idx on movie_credits(person_id);
+
+
+create table translations(
+    entity_type text not null,
+    entity_id int not null,
+    locale text not null check (locale in ('kk', 'ru', 'en')),
+    field text not null,
+    value text not null,
+    primary key (entity_type, entity_id, locale, field)
+);
+
+
+create table media_assets
+(
+    id             serial primary key,
+    movie_id       int not null references movies(id) on delete cascade,
+    allserie_id    int references allseries(id) on delete cascade,
+    status         text not null default 'uploading'
+        check (status in ('uploading', 'queued', 'processing', 'ready', 'failed')),
+    filename       text not null,
+    size           bigint not null check (size > 0),
+    uploaded_bytes bigint not null default 0,
+    renditions     text[] not null default '{}',
+    playback_url   text,
+    error          text,
+    created_by     int,
+    created_at     timestamp not null default now(),
+    updated_at     timestamp not null default now(),
+    ready_at       timestamp
+);
+
+create index media_assets_movie_idx on media_assets(movie_id);
+create index media_assets_queue_idx on media_assets(id) where status = 'queued';
+
+
+create table subtitle_tracks
+(
+    id          serial primary key,
+    movie_id    int not null references movies(id) on delete cascade,
+    allserie_id int references allseries(id) on delete cascade,
+    language    text not null,
+    label       text not null,
+    is_default  boolean not null default false,
+    offset_ms   int not null default 0,
+    version     int not null default 1,
+    created_at  timestamp not null default now(),
+    updated_at  timestamp not null default now()
+);
+
+create index subtitle_tracks_movie_idx on subtitle_tracks(movie_id);
+create index subtitle_tracks_allserie_idx on subtitle_tracks(allserie_id);
+-- у фильма и у каждой серии не больше одной дорожки по умолчанию
+create unique index subtitle_tracks_default_idx on subtitle_tracks(movie_id, coalesce(allserie_id, 0)) where is_default;
+
+
+create table watch_progress
+(
+    user_id          int not null references users(id) on delete cascade,
+    profile_id       int references profiles(id) on delete cascade,
+    movie_id         int not null references movies(id) on delete cascade,
+    allserie_id      int references allseries(id) on delete cascade,
+    position_seconds int not null check (position_seconds >= 0),
+    duration_seconds int not null check (duration_seconds > 0),
+    completed        boolean not null default false,
+    watched_at       timestamp,
+    updated_at       timestamp not null default now()
+);
+
+-- одна запись на фильм или серию в профиле; coalesce — чтобы фильм без серий и запросы без профиля тоже были уникальны
+create unique index watch_progress_item_idx on watch_progress(user_id, coalesce(profile_id, 0), movie_id, coalesce(allserie_id, 0));
+create index watch_progress_recent_idx on watch_progress(user_id, profile_id, updated_at desc);
+
+
+-- возрастные рейтинги сравниваются по числу: "16+" -> 16
+alter table ages add column min_age int not null default 0;
+update ages set min_age = coalesce(substring(age from '\d+')::int, 0);
+
+alter table users add column max_age_id int references ages(id) on delete set null;
+alter table users add column parental_pin_hash text;
+alter table users add column parental_pin_failures int not null default 0;
+alter table users add column parental_blocked_until timestamp;
+
+
+create table favorites
+(
+    user_id    int not null references users(id) on delete cascade,
+    profile_id int references profiles(id) on delete cascade,
+    movie_id   int not null references movies(id) on delete cascade,
+    created_at timestamp not null default now()
+);
+
+-- одно избранное на фильм у аккаунта или профиля; null — запросы без выбранного профиля
+create unique index favorites_item_idx on favorites(user_id, coalesce(profile_id, 0), movie_id);
+create index favorites_recent_idx on favorites(user_id, profile_id, created_at desc);
+-- счётчик избранного в списках фильмов
+create index favorites_movie_idx on favorites(movie_id);
+
+
+create table reviews
+(
+    id              serial primary key,
+    movie_id        int not null references movies(id) on delete cascade,
+    allserie_id     int references allseries(id) on delete cascade,
+    parent_id       int references reviews(id) on delete cascade,
+    user_id         int not null references users(id) on delete cascade,
+    profile_id      int references profiles(id) on delete set null,
+    body            text not null,
+    status          text not null default 'pending' check (status in ('pending', 'approved', 'rejected')),
+    flagged_words   text[] not null default '{}',
+    moderation_note text,
+    moderated_by    int references users(id) on delete set null,
+    moderated_at    timestamp,
+    helpful_count   int not null default 0,
+    version         int not null default 1,
+    created_at      timestamp not null default now(),
+    updated_at      timestamp not null default now(),
+    deleted_at      timestamp
+);
+
+create index reviews_movie_idx on reviews(movie_id, allserie_id, created_at desc) where parent_id is null;
+create index reviews_parent_idx on reviews(parent_id, created_at) where parent_id is not null;
+create index reviews_user_idx on reviews(user_id, created_at desc);
+create index reviews_moderation_idx on reviews(status, created_at) where deleted_at is null;
+
+create table review_votes
+(
+    review_id  int not null references reviews(id) on delete cascade,
+    user_id    int not null references users(id) on delete cascade,
+    created_at timestamp not null default now(),
+    primary key (review_id, user_id)
+);
+
+create table review_reports
+(
+    id          serial primary key,
+    review_id   int not null references reviews(id) on delete cascade,
+    user_id     int not null references users(id) on delete cascade,
+    reason      text not null check (reason in ('spam', 'abuse', 'spoiler', 'other')),
+    comment     text,
+    created_at  timestamp not null default now(),
+    resolved_at timestamp,
+    unique (review_id, user_id)
+);
+
+-- слова фильтра отзывов; "*" в конце — все слова с этим началом
+create table bad_words
+(
+    id         serial primary key,
+    language   text not null check (language in ('kk', 'ru')),
+    word       text not null,
+    created_at timestamp not null default now()
+);
+
+create unique index bad_words_word_idx on bad_words(language, lower(word));
+
+
+create table recommendations
+(
+    user_id     int not null references users(id) on delete cascade,
+    profile_id  int references profiles(id) on delete cascade,
+    movie_id    int not null references movies(id) on delete cascade,
+    score       double precision not null,
+    computed_at timestamp not null default now()
+);
+
+-- пересчитывается фоновой задачей целиком для каждого зрителя; null — аккаунт без выбранного профиля
+create unique index recommendations_item_idx on recommendations(user_id, coalesce(profile_id, 0), movie_id);
+create index recommendations_viewer_idx on recommendations(user_id, profile_id, score desc);
+-- совместные просмотры для похожих фильмов
+create index watch_progress_movie_idx on watch_progress(movie_id, user_id);
@@ -5,21 +5,21 @@ INSERT INTO movies (title,description,release_year,director,rating,is_watched,tr
 	 'Ли Анкрич',
 	 0,
 	 false,
-	 'https://youtu.be/EmsZ9vcoKAo?si=nTn5_tKuN9gXjXWK','d379a777-e08c-4c9e-ab83-32e447cf78e4.jpeg'),
+	 'https://www.youtube.com/watch?v=EmsZ9vcoKAo','d379a777-e08c-4c9e-ab83-32e447cf78e4.jpeg'),
 	 ('Три кота Сериал',
 	 'В дружной кошачьей семье постоянно происходят поучительные и забавные события, героями которых становятся котята Карамелька, Компот и Коржик. С любыми трудностями им всегда помогают справиться родители —папа, глава семейства, трудящийся на кондитерской фабрике, и мама, хранительница домашнего уюта и по совместительству талантливый дизайнер детской одежды. Главные герои имеют свой неповторимый характер и отличительные черты. Семилетний Компот очень развит и эрудирован, все свободное время проводит с книгой и обожает разгадывать головоломки, периодически прерываясь на игры в шахматы с папой. Шестилетний Коржик — кот-батарейка, всегда озорной и с неограниченным запасом энергии, не представляет своей жизни без игр в футбол, бадминтон, догонялок и любой активности, а разговаривает всегда очень громко, шумно. Четырехлетняя Карамелька спокойна и рассудительна, мудра не по годам. Посмотрев онлайн сериал «Три Кота», ваш ребенок сможет познакомиться с героями поближе и узнать в них как себя, так и своих друзей, будет постепенно учиться общаться и взаимодействовать как со сверстниками, так и со взрослыми.',
 	 2015,
 	 'Дмитрий Высоцкий',
 	 0,
 	 false,
-	'https://www.youtube.com/watch?v=bgxiTkAlQrw&ab_channel=iVideos','d379a777-e08c-4c9e-ab83-32e447cf78e4.jpeg'),
+	'https://www.youtube.com/watch?v=bgxiTkAlQrw','d379a777-e08c-4c9e-ab83-32e447cf78e4.jpeg'),
 	 ('fgfbg',
 	 'bgfbgf',
 	  2014,
 	  'ggbgf',
 	  0,
 	  false,
-	  'https://www.youtube.com/',
+	  '',
 	  'd379a777-e08c-4c9e-ab83-32e447cf78e4.jpeg'),
 	 ('Байланысты әлемдер',
 	 'Екі параллель әлем бір тағдырмен байланысты. Біреуі кенеттен қайтыс болған эпидемиядан зардап шегеді, екіншісі тиранның мейіріміне бөленді. Бір әлемде Син есімді жігіт которидің балалық шақтағы досына ғашық, ал екіншісінде олар жау: ол Джин бүлікшісі, ал ол Котоконың деспоттық ханшайымы. Джин бір әлемдегі адамның өлімі оның доппелгангерінің өлімін де білдіретінін біледі, сондықтан Коториді өлтіру үшін біздің әлемге көшеді. Бірақ Ұлы басқа адамдардың қақтығыстары үшін махаббатын құрбан етуге дайын емес.',
@@ -27,19 +27,19 @@ INSERT INTO movies (title,description,release_year,director,rating,is_watched,tr
 	 'Юхэй Сакураги',
 	 0,
 	 false,
-	 'https://www.youtube.com/watch?v=Y14MaJCKAoI&pp=ygUb0KHQstGP0LfQsNC90L3Ri9C1INC80LjRgNGL',
+	 'https://www.youtube.com/watch?v=Y14MaJCKAoI',
 	 '4415cc51-fe93-45c7-8cff-a0b5c1862fae.jpeg'),
-	 ('«Айдар» мультхикаясы (2018 ж.) 1-бөлім ','Қасиетті тұмарды тауып алған Айдар атты кейіпкерімізге құпия міндет жүктеледі. Оның міндеті мифтік әлемде қалған бабалардың қаруын тауып, жеңілмес күшке ие болып, нағыз қаҺарманға айналу. Зұлымдық әміршісі Еркілікпен шайқасып, бабалар аманатын орындау',2018,'Дильшат Рахматуллин',0,false,'https://youtu.be/ZIN8iq9x19g?si=_z0nDSbguE_s7jo6','4415cc51-fe93-45c7-8cff-a0b5c1862fae.jpeg');
+	 ('«Айдар» мультхикаясы (2018 ж.) 1-бөлім ','Қасиетті тұмарды тауып алған Айдар атты кейіпкерімізге құпия міндет жүктеледі. Оның міндеті мифтік әлемде қалған бабалардың қаруын тауып, жеңілмес күшке ие болып, нағыз қаҺарманға айналу. Зұлымдық әміршісі Еркілікпен шайқасып, бабалар аманатын орындау',2018,'Дильшат Рахматуллин',0,false,'https://www.youtube.com/watch?v=ZIN8iq9x19g','4415cc51-fe93-45c7-8cff-a0b5c1862fae.jpeg');
 	________________________________________________________________________________________________________________________________________________________________________________
 	
 insert into allseries(title, series, description, release_year, director, rating, trailer_url)
 values 
-  ('3', 'Ойыншықтар Хикаясы 3', 'Энди шамамен 18 жаста, колледжге жіберілгенге дейін 3 күн қалды, ал оның ойыншықтары, соның ішінде Вуди мен Базз Лайтер, олардың болашағы туралы болжам жасайды. Олардың тағдыры қайда кетеді? Шатырға, полигонға немесе мүмкін "күн"балабақшасына? Оқиғалар күтпеген бағытта дамиды және сүйікті кейіпкерлердің шытырман оқиғалары жалғасуда!', 2010, 'Ли Анкрич', 0, 'https://youtu.be/EmsZ9vcoKAo?si=nTn5_tKuN9gXjXWK'),
-  ('1', 'Ойыншықтар Хикаясы 1', 'Сюжет Энди есімді балаға тиесілі ойыншықтардың шытырман оқиғаларына арналған. Ұзақ уақыт бойы баланың сүйіктісі ковбой Вуди болды, оның келесі туған күніне дейін Энди ерекше сыйлық - электронды астроранжер Buzz Lightyear алды. Вуди көп ұзамай Эндидің жаңа ойыншыққа есінен танып қалғанын және баланың Баззды өзімен бірге алып кетпеуін қалайтынын түсіне бастайды. Осылайша, сәтті пайдаланып, бұрынғы сүйіктісі танымал жаңадан келгенді терезеден лақтырып жібереді. Кінәлі сезінген ковбой Эндидің отбасы жаңа үйіне көшкенше астронавтты үйіне әкелуді ұйғарады', 1995, 'Джон Лассетер', 0, 'https://www.youtube.com/watch?v=LI_GT4uFd-o&pp=ygU00L7QudGL0L3RiNGL0pvRgtCw0YAg0YXQuNC60LDRj9GB0YsgMyDQutCw0LfQsNC60YjQsA%3D%3D'),
-  ('2', 'Ойыншықтар Хикаясы 2', 'Ковбой Вуди және қалған ойыншықтар Эндидің бөлмесінде тұруды жалғастырады. Коллекционер Аль Вудиді экспонат жасау үшін ұрлап кеткенде бәрі өзгереді. Оған 1950 жылдардағы телешоу ойыншықтар жиынтығын аяқтау үшін бар болғаны ковбой ғана қажет - содан кейін Аль коллекцияны жапондық ойыншықтар мұражайына сата алады. Вуди өзінің құнды және коллекциялық деп санайтынына қуанады, сонымен қатар өзінің «байланысты» ойыншықтарымен танысады. Олар ковбойды мұражайдағы өмірдің Эндидің өсіп, одан асып кетуін күткеннен гөрі жақсырақ екеніне сендіреді. Осы уақытта Buzz Light Вудиді үйіне қайтару үшін құтқару операциясын ұйымдастырады.', 1999, 'Джон Лассетер', 0, 'https://www.youtube.com/watch?v=gcawsj1M1b0&pp=ygU00L7QudGL0L3RiNGL0pvRgtCw0YAg0YXQuNC60LDRj9GB0YsgMiDQutCw0LfQsNC60YjQsA%3D%3D'),
-  ('1', 'Три кота Серия 1', 'В дружной кошачьей семье постоянно происходят поучительные и забавные события, героями которых становятся котята Карамелька, Компот и Коржик. С любыми трудностями им всегда помогают справиться родители —папа, глава семейства, трудящийся на кондитерской фабрике, и мама, хранительница домашнего уюта и по совместительству талантливый дизайнер детской одежды. Главные герои имеют свой неповторимый характер и отличительные черты. Семилетний Компот очень развит и эрудирован, все свободное время проводит с книгой и обожает разгадывать головоломки, периодически прерываясь на игры в шахматы с папой. Шестилетний Коржик — кот-батарейка, всегда озорной и с неограниченным запасом энергии, не представляет своей жизни без игр в футбол, бадминтон, догонялок и любой активности, а разговаривает всегда очень громко, шумно. Четырехлетняя Карамелька спокойна и рассудительна, мудра не по годам. Посмотрев онлайн сериал «Три Кота», ваш ребенок сможет познакомиться с героями поближе и узнать в них как себя, так и своих друзей, будет постепенно учиться общаться и взаимодействовать как со сверстниками, так и со взрослыми.', 2015, 'Дмитрий Высоцкий', 0, 'https://www.youtube.com/watch?v=bgxiTkAlQrw&ab_channel=iVideos'),
-  ('2', 'Три кота Серия 2', 'В дружной кошачьей семье постоянно происходят поучительные и забавные события, героями которых становятся котята Карамелька, Компот и Коржик. С любыми трудностями им всегда помогают справиться родители —папа, глава семейства, трудящийся на кондитерской фабрике, и мама, хранительница домашнего уюта и по совместительству талантливый дизайнер детской одежды. Главные герои имеют свой неповторимый характер и отличительные черты. Семилетний Компот очень развит и эрудирован, все свободное время проводит с книгой и обожает разгадывать головоломки, периодически прерываясь на игры в шахматы с папой. Шестилетний Коржик — кот-батарейка, всегда озорной и с неограниченным запасом энергии, не представляет своей жизни без игр в футбол, бадминтон, догонялок и любой активности, а разговаривает всегда очень громко, шумно. Четырехлетняя Карамелька спокойна и рассудительна, мудра не по годам. Посмотрев онлайн сериал «Три Кота», ваш ребенок сможет познакомиться с героями поближе и узнать в них как себя, так и своих друзей, будет постепенно учиться общаться и взаимодействовать как со сверстниками, так и со взрослыми.', 2015, 'Дмитрий Высоцкий', 0, 'https://www.youtube.com/watch?v=bgxiTkAlQrw&ab_channel=iVideos'),
-  ('3', 'Три кота Серия 3', 'В дружной кошачьей семье постоянно происходят поучительные и забавные события, героями которых становятся котята Карамелька, Компот и Коржик. С любыми трудностями им всегда помогают справиться родители —папа, глава семейства, трудящийся на кондитерской фабрике, и мама, хранительница домашнего уюта и по совместительству талантливый дизайнер детской одежды. Главные герои имеют свой неповторимый характер и отличительные черты. Семилетний Компот очень развит и эрудирован, все свободное время проводит с книгой и обожает разгадывать головоломки, периодически прерываясь на игры в шахматы с папой. Шестилетний Коржик — кот-батарейка, всегда озорной и с неограниченным запасом энергии, не представляет своей жизни без игр в футбол, бадминтон, догонялок и любой активности, а разговаривает всегда очень громко, шумно. Четырехлетняя Карамелька спокойна и рассудительна, мудра не по годам. Посмотрев онлайн сериал «Три Кота», ваш ребенок сможет познакомиться с героями поближе и узнать в них как себя, так и своих друзей, будет постепенно учиться общаться и взаимодействовать как со сверстниками, так и со взрослыми.', 2015, 'Дмитрий Высоцкий', 0, 'https://www.youtube.com/watch?v=bgxiTkAlQrw&ab_channel=iVideos')
+  ('3', 'Ойыншықтар Хикаясы 3', 'Энди шамамен 18 жаста, колледжге жіберілгенге дейін 3 күн қалды, ал оның ойыншықтары, соның ішінде Вуди мен Базз Лайтер, олардың болашағы туралы болжам жасайды. Олардың тағдыры қайда кетеді? Шатырға, полигонға немесе мүмкін "күн"балабақшасына? Оқиғалар күтпеген бағытта дамиды және сүйікті кейіпкерлердің шытырман оқиғалары жалғасуда!', 2010, 'Ли Анкрич', 0, 'https://www.youtube.com/watch?v=EmsZ9vcoKAo'),
+  ('1', 'Ойыншықтар Хикаясы 1', 'Сюжет Энди есімді балаға тиесілі ойыншықтардың шытырман оқиғаларына арналған. Ұзақ уақыт бойы баланың сүйіктісі ковбой Вуди болды, оның келесі туған күніне дейін Энди ерекше сыйлық - электронды астроранжер Buzz Lightyear алды. Вуди көп ұзамай Эндидің жаңа ойыншыққа есінен танып қалғанын және баланың Баззды өзімен бірге алып кетпеуін қалайтынын түсіне бастайды. Осылайша, сәтті пайдаланып, бұрынғы сүйіктісі танымал жаңадан келгенді терезеден лақтырып жібереді. Кінәлі сезінген ковбой Эндидің отбасы жаңа үйіне көшкенше астронавтты үйіне әкелуді ұйғарады', 1995, 'Джон Лассетер', 0, 'https://www.youtube.com/watch?v=LI_GT4uFd-o'),
+  ('2', 'Ойыншықтар Хикаясы 2', 'Ковбой Вуди және қалған ойыншықтар Эндидің бөлмесінде тұруды жалғастырады. Коллекционер Аль Вудиді экспонат жасау үшін ұрлап кеткенде бәрі өзгереді. Оған 1950 жылдардағы телешоу ойыншықтар жиынтығын аяқтау үшін бар болғаны ковбой ғана қажет - содан кейін Аль коллекцияны жапондық ойыншықтар мұражайына сата алады. Вуди өзінің құнды және коллекциялық деп санайтынына қуанады, сонымен қатар өзінің «байланысты» ойыншықтарымен танысады. Олар ковбойды мұражайдағы өмірдің Эндидің өсіп, одан асып кетуін күткеннен гөрі жақсырақ екеніне сендіреді. Осы уақытта Buzz Light Вудиді үйіне қайтару үшін құтқару операциясын ұйымдастырады.', 1999, 'Джон Лассетер', 0, 'https://www.youtube.com/watch?v=gcawsj1M1b0'),
+  ('1', 'Три кота Серия 1', 'В дружной кошачьей семье постоянно происходят поучительные и забавные события, героями которых становятся котята Карамелька, Компот и Коржик. С любыми трудностями им всегда помогают справиться родители —папа, глава семейства, трудящийся на кондитерской фабрике, и мама, хранительница домашнего уюта и по совместительству талантливый дизайнер детской одежды. Главные герои имеют свой неповторимый характер и отличительные черты. Семилетний Компот очень развит и эрудирован, все свободное время проводит с книгой и обожает разгадывать головоломки, периодически прерываясь на игры в шахматы с папой. Шестилетний Коржик — кот-батарейка, всегда озорной и с неограниченным запасом энергии, не представляет своей жизни без игр в футбол, бадминтон, догонялок и любой активности, а разговаривает всегда очень громко, шумно. Четырехлетняя Карамелька спокойна и рассудительна, мудра не по годам. Посмотрев онлайн сериал «Три Кота», ваш ребенок сможет познакомиться с героями поближе и узнать в них как себя, так и своих друзей, будет постепенно учиться общаться и взаимодействовать как со сверстниками, так и со взрослыми.', 2015, 'Дмитрий Высоцкий', 0, 'https://www.youtube.com/watch?v=bgxiTkAlQrw'),
+  ('2', 'Три кота Серия 2', 'В дружной кошачьей семье постоянно происходят поучительные и забавные события, героями которых становятся котята Карамелька, Компот и Коржик. С любыми трудностями им всегда помогают справиться родители —папа, глава семейства, трудящийся на кондитерской фабрике, и мама, хранительница домашнего уюта и по совместительству талантливый дизайнер детской одежды. Главные герои имеют свой неповторимый характер и отличительные черты. Семилетний Компот очень развит и эрудирован, все свободное время проводит с книгой и обожает разгадывать головоломки, периодически прерываясь на игры в шахматы с папой. Шестилетний Коржик — кот-батарейка, всегда озорной и с неограниченным запасом энергии, не представляет своей жизни без игр в футбол, бадминтон, догонялок и любой активности, а разговаривает всегда очень громко, шумно. Четырехлетняя Карамелька спокойна и рассудительна, мудра не по годам. Посмотрев онлайн сериал «Три Кота», ваш ребенок сможет познакомиться с героями поближе и узнать в них как себя, так и своих друзей, будет постепенно учиться общаться и взаимодействовать как со сверстниками, так и со взрослыми.', 2015, 'Дмитрий Высоцкий', 0, 'https://www.youtube.com/watch?v=bgxiTkAlQrw'),
+  ('3', 'Три кота Серия 3', 'В дружной кошачьей семье постоянно происходят поучительные и забавные события, героями которых становятся котята Карамелька, Компот и Коржик. С любыми трудностями им всегда помогают справиться родители —папа, глава семейства, трудящийся на кондитерской фабрике, и мама, хранительница домашнего уюта и по совместительству талантливый дизайнер детской одежды. Главные герои имеют свой неповторимый характер и отличительные черты. Семилетний Компот очень развит и эрудирован, все свободное время проводит с книгой и обожает разгадывать головоломки, периодически прерываясь на игры в шахматы с папой. Шестилетний Коржик — кот-батарейка, всегда озорной и с неограниченным запасом энергии, не представляет своей жизни без игр в футбол, бадминтон, догонялок и любой активности, а разговаривает всегда очень громко, шумно. Четырехлетняя Карамелька спокойна и рассудительна, мудра не по годам. Посмотрев онлайн сериал «Три Кота», ваш ребенок сможет познакомиться с героями поближе и узнать в них как себя, так и своих друзей, будет постепенно учиться общаться и взаимодействовать как со сверстниками, так и со взрослыми.', 2015, 'Дмитрий Высоцкий', 0, 'https://www.youtube.com/watch?v=bgxiTkAlQrw')
 ___________________________________________________________________________________________________________________________________________________________________________
 
 INSERT INTO movies_allseries (movie_id,allserie_id) VALUES
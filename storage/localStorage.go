@@ -0,0 +1,117 @@
+package storage
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+var ErrInvalidKey = errors.New("invalid storage key")
+
+// LocalStorage хранит файлы в каталоге на диске. Ключ — путь относительно корня,
+// например "poster.png" или "media/12/master.m3u8"
+type LocalStorage struct {
+	root string
+}
+
+func NewLocalStorage(root string) *LocalStorage {
+	return &LocalStorage{root: root}
+}
+
+func (s *LocalStorage) ReadFile(key string) ([]byte, error) {
+	path, err := s.path(key)
+	if err != nil {
+		return nil, err
+	}
+
+	return os.ReadFile(path)
+}
+
+func (s *LocalStorage) Open(key string) (*os.File, error) {
+	path, err := s.path(key)
+	if err != nil {
+		return nil, err
+	}
+
+	return os.Open(path)
+}
+
+// TempDir создаёт временный каталог внутри хранилища, чтобы Put мог перенести его без копирования
+func (s *LocalStorage) TempDir(pattern string) (string, error) {
+	err := os.MkdirAll(s.root, 0755)
+	if err != nil {
+		return "", err
+	}
+
+	return os.MkdirTemp(s.root, "."+pattern)
+}
+
+// WriteFile записывает файл под ключ целиком: сначала во временный файл, потом rename
+func (s *LocalStorage) WriteFile(key string, data []byte) error {
+	path, err := s.path(key)
+	if err != nil {
+		return err
+	}
+
+	err = os.MkdirAll(filepath.Dir(path), 0755)
+	if err != nil {
+		return err
+	}
+
+	file, err := os.CreateTemp(filepath.Dir(path), ".write-")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(file.Name())
+
+	_, err = file.Write(data)
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return err
+	}
+
+	return os.Rename(file.Name(), path)
+}
+
+// Put переносит готовый файл или каталог под ключ, заменяя прежнее содержимое
+func (s *LocalStorage) Put(key string, source string) error {
+	path, err := s.path(key)
+	if err != nil {
+		return err
+	}
+
+	err = os.MkdirAll(filepath.Dir(path), 0755)
+	if err != nil {
+		return err
+	}
+
+	err = os.RemoveAll(path)
+	if err != nil {
+		return err
+	}
+
+	return os.Rename(source, path)
+}
+
+// Remove удаляет файл или каталог целиком; отсутствие ключа не ошибка
+func (s *LocalStorage) Remove(key string) error {
+	path, err := s.path(key)
+	if err != nil {
+		return err
+	}
+
+	return os.RemoveAll(path)
+}
+
+// path не выпускает ключ за пределы корня хранилища
+func (s *LocalStorage) path(key string) (string, error) {
+	cleaned := filepath.Clean("/" + filepath.FromSlash(key))
+	if cleaned == string(filepath.Separator) || strings.Contains(key, "\x00") {
+		return "", ErrInvalidKey
+	}
+
+	return filepath.Join(s.root, cleaned), nil
+}
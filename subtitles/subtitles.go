@@ -0,0 +1,254 @@
+// Package subtitles разбирает субтитры SRT и WebVTT и записывает их в WebVTT
+package subtitles
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+const (
+	FormatSrt    = "srt"
+	FormatWebVtt = "vtt"
+
+	vttHeader = "WEBVTT"
+	utf8Bom   = "\uFEFF"
+)
+
+var (
+	ErrNotUtf8 = errors.New("subtitles must be UTF-8 encoded")
+	ErrNoCues  = errors.New("subtitles contain no cues")
+)
+
+// 00:01:02,345 (SRT), 00:01:02.345 и 01:02.345 (WebVTT)
+var timestampPattern = regexp.MustCompile(`^(?:(\d+):)?([0-5]\d):([0-5]\d)[.,](\d{3})$`)
+
+// ParseError — ошибка в конкретной строке файла
+type ParseError struct {
+	Line    int
+	Message string
+}
+
+func (e *ParseError) Error() string {
+	return fmt.Sprintf("line %d: %s", e.Line, e.Message)
+}
+
+// Cue — одна реплика. Settings — параметры WebVTT после времени (position, align и т.п.)
+type Cue struct {
+	Id       string
+	Start    time.Duration
+	End      time.Duration
+	Settings string
+	Text     string
+}
+
+// DetectFormat определяет формат по содержимому: WebVTT обязан начинаться с WEBVTT
+func DetectFormat(data []byte) string {
+	data = bytes.TrimPrefix(data, []byte(utf8Bom))
+	if bytes.HasPrefix(data, []byte(vttHeader)) {
+		return FormatWebVtt
+	}
+	return FormatSrt
+}
+
+// Parse разбирает SRT или WebVTT и проверяет времена реплик
+func Parse(data []byte) ([]Cue, error) {
+	if !utf8.Valid(data) {
+		return nil, ErrNotUtf8
+	}
+
+	format := DetectFormat(data)
+	text := strings.TrimPrefix(string(data), utf8Bom)
+	text = strings.ReplaceAll(text, "\r\n", "\n")
+	text = strings.ReplaceAll(text, "\r", "\n")
+
+	blocks := splitBlocks(text)
+	if format == FormatWebVtt {
+		header := blocks[0]
+		if header.lines[0] != vttHeader && !strings.HasPrefix(header.lines[0], vttHeader+" ") && !strings.HasPrefix(header.lines[0], vttHeader+"\t") {
+			return nil, &ParseError{Line: header.line, Message: "invalid WEBVTT header"}
+		}
+		blocks = blocks[1:]
+	}
+
+	cues := make([]Cue, 0, len(blocks))
+	for _, block := range blocks {
+		if format == FormatWebVtt && isVttMetadataBlock(block.lines[0]) {
+			continue
+		}
+
+		cue, err := parseCue(block, format)
+		if err != nil {
+			return nil, err
+		}
+		cues = append(cues, cue)
+	}
+
+	if len(cues) == 0 {
+		return nil, ErrNoCues
+	}
+
+	return cues, nil
+}
+
+// WriteWebVtt записывает реплики, сдвинув их на offset. Реплики, целиком ушедшие до нуля, отбрасываются
+func WriteWebVtt(w io.Writer, cues []Cue, offset time.Duration) error {
+	writer := bufio.NewWriter(w)
+	writer.WriteString(vttHeader + "\n")
+
+	for _, cue := range cues {
+		start, end := cue.Start+offset, cue.End+offset
+		if end <= 0 {
+			continue
+		}
+		start = max(start, 0)
+
+		writer.WriteString("\n")
+		if cue.Id != "" {
+			writer.WriteString(cue.Id + "\n")
+		}
+		writer.WriteString(formatTimestamp(start) + " --> " + formatTimestamp(end))
+		if cue.Settings != "" {
+			writer.WriteString(" " + cue.Settings)
+		}
+		writer.WriteString("\n" + cue.Text + "\n")
+	}
+
+	return writer.Flush()
+}
+
+type block struct {
+	line  int
+	lines []string
+}
+
+// splitBlocks делит текст на блоки, разделённые пустыми строками; line — номер первой строки блока
+func splitBlocks(text string) []block {
+	blocks := make([]block, 0)
+	var current *block
+
+	for i, line := range strings.Split(text, "\n") {
+		if strings.TrimSpace(line) == "" {
+			current = nil
+			continue
+		}
+
+		if current == nil {
+			blocks = append(blocks, block{line: i + 1})
+			current = &blocks[len(blocks)-1]
+		}
+		current.lines = append(current.lines, line)
+	}
+
+	if len(blocks) == 0 {
+		blocks = append(blocks, block{line: 1, lines: []string{""}})
+	}
+
+	return blocks
+}
+
+func isVttMetadataBlock(first string) bool {
+	for _, prefix := range []string{"NOTE", "STYLE", "REGION"} {
+		if first == prefix || strings.HasPrefix(first, prefix+" ") || strings.HasPrefix(first, prefix+"\t") {
+			return true
+		}
+	}
+	return false
+}
+
+func parseCue(block block, format string) (Cue, error) {
+	var cue Cue
+	lines := block.lines
+	timingLine := block.line
+
+	// перед временем может стоять номер (SRT) или идентификатор (WebVTT)
+	if !strings.Contains(lines[0], "-->") {
+		if len(lines) < 2 {
+			return Cue{}, &ParseError{Line: block.line, Message: "cue timing expected"}
+		}
+		if format == FormatSrt {
+			if _, err := strconv.Atoi(strings.TrimSpace(lines[0])); err != nil {
+				return Cue{}, &ParseError{Line: block.line, Message: "cue number expected"}
+			}
+		} else {
+			cue.Id = strings.TrimSpace(lines[0])
+		}
+		lines = lines[1:]
+		timingLine++
+	}
+
+	start, rest, ok := strings.Cut(lines[0], "-->")
+	if !ok {
+		return Cue{}, &ParseError{Line: timingLine, Message: "cue timing expected"}
+	}
+
+	fields := strings.Fields(rest)
+	if len(fields) == 0 {
+		return Cue{}, &ParseError{Line: timingLine, Message: "cue end time expected"}
+	}
+
+	var err error
+	cue.Start, err = parseTimestamp(strings.TrimSpace(start))
+	if err != nil {
+		return Cue{}, &ParseError{Line: timingLine, Message: err.Error()}
+	}
+	cue.End, err = parseTimestamp(fields[0])
+	if err != nil {
+		return Cue{}, &ParseError{Line: timingLine, Message: err.Error()}
+	}
+	if cue.End <= cue.Start {
+		return Cue{}, &ParseError{Line: timingLine, Message: "cue ends before it starts"}
+	}
+
+	// у SRT после времени бывают координаты X1:.. Y1:.., в WebVTT их нет — отбрасываем
+	if format == FormatWebVtt {
+		cue.Settings = strings.Join(fields[1:], " ")
+	}
+
+	// "-->" внутри текста реплики ломает WebVTT
+	text := lines[1:]
+	for i, line := range text {
+		if strings.Contains(line, "-->") {
+			return Cue{}, &ParseError{Line: timingLine + 1 + i, Message: "unexpected cue timing in text"}
+		}
+	}
+	cue.Text = strings.Join(text, "\n")
+
+	return cue, nil
+}
+
+func parseTimestamp(value string) (time.Duration, error) {
+	match := timestampPattern.FindStringSubmatch(value)
+	if match == nil {
+		return 0, fmt.Errorf("invalid timestamp %q", value)
+	}
+
+	hours := 0
+	if match[1] != "" {
+		hours, _ = strconv.Atoi(match[1])
+	}
+	minutes, _ := strconv.Atoi(match[2])
+	seconds, _ := strconv.Atoi(match[3])
+	millis, _ := strconv.Atoi(match[4])
+
+	return time.Duration(hours)*time.Hour +
+		time.Duration(minutes)*time.Minute +
+		time.Duration(seconds)*time.Second +
+		time.Duration(millis)*time.Millisecond, nil
+}
+
+func formatTimestamp(value time.Duration) string {
+	millis := value.Milliseconds()
+	return fmt.Sprintf("%02d:%02d:%02d.%03d",
+		millis/3600000,
+		millis/60000%60,
+		millis/1000%60,
+		millis%1000)
+}
@@ -0,0 +1,184 @@
+package subtitles
+
+import (
+	"bytes"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func ms(value int) time.Duration {
+	return time.Duration(value) * time.Millisecond
+}
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name     string
+		data     string
+		want     []Cue
+		wantErr  error
+		wantLine int
+	}{
+		{
+			name: "srt",
+			data: "1\n00:00:01,000 --> 00:00:02,500\nСәлем!\n\n2\n00:00:03,000 --> 00:00:04,000\nHello\nworld\n",
+			want: []Cue{
+				{Start: ms(1000), End: ms(2500), Text: "Сәлем!"},
+				{Start: ms(3000), End: ms(4000), Text: "Hello\nworld"},
+			},
+		},
+		{
+			name: "srt with bom, crlf and coordinates",
+			data: "\ufeff1\r\n00:00:01,000 --> 00:00:02,000 X1:10 X2:20 Y1:30 Y2:40\r\nText\r\n",
+			want: []Cue{{Start: ms(1000), End: ms(2000), Text: "Text"}},
+		},
+		{
+			name: "srt without cue numbers",
+			data: "00:00:01,000 --> 00:00:02,000\nText\n",
+			want: []Cue{{Start: ms(1000), End: ms(2000), Text: "Text"}},
+		},
+		{
+			name: "srt hours over 99",
+			data: "1\n100:00:00,000 --> 100:00:01,000\nText\n",
+			want: []Cue{{Start: 100 * time.Hour, End: 100*time.Hour + ms(1000), Text: "Text"}},
+		},
+		{
+			name: "webvtt with ids, settings and metadata",
+			data: "WEBVTT - Kazakh\n\nNOTE translated by volunteers\n\nSTYLE\n::cue { color: yellow }\n\nintro\n00:01.000 --> 00:02.000 align:start position:10%\n<i>Text</i>\n\n01:00:00.000 --> 01:00:01.500\nLast\n",
+			want: []Cue{
+				{Id: "intro", Start: ms(1000), End: ms(2000), Settings: "align:start position:10%", Text: "<i>Text</i>"},
+				{Start: time.Hour, End: time.Hour + ms(1500), Text: "Last"},
+			},
+		},
+		{
+			name:    "not utf-8",
+			data:    "1\n00:00:01,000 --> 00:00:02,000\n\xcf\xf0\xe8\xe2\xe5\xf2\n",
+			wantErr: ErrNotUtf8,
+		},
+		{
+			name:    "webvtt without cues",
+			data:    "WEBVTT\n\nNOTE nothing here\n",
+			wantErr: ErrNoCues,
+		},
+		{
+			name:     "invalid webvtt header",
+			data:     "WEBVTTX\n\n00:01.000 --> 00:02.000\nText\n",
+			wantLine: 1,
+		},
+		{
+			name:     "empty file",
+			data:     "",
+			wantLine: 1,
+		},
+		{
+			name:     "srt cue number is not a number",
+			data:     "1\n00:00:01,000 --> 00:00:02,000\nOk\n\nfirst\n00:00:03,000 --> 00:00:04,000\nText\n",
+			wantLine: 5,
+		},
+		{
+			name:     "missing timing",
+			data:     "1\nText\n",
+			wantLine: 2,
+		},
+		{
+			name:     "missing end time",
+			data:     "1\n00:00:01,000 -->\nText\n",
+			wantLine: 2,
+		},
+		{
+			name:     "invalid timestamp",
+			data:     "1\n00:00:01 --> 00:00:02,000\nText\n",
+			wantLine: 2,
+		},
+		{
+			name:     "seconds out of range",
+			data:     "1\n00:00:61,000 --> 00:01:02,000\nText\n",
+			wantLine: 2,
+		},
+		{
+			name:     "cue ends before it starts",
+			data:     "1\n00:00:02,000 --> 00:00:02,000\nText\n",
+			wantLine: 2,
+		},
+		{
+			name:     "timing inside text",
+			data:     "1\n00:00:01,000 --> 00:00:02,000\nText\n00:00:03,000 --> 00:00:04,000\n",
+			wantLine: 4,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Parse([]byte(tt.data))
+
+			if tt.wantLine != 0 {
+				var parseErr *ParseError
+				if !errors.As(err, &parseErr) {
+					t.Fatalf("Parse() error = %v, want ParseError", err)
+				}
+				if parseErr.Line != tt.wantLine {
+					t.Errorf("Parse() error line = %d, want %d (%v)", parseErr.Line, tt.wantLine, err)
+				}
+				return
+			}
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Parse() error = %v, want %v", err, tt.wantErr)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Parse() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestWriteWebVtt(t *testing.T) {
+	cues := []Cue{
+		{Start: ms(500), End: ms(1500), Text: "First"},
+		{Id: "second", Start: ms(2000), End: ms(3000), Settings: "align:end", Text: "Second\nline"},
+		{Start: time.Hour + ms(61001), End: time.Hour + ms(62000), Text: "Last"},
+	}
+
+	tests := []struct {
+		name   string
+		offset time.Duration
+		want   string
+	}{
+		{
+			name:   "without offset",
+			offset: 0,
+			want: "WEBVTT\n\n00:00:00.500 --> 00:00:01.500\nFirst\n\nsecond\n00:00:02.000 --> 00:00:03.000 align:end\nSecond\nline\n" +
+				"\n01:01:01.001 --> 01:01:02.000\nLast\n",
+		},
+		{
+			name:   "positive offset",
+			offset: ms(250),
+			want: "WEBVTT\n\n00:00:00.750 --> 00:00:01.750\nFirst\n\nsecond\n00:00:02.250 --> 00:00:03.250 align:end\nSecond\nline\n" +
+				"\n01:01:01.251 --> 01:01:02.250\nLast\n",
+		},
+		{
+			name:   "negative offset clamps start and drops past cues",
+			offset: -ms(1500),
+			want: "WEBVTT\n\nsecond\n00:00:00.500 --> 00:00:01.500 align:end\nSecond\nline\n" +
+				"\n01:00:59.501 --> 01:01:00.500\nLast\n",
+		},
+		{
+			name:   "negative offset inside cue",
+			offset: -ms(1000),
+			want: "WEBVTT\n\n00:00:00.000 --> 00:00:00.500\nFirst\n\nsecond\n00:00:01.000 --> 00:00:02.000 align:end\nSecond\nline\n" +
+				"\n01:01:00.001 --> 01:01:01.000\nLast\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buffer bytes.Buffer
+			if err := WriteWebVtt(&buffer, cues, tt.offset); err != nil {
+				t.Fatalf("WriteWebVtt() error = %v", err)
+			}
+			if got := buffer.String(); got != tt.want {
+				t.Errorf("WriteWebVtt() =\n%s\nwant\n%s", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,78 @@
+package tokens
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"strings"
+)
+
+// encryptedKeyPrefix помечает зашифрованный приватный ключ в jwt_signing_keys.private_key.
+// Ключи без префикса — открытый PEM, сохранённый до появления шифрования
+const encryptedKeyPrefix = "aesgcm:"
+
+// keyCipher шифрует приватные ключи подписи AES-256-GCM, чтобы утечка базы или бэкапа не давала подделывать токены
+type keyCipher struct {
+	aead cipher.AEAD
+}
+
+// newKeyCipher принимает 32 байта в base64 из JWT_KEY_ENCRYPTION_KEY
+func newKeyCipher(secret string) (*keyCipher, error) {
+	key, err := base64.StdEncoding.DecodeString(secret)
+	if err != nil || len(key) != 32 {
+		return nil, errors.New("jwt key encryption key must be 32 bytes in base64")
+	}
+
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, err
+	}
+
+	return &keyCipher{aead: aead}, nil
+}
+
+// encrypt привязывает шифртекст к kid, чтобы зашифрованный ключ нельзя было подставить в чужую строку
+func (k *keyCipher) encrypt(kid string, plain string) (string, error) {
+	nonce := make([]byte, k.aead.NonceSize())
+	_, err := rand.Read(nonce)
+	if err != nil {
+		return "", err
+	}
+
+	sealed := k.aead.Seal(nonce, nonce, []byte(plain), []byte(kid))
+	return encryptedKeyPrefix + base64.StdEncoding.EncodeToString(sealed), nil
+}
+
+func (k *keyCipher) decrypt(kid string, stored string) (string, error) {
+	encoded, ok := strings.CutPrefix(stored, encryptedKeyPrefix)
+	if !ok {
+		return "", errors.New("private key is not encrypted")
+	}
+
+	sealed, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil {
+		return "", err
+	}
+	if len(sealed) < k.aead.NonceSize() {
+		return "", errors.New("encrypted private key is too short")
+	}
+
+	nonce, ciphertext := sealed[:k.aead.NonceSize()], sealed[k.aead.NonceSize():]
+	plain, err := k.aead.Open(nil, nonce, ciphertext, []byte(kid))
+	if err != nil {
+		return "", err
+	}
+
+	return string(plain), nil
+}
+
+func isEncryptedKey(stored string) bool {
+	return strings.HasPrefix(stored, encryptedKeyPrefix)
+}
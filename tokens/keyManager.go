@@ -0,0 +1,370 @@
+package tokens
+
+import (
+	"context"
+	"crypto"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"goozinshe/logger"
+	"goozinshe/models"
+	"goozinshe/repositories"
+	"math/big"
+	"sync"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+const (
+	rotationCheckInterval = time.Hour
+	// не чаще раза в минуту перечитываем ключи из базы из-за неизвестного kid
+	unknownKidReloadInterval = time.Minute
+)
+
+// ValidMethods — алгоритмы, которые принимает AuthMiddleware; HS256 больше не поддерживается
+var ValidMethods = []string{jwt.SigningMethodRS256.Alg(), jwt.SigningMethodEdDSA.Alg()}
+
+type signingKey struct {
+	kid       string
+	method    jwt.SigningMethod
+	private   crypto.Signer
+	public    crypto.PublicKey
+	createdAt time.Time
+	canSign   bool
+}
+
+// KeyManager хранит в памяти активные ключи подписи JWT и периодически ротирует их.
+// Токены подписываются самым новым ключом, проверяются любым неотозванным по kid.
+type KeyManager struct {
+	repo             *repositories.SigningKeysRepository
+	algorithm        string
+	rotationInterval time.Duration
+	gracePeriod      time.Duration
+	cipher           *keyCipher
+
+	mu         sync.RWMutex
+	keys       []signingKey
+	lastReload time.Time
+}
+
+func NewKeyManager(repo *repositories.SigningKeysRepository, algorithm string, rotationInterval time.Duration, gracePeriod time.Duration, encryptionKey string) (*KeyManager, error) {
+	if signingMethod(algorithm) == nil {
+		return nil, fmt.Errorf("unsupported jwt signing method %q", algorithm)
+	}
+
+	keyCipher, err := newKeyCipher(encryptionKey)
+	if err != nil {
+		return nil, err
+	}
+
+	return &KeyManager{
+		repo:             repo,
+		algorithm:        algorithm,
+		rotationInterval: rotationInterval,
+		gracePeriod:      gracePeriod,
+		cipher:           keyCipher,
+	}, nil
+}
+
+// Init загружает ключи из базы и создаёт первый ключ, если их ещё нет
+func (m *KeyManager) Init(c context.Context) error {
+	err := m.Reload(c)
+	if err != nil {
+		return err
+	}
+
+	return m.rotateIfDue(c)
+}
+
+func (m *KeyManager) Reload(c context.Context) error {
+	stored, err := m.repo.FindActive(c)
+	if err != nil {
+		return err
+	}
+
+	l := logger.GetLogger()
+	keys := make([]signingKey, 0, len(stored))
+	for _, s := range stored {
+		if isEncryptedKey(s.PrivateKey) {
+			s.PrivateKey, err = m.cipher.decrypt(s.Kid, s.PrivateKey)
+		} else {
+			err = m.encryptStoredKey(c, s)
+		}
+		if err != nil {
+			l.Error("Could not decrypt signing key", zap.String("kid", s.Kid), zap.String("err", err.Error()))
+			continue
+		}
+
+		key, err := decodeSigningKey(s)
+		if err != nil {
+			l.Error("Could not decode signing key", zap.String("kid", s.Kid), zap.String("err", err.Error()))
+			continue
+		}
+
+		keys = append(keys, key)
+	}
+
+	m.mu.Lock()
+	m.keys = keys
+	m.lastReload = time.Now()
+	m.mu.Unlock()
+
+	return nil
+}
+
+// encryptStoredKey шифрует ключ, сохранённый открытым PEM до появления шифрования
+func (m *KeyManager) encryptStoredKey(c context.Context, stored models.SigningKey) error {
+	encrypted, err := m.cipher.encrypt(stored.Kid, stored.PrivateKey)
+	if err != nil {
+		return err
+	}
+
+	return m.repo.UpdatePrivateKey(c, stored.Kid, encrypted)
+}
+
+// StartRotation раз в час проверяет возраст текущего ключа и при необходимости выпускает новый
+func (m *KeyManager) StartRotation(c context.Context) {
+	l := logger.GetLogger()
+	ticker := time.NewTicker(rotationCheckInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-c.Done():
+			return
+		case <-ticker.C:
+			err := m.Reload(c)
+			if err == nil {
+				err = m.rotateIfDue(c)
+			}
+			if err != nil {
+				l.Error("Could not rotate jwt signing keys", zap.String("err", err.Error()))
+			}
+		}
+	}
+}
+
+func (m *KeyManager) rotateIfDue(c context.Context) error {
+	current, ok := m.currentKey()
+	if ok && current.method.Alg() == m.algorithm && time.Since(current.createdAt) < m.rotationInterval {
+		return nil
+	}
+
+	return m.Rotate(c)
+}
+
+func (m *KeyManager) Rotate(c context.Context) error {
+	key, err := generateSigningKey(m.algorithm)
+	if err != nil {
+		return err
+	}
+
+	key.PrivateKey, err = m.cipher.encrypt(key.Kid, key.PrivateKey)
+	if err != nil {
+		return err
+	}
+
+	err = m.repo.Rotate(c, key, m.gracePeriod)
+	if err != nil {
+		return err
+	}
+
+	return m.Reload(c)
+}
+
+func (m *KeyManager) currentKey() (signingKey, bool) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	for _, key := range m.keys {
+		if key.canSign {
+			return key, true
+		}
+	}
+
+	return signingKey{}, false
+}
+
+func (m *KeyManager) findKey(kid string) (signingKey, bool) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	for _, key := range m.keys {
+		if key.kid == kid {
+			return key, true
+		}
+	}
+
+	return signingKey{}, false
+}
+
+func (m *KeyManager) canReload() bool {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	return time.Since(m.lastReload) > unknownKidReloadInterval
+}
+
+func (m *KeyManager) Sign(claims jwt.Claims) (string, error) {
+	key, ok := m.currentKey()
+	if !ok {
+		return "", errors.New("no active jwt signing key")
+	}
+
+	token := jwt.NewWithClaims(key.method, claims)
+	token.Header["kid"] = key.kid
+
+	return token.SignedString(key.private)
+}
+
+// Keyfunc для jwt.Parse: ищет ключ по kid и сверяет алгоритм токена с алгоритмом ключа
+func (m *KeyManager) Keyfunc(token *jwt.Token) (interface{}, error) {
+	kid, _ := token.Header["kid"].(string)
+	if kid == "" {
+		return nil, errors.New("token has no kid")
+	}
+
+	key, ok := m.findKey(kid)
+	if !ok && m.canReload() {
+		// ключ мог выпустить другой инстанс приложения
+		err := m.Reload(context.Background())
+		if err != nil {
+			return nil, err
+		}
+
+		key, ok = m.findKey(kid)
+	}
+	if !ok {
+		return nil, fmt.Errorf("unknown kid %s", kid)
+	}
+
+	if token.Method.Alg() != key.method.Alg() {
+		return nil, fmt.Errorf("unexpected signing method %s", token.Method.Alg())
+	}
+
+	return key.public, nil
+}
+
+// JWKS перечитывает ключи из базы, если кэш старше минуты: ключ мог выпустить другой инстанс приложения
+func (m *KeyManager) JWKS(c context.Context) models.JsonWebKeySet {
+	if m.canReload() {
+		err := m.Reload(c)
+		if err != nil {
+			l := logger.GetLogger()
+			l.Error("Could not reload jwt signing keys", zap.String("err", err.Error()))
+		}
+	}
+
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	set := models.JsonWebKeySet{Keys: make([]models.JsonWebKey, 0, len(m.keys))}
+	for _, key := range m.keys {
+		jwk := models.JsonWebKey{
+			Kid: key.kid,
+			Use: "sig",
+			Alg: key.method.Alg(),
+		}
+
+		switch public := key.public.(type) {
+		case *rsa.PublicKey:
+			jwk.Kty = "RSA"
+			jwk.N = base64.RawURLEncoding.EncodeToString(public.N.Bytes())
+			jwk.E = base64.RawURLEncoding.EncodeToString(big.NewInt(int64(public.E)).Bytes())
+		case ed25519.PublicKey:
+			jwk.Kty = "OKP"
+			jwk.Crv = "Ed25519"
+			jwk.X = base64.RawURLEncoding.EncodeToString(public)
+		default:
+			continue
+		}
+
+		set.Keys = append(set.Keys, jwk)
+	}
+
+	return set
+}
+
+func signingMethod(algorithm string) jwt.SigningMethod {
+	switch algorithm {
+	case jwt.SigningMethodRS256.Alg():
+		return jwt.SigningMethodRS256
+	case jwt.SigningMethodEdDSA.Alg():
+		return jwt.SigningMethodEdDSA
+	default:
+		return nil
+	}
+}
+
+func generateSigningKey(algorithm string) (models.SigningKey, error) {
+	var private crypto.Signer
+	var err error
+
+	switch algorithm {
+	case jwt.SigningMethodRS256.Alg():
+		private, err = rsa.GenerateKey(rand.Reader, 2048)
+	case jwt.SigningMethodEdDSA.Alg():
+		_, private, err = ed25519.GenerateKey(rand.Reader)
+	default:
+		err = fmt.Errorf("unsupported jwt signing method %q", algorithm)
+	}
+	if err != nil {
+		return models.SigningKey{}, err
+	}
+
+	privateDer, err := x509.MarshalPKCS8PrivateKey(private)
+	if err != nil {
+		return models.SigningKey{}, err
+	}
+
+	publicDer, err := x509.MarshalPKIXPublicKey(private.Public())
+	if err != nil {
+		return models.SigningKey{}, err
+	}
+
+	return models.SigningKey{
+		Kid:        uuid.NewString(),
+		Algorithm:  algorithm,
+		PrivateKey: string(pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: privateDer})),
+		PublicKey:  string(pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: publicDer})),
+	}, nil
+}
+
+func decodeSigningKey(stored models.SigningKey) (signingKey, error) {
+	method := signingMethod(stored.Algorithm)
+	if method == nil {
+		return signingKey{}, fmt.Errorf("unsupported jwt signing method %q", stored.Algorithm)
+	}
+
+	block, _ := pem.Decode([]byte(stored.PrivateKey))
+	if block == nil {
+		return signingKey{}, errors.New("invalid private key pem")
+	}
+
+	parsed, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		return signingKey{}, err
+	}
+
+	private, ok := parsed.(crypto.Signer)
+	if !ok {
+		return signingKey{}, errors.New("private key is not a signer")
+	}
+
+	return signingKey{
+		kid:       stored.Kid,
+		method:    method,
+		private:   private,
+		public:    private.Public(),
+		createdAt: stored.CreatedAt,
+		canSign:   stored.RetiredAt == nil,
+	}, nil
+}